@@ -30,6 +30,14 @@ func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	// Schema migrations run as a separate subcommand
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
 	// Create server
 	srv := server.NewServer(cfg)
 
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"ngo-transparency-platform/pkg/config"
+	"ngo-transparency-platform/pkg/database"
+)
+
+const migrateUsage = `Usage: api migrate <command> [flags]
+
+Commands:
+  up       Apply pending migrations (-to VERSION stops at a version)
+  down     Roll back applied migrations (-steps N, default 1)
+  status   List migrations and whether they are applied
+  verify   Check applied migrations against their checksums
+
+Flags:
+`
+
+// runMigrate implements the migrate subcommand
+func runMigrate(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "show what would run without changing the database")
+	target := flags.Int64("to", 0, "target version for up (0 applies all)")
+	steps := flags.Int("steps", 1, "number of migrations to roll back for down")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), migrateUsage)
+		flags.PrintDefaults()
+	}
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		flags.Usage()
+		return fmt.Errorf("missing migrate command")
+	}
+	command := args[0]
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if err := database.InitDatabase(cfg); err != nil {
+		return err
+	}
+	defer database.CloseDatabase()
+
+	migrator, err := database.NewMigrator(database.DB, database.Migrations())
+	if err != nil {
+		return err
+	}
+	migrator.DryRun = *dryRun
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(*target)
+		printSteps(applied, *dryRun)
+		return err
+	case "down":
+		reverted, err := migrator.Down(*steps)
+		printSteps(reverted, *dryRun)
+		return err
+	case "status":
+		return printStatus(migrator)
+	case "verify":
+		if err := migrator.Verify(); err != nil {
+			return err
+		}
+		fmt.Println("All applied migrations match their checksums")
+		return nil
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown migrate command: %s", command)
+	}
+}
+
+func printSteps(steps []database.MigrationStep, dryRun bool) {
+	if len(steps) == 0 {
+		fmt.Println("No migrations to run")
+		return
+	}
+
+	verb := "Ran"
+	if dryRun {
+		verb = "Would run"
+	}
+	for _, step := range steps {
+		fmt.Printf("%s %s %d_%s\n", verb, step.Direction, step.Version, step.Name)
+		if dryRun {
+			for _, stmt := range step.Statements {
+				fmt.Printf("    %s;\n", stmt)
+			}
+		}
+	}
+}
+
+func printStatus(migrator *database.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := ""
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		if status.Missing {
+			state = "missing"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
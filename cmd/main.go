@@ -5,7 +5,10 @@ import (
 	"log"
 	"math/big"
 	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/money"
 	"ngo-transparency-platform/pkg/platform"
+	"ngo-transparency-platform/pkg/transactions"
+	"time"
 )
 
 func main() {
@@ -16,12 +19,15 @@ func main() {
 	fmt.Println("✓ Platform initialized")
 
 	// Initialize Polygon integration (testnet)
-	ngoPlat.InitializePolygon(
+	err := ngoPlat.InitializePolygon(
 		"https://polygon-mumbai.g.alchemy.com/v2/demo",
 		"0x"+"1111111111111111111111111111111111111111111111111111111111111111", // Dummy private key
 		300000,
 		big.NewInt(30000000000), // 30 gwei
 	)
+	if err != nil {
+		log.Fatalf("Failed to initialize Polygon integration: %v", err)
+	}
 	fmt.Println("✓ Polygon integration initialized")
 
 	// Register Auditor
@@ -104,12 +110,12 @@ func main() {
 	fmt.Println("\n--- Processing Donations ---")
 
 	donations := []struct {
-		amount float64
+		amount money.Money
 		method string
 	}{
-		{50000, "UPI"},
-		{25000, "Credit Card"},
-		{30000, "Net Banking"},
+		{money.INR(50000 * 100), "UPI"},
+		{money.INR(25000 * 100), "Credit Card"},
+		{money.INR(30000 * 100), "Net Banking"},
 	}
 
 	for _, donationData := range donations {
@@ -124,11 +130,11 @@ func main() {
 			continue
 		}
 
-		fmt.Printf("✓ Donation processed: ₹%.0f via %s\n", donationData.amount, donationData.method)
+		fmt.Printf("✓ Donation processed: ₹%s via %s\n", donationData.amount, donationData.method)
 		fmt.Printf("  Transaction ID: %s\n", result["transaction_id"])
 		fmt.Printf("  Block Hash: %s\n", result["block_hash"])
-		fmt.Printf("  Platform Fee: ₹%.2f\n", result["platform_fee"])
-		fmt.Printf("  Net Amount: ₹%.2f\n", result["net_amount"])
+		fmt.Printf("  Platform Fee: ₹%s\n", result["platform_fee"])
+		fmt.Printf("  Net Amount: ₹%s\n", result["net_amount"])
 
 		// Check if anchored to Polygon
 		if eBill, ok := result["e_bill"].(map[string]interface{}); ok {
@@ -143,28 +149,51 @@ func main() {
 
 	expenditures := []map[string]interface{}{
 		{
-			"amount":      float64(40000),
+			"amount":      money.INR(40000 * 100),
 			"category":    "Education",
 			"description": "School books and supplies for 50 children",
+			"invoice": transactions.InvoiceDetails{
+				InvoiceNumber: "BB/2024/0417",
+				GSTIN:         "27AAPFU0939F1ZV",
+				VendorName:    "Bharat Books",
+				VendorGSTIN:   "29AAGCB7383J1Z4",
+				InvoiceDate:   time.Now(),
+				Documents:     []string{"invoice.pdf"},
+			},
 		},
 		{
-			"amount":      float64(35000),
+			"amount":      money.INR(35000 * 100),
 			"category":    "Healthcare",
 			"description": "Medical supplies and medicines",
+			"invoice": transactions.InvoiceDetails{
+				InvoiceNumber: "MED/2024/1182",
+				GSTIN:         "27AAPFU0939F1ZV",
+				VendorName:    "Sahyadri Medicals",
+				VendorGSTIN:   "27AASCS2460H1Z0",
+				InvoiceDate:   time.Now(),
+				Documents:     []string{"invoice.pdf"},
+			},
 		},
 	}
 
 	for _, expenditureData := range expenditures {
-		result, err := ngoPlat.ProcessExpenditure("NGO001", expenditureData, "AUD001")
+		submitted, err := ngoPlat.SubmitExpenditure("NGO001", expenditureData)
 		if err != nil {
 			fmt.Printf("✗ Expenditure failed: %s\n", err.Error())
 			continue
 		}
 
-		amount := expenditureData["amount"].(float64)
+		// The assigned auditor reviews the expenditure before it is recorded
+		result, err := ngoPlat.ReviewExpenditure(submitted["assigned_auditor_id"].(string), submitted["transaction_id"].(string), transactions.DecisionApprove, "Invoice and payment proof checked", nil)
+		if err != nil {
+			fmt.Printf("✗ Expenditure not approved: %s\n", err.Error())
+			continue
+		}
+
+		amount := expenditureData["amount"].(money.Money)
 		category := expenditureData["category"].(string)
 
-		fmt.Printf("✓ Expenditure processed: ₹%.0f for %s\n", amount, category)
+		fmt.Printf("✓ Expenditure processed: ₹%s for %s\n", amount, category)
 		fmt.Printf("  Transaction ID: %s\n", result["transaction_id"])
 		fmt.Printf("  Block Hash: %s\n", result["block_hash"])
 
@@ -194,12 +223,12 @@ func main() {
 	}
 
 	if stats, ok := donorDashboard["stats"].(entities.DonorStats); ok {
-		fmt.Printf("Total Donated: ₹%.2f\n", stats.TotalDonated)
-		fmt.Printf("Current Year Donations: ₹%.2f\n", stats.CurrentYearDonations)
+		fmt.Printf("Total Donated: ₹%s\n", stats.TotalDonated)
+		fmt.Printf("Current Year Donations: ₹%s\n", stats.CurrentYearDonations)
 		fmt.Printf("Donation Count: %d\n", stats.DonationCount)
 		fmt.Printf("Preferred NGOs: %d\n", stats.PreferredNGOsCount)
-		fmt.Printf("Average Donation: ₹%.2f\n", stats.AverageDonation)
-		fmt.Printf("Annual Limit: ₹%.2f\n", stats.AnnualLimit)
+		fmt.Printf("Average Donation: ₹%s\n", stats.AverageDonation)
+		fmt.Printf("Annual Limit: ₹%s\n", stats.AnnualLimit)
 	}
 
 	fmt.Println("\n--- NGO Dashboard ---")
@@ -209,8 +238,8 @@ func main() {
 	}
 
 	if stats, ok := ngoDashboard["stats"].(map[string]interface{}); ok {
-		fmt.Printf("Total Donations Received: ₹%.2f\n", stats["total_donations_received"])
-		fmt.Printf("Total Expenditure Reported: ₹%.2f\n", stats["total_expenditure_reported"])
+		fmt.Printf("Total Donations Received: ₹%s\n", stats["total_donations_received"])
+		fmt.Printf("Total Expenditure Reported: ₹%s\n", stats["total_expenditure_reported"])
 		fmt.Printf("Rating: %.2f/5.0\n", stats["rating"])
 		fmt.Printf("Transparency Score: %d%%\n", stats["transparency_score"])
 		fmt.Printf("Donation Blocks: %d\n", stats["donation_blockchain_length"])
@@ -239,9 +268,9 @@ func main() {
 	fmt.Printf("Total Donors: %d\n", platformStats.TotalDonors)
 	fmt.Printf("Total Auditors: %d\n", platformStats.TotalAuditors)
 	fmt.Printf("Total Transactions: %d\n", platformStats.TotalTransactions)
-	fmt.Printf("Total Donations: ₹%.2f\n", platformStats.TotalDonations)
-	fmt.Printf("Total Expenditures: ₹%.2f\n", platformStats.TotalExpenditures)
-	fmt.Printf("Platform Fee Collected: ₹%.2f\n", platformStats.PlatformFeeCollected)
+	fmt.Printf("Total Donations: ₹%s\n", platformStats.TotalDonations)
+	fmt.Printf("Total Expenditures: ₹%s\n", platformStats.TotalExpenditures)
+	fmt.Printf("Platform Fee Collected: ₹%s\n", platformStats.PlatformFeeCollected)
 	fmt.Printf("Average NGO Rating: %.2f/5.0\n", platformStats.AverageNGORating)
 	fmt.Printf("Days Active: %d\n", platformStats.DaysActive)
 	fmt.Printf("Verified NGOs: %d\n", platformStats.VerifiedNGOs)
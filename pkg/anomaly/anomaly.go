@@ -0,0 +1,380 @@
+// Package anomaly scans an NGO's expenditures for statistical patterns worth
+// an auditor's attention: first digits departing from Benford's law, invoices
+// split to stay under approval thresholds, clusters of round amounts, sudden
+// spikes in a category's spending and spending concentrated on one vendor.
+// Each alert carries a risk score from 0 to 100 so reviewers can start with
+// the riskiest.
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Kinds of alert
+const (
+	KindBenford             = "benford"
+	KindSplitInvoice        = "split_invoice"
+	KindRoundNumbers        = "round_numbers"
+	KindCategorySpike       = "category_spike"
+	KindVendorConcentration = "vendor_concentration"
+)
+
+// Expenditure is the part of an expenditure the detectors look at
+type Expenditure struct {
+	TransactionID string
+	Amount        money.Money
+	Category      string
+	VendorGSTIN   string // Empty when the vendor is unknown
+	Timestamp     time.Time
+}
+
+// Alert is an anomaly found in an NGO's expenditures
+type Alert struct {
+	Kind           string   `json:"kind"`
+	RiskScore      float64  `json:"risk_score"` // 0 to 100
+	Severity       string   `json:"severity"`   // low, medium or high
+	Description    string   `json:"description"`
+	TransactionIDs []string `json:"transaction_ids,omitempty"` // Empty for patterns across all expenditures
+}
+
+// Involves reports whether the alert concerns an expenditure, counting
+// patterns across all expenditures as concerning every one
+func (a Alert) Involves(transactionID string) bool {
+	if len(a.TransactionIDs) == 0 {
+		return true
+	}
+	for _, id := range a.TransactionIDs {
+		if id == transactionID {
+			return true
+		}
+	}
+	return false
+}
+
+// Config holds the detectors' sensitivity
+type Config struct {
+	BenfordMinSample int     // Expenditures needed before testing Benford's law
+	BenfordMaxMAD    float64 // Mean absolute deviation of first digits tolerated
+
+	SplitThresholds []money.Money // Approval thresholds invoices may be split to avoid
+	SplitWindow     time.Duration // How close together split invoices are
+	SplitMinShare   float64       // Smallest part of the threshold each split invoice is
+
+	RoundUnit      money.Money // Amounts that are multiples of it are round
+	RoundMinSample int         // Expenditures needed before testing round amounts
+	RoundMaxShare  float64     // Share of round amounts tolerated
+
+	SpikeWindow     time.Duration // Period compared with those before it
+	SpikeHistory    int           // Earlier periods the average is taken over
+	SpikeMinHistory int           // Earlier periods needed before testing for spikes
+	SpikeFactor     float64       // Multiple of the average that counts as a spike
+
+	VendorMinSample int     // Expenditures with known vendors needed before testing concentration
+	VendorMaxShare  float64 // Share of spending with one vendor tolerated
+}
+
+// DefaultConfig returns sensitivities suited to a small NGO's spending. It
+// sets no split thresholds; callers supply their approval thresholds.
+func DefaultConfig() Config {
+	return Config{
+		BenfordMinSample: 50,
+		BenfordMaxMAD:    0.015, // Nigrini's limit for nonconformity of first digits
+		SplitWindow:      7 * 24 * time.Hour,
+		SplitMinShare:    0.25,
+		RoundUnit:        money.FromMajor(1000),
+		RoundMinSample:   10,
+		RoundMaxShare:    0.5,
+		SpikeWindow:      30 * 24 * time.Hour,
+		SpikeHistory:     6,
+		SpikeMinHistory:  2,
+		SpikeFactor:      3,
+		VendorMinSample:  5,
+		VendorMaxShare:   0.5,
+	}
+}
+
+// Detect runs every detector over an NGO's expenditures and returns the
+// alerts, riskiest first
+func Detect(config Config, expenditures []Expenditure, now time.Time) []Alert {
+	var alerts []Alert
+	alerts = append(alerts, detectBenford(config, expenditures)...)
+	alerts = append(alerts, detectSplitInvoices(config, expenditures)...)
+	alerts = append(alerts, detectRoundNumbers(config, expenditures)...)
+	alerts = append(alerts, detectCategorySpikes(config, expenditures, now)...)
+	alerts = append(alerts, detectVendorConcentration(config, expenditures)...)
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].RiskScore > alerts[j].RiskScore
+	})
+	return alerts
+}
+
+// RiskScore returns the highest risk among alerts, 0 when there are none
+func RiskScore(alerts []Alert) float64 {
+	risk := 0.0
+	for _, alert := range alerts {
+		risk = math.Max(risk, alert.RiskScore)
+	}
+	return risk
+}
+
+func newAlert(kind string, risk float64, description string, transactionIDs []string) Alert {
+	risk = math.Round(math.Min(100, math.Max(0, risk))*10) / 10
+	severity := "low"
+	switch {
+	case risk >= 70:
+		severity = "high"
+	case risk >= 40:
+		severity = "medium"
+	}
+	return Alert{Kind: kind, RiskScore: risk, Severity: severity, Description: description, TransactionIDs: transactionIDs}
+}
+
+// benfordExpected is the share of amounts Benford's law expects to start with
+// each digit from 1 to 9
+var benfordExpected = func() [10]float64 {
+	var expected [10]float64
+	for d := 1; d <= 9; d++ {
+		expected[d] = math.Log10(1 + 1/float64(d))
+	}
+	return expected
+}()
+
+// detectBenford compares the first digits of the amounts with Benford's law
+func detectBenford(config Config, expenditures []Expenditure) []Alert {
+	var counts [10]int
+	sample := 0
+	for _, expenditure := range expenditures {
+		if digit := firstDigit(expenditure.Amount); digit > 0 {
+			counts[digit]++
+			sample++
+		}
+	}
+	if sample == 0 || sample < config.BenfordMinSample {
+		return nil
+	}
+
+	mad := 0.0
+	for d := 1; d <= 9; d++ {
+		mad += math.Abs(float64(counts[d])/float64(sample) - benfordExpected[d])
+	}
+	mad /= 9
+	if mad <= config.BenfordMaxMAD {
+		return nil
+	}
+
+	return []Alert{newAlert(KindBenford, mad/config.BenfordMaxMAD*50,
+		fmt.Sprintf("First digits of %d amounts depart from Benford's law (mean absolute deviation %.4f)", sample, mad), nil)}
+}
+
+// firstDigit returns the leading digit of an amount in rupees, or 0 below ₹1
+func firstDigit(amount money.Money) int {
+	rupees := amount.Abs().Minor() / 100
+	if rupees == 0 {
+		return 0
+	}
+	for rupees >= 10 {
+		rupees /= 10
+	}
+	return int(rupees)
+}
+
+// detectSplitInvoices finds invoices from one vendor, close together, each
+// under an approval threshold but together reaching it
+func detectSplitInvoices(config Config, expenditures []Expenditure) []Alert {
+	thresholds := append([]money.Money(nil), config.SplitThresholds...)
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i].Cmp(thresholds[j]) > 0 })
+
+	byVendor := make(map[string][]Expenditure)
+	for _, expenditure := range expenditures {
+		if expenditure.VendorGSTIN != "" {
+			byVendor[expenditure.VendorGSTIN] = append(byVendor[expenditure.VendorGSTIN], expenditure)
+		}
+	}
+
+	var alerts []Alert
+	for _, vendor := range sortedKeys(byVendor) {
+		invoices := byVendor[vendor]
+		sort.Slice(invoices, func(i, j int) bool { return invoices[i].Timestamp.Before(invoices[j].Timestamp) })
+
+		used := make(map[string]bool)
+		for _, threshold := range thresholds {
+			floor, err := threshold.MulRatio(int64(config.SplitMinShare*10000), 10000, money.RoundHalfUp)
+			if err != nil {
+				continue
+			}
+			for i := 0; i < len(invoices); i++ {
+				var group []Expenditure
+				total := money.Zero()
+				largest := money.Zero()
+				for j := i; j < len(invoices) && invoices[j].Timestamp.Sub(invoices[i].Timestamp) <= config.SplitWindow; j++ {
+					invoice := invoices[j]
+					if used[invoice.TransactionID] || invoice.Amount.Cmp(threshold) >= 0 || invoice.Amount.Cmp(floor) < 0 {
+						continue
+					}
+					group = append(group, invoice)
+					total = total.Add(invoice.Amount)
+					largest = money.Max(largest, invoice.Amount)
+				}
+				if len(group) < 2 || total.Cmp(threshold) < 0 {
+					continue
+				}
+
+				ids := make([]string, 0, len(group))
+				for _, invoice := range group {
+					ids = append(ids, invoice.TransactionID)
+					used[invoice.TransactionID] = true
+				}
+				alerts = append(alerts, newAlert(KindSplitInvoice, 60+40*largest.Ratio(threshold),
+					fmt.Sprintf("%d invoices from vendor %s within %s total ₹%s, each under the ₹%s approval threshold",
+						len(group), vendor, formatWindow(config.SplitWindow), total, threshold), ids))
+			}
+		}
+	}
+	return alerts
+}
+
+// detectRoundNumbers flags an unusually large share of round amounts
+func detectRoundNumbers(config Config, expenditures []Expenditure) []Alert {
+	if len(expenditures) == 0 || len(expenditures) < config.RoundMinSample || !config.RoundUnit.IsPositive() {
+		return nil
+	}
+
+	var ids []string
+	for _, expenditure := range expenditures {
+		if expenditure.Amount.Minor()%config.RoundUnit.Minor() == 0 {
+			ids = append(ids, expenditure.TransactionID)
+		}
+	}
+	share := float64(len(ids)) / float64(len(expenditures))
+	if share <= config.RoundMaxShare {
+		return nil
+	}
+
+	return []Alert{newAlert(KindRoundNumbers, share*100,
+		fmt.Sprintf("%d of %d amounts are exact multiples of ₹%s", len(ids), len(expenditures), config.RoundUnit), ids)}
+}
+
+// detectCategorySpikes compares each category's spending in the latest
+// period with its average over the periods before
+func detectCategorySpikes(config Config, expenditures []Expenditure, now time.Time) []Alert {
+	if len(expenditures) == 0 || config.SpikeWindow <= 0 {
+		return nil
+	}
+
+	earliest := now
+	for _, expenditure := range expenditures {
+		if expenditure.Timestamp.Before(earliest) {
+			earliest = expenditure.Timestamp
+		}
+	}
+	windowStart := now.Add(-config.SpikeWindow)
+	periods := int(windowStart.Sub(earliest) / config.SpikeWindow)
+	if periods > config.SpikeHistory {
+		periods = config.SpikeHistory
+	}
+	if periods < config.SpikeMinHistory || periods < 1 {
+		return nil
+	}
+	historyStart := windowStart.Add(-time.Duration(periods) * config.SpikeWindow)
+
+	current := make(map[string]money.Money)
+	history := make(map[string]money.Money)
+	currentIDs := make(map[string][]string)
+	for _, expenditure := range expenditures {
+		category := strings.ToLower(strings.TrimSpace(expenditure.Category))
+		switch {
+		case !expenditure.Timestamp.Before(windowStart) && !expenditure.Timestamp.After(now):
+			current[category] = sumOrZero(current, category).Add(expenditure.Amount)
+			currentIDs[category] = append(currentIDs[category], expenditure.TransactionID)
+		case !expenditure.Timestamp.Before(historyStart) && expenditure.Timestamp.Before(windowStart):
+			history[category] = sumOrZero(history, category).Add(expenditure.Amount)
+		}
+	}
+
+	var alerts []Alert
+	for _, category := range sortedKeys(current) {
+		spent := current[category]
+		average, err := sumOrZero(history, category).Div(int64(periods), money.RoundHalfUp)
+		if err != nil {
+			continue
+		}
+		if average.IsZero() {
+			alerts = append(alerts, newAlert(KindCategorySpike, 50,
+				fmt.Sprintf("₹%s spent on %s in the last %s, with nothing spent in the %d periods before", spent, category, formatWindow(config.SpikeWindow), periods),
+				currentIDs[category]))
+			continue
+		}
+		ratio := spent.Ratio(average)
+		if ratio < config.SpikeFactor {
+			continue
+		}
+		alerts = append(alerts, newAlert(KindCategorySpike, 40+10*ratio,
+			fmt.Sprintf("₹%s spent on %s in the last %s, %.1f times the average of ₹%s over the %d periods before",
+				spent, category, formatWindow(config.SpikeWindow), ratio, average, periods),
+			currentIDs[category]))
+	}
+	return alerts
+}
+
+// detectVendorConcentration flags a vendor receiving most of the spending
+// with known vendors
+func detectVendorConcentration(config Config, expenditures []Expenditure) []Alert {
+	total := money.Zero()
+	spent := make(map[string]money.Money)
+	ids := make(map[string][]string)
+	sample := 0
+	for _, expenditure := range expenditures {
+		if expenditure.VendorGSTIN == "" {
+			continue
+		}
+		sample++
+		total = total.Add(expenditure.Amount)
+		spent[expenditure.VendorGSTIN] = sumOrZero(spent, expenditure.VendorGSTIN).Add(expenditure.Amount)
+		ids[expenditure.VendorGSTIN] = append(ids[expenditure.VendorGSTIN], expenditure.TransactionID)
+	}
+	if sample == 0 || sample < config.VendorMinSample || !total.IsPositive() {
+		return nil
+	}
+
+	var alerts []Alert
+	for _, vendor := range sortedKeys(spent) {
+		share := spent[vendor].Ratio(total)
+		if share <= config.VendorMaxShare {
+			continue
+		}
+		alerts = append(alerts, newAlert(KindVendorConcentration, share*100,
+			fmt.Sprintf("Vendor %s received %.0f%% of ₹%s paid to known vendors", vendor, share*100, total), ids[vendor]))
+	}
+	return alerts
+}
+
+func sumOrZero(sums map[string]money.Money, key string) money.Money {
+	if sum, exists := sums[key]; exists {
+		return sum
+	}
+	return money.Zero()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatWindow(window time.Duration) string {
+	days := int(window.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
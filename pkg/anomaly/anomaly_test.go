@@ -0,0 +1,159 @@
+package anomaly
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+var now = time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+func expenditure(id string, rupees float64, category, vendor string, age time.Duration) Expenditure {
+	return Expenditure{TransactionID: id, Amount: money.FromMajor(rupees), Category: category, VendorGSTIN: vendor, Timestamp: now.Add(-age)}
+}
+
+func alertsOfKind(alerts []Alert, kind string) []Alert {
+	var found []Alert
+	for _, alert := range alerts {
+		if alert.Kind == kind {
+			found = append(found, alert)
+		}
+	}
+	return found
+}
+
+func TestDetectFindsNothingInOrdinarySpending(t *testing.T) {
+	config := DefaultConfig()
+	config.SplitThresholds = []money.Money{money.FromMajor(500000)}
+
+	expenditures := []Expenditure{
+		expenditure("EXP1", 1240.50, "education", "V1", 2*time.Hour),
+		expenditure("EXP2", 385, "health", "V2", 24*time.Hour),
+		expenditure("EXP3", 2710, "education", "V3", 48*time.Hour),
+	}
+	if alerts := Detect(config, expenditures, now); len(alerts) != 0 {
+		t.Errorf("Expected no alerts, got %+v", alerts)
+	}
+	if risk := RiskScore(nil); risk != 0 {
+		t.Errorf("Expected no risk without alerts, got %v", risk)
+	}
+}
+
+func TestDetectBenford(t *testing.T) {
+	config := DefaultConfig()
+
+	var expenditures []Expenditure
+	for i := 0; i < 60; i++ {
+		expenditures = append(expenditures, expenditure(fmt.Sprintf("EXP%d", i), 9000+float64(i)*13.7, "education", "", 0))
+	}
+	alerts := alertsOfKind(Detect(config, expenditures, now), KindBenford)
+	if len(alerts) != 1 || alerts[0].Severity != "high" || len(alerts[0].TransactionIDs) != 0 {
+		t.Fatalf("Expected amounts all starting with 9 to break Benford's law, got %+v", alerts)
+	}
+	if !alerts[0].Involves("EXP0") {
+		t.Errorf("Expected a pattern across all expenditures to involve each one")
+	}
+
+	if alerts := alertsOfKind(Detect(config, expenditures[:49], now), KindBenford); len(alerts) != 0 {
+		t.Errorf("Expected no Benford test below the minimum sample, got %+v", alerts)
+	}
+}
+
+func TestDetectSplitInvoices(t *testing.T) {
+	config := DefaultConfig()
+	config.SplitThresholds = []money.Money{money.FromMajor(500000)}
+
+	expenditures := []Expenditure{
+		expenditure("EXP1", 300000, "infrastructure", "V1", 72*time.Hour),
+		expenditure("EXP2", 290000, "infrastructure", "V1", 24*time.Hour),
+		expenditure("EXP3", 300000, "infrastructure", "V2", 24*time.Hour),
+		expenditure("EXP4", 310000, "infrastructure", "V3", 40*24*time.Hour),
+		expenditure("EXP5", 300000, "infrastructure", "V3", 20*24*time.Hour),
+	}
+	alerts := alertsOfKind(Detect(config, expenditures, now), KindSplitInvoice)
+	if len(alerts) != 1 {
+		t.Fatalf("Expected one split invoice alert, got %+v", alerts)
+	}
+	if !alerts[0].Involves("EXP1") || !alerts[0].Involves("EXP2") || alerts[0].Involves("EXP3") {
+		t.Errorf("Expected the alert to name the split invoices, got %v", alerts[0].TransactionIDs)
+	}
+	if alerts[0].Severity != "high" {
+		t.Errorf("Expected invoices just under the threshold to be high risk, got %+v", alerts[0])
+	}
+
+	config.SplitThresholds = nil
+	if alerts := alertsOfKind(Detect(config, expenditures, now), KindSplitInvoice); len(alerts) != 0 {
+		t.Errorf("Expected no split invoice alerts without thresholds, got %+v", alerts)
+	}
+}
+
+func TestDetectRoundNumbers(t *testing.T) {
+	config := DefaultConfig()
+
+	var expenditures []Expenditure
+	for i := 0; i < 8; i++ {
+		expenditures = append(expenditures, expenditure(fmt.Sprintf("ROUND%d", i), float64(i+1)*5000, "education", "", 0))
+	}
+	expenditures = append(expenditures, expenditure("ODD1", 1234.56, "education", "", 0), expenditure("ODD2", 987, "education", "", 0))
+
+	alerts := alertsOfKind(Detect(config, expenditures, now), KindRoundNumbers)
+	if len(alerts) != 1 || len(alerts[0].TransactionIDs) != 8 || alerts[0].RiskScore != 80 || alerts[0].Involves("ODD1") {
+		t.Errorf("Expected 8 of 10 round amounts to be flagged, got %+v", alerts)
+	}
+}
+
+func TestDetectCategorySpikes(t *testing.T) {
+	config := DefaultConfig()
+	day := 24 * time.Hour
+
+	expenditures := []Expenditure{
+		expenditure("OLD", 100, "health", "", 95*day),
+		expenditure("HIST1", 1000, "education", "", 70*day),
+		expenditure("HIST2", 1000, "education", "", 40*day),
+		expenditure("NOW1", 5000, "education", "", day),
+		expenditure("NOW2", 200, "events", "", day),
+	}
+	alerts := alertsOfKind(Detect(config, expenditures, now), KindCategorySpike)
+	if len(alerts) != 2 {
+		t.Fatalf("Expected spikes in education and events, got %+v", alerts)
+	}
+	if alerts[0].TransactionIDs[0] != "NOW1" || alerts[0].RiskScore != 90 {
+		t.Errorf("Expected five times the average to be flagged first, got %+v", alerts[0])
+	}
+	if alerts[1].TransactionIDs[0] != "NOW2" || alerts[1].Severity != "medium" {
+		t.Errorf("Expected spending in a new category to be flagged, got %+v", alerts[1])
+	}
+
+	expenditures[3].Amount = money.FromMajor(1500)
+	expenditures = expenditures[:4]
+	if alerts := alertsOfKind(Detect(config, expenditures, now), KindCategorySpike); len(alerts) != 0 {
+		t.Errorf("Expected ordinary spending not to be flagged, got %+v", alerts)
+	}
+
+	if alerts := alertsOfKind(Detect(config, expenditures[2:], now), KindCategorySpike); len(alerts) != 0 {
+		t.Errorf("Expected no spike test without enough history, got %+v", alerts)
+	}
+}
+
+func TestDetectVendorConcentration(t *testing.T) {
+	config := DefaultConfig()
+
+	expenditures := []Expenditure{
+		expenditure("EXP1", 4000, "education", "V1", 0),
+		expenditure("EXP2", 3000, "education", "V1", 0),
+		expenditure("EXP3", 2000, "education", "V1", 0),
+		expenditure("EXP4", 600, "education", "V2", 0),
+		expenditure("EXP5", 400, "education", "V3", 0),
+		expenditure("EXP6", 50000, "education", "", 0),
+	}
+	alerts := alertsOfKind(Detect(config, expenditures, now), KindVendorConcentration)
+	if len(alerts) != 1 || alerts[0].RiskScore != 90 || len(alerts[0].TransactionIDs) != 3 || alerts[0].Involves("EXP6") {
+		t.Errorf("Expected 90%% of known vendor spending with V1 to be flagged, got %+v", alerts)
+	}
+
+	if alerts := alertsOfKind(Detect(config, expenditures[:4], now), KindVendorConcentration); len(alerts) != 0 {
+		t.Errorf("Expected no concentration test below the minimum sample, got %+v", alerts)
+	}
+}
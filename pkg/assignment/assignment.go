@@ -0,0 +1,107 @@
+// Package assignment chooses which auditors review an expenditure. Auditors
+// who declared a conflict of interest with the NGO, or who have reached the
+// rotation limit of reviews of its expenditures in the past year, are never
+// chosen. Among the rest, specialists in the expenditure's category come
+// before generalists, and then auditors with the fewest open reviews.
+package assignment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Policy holds the rules auditors are chosen by
+type Policy struct {
+	// MaxReviewsPerNGO is how many expenditures of one NGO an auditor may be
+	// assigned in a year before rotating off it
+	MaxReviewsPerNGO int
+	// Generalist is the specialization qualifying an auditor for any category
+	Generalist string
+}
+
+// DefaultPolicy rotates auditors off an NGO after 12 reviews a year and lets
+// financial auditors review any category
+func DefaultPolicy() Policy {
+	return Policy{MaxReviewsPerNGO: 12, Generalist: "financial"}
+}
+
+// Candidate is an auditor who might review an expenditure
+type Candidate struct {
+	AuditorID       string
+	Specializations []string
+	Conflicted      bool // Declared a conflict of interest with the NGO
+	OpenReviews     int  // Expenditures assigned and not yet decided
+	ReviewsOfNGO    int  // Expenditures of the NGO assigned in the past year
+}
+
+// Choice is a candidate selected to review an expenditure and why
+type Choice struct {
+	AuditorID    string `json:"auditor_id"`
+	Specialist   bool   `json:"specialist"` // Specializes in the expenditure's category
+	OpenReviews  int    `json:"open_reviews"`
+	ReviewsOfNGO int    `json:"reviews_of_ngo"`
+}
+
+// Reason explains the choice for the assignment record
+func (c Choice) Reason() string {
+	qualification := "generalist"
+	if c.Specialist {
+		qualification = "category specialist"
+	}
+	return fmt.Sprintf("%s with %d open reviews and %d reviews of the NGO in the past year",
+		qualification, c.OpenReviews, c.ReviewsOfNGO)
+}
+
+// Rank returns the candidates qualified to review an expenditure in
+// category, best first. Ties are broken by auditor ID so assignments are
+// reproducible.
+func Rank(policy Policy, category string, candidates []Candidate) []Choice {
+	choices := make([]Choice, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Conflicted {
+			continue
+		}
+		if policy.MaxReviewsPerNGO > 0 && candidate.ReviewsOfNGO >= policy.MaxReviewsPerNGO {
+			continue
+		}
+
+		specialist := hasSpecialization(candidate.Specializations, category)
+		if !specialist && !hasSpecialization(candidate.Specializations, policy.Generalist) {
+			continue
+		}
+		choices = append(choices, Choice{
+			AuditorID:    candidate.AuditorID,
+			Specialist:   specialist,
+			OpenReviews:  candidate.OpenReviews,
+			ReviewsOfNGO: candidate.ReviewsOfNGO,
+		})
+	}
+
+	sort.Slice(choices, func(i, j int) bool {
+		a, b := choices[i], choices[j]
+		if a.Specialist != b.Specialist {
+			return a.Specialist
+		}
+		if a.OpenReviews != b.OpenReviews {
+			return a.OpenReviews < b.OpenReviews
+		}
+		if a.ReviewsOfNGO != b.ReviewsOfNGO {
+			return a.ReviewsOfNGO < b.ReviewsOfNGO
+		}
+		return a.AuditorID < b.AuditorID
+	})
+	return choices
+}
+
+func hasSpecialization(specializations []string, specialization string) bool {
+	if specialization == "" {
+		return false
+	}
+	for _, s := range specializations {
+		if strings.EqualFold(strings.TrimSpace(s), strings.TrimSpace(specialization)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package assignment
+
+import "testing"
+
+func TestRankPrefersSpecialistsThenLightestWorkload(t *testing.T) {
+	candidates := []Candidate{
+		{AuditorID: "AUD1", Specializations: []string{"financial"}, OpenReviews: 0},
+		{AuditorID: "AUD2", Specializations: []string{"Education"}, OpenReviews: 3},
+		{AuditorID: "AUD3", Specializations: []string{"education", "financial"}, OpenReviews: 1},
+		{AuditorID: "AUD4", Specializations: []string{"technical"}},
+	}
+
+	choices := Rank(DefaultPolicy(), "education", candidates)
+	if len(choices) != 3 {
+		t.Fatalf("Expected the technical auditor to be unqualified, got %+v", choices)
+	}
+	if choices[0].AuditorID != "AUD3" || choices[1].AuditorID != "AUD2" || choices[2].AuditorID != "AUD1" {
+		t.Errorf("Expected specialists by workload, then the generalist, got %+v", choices)
+	}
+	if !choices[0].Specialist || choices[2].Specialist {
+		t.Errorf("Unexpected specialist flags: %+v", choices)
+	}
+}
+
+func TestRankExcludesConflictsAndRotatedAuditors(t *testing.T) {
+	policy := Policy{MaxReviewsPerNGO: 2, Generalist: "financial"}
+	candidates := []Candidate{
+		{AuditorID: "AUD1", Specializations: []string{"financial"}, Conflicted: true},
+		{AuditorID: "AUD2", Specializations: []string{"financial"}, ReviewsOfNGO: 2},
+		{AuditorID: "AUD3", Specializations: []string{"financial"}, ReviewsOfNGO: 1},
+	}
+
+	choices := Rank(policy, "health", candidates)
+	if len(choices) != 1 || choices[0].AuditorID != "AUD3" {
+		t.Errorf("Expected only AUD3 to remain, got %+v", choices)
+	}
+
+	policy.MaxReviewsPerNGO = 0
+	if choices := Rank(policy, "health", candidates); len(choices) != 2 {
+		t.Errorf("Expected no rotation limit when it is zero, got %+v", choices)
+	}
+}
@@ -44,7 +44,7 @@ func NewBlock(index int, timestamp time.Time, data interface{}, previousHash, bl
 	block := &Block{
 		Index:        index,
 		Timestamp:    timestamp,
-		Data:         data,
+		Data:         normalizeData(data),
 		PreviousHash: previousHash,
 		BlockType:    blockType,
 		Nonce:        0,
@@ -58,6 +58,42 @@ func NewBlock(index int, timestamp time.Time, data interface{}, previousHash, bl
 	return block
 }
 
+// RestoreBlock rebuilds a previously persisted block without recomputing its hash
+func RestoreBlock(index int, timestamp time.Time, data interface{}, previousHash, blockType, hash, merkleRoot string, nonce int, validated bool, validators []Validator) *Block {
+	if validators == nil {
+		validators = make([]Validator, 0)
+	}
+
+	return &Block{
+		Index:        index,
+		Timestamp:    timestamp,
+		Data:         normalizeData(data),
+		PreviousHash: previousHash,
+		BlockType:    blockType,
+		Hash:         hash,
+		Nonce:        nonce,
+		Validated:    validated,
+		Validators:   validators,
+		MerkleRoot:   merkleRoot,
+	}
+}
+
+// normalizeData converts block data to its canonical JSON form (maps, slices,
+// float64, string, bool) so a block hashes identically after being persisted
+// and reloaded
+func normalizeData(data interface{}) interface{} {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(dataBytes, &normalized); err != nil {
+		return data
+	}
+	return normalized
+}
+
 // calculateHash computes the hash of the block
 func (b *Block) calculateHash() string {
 	dataBytes, err := json.Marshal(b.Data)
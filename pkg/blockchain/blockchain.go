@@ -54,6 +54,37 @@ func NewBlockchain(ngoID, chainType string, difficulty int) *Blockchain {
 	return blockchain
 }
 
+// RestoreBlockchain rebuilds a blockchain from previously persisted blocks
+func RestoreBlockchain(ngoID, chainType string, difficulty int, blocks []*Block) (*Blockchain, error) {
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("cannot restore %s chain of NGO %s: no blocks", chainType, ngoID)
+	}
+	if difficulty < 1 {
+		difficulty = 2
+	}
+
+	for i, block := range blocks {
+		if block.Index != i {
+			return nil, fmt.Errorf("cannot restore %s chain of NGO %s: expected block %d, got %d", chainType, ngoID, i, block.Index)
+		}
+		if i > 0 && block.PreviousHash != blocks[i-1].Hash {
+			return nil, fmt.Errorf("cannot restore %s chain of NGO %s: block %d is not linked to block %d", chainType, ngoID, i, i-1)
+		}
+	}
+
+	chain := make([]*Block, len(blocks))
+	copy(chain, blocks)
+
+	return &Blockchain{
+		NGOID:         ngoID,
+		ChainType:     chainType,
+		Chain:         chain,
+		Difficulty:    difficulty,
+		PendingBlocks: make([]*Block, 0),
+		NetworkNodes:  make([]string, 0),
+	}, nil
+}
+
 // createGenesisBlock creates the first block in the chain
 func (bc *Blockchain) createGenesisBlock() *Block {
 	genesisData := map[string]interface{}{
@@ -201,6 +232,33 @@ func (bc *Blockchain) GetBlockByHash(hash string) *Block {
 	return nil
 }
 
+// FindBlock returns the first block, oldest first, that matches
+func (bc *Blockchain) FindBlock(match func(block *Block) bool) *Block {
+	bc.mutex.RLock()
+	defer bc.mutex.RUnlock()
+
+	for _, block := range bc.Chain {
+		if match(block) {
+			return block
+		}
+	}
+	return nil
+}
+
+// FindBlocks returns every block, oldest first, that matches
+func (bc *Blockchain) FindBlocks(match func(block *Block) bool) []*Block {
+	bc.mutex.RLock()
+	defer bc.mutex.RUnlock()
+
+	var blocks []*Block
+	for _, block := range bc.Chain {
+		if match(block) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
 // GetBlocksByDateRange returns blocks within a date range
 func (bc *Blockchain) GetBlocksByDateRange(startDate, endDate time.Time) []*Block {
 	bc.mutex.RLock()
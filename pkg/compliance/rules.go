@@ -0,0 +1,282 @@
+// Package compliance scores how well an expenditure is documented. The
+// checks, their weights, per-category overrides and recommendation bands form
+// a declarative, versioned RuleSet loaded from YAML or JSON, so funders'
+// documentation standards can change without a new build. Every score
+// carries the version of the rules that produced it.
+package compliance
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Checks a rule set may weigh
+const (
+	CheckInvoiceNumber       = "invoice_number"
+	CheckGSTIN               = "gstin_validation"
+	CheckVendorDetails       = "vendor_details"
+	CheckPaymentProof        = "payment_proof"
+	CheckSupportingDocuments = "supporting_documents"
+	CheckAttachments         = "attachments"
+	CheckInvoiceRecency      = "invoice_recency"
+)
+
+var knownChecks = map[string]bool{
+	CheckInvoiceNumber:       true,
+	CheckGSTIN:               true,
+	CheckVendorDetails:       true,
+	CheckPaymentProof:        true,
+	CheckSupportingDocuments: true,
+	CheckAttachments:         true,
+	CheckInvoiceRecency:      true,
+}
+
+//go:embed default_rules.yaml
+var defaultRules []byte
+
+// Check is one weighted documentation requirement
+type Check struct {
+	ID         string  `yaml:"id" json:"id"`
+	Weight     float64 `yaml:"weight" json:"weight"`
+	MaxAgeDays int     `yaml:"max_age_days,omitempty" json:"max_age_days,omitempty"` // invoice_recency only
+}
+
+// CategoryOverride adjusts the checks for expenditures of one category
+type CategoryOverride struct {
+	Weights           map[string]float64 `yaml:"weights,omitempty" json:"weights,omitempty"` // Replaces the weight of the listed checks, 0 drops a check
+	MaxInvoiceAgeDays int                `yaml:"max_invoice_age_days,omitempty" json:"max_invoice_age_days,omitempty"`
+}
+
+// Band recommends a course of action for scores of at least MinScore
+type Band struct {
+	MinScore       float64 `yaml:"min_score" json:"min_score"`
+	Recommendation string  `yaml:"recommendation" json:"recommendation"`
+}
+
+// RuleSet is a versioned set of compliance scoring rules
+type RuleSet struct {
+	Version              string                      `yaml:"version" json:"version"`
+	Checks               []Check                     `yaml:"checks" json:"checks"`
+	Categories           map[string]CategoryOverride `yaml:"categories,omitempty" json:"categories,omitempty"`   // Keyed by lower-case category
+	Recommendations      []Band                      `yaml:"recommendations" json:"recommendations"`             // Suggested to reviewers
+	AuditRecommendations []Band                      `yaml:"audit_recommendations" json:"audit_recommendations"` // Recorded with each audit
+}
+
+// Default returns the built-in rule set
+func Default() *RuleSet {
+	rules, err := Parse(defaultRules, "yaml")
+	if err != nil {
+		panic(fmt.Sprintf("invalid default compliance rules: %v", err))
+	}
+	return rules
+}
+
+// Load reads a rule set from a .yaml, .yml or .json file
+func Load(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compliance rules: %w", err)
+	}
+
+	format := "yaml"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "json"
+	}
+	return Parse(data, format)
+}
+
+// Parse decodes and validates a rule set in the given format, yaml or json
+func Parse(data []byte, format string) (*RuleSet, error) {
+	var rules RuleSet
+	var err error
+	switch format {
+	case "yaml":
+		err = yaml.Unmarshal(data, &rules)
+	case "json":
+		err = json.Unmarshal(data, &rules)
+	default:
+		return nil, fmt.Errorf("unsupported compliance rules format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid compliance rules: %w", err)
+	}
+
+	if err := rules.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid compliance rules: %w", err)
+	}
+	return &rules, nil
+}
+
+// normalize validates the rule set, lower-cases category keys and orders
+// recommendation bands from the highest score down
+func (r *RuleSet) normalize() error {
+	if strings.TrimSpace(r.Version) == "" {
+		return fmt.Errorf("version is required")
+	}
+	if len(r.Checks) == 0 {
+		return fmt.Errorf("at least one check is required")
+	}
+
+	seen := make(map[string]bool)
+	total := 0.0
+	for _, check := range r.Checks {
+		if !knownChecks[check.ID] {
+			return fmt.Errorf("unknown check %q", check.ID)
+		}
+		if seen[check.ID] {
+			return fmt.Errorf("check %q is listed twice", check.ID)
+		}
+		if check.Weight < 0 {
+			return fmt.Errorf("check %q has a negative weight", check.ID)
+		}
+		if check.ID == CheckInvoiceRecency && check.MaxAgeDays <= 0 {
+			return fmt.Errorf("check %q needs max_age_days", check.ID)
+		}
+		seen[check.ID] = true
+		total += check.Weight
+	}
+	if total <= 0 {
+		return fmt.Errorf("checks must have a positive total weight")
+	}
+
+	categories := make(map[string]CategoryOverride, len(r.Categories))
+	for category, override := range r.Categories {
+		for id, weight := range override.Weights {
+			if !seen[id] {
+				return fmt.Errorf("category %q overrides check %q, which is not in the rule set", category, id)
+			}
+			if weight < 0 {
+				return fmt.Errorf("category %q gives check %q a negative weight", category, id)
+			}
+		}
+		if override.MaxInvoiceAgeDays < 0 {
+			return fmt.Errorf("category %q has a negative max_invoice_age_days", category)
+		}
+		categories[strings.ToLower(strings.TrimSpace(category))] = override
+	}
+	r.Categories = categories
+
+	for name, bands := range map[string][]Band{"recommendations": r.Recommendations, "audit_recommendations": r.AuditRecommendations} {
+		if len(bands) == 0 {
+			return fmt.Errorf("%s are required", name)
+		}
+		sort.SliceStable(bands, func(i, j int) bool { return bands[i].MinScore > bands[j].MinScore })
+	}
+	return nil
+}
+
+// Evidence is what an expenditure offers for each check
+type Evidence struct {
+	Category       string
+	InvoiceNumber  bool
+	GSTINValid     bool
+	VendorDetails  bool // Vendor named with a valid GSTIN
+	PaymentProof   bool
+	Documents      int
+	Attachments    int
+	InvoiceAgeDays float64
+}
+
+// Component is the outcome of one check
+type Component struct {
+	Check    string  `json:"check"`
+	Score    float64 `json:"score"`
+	MaxScore float64 `json:"max_score"`
+	Passed   bool    `json:"passed"`
+}
+
+// Result is an expenditure's score under a rule set
+type Result struct {
+	Version    string      `json:"rules_version"`
+	Score      float64     `json:"score"` // Out of 100
+	Components []Component `json:"components"`
+}
+
+// Evaluate scores evidence under the rule set. Weights are scaled so a
+// submission passing every check scores 100.
+func (r *RuleSet) Evaluate(evidence Evidence) Result {
+	override := r.Categories[strings.ToLower(strings.TrimSpace(evidence.Category))]
+
+	result := Result{Version: r.Version, Components: make([]Component, 0, len(r.Checks))}
+	earned, total := 0.0, 0.0
+	for _, check := range r.Checks {
+		weight := check.Weight
+		if w, exists := override.Weights[check.ID]; exists {
+			weight = w
+		}
+		if weight == 0 {
+			continue
+		}
+
+		var passed bool
+		switch check.ID {
+		case CheckInvoiceNumber:
+			passed = evidence.InvoiceNumber
+		case CheckGSTIN:
+			passed = evidence.GSTINValid
+		case CheckVendorDetails:
+			passed = evidence.VendorDetails
+		case CheckPaymentProof:
+			passed = evidence.PaymentProof
+		case CheckSupportingDocuments:
+			passed = evidence.Documents > 0
+		case CheckAttachments:
+			passed = evidence.Attachments > 0
+		case CheckInvoiceRecency:
+			passed = evidence.InvoiceAgeDays <= float64(r.MaxInvoiceAgeDays(evidence.Category))
+		}
+
+		component := Component{Check: check.ID, MaxScore: weight, Passed: passed}
+		if passed {
+			component.Score = weight
+			earned += weight
+		}
+		total += weight
+		result.Components = append(result.Components, component)
+	}
+
+	if total > 0 {
+		result.Score = earned / total * 100
+	}
+	return result
+}
+
+// MaxInvoiceAgeDays returns how old an invoice of category may be before it
+// fails the recency check, or 0 when the rule set does not check recency
+func (r *RuleSet) MaxInvoiceAgeDays(category string) int {
+	if override, exists := r.Categories[strings.ToLower(strings.TrimSpace(category))]; exists && override.MaxInvoiceAgeDays > 0 {
+		return override.MaxInvoiceAgeDays
+	}
+	for _, check := range r.Checks {
+		if check.ID == CheckInvoiceRecency {
+			return check.MaxAgeDays
+		}
+	}
+	return 0
+}
+
+// Recommend returns the reviewer recommendation for a score
+func (r *RuleSet) Recommend(score float64) string {
+	return recommend(r.Recommendations, score)
+}
+
+// AuditRecommendation returns the recommendation recorded with an audit of a score
+func (r *RuleSet) AuditRecommendation(score float64) string {
+	return recommend(r.AuditRecommendations, score)
+}
+
+func recommend(bands []Band, score float64) string {
+	for _, band := range bands {
+		if score >= band.MinScore {
+			return band.Recommendation
+		}
+	}
+	return bands[len(bands)-1].Recommendation
+}
@@ -0,0 +1,84 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultRulesMatchOriginalWeights(t *testing.T) {
+	rules := Default()
+
+	complete := Evidence{InvoiceNumber: true, GSTINValid: true, VendorDetails: true, PaymentProof: true, Documents: 1, Attachments: 1, InvoiceAgeDays: 10}
+	if result := rules.Evaluate(complete); result.Score != 100 || result.Version != "1" || len(result.Components) != 7 {
+		t.Errorf("Expected complete evidence to score 100 under version 1, got %+v", result)
+	}
+
+	// Missing payment proof (15) and a stale invoice (5)
+	partial := complete
+	partial.PaymentProof = false
+	partial.InvoiceAgeDays = 120
+	if result := rules.Evaluate(partial); result.Score != 80 {
+		t.Errorf("Expected 80, got %v", result.Score)
+	}
+
+	if got := rules.Recommend(75); got != "Approve with minor observations" {
+		t.Errorf("Unexpected recommendation %q", got)
+	}
+	if got := rules.AuditRecommendation(55); got != "Review required - Significant compliance gaps" {
+		t.Errorf("Unexpected audit recommendation %q", got)
+	}
+}
+
+func TestLoadJSONRulesWithCategoryOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	data := `{
+		"version": "funder-2025",
+		"checks": [
+			{"id": "invoice_number", "weight": 50},
+			{"id": "payment_proof", "weight": 25},
+			{"id": "invoice_recency", "weight": 25, "max_age_days": 30}
+		],
+		"categories": {"Infrastructure": {"weights": {"invoice_recency": 0}, "max_invoice_age_days": 365}},
+		"recommendations": [{"min_score": 0, "recommendation": "Reject"}, {"min_score": 75, "recommendation": "Approve"}],
+		"audit_recommendations": [{"min_score": 0, "recommendation": "Review"}]
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write rules: %v", err)
+	}
+
+	rules, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load rules: %v", err)
+	}
+
+	evidence := Evidence{Category: "education", InvoiceNumber: true, PaymentProof: true, InvoiceAgeDays: 60}
+	if result := rules.Evaluate(evidence); result.Score != 75 || result.Version != "funder-2025" {
+		t.Errorf("Expected a stale invoice to cost 25 points, got %+v", result)
+	}
+	evidence.Category = "infrastructure"
+	if result := rules.Evaluate(evidence); result.Score != 100 || len(result.Components) != 2 {
+		t.Errorf("Expected the category to drop the recency check, got %+v", result)
+	}
+	if rules.MaxInvoiceAgeDays("INFRASTRUCTURE") != 365 || rules.MaxInvoiceAgeDays("education") != 30 {
+		t.Error("Expected the category's invoice age limit to apply")
+	}
+	if got := rules.Recommend(80); got != "Approve" {
+		t.Errorf("Expected bands to be ordered from the highest score, got %q", got)
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	invalid := map[string]string{
+		"no version":     "checks: [{id: invoice_number, weight: 1}]\nrecommendations: [{min_score: 0, recommendation: x}]\naudit_recommendations: [{min_score: 0, recommendation: x}]",
+		"unknown check":  "version: x\nchecks: [{id: vibes, weight: 1}]\nrecommendations: [{min_score: 0, recommendation: x}]\naudit_recommendations: [{min_score: 0, recommendation: x}]",
+		"no recency age": "version: x\nchecks: [{id: invoice_recency, weight: 1}]\nrecommendations: [{min_score: 0, recommendation: x}]\naudit_recommendations: [{min_score: 0, recommendation: x}]",
+		"no bands":       "version: x\nchecks: [{id: invoice_number, weight: 1}]",
+		"bad override":   "version: x\nchecks: [{id: invoice_number, weight: 1}]\ncategories: {health: {weights: {attachments: 5}}}\nrecommendations: [{min_score: 0, recommendation: x}]\naudit_recommendations: [{min_score: 0, recommendation: x}]",
+	}
+	for name, data := range invalid {
+		if _, err := Parse([]byte(data), "yaml"); err == nil {
+			t.Errorf("Expected rules with %s to be refused", name)
+		}
+	}
+}
@@ -10,11 +10,14 @@ import (
 
 type Config struct {
 	Server struct {
-		Port string
-		Host string
-		Mode string // debug, release, test
+		Port                   string
+		Host                   string
+		Mode                   string // debug, release, test
+		IdempotencyKeyTTLHours int    // How long responses to Idempotency-Key requests are kept for replay
 	}
 	Database struct {
+		Driver   string // postgres, sqlite
+		Path     string // SQLite database file, ":memory:" for an in-memory database
 		Host     string
 		Port     string
 		User     string
@@ -23,19 +26,55 @@ type Config struct {
 		SSLMode  string
 	}
 	JWT struct {
-		Secret     string
+		Secret      string
 		ExpiryHours int
 	}
 	Blockchain struct {
-		PolygonRPC    string
-		PrivateKey    string
-		GasLimit      int64
-		GasPriceGwei  int64
+		PolygonRPC         string
+		PrivateKey         string
+		GasLimit           int64
+		GasPriceGwei       int64
+		MaxFeeGwei         int64   // Hard ceiling on maxFeePerGas, 0 disables
+		MinPriorityFeeGwei int64   // Floor for maxPriorityFeePerGas
+		DailySpendCapMATIC float64 // Maximum anchoring spend per UTC day, 0 disables
+		MaticINRRate       float64 // Conversion rate used for cost reporting
+		AnchorRetryMinutes int     // How often blocks not yet anchored are retried, 0 disables retries
 	}
 	Platform struct {
 		FeePercentage float64
 		Environment   string
 	}
+	Payments struct {
+		Gateway         string // fake in development, or empty to disable payment intents
+		WebhookSecret   string // Shared secret for webhook HMAC signatures, required with a gateway
+		CheckoutBaseURL string // Where the fake gateway sends donors to pay
+	}
+	Recurring struct {
+		SchedulerIntervalMinutes int // How often due recurring donations are charged, 0 disables the scheduler
+		MaxRetries               int // Retries of a failed installment before it is skipped
+		RetryDelayHours          int // Wait before retrying a failed installment
+	}
+	Compliance struct {
+		RulesFile string // YAML or JSON compliance scoring rules, the built-in rules when empty
+	}
+	Rating struct {
+		Model        string  // NGO rating model, baseline by default
+		HalfLifeDays float64 // Age at which activity counts half as much in ratings, 0 weights all activity equally
+		PeerWeight   float64 // Share of the gap between a category's average rating and the platform's closed, 0 disables
+		MinPeers     int     // NGOs a category needs before its ratings are normalized
+	}
+	Consensus struct {
+		Thresholds     string  // Panels for high-value expenditures as amount:k/n pairs, e.g. "500000:2/3,2500000:3/5"
+		MaxScoreSpread float64 // Largest difference between panel scores before escalation, 0 disables
+	}
+	EInvoice struct {
+		IRPPublicKeyFile string // PEM public key or certificate of the Invoice Registration Portal, e-invoice import disabled when empty
+	}
+	Storage struct {
+		Path         string // Directory uploaded documents are stored in
+		MaxUploadMB  int    // Largest document accepted, 0 for the default of 10
+		AllowedTypes string // Comma-separated MIME types accepted, PDF, JPEG and PNG when empty
+	}
 	Logging struct {
 		Level  string
 		Format string // json, text
@@ -56,8 +95,11 @@ func LoadConfig() *Config {
 	config.Server.Port = getEnv("SERVER_PORT", "8080")
 	config.Server.Host = getEnv("SERVER_HOST", "0.0.0.0")
 	config.Server.Mode = getEnv("GIN_MODE", "debug")
+	config.Server.IdempotencyKeyTTLHours = getEnvInt("IDEMPOTENCY_KEY_TTL_HOURS", 24)
 
 	// Database configuration
+	config.Database.Driver = getEnv("DB_DRIVER", "postgres")
+	config.Database.Path = getEnv("DB_PATH", "trusture.db")
 	config.Database.Host = getEnv("DB_HOST", "localhost")
 	config.Database.Port = getEnv("DB_PORT", "5432")
 	config.Database.User = getEnv("DB_USER", "trusture")
@@ -74,11 +116,47 @@ func LoadConfig() *Config {
 	config.Blockchain.PrivateKey = getEnv("POLYGON_PRIVATE_KEY", "1111111111111111111111111111111111111111111111111111111111111111")
 	config.Blockchain.GasLimit = getEnvInt64("POLYGON_GAS_LIMIT", 300000)
 	config.Blockchain.GasPriceGwei = getEnvInt64("POLYGON_GAS_PRICE_GWEI", 30)
+	config.Blockchain.MaxFeeGwei = getEnvInt64("POLYGON_MAX_FEE_GWEI", 0)
+	config.Blockchain.MinPriorityFeeGwei = getEnvInt64("POLYGON_MIN_PRIORITY_FEE_GWEI", 30)
+	config.Blockchain.DailySpendCapMATIC = getEnvFloat("POLYGON_DAILY_SPEND_CAP_MATIC", 0)
+	config.Blockchain.MaticINRRate = getEnvFloat("MATIC_INR_RATE", 45.0)
+	config.Blockchain.AnchorRetryMinutes = getEnvInt("POLYGON_ANCHOR_RETRY_MINUTES", 15)
 
 	// Platform configuration
 	config.Platform.FeePercentage = getEnvFloat("PLATFORM_FEE_PERCENTAGE", 1.0)
 	config.Platform.Environment = getEnv("ENVIRONMENT", "development")
 
+	// Payment gateway configuration
+	config.Payments.Gateway = getEnv("PAYMENT_GATEWAY", "")
+	config.Payments.WebhookSecret = getEnv("PAYMENT_WEBHOOK_SECRET", "")
+	config.Payments.CheckoutBaseURL = getEnv("PAYMENT_CHECKOUT_URL", "http://localhost:8080/api/v1/payments/fake/checkout")
+
+	// Recurring donation configuration
+	config.Recurring.SchedulerIntervalMinutes = getEnvInt("RECURRING_SCHEDULER_INTERVAL_MINUTES", 60)
+	config.Recurring.MaxRetries = getEnvInt("RECURRING_MAX_RETRIES", 3)
+	config.Recurring.RetryDelayHours = getEnvInt("RECURRING_RETRY_DELAY_HOURS", 24)
+
+	// Compliance scoring configuration
+	config.Compliance.RulesFile = getEnv("COMPLIANCE_RULES_FILE", "")
+
+	// NGO rating configuration
+	config.Rating.Model = getEnv("RATING_MODEL", "baseline")
+	config.Rating.HalfLifeDays = getEnvFloat("RATING_HALF_LIFE_DAYS", 15)
+	config.Rating.PeerWeight = getEnvFloat("RATING_PEER_WEIGHT", 0.5)
+	config.Rating.MinPeers = getEnvInt("RATING_MIN_PEERS", 3)
+
+	// Multi-auditor consensus configuration
+	config.Consensus.Thresholds = getEnv("CONSENSUS_THRESHOLDS", "500000:2/3,2500000:3/5")
+	config.Consensus.MaxScoreSpread = getEnvFloat("CONSENSUS_MAX_SCORE_SPREAD", 20)
+
+	// E-invoice configuration
+	config.EInvoice.IRPPublicKeyFile = getEnv("EINVOICE_IRP_PUBLIC_KEY_FILE", "")
+
+	// Document storage configuration
+	config.Storage.Path = getEnv("STORAGE_PATH", "./data/documents")
+	config.Storage.MaxUploadMB = getEnvInt("STORAGE_MAX_UPLOAD_MB", 10)
+	config.Storage.AllowedTypes = getEnv("STORAGE_ALLOWED_TYPES", "application/pdf,image/jpeg,image/png")
+
 	// Logging configuration
 	config.Logging.Level = getEnv("LOG_LEVEL", "info")
 	config.Logging.Format = getEnv("LOG_FORMAT", "json")
@@ -0,0 +1,164 @@
+// Package consensus decides expenditures reviewed by a panel of auditors.
+// Expenditures at or above a threshold amount are assigned to a panel of n
+// auditors and approved once k of them approve; they are rejected once so
+// many have rejected that k approvals can no longer be reached. Split votes
+// and widely differing scores are flagged for escalation.
+package consensus
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Threshold requires Required approvals from a panel of Panel auditors for
+// expenditures of at least MinAmount
+type Threshold struct {
+	MinAmount money.Money `json:"min_amount"`
+	Required  int         `json:"required"` // Approvals needed (k)
+	Panel     int         `json:"panel"`    // Auditors assigned (n)
+}
+
+// Policy holds the thresholds and the tolerance for disagreement
+type Policy struct {
+	Thresholds []Threshold
+	// MaxScoreSpread is the largest difference between the panel's compliance
+	// scores before the panel is considered to disagree, 0 disables the check
+	MaxScoreSpread float64
+}
+
+// DefaultPolicy requires 2 of 3 auditors from ₹5,00,000 and 3 of 5 from
+// ₹25,00,000, and flags scores more than 20 points apart
+func DefaultPolicy() Policy {
+	return Policy{
+		Thresholds: []Threshold{
+			{MinAmount: money.FromMajor(500000), Required: 2, Panel: 3},
+			{MinAmount: money.FromMajor(2500000), Required: 3, Panel: 5},
+		},
+		MaxScoreSpread: 20,
+	}
+}
+
+// ParseThresholds parses thresholds written as "amount:k/n" pairs separated
+// by commas, with amounts in rupees, e.g. "500000:2/3,2500000:3/5"
+func ParseThresholds(s string) ([]Threshold, error) {
+	var thresholds []Threshold
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		amountPart, quorum, found := strings.Cut(part, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid consensus threshold %q: expected amount:k/n", part)
+		}
+		requiredPart, panelPart, found := strings.Cut(quorum, "/")
+		if !found {
+			return nil, fmt.Errorf("invalid consensus threshold %q: expected amount:k/n", part)
+		}
+
+		amount, err := money.Parse(strings.TrimSpace(amountPart))
+		if err != nil || !amount.IsPositive() {
+			return nil, fmt.Errorf("invalid consensus threshold amount %q", amountPart)
+		}
+		required, err := strconv.Atoi(strings.TrimSpace(requiredPart))
+		if err != nil {
+			return nil, fmt.Errorf("invalid consensus threshold %q: %w", part, err)
+		}
+		panel, err := strconv.Atoi(strings.TrimSpace(panelPart))
+		if err != nil {
+			return nil, fmt.Errorf("invalid consensus threshold %q: %w", part, err)
+		}
+		if required < 1 || panel < required {
+			return nil, fmt.Errorf("invalid consensus threshold %q: need 1 <= k <= n", part)
+		}
+
+		thresholds = append(thresholds, Threshold{MinAmount: amount, Required: required, Panel: panel})
+	}
+	return thresholds, nil
+}
+
+// For returns the threshold an expenditure of amount falls under: the one
+// with the highest MinAmount not above it, or a single auditor below them all
+func (p Policy) For(amount money.Money) Threshold {
+	threshold := Threshold{MinAmount: money.Zero(), Required: 1, Panel: 1}
+	for _, t := range p.Thresholds {
+		if amount.Cmp(t.MinAmount) >= 0 && t.MinAmount.Cmp(threshold.MinAmount) >= 0 {
+			threshold = t
+		}
+	}
+	return threshold
+}
+
+// Vote is one panel member's decision and compliance score
+type Vote struct {
+	AuditorID string
+	Approve   bool
+	Score     float64
+}
+
+// Outcome is the state of a panel's review
+type Outcome struct {
+	Required         int      `json:"required"`
+	Panel            int      `json:"panel"`
+	Approvals        int      `json:"approvals"`
+	Rejections       int      `json:"rejections"`
+	Voters           []string `json:"voters"`
+	Score            float64  `json:"score"`        // Mean score of the deciding votes, or of all votes while undecided
+	ScoreSpread      float64  `json:"score_spread"` // Highest minus lowest score
+	Decided          bool     `json:"decided"`
+	Approved         bool     `json:"approved"`
+	Escalated        bool     `json:"escalated"`
+	EscalationReason string   `json:"escalation_reason,omitempty"`
+}
+
+// Tally counts the votes of a panel of panel auditors needing required approvals
+func (p Policy) Tally(required, panel int, votes []Vote) Outcome {
+	outcome := Outcome{Required: required, Panel: panel, Voters: make([]string, 0, len(votes))}
+	if len(votes) == 0 {
+		return outcome
+	}
+
+	var approveTotal, rejectTotal float64
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, vote := range votes {
+		outcome.Voters = append(outcome.Voters, vote.AuditorID)
+		if vote.Approve {
+			outcome.Approvals++
+			approveTotal += vote.Score
+		} else {
+			outcome.Rejections++
+			rejectTotal += vote.Score
+		}
+		low = math.Min(low, vote.Score)
+		high = math.Max(high, vote.Score)
+	}
+	sort.Strings(outcome.Voters)
+	outcome.ScoreSpread = high - low
+
+	switch {
+	case outcome.Approvals >= required:
+		outcome.Decided, outcome.Approved = true, true
+		outcome.Score = approveTotal / float64(outcome.Approvals)
+	case outcome.Rejections > panel-required:
+		outcome.Decided = true
+		outcome.Score = rejectTotal / float64(outcome.Rejections)
+	default:
+		outcome.Score = (approveTotal + rejectTotal) / float64(len(votes))
+	}
+
+	switch {
+	case outcome.Approvals > 0 && outcome.Rejections > 0:
+		outcome.Escalated = true
+		outcome.EscalationReason = fmt.Sprintf("auditors disagree: %d approved and %d rejected", outcome.Approvals, outcome.Rejections)
+	case p.MaxScoreSpread > 0 && outcome.ScoreSpread > p.MaxScoreSpread:
+		outcome.Escalated = true
+		outcome.EscalationReason = fmt.Sprintf("compliance scores differ by %.1f points", outcome.ScoreSpread)
+	}
+	return outcome
+}
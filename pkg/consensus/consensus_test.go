@@ -0,0 +1,71 @@
+package consensus
+
+import (
+	"testing"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+func TestPolicyForPicksHighestThresholdReached(t *testing.T) {
+	policy := DefaultPolicy()
+
+	cases := []struct {
+		amount          money.Money
+		required, panel int
+	}{
+		{money.FromMajor(499999.99), 1, 1},
+		{money.FromMajor(500000), 2, 3},
+		{money.FromMajor(3000000), 3, 5},
+	}
+	for _, c := range cases {
+		threshold := policy.For(c.amount)
+		if threshold.Required != c.required || threshold.Panel != c.panel {
+			t.Errorf("For(%s) = %d of %d, want %d of %d", c.amount, threshold.Required, threshold.Panel, c.required, c.panel)
+		}
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	thresholds, err := ParseThresholds(" 500000:2/3, 2500000.50:3/5 ")
+	if err != nil {
+		t.Fatalf("Failed to parse thresholds: %v", err)
+	}
+	if len(thresholds) != 2 || !thresholds[1].MinAmount.Equal(money.FromMajor(2500000.50)) || thresholds[1].Required != 3 || thresholds[1].Panel != 5 {
+		t.Errorf("Unexpected thresholds: %+v", thresholds)
+	}
+
+	for _, invalid := range []string{"500000", "500000:2", "500000:4/3", "0:1/1", "abc:1/2"} {
+		if _, err := ParseThresholds(invalid); err == nil {
+			t.Errorf("Expected %q to be refused", invalid)
+		}
+	}
+}
+
+func TestTally(t *testing.T) {
+	policy := Policy{MaxScoreSpread: 20}
+
+	outcome := policy.Tally(2, 3, []Vote{{AuditorID: "AUD1", Approve: true, Score: 90}})
+	if outcome.Decided || outcome.Escalated {
+		t.Errorf("Expected one approval of two to leave the panel undecided, got %+v", outcome)
+	}
+
+	outcome = policy.Tally(2, 3, []Vote{
+		{AuditorID: "AUD1", Approve: true, Score: 90},
+		{AuditorID: "AUD2", Approve: false, Score: 40},
+		{AuditorID: "AUD3", Approve: true, Score: 80},
+	})
+	if !outcome.Decided || !outcome.Approved || outcome.Score != 85 || outcome.ScoreSpread != 50 {
+		t.Errorf("Expected approval scored on the approving votes, got %+v", outcome)
+	}
+	if !outcome.Escalated || outcome.EscalationReason == "" {
+		t.Errorf("Expected the split vote to be escalated, got %+v", outcome)
+	}
+
+	outcome = policy.Tally(2, 3, []Vote{
+		{AuditorID: "AUD1", Approve: false, Score: 30},
+		{AuditorID: "AUD2", Approve: false, Score: 60},
+	})
+	if !outcome.Decided || outcome.Approved || !outcome.Escalated {
+		t.Errorf("Expected rejection once approval is out of reach, with the score gap escalated, got %+v", outcome)
+	}
+}
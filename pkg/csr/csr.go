@@ -0,0 +1,213 @@
+// Package csr maps NGO work to the activities companies may count as
+// Corporate Social Responsibility spend under Schedule VII of the Companies
+// Act, 2013, and summarises a company's contributions by financial year.
+package csr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Activity is an item of Schedule VII
+type Activity struct {
+	Item  string `json:"item"` // Roman numeral of the item, e.g. "ii"
+	Title string `json:"title"`
+}
+
+// ScheduleVII lists the CSR activities in the order of the schedule
+var ScheduleVII = []Activity{
+	{"i", "Eradicating hunger, poverty and malnutrition, promoting health care and sanitation, and making available safe drinking water"},
+	{"ii", "Promoting education, including special education and employment enhancing vocation skills, and livelihood enhancement projects"},
+	{"iii", "Promoting gender equality, empowering women, setting up homes for orphans and senior citizens, and reducing inequalities"},
+	{"iv", "Ensuring environmental sustainability, ecological balance, protection of flora and fauna, and animal welfare"},
+	{"v", "Protection of national heritage, art and culture"},
+	{"vi", "Measures for the benefit of armed forces veterans, war widows and their dependents"},
+	{"vii", "Training to promote rural sports, nationally recognised sports, paralympic sports and Olympic sports"},
+	{"viii", "Contribution to the Prime Minister's National Relief Fund or other central government relief funds"},
+	{"ix", "Contributions to incubators and research and development projects in science, technology, engineering and medicine"},
+	{"x", "Rural development projects"},
+	{"xi", "Slum area development"},
+	{"xii", "Disaster management, including relief, rehabilitation and reconstruction activities"},
+}
+
+// categoryKeywords maps words found in NGO categories to Schedule VII items.
+// Keywords match at the start of a word, and earlier entries win, so narrower
+// terms come before broader ones.
+var categoryKeywords = []struct{ keyword, item string }{
+	{"disaster", "xii"},
+	{"slum", "xi"},
+	{"rural development", "x"},
+	{"agri", "x"},
+	{"research", "ix"},
+	{"science", "ix"},
+	{"relief fund", "viii"},
+	{"sport", "vii"},
+	{"veteran", "vi"},
+	{"armed forces", "vi"},
+	{"heritage", "v"},
+	{"culture", "v"},
+	{"art", "v"},
+	{"environment", "iv"},
+	{"animal", "iv"},
+	{"wildlife", "iv"},
+	{"conservation", "iv"},
+	{"climate", "iv"},
+	{"women", "iii"},
+	{"gender", "iii"},
+	{"orphan", "iii"},
+	{"elderly", "iii"},
+	{"senior citizen", "iii"},
+	{"education", "ii"},
+	{"skill", "ii"},
+	{"livelihood", "ii"},
+	{"health", "i"},
+	{"hunger", "i"},
+	{"poverty", "i"},
+	{"nutrition", "i"},
+	{"sanitation", "i"},
+	{"water", "i"},
+	{"rural", "x"},
+}
+
+// Classify returns the Schedule VII activity an NGO category falls under.
+// It reports false for categories that match no activity.
+func Classify(category string) (Activity, bool) {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(category), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}), " ")
+	for _, k := range categoryKeywords {
+		if strings.Contains(words, " "+k.keyword) {
+			return activity(k.item), true
+		}
+	}
+	return Activity{}, false
+}
+
+func activity(item string) Activity {
+	for _, a := range ScheduleVII {
+		if a.Item == item {
+			return a
+		}
+	}
+	return Activity{}
+}
+
+// FinancialYear returns the year in which the Indian financial year
+// containing t starts; it runs from 1 April to 31 March
+func FinancialYear(t time.Time) int {
+	if t.Month() < time.April {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// FinancialYearLabel formats the financial year starting in startYear, e.g. "2025-26"
+func FinancialYearLabel(startYear int) string {
+	return fmt.Sprintf("%d-%02d", startYear, (startYear+1)%100)
+}
+
+// Contribution is a donation a company made to an NGO
+type Contribution struct {
+	TransactionID string      `json:"transaction_id"`
+	NGOID         string      `json:"ngo_id"`
+	NGOName       string      `json:"ngo_name"`
+	NGOCategory   string      `json:"ngo_category"`
+	Amount        money.Money `json:"amount"` // Gross amount paid, less reversals
+	Timestamp     time.Time   `json:"timestamp"`
+	MatchFor      string      `json:"match_for,omitempty"` // Employee donation the contribution matched
+	Activity      *Activity   `json:"activity,omitempty"`  // Nil when the NGO's category is not mapped
+}
+
+// ActivitySpend is a company's spend on one Schedule VII activity
+type ActivitySpend struct {
+	Activity
+	Amount        money.Money `json:"amount"`
+	Contributions int         `json:"contributions"`
+	NGOs          int         `json:"ngos"`
+}
+
+// Report summarises a company's CSR spend for a financial year
+type Report struct {
+	CorporateID   string          `json:"corporate_id"`
+	CompanyName   string          `json:"company_name"`
+	CIN           string          `json:"cin,omitempty"`
+	FinancialYear string          `json:"financial_year"`
+	TotalSpend    money.Money     `json:"total_spend"`
+	DirectSpend   money.Money     `json:"direct_spend"`
+	MatchedSpend  money.Money     `json:"matched_spend"`  // Spent matching employee donations
+	UnmappedSpend money.Money     `json:"unmapped_spend"` // Given to NGOs whose category maps to no activity
+	Activities    []ActivitySpend `json:"activities"`     // In Schedule VII order
+	Contributions []Contribution  `json:"contributions"`  // Oldest first
+}
+
+// NewReport summarises the contributions made in the financial year starting
+// in startYear, classifying each by its NGO's category
+func NewReport(corporateID, companyName, cin string, startYear int, contributions []Contribution) *Report {
+	start := time.Date(startYear, time.April, 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(1, 0, 0)
+
+	report := &Report{
+		CorporateID:   corporateID,
+		CompanyName:   companyName,
+		CIN:           cin,
+		FinancialYear: FinancialYearLabel(startYear),
+		TotalSpend:    money.Zero(),
+		DirectSpend:   money.Zero(),
+		MatchedSpend:  money.Zero(),
+		UnmappedSpend: money.Zero(),
+		Activities:    make([]ActivitySpend, 0),
+		Contributions: make([]Contribution, 0),
+	}
+
+	spend := make(map[string]*ActivitySpend)
+	ngos := make(map[string]map[string]bool)
+	for _, c := range contributions {
+		if c.Timestamp.Before(start) || !c.Timestamp.Before(end) || !c.Amount.IsPositive() {
+			continue
+		}
+
+		report.TotalSpend = report.TotalSpend.Add(c.Amount)
+		if c.MatchFor != "" {
+			report.MatchedSpend = report.MatchedSpend.Add(c.Amount)
+		} else {
+			report.DirectSpend = report.DirectSpend.Add(c.Amount)
+		}
+
+		a, ok := Classify(c.NGOCategory)
+		if !ok {
+			report.UnmappedSpend = report.UnmappedSpend.Add(c.Amount)
+			report.Contributions = append(report.Contributions, c)
+			continue
+		}
+		c.Activity = &a
+
+		s, exists := spend[a.Item]
+		if !exists {
+			s = &ActivitySpend{Activity: a, Amount: money.Zero()}
+			spend[a.Item] = s
+			ngos[a.Item] = make(map[string]bool)
+		}
+		s.Amount = s.Amount.Add(c.Amount)
+		s.Contributions++
+		ngos[a.Item][c.NGOID] = true
+		s.NGOs = len(ngos[a.Item])
+
+		report.Contributions = append(report.Contributions, c)
+	}
+
+	for _, a := range ScheduleVII {
+		if s, exists := spend[a.Item]; exists {
+			report.Activities = append(report.Activities, *s)
+		}
+	}
+	sort.SliceStable(report.Contributions, func(i, j int) bool {
+		return report.Contributions[i].Timestamp.Before(report.Contributions[j].Timestamp)
+	})
+
+	return report
+}
@@ -0,0 +1,68 @@
+package csr
+
+import (
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+func TestClassify(t *testing.T) {
+	cases := map[string]string{
+		"education":         "ii",
+		"Healthcare":        "i",
+		"Environment":       "iv",
+		"Women Empowerment": "iii",
+		"Disaster Relief":   "xii",
+		"Rural Development": "x",
+		"Arts and Culture":  "v",
+		"agriculture":       "x",
+		"heart-health":      "i",
+	}
+	for category, item := range cases {
+		activity, ok := Classify(category)
+		if !ok || activity.Item != item {
+			t.Errorf("Classify(%q) = %q, %v; want item %q", category, activity.Item, ok, item)
+		}
+	}
+
+	if _, ok := Classify("miscellaneous"); ok {
+		t.Error("Expected unknown category to be unmapped")
+	}
+}
+
+func TestFinancialYear(t *testing.T) {
+	if fy := FinancialYear(time.Date(2026, time.March, 31, 12, 0, 0, 0, time.Local)); fy != 2025 {
+		t.Errorf("Expected March 2026 in FY 2025, got %d", fy)
+	}
+	if fy := FinancialYear(time.Date(2026, time.April, 1, 0, 0, 0, 0, time.Local)); fy != 2026 {
+		t.Errorf("Expected April 2026 in FY 2026, got %d", fy)
+	}
+	if label := FinancialYearLabel(2099); label != "2099-00" {
+		t.Errorf("Unexpected label %q", label)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	inYear := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.Local)
+	contributions := []Contribution{
+		{TransactionID: "A", NGOID: "NGO1", NGOCategory: "education", Amount: money.INR(100000), Timestamp: inYear},
+		{TransactionID: "B", NGOID: "NGO2", NGOCategory: "Education", Amount: money.INR(50000), Timestamp: inYear, MatchFor: "E1"},
+		{TransactionID: "C", NGOID: "NGO3", NGOCategory: "other", Amount: money.INR(20000), Timestamp: inYear},
+		{TransactionID: "D", NGOID: "NGO1", NGOCategory: "education", Amount: money.INR(70000), Timestamp: inYear.AddDate(1, 0, 0)},
+	}
+
+	report := NewReport("CORP", "Acme Ltd", "", 2025, contributions)
+	if report.FinancialYear != "2025-26" || len(report.Contributions) != 3 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	if report.TotalSpend.Minor() != 170000 || report.DirectSpend.Minor() != 120000 ||
+		report.MatchedSpend.Minor() != 50000 || report.UnmappedSpend.Minor() != 20000 {
+		t.Errorf("Unexpected totals: total %s, direct %s, matched %s, unmapped %s",
+			report.TotalSpend, report.DirectSpend, report.MatchedSpend, report.UnmappedSpend)
+	}
+	if len(report.Activities) != 1 || report.Activities[0].Item != "ii" ||
+		report.Activities[0].Amount.Minor() != 150000 || report.Activities[0].NGOs != 2 {
+		t.Errorf("Unexpected activities %+v", report.Activities)
+	}
+}
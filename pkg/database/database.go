@@ -3,26 +3,39 @@ package database
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"ngo-transparency-platform/pkg/config"
+	"ngo-transparency-platform/pkg/money"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
+// Supported database drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 // InitDatabase initializes the database connection
 func InitDatabase(cfg *config.Config) error {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		cfg.Database.Host,
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.DBName,
-		cfg.Database.Port,
-		cfg.Database.SSLMode,
-	)
+	db, err := OpenDatabase(cfg)
+	if err != nil {
+		return err
+	}
+
+	DB = db
+	log.Printf("Database connected successfully (%s)", cfg.Database.Driver)
+	return nil
+}
 
+// OpenDatabase opens a connection using the driver selected in the configuration
+func OpenDatabase(cfg *config.Config) (*gorm.DB, error) {
 	// Configure GORM logger
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
@@ -33,50 +46,93 @@ func InitDatabase(cfg *config.Config) error {
 		gormConfig.Logger = logger.Default.LogMode(logger.Error)
 	}
 
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), gormConfig)
+	switch cfg.Database.Driver {
+	case DriverPostgres, "":
+		return openPostgres(cfg, gormConfig)
+	case DriverSQLite:
+		return openSQLite(cfg, gormConfig)
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Database.Driver)
+	}
+}
+
+func openPostgres(cfg *config.Config, gormConfig *gorm.Config) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		cfg.Database.Host,
+		cfg.Database.User,
+		cfg.Database.Password,
+		cfg.Database.DBName,
+		cfg.Database.Port,
+		cfg.Database.SSLMode,
+	)
+
+	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// Configure connection pool
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("failed to get database instance: %w", err)
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
 	}
 
 	// Connection pool settings
 	sqlDB.SetMaxOpenConns(25)
 	sqlDB.SetMaxIdleConns(25)
-	sqlDB.SetConnMaxLifetime(300) // 5 minutes
+	sqlDB.SetConnMaxLifetime(5 * time.Minute)
 
-	log.Println("Database connected successfully")
-	return nil
+	return db, nil
 }
 
-// MigrateDatabase runs all database migrations
+func openSQLite(cfg *config.Config, gormConfig *gorm.Config) (*gorm.DB, error) {
+	path := cfg.Database.Path
+	if path == "" {
+		path = "trusture.db"
+	}
+
+	// Enforce foreign keys and wait on locks instead of failing immediately
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	if path != ":memory:" {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+
+	db, err := gorm.Open(sqlite.Open(dsn), gormConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", path, err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	// SQLite allows a single writer, and every connection to ":memory:"
+	// would otherwise get its own empty database
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+
+	return db, nil
+}
+
+// MigrateDatabase applies all pending schema migrations
 func MigrateDatabase() error {
 	if DB == nil {
 		return fmt.Errorf("database not initialized")
 	}
 
-	// Auto-migrate all models
-	err := DB.AutoMigrate(
-		&User{},
-		&NGOModel{},
-		&DonorModel{},
-		&AuditorModel{},
-		&DonationModel{},
-		&ExpenditureModel{},
-		&AuditModel{},
-		&BlockchainBlockModel{},
-	)
+	migrator, err := NewMigrator(DB, Migrations())
+	if err != nil {
+		return err
+	}
 
+	steps, err := migrator.Up(0)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	log.Println("Database migration completed successfully")
+	log.Printf("Database migration completed successfully (%d applied)", len(steps))
 	return nil
 }
 
@@ -140,6 +196,67 @@ func (r *BaseRepository) List(entities interface{}, limit, offset int) error {
 	return query.Find(entities).Error
 }
 
+// Repositories groups the specialized repositories bound to a single database handle
+type Repositories struct {
+	db           *gorm.DB
+	NGOs         *NGORepository
+	Donors       *DonorRepository
+	Auditors     *AuditorRepository
+	Donations    *DonationRepository
+	Expenditures *ExpenditureRepository
+	Audits       *AuditRepository
+	Blocks       *BlockRepository
+	Journal      *JournalRepository
+	Payments     *PaymentIntentRepository
+	Reversals    *DonationReversalRepository
+	Idempotency  *IdempotencyKeyRepository
+	Campaigns    *CampaignRepository
+	Mandates     *RecurringMandateRepository
+	Corporates   *CorporateRepository
+	Vendors      *VendorRepository
+	Assignments  *AssignmentRepository
+	Documents    *DocumentRepository
+	Ratings      *RatingRepository
+	Anchors      *AnchorRepository
+	Pending      *PendingAnchorRepository
+}
+
+// NewRepositories creates all repositories on the given database handle
+func NewRepositories(db *gorm.DB) *Repositories {
+	base := &BaseRepository{db: db}
+	return &Repositories{
+		db:           db,
+		NGOs:         &NGORepository{base},
+		Donors:       &DonorRepository{base},
+		Auditors:     &AuditorRepository{base},
+		Donations:    &DonationRepository{base},
+		Expenditures: &ExpenditureRepository{base},
+		Audits:       &AuditRepository{base},
+		Blocks:       &BlockRepository{base},
+		Journal:      &JournalRepository{base},
+		Payments:     &PaymentIntentRepository{base},
+		Reversals:    &DonationReversalRepository{base},
+		Idempotency:  &IdempotencyKeyRepository{base},
+		Campaigns:    &CampaignRepository{base},
+		Mandates:     &RecurringMandateRepository{base},
+		Corporates:   &CorporateRepository{base},
+		Vendors:      &VendorRepository{base},
+		Assignments:  &AssignmentRepository{base},
+		Documents:    &DocumentRepository{base},
+		Ratings:      &RatingRepository{base},
+		Anchors:      &AnchorRepository{base},
+		Pending:      &PendingAnchorRepository{base},
+	}
+}
+
+// Transaction runs fn with repositories bound to a single database transaction.
+// The transaction is rolled back if fn returns an error.
+func (r *Repositories) Transaction(fn func(tx *Repositories) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
+
 // Specialized repositories
 
 // NGORepository handles NGO-specific database operations
@@ -169,6 +286,26 @@ func (r *NGORepository) GetVerifiedNGOs() ([]NGOModel, error) {
 	return ngos, err
 }
 
+// Save inserts the NGO or updates the platform-managed columns of an existing row
+func (r *NGORepository) Save(ngo *NGOModel) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "ngo_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "category", "rating", "kyc_verified", "kyc_data",
+			"total_donations_received", "total_expenditure_reported",
+			"transparency_score", "certificates", "multi_sig_signers", "updated_at",
+		}),
+	}).Create(ngo).Error
+}
+
+func (r *NGORepository) UpdateTotals(ngoID string, totalDonations, totalExpenditures money.Money) error {
+	return r.db.Model(&NGOModel{}).Where("ngo_id = ?", ngoID).
+		Updates(map[string]interface{}{
+			"total_donations_received":   totalDonations,
+			"total_expenditure_reported": totalExpenditures,
+		}).Error
+}
+
 func (r *NGORepository) UpdateRating(ngoID string, rating float64, transparencyScore int) error {
 	return r.db.Model(&NGOModel{}).Where("ngo_id = ?", ngoID).
 		Updates(map[string]interface{}{
@@ -192,7 +329,18 @@ func (r *DonorRepository) GetByDonorID(donorID string) (*DonorModel, error) {
 	return &donor, err
 }
 
-func (r *DonorRepository) UpdateTotalDonated(donorID string, totalDonated float64) error {
+// Save inserts the donor or updates the platform-managed columns of an existing row
+func (r *DonorRepository) Save(donor *DonorModel) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "donor_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"kyc_verified", "kyc_data", "total_donated", "preferred_ng_os",
+			"annual_donation_limit", "updated_at",
+		}),
+	}).Create(donor).Error
+}
+
+func (r *DonorRepository) UpdateTotalDonated(donorID string, totalDonated money.Money) error {
 	return r.db.Model(&DonorModel{}).Where("donor_id = ?", donorID).
 		Update("total_donated", totalDonated).Error
 }
@@ -212,6 +360,17 @@ func (r *AuditorRepository) GetByAuditorID(auditorID string) (*AuditorModel, err
 	return &auditor, err
 }
 
+// Save inserts the auditor or updates the platform-managed columns of an existing row
+func (r *AuditorRepository) Save(auditor *AuditorModel) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "auditor_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "credentials", "specializations", "verified", "rating",
+			"verification_authority", "verification_date", "updated_at",
+		}),
+	}).Create(auditor).Error
+}
+
 func (r *AuditorRepository) GetVerifiedAuditors() ([]AuditorModel, error) {
 	var auditors []AuditorModel
 	err := r.db.Where("verified = ?", true).Find(&auditors).Error
@@ -259,6 +418,39 @@ func (r *DonationRepository) GetByNGOID(ngoID string, limit, offset int) ([]Dona
 	return donations, err
 }
 
+// SetPolygonTxHash records the Polygon transaction that anchored a donation's block
+func (r *DonationRepository) SetPolygonTxHash(transactionID, polygonTxHash string) error {
+	return r.db.Model(&DonationModel{}).Where("transaction_id = ?", transactionID).Update("polygon_tx_hash", polygonTxHash).Error
+}
+
+// SumPlatformFees returns the platform fees kept on received donations, net
+// of the fees returned with refunds and chargebacks
+func (r *DonationRepository) SumPlatformFees() (money.Money, error) {
+	var total money.Money
+	err := r.db.Model(&DonationModel{}).Where("status IN ?", []string{"completed", "partially_reversed", "reversed"}).
+		Select("COALESCE(SUM(platform_fee - reversed_fee), 0)").Scan(&total).Error
+	return total, err
+}
+
+// ApplyReversal adds a reversal to a donation's reversed totals and stores
+// its replacement e-bill and status
+func (r *DonationRepository) ApplyReversal(transactionID string, grossAmount, platformFee money.Money, status, eBillData, taxBenefit string) error {
+	result := r.db.Model(&DonationModel{}).Where("transaction_id = ?", transactionID).Updates(map[string]interface{}{
+		"reversed_amount": gorm.Expr("reversed_amount + ?", grossAmount),
+		"reversed_fee":    gorm.Expr("reversed_fee + ?", platformFee),
+		"status":          status,
+		"e_bill_data":     eBillData,
+		"tax_benefit":     taxBenefit,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 // ExpenditureRepository handles Expenditure-specific database operations
 type ExpenditureRepository struct {
 	*BaseRepository
@@ -287,8 +479,462 @@ func (r *ExpenditureRepository) GetByNGOID(ngoID string, limit, offset int) ([]E
 	return expenditures, err
 }
 
+// SetPolygonTxHash records the Polygon transaction that anchored an expenditure's block
+func (r *ExpenditureRepository) SetPolygonTxHash(transactionID, polygonTxHash string) error {
+	return r.db.Model(&ExpenditureModel{}).Where("transaction_id = ?", transactionID).Update("polygon_tx_hash", polygonTxHash).Error
+}
+
 func (r *ExpenditureRepository) GetPendingValidation() ([]ExpenditureModel, error) {
 	var expenditures []ExpenditureModel
 	err := r.db.Where("status = ?", "pending_validation").Find(&expenditures).Error
 	return expenditures, err
-}
\ No newline at end of file
+}
+
+// GetAwaitingReview returns the expenditures waiting on an auditor's decision,
+// including appeals and amendments, or on the NGO's reply to a request for
+// information, oldest first
+func (r *ExpenditureRepository) GetAwaitingReview() ([]ExpenditureModel, error) {
+	var expenditures []ExpenditureModel
+	err := r.db.Where("status IN ?", []string{"pending_validation", "information_requested", "under_appeal", "under_amendment"}).
+		Order("created_at ASC").Find(&expenditures).Error
+	return expenditures, err
+}
+
+// GetRejected returns the rejected expenditures, oldest first
+func (r *ExpenditureRepository) GetRejected() ([]ExpenditureModel, error) {
+	var expenditures []ExpenditureModel
+	err := r.db.Where("status = ?", "rejected").Order("created_at ASC").Find(&expenditures).Error
+	return expenditures, err
+}
+
+// Save upserts an expenditure keyed by its transaction ID as it moves through
+// review and is amended. The block hash and Polygon anchor of a recorded
+// expenditure are kept when an amendment under review is saved without them.
+func (r *ExpenditureRepository) Save(expenditure *ExpenditureModel) error {
+	updates := clause.AssignmentColumns([]string{
+		"amount", "category", "description",
+		"status", "assigned_auditor_id", "invoice_details", "attachments", "auditor_validation", "compliance_score",
+		"funding", "invoice_flags", "reviews",
+		"panel", "required_approvals", "consensus", "compliance_rules_version", "appeal", "amendment", "updated_at",
+	})
+	for _, column := range []string{"block_hash", "polygon_tx_hash"} {
+		updates = append(updates, clause.Assignment{
+			Column: clause.Column{Name: column},
+			Value:  gorm.Expr(fmt.Sprintf("COALESCE(NULLIF(excluded.%s, ''), expenditures.%s)", column, column)),
+		})
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "transaction_id"}},
+		DoUpdates: updates,
+	}).Create(expenditure).Error
+}
+
+// AuditRepository handles Audit-specific database operations
+type AuditRepository struct {
+	*BaseRepository
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{NewBaseRepository()}
+}
+
+func (r *AuditRepository) GetByAuditID(auditID string) (*AuditModel, error) {
+	var audit AuditModel
+	err := r.db.Where("audit_id = ?", auditID).First(&audit).Error
+	return &audit, err
+}
+
+func (r *AuditRepository) GetByAuditorID(auditorID string) ([]AuditModel, error) {
+	var audits []AuditModel
+	err := r.db.Where("auditor_id = ?", auditorID).Order("created_at ASC").Find(&audits).Error
+	return audits, err
+}
+
+func (r *AuditRepository) GetByExpenditureID(expenditureID string) ([]AuditModel, error) {
+	var audits []AuditModel
+	err := r.db.Where("expenditure_id = ?", expenditureID).Order("created_at ASC").Find(&audits).Error
+	return audits, err
+}
+
+// BlockRepository handles blockchain block database operations
+type BlockRepository struct {
+	*BaseRepository
+}
+
+func NewBlockRepository() *BlockRepository {
+	return &BlockRepository{NewBaseRepository()}
+}
+
+func (r *BlockRepository) GetByHash(hash string) (*BlockchainBlockModel, error) {
+	var block BlockchainBlockModel
+	err := r.db.Where("hash = ?", hash).First(&block).Error
+	return &block, err
+}
+
+// GetChain returns all blocks of an NGO's chain ordered by index
+func (r *BlockRepository) GetChain(ngoID, blockType string) ([]BlockchainBlockModel, error) {
+	var blocks []BlockchainBlockModel
+	err := r.db.Where("ngo_id = ? AND block_type = ?", ngoID, blockType).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "index"}}).
+		Find(&blocks).Error
+	return blocks, err
+}
+
+// JournalRepository handles ledger journal entry database operations
+type JournalRepository struct {
+	*BaseRepository
+}
+
+func NewJournalRepository() *JournalRepository {
+	return &JournalRepository{NewBaseRepository()}
+}
+
+// GetByNGOID returns an NGO's journal entries with their lines in posting order
+func (r *JournalRepository) GetByNGOID(ngoID string) ([]JournalEntryModel, error) {
+	var entries []JournalEntryModel
+	err := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("ngo_id = ?", ngoID).Order("posted_at ASC, id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// GetByReference returns the journal entries posted for a source transaction
+func (r *JournalRepository) GetByReference(reference string) ([]JournalEntryModel, error) {
+	var entries []JournalEntryModel
+	err := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("reference = ?", reference).Order("posted_at ASC, id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// PaymentIntentRepository handles payment intent database operations
+type PaymentIntentRepository struct {
+	*BaseRepository
+}
+
+func NewPaymentIntentRepository() *PaymentIntentRepository {
+	return &PaymentIntentRepository{NewBaseRepository()}
+}
+
+// Save upserts a payment intent keyed by its intent ID, updating its outcome
+func (r *PaymentIntentRepository) Save(intent *PaymentIntentModel) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "intent_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "gateway_payment_id", "block_hash", "donation_data", "failure_reason", "refund_id", "updated_at",
+		}),
+	}).Create(intent).Error
+}
+
+// GetByIntentID returns a payment intent by its platform ID
+func (r *PaymentIntentRepository) GetByIntentID(intentID string) (*PaymentIntentModel, error) {
+	var intent PaymentIntentModel
+	err := r.db.Where("intent_id = ?", intentID).First(&intent).Error
+	return &intent, err
+}
+
+// GetByGatewayOrderID returns the payment intent for a gateway order
+func (r *PaymentIntentRepository) GetByGatewayOrderID(orderID string) (*PaymentIntentModel, error) {
+	var intent PaymentIntentModel
+	err := r.db.Where("gateway_order_id = ?", orderID).First(&intent).Error
+	return &intent, err
+}
+
+// GetByStatus returns payment intents in the given status, oldest first
+func (r *PaymentIntentRepository) GetByStatus(status string) ([]PaymentIntentModel, error) {
+	var intents []PaymentIntentModel
+	err := r.db.Where("status = ?", status).Order("created_at ASC").Find(&intents).Error
+	return intents, err
+}
+
+// DonationReversalRepository handles refund and chargeback database operations
+type DonationReversalRepository struct {
+	*BaseRepository
+}
+
+func NewDonationReversalRepository() *DonationReversalRepository {
+	return &DonationReversalRepository{NewBaseRepository()}
+}
+
+// GetByTransactionID returns the reversals of a donation, oldest first
+func (r *DonationReversalRepository) GetByTransactionID(transactionID string) ([]DonationReversalModel, error) {
+	var reversals []DonationReversalModel
+	err := r.db.Where("transaction_id = ?", transactionID).Order("created_at ASC, id ASC").Find(&reversals).Error
+	return reversals, err
+}
+
+// GetByNGOID returns an NGO's reversals, oldest first
+func (r *DonationReversalRepository) GetByNGOID(ngoID string) ([]DonationReversalModel, error) {
+	var reversals []DonationReversalModel
+	err := r.db.Where("ngo_id = ?", ngoID).Order("created_at ASC, id ASC").Find(&reversals).Error
+	return reversals, err
+}
+
+// CampaignRepository handles campaign database operations
+type CampaignRepository struct {
+	*BaseRepository
+}
+
+func NewCampaignRepository() *CampaignRepository {
+	return &CampaignRepository{NewBaseRepository()}
+}
+
+// Save upserts a campaign keyed by its campaign ID
+func (r *CampaignRepository) Save(campaign *CampaignModel) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "campaign_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "description", "goal_amount", "raised_amount", "spent_amount", "status", "end_date", "closed_at", "updated_at",
+		}),
+	}).Create(campaign).Error
+}
+
+// GetByNGOID returns an NGO's campaigns, oldest first
+func (r *CampaignRepository) GetByNGOID(ngoID string) ([]CampaignModel, error) {
+	var campaigns []CampaignModel
+	err := r.db.Where("ngo_id = ?", ngoID).Order("created_at ASC, id ASC").Find(&campaigns).Error
+	return campaigns, err
+}
+
+// RecurringMandateRepository handles recurring donation mandate database operations
+type RecurringMandateRepository struct {
+	*BaseRepository
+}
+
+func NewRecurringMandateRepository() *RecurringMandateRepository {
+	return &RecurringMandateRepository{NewBaseRepository()}
+}
+
+// Save upserts a mandate keyed by its mandate ID
+func (r *RecurringMandateRepository) Save(mandate *RecurringMandateModel) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "mandate_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "installment", "next_charge_date", "retry_at", "failed_attempts", "last_error",
+			"charged_count", "missed_count", "last_charged_at", "paused_at", "cancelled_at", "updated_at",
+		}),
+	}).Create(mandate).Error
+}
+
+// GetByDonorID returns a donor's mandates, oldest first
+func (r *RecurringMandateRepository) GetByDonorID(donorID string) ([]RecurringMandateModel, error) {
+	var mandates []RecurringMandateModel
+	err := r.db.Where("donor_id = ?", donorID).Order("created_at ASC, id ASC").Find(&mandates).Error
+	return mandates, err
+}
+
+// CorporateRepository handles corporate donor profiles and their employees
+type CorporateRepository struct {
+	*BaseRepository
+}
+
+func NewCorporateRepository() *CorporateRepository {
+	return &CorporateRepository{NewBaseRepository()}
+}
+
+// SaveProfile upserts a corporate profile keyed by its donor ID
+func (r *CorporateRepository) SaveProfile(profile *CorporateProfileModel) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "donor_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"company_name", "cin", "match_ratio", "per_employee_cap", "eligible_categories", "matching_active", "updated_at",
+		}),
+	}).Create(profile).Error
+}
+
+// GetProfile returns a donor's corporate profile, or nil if the donor is not a corporate
+func (r *CorporateRepository) GetProfile(donorID string) (*CorporateProfileModel, error) {
+	var profiles []CorporateProfileModel
+	if err := r.db.Where("donor_id = ?", donorID).Limit(1).Find(&profiles).Error; err != nil || len(profiles) == 0 {
+		return nil, err
+	}
+	return &profiles[0], nil
+}
+
+// AddEmployee links an employee donor to a corporate
+func (r *CorporateRepository) AddEmployee(employee *CorporateEmployeeModel) error {
+	return r.db.Create(employee).Error
+}
+
+// RemoveEmployee unlinks an employee donor from its corporate
+func (r *CorporateRepository) RemoveEmployee(corporateID, donorID string) error {
+	return r.db.Where("corporate_id = ? AND donor_id = ?", corporateID, donorID).Delete(&CorporateEmployeeModel{}).Error
+}
+
+// GetEmployees returns a corporate's employees, oldest first
+func (r *CorporateRepository) GetEmployees(corporateID string) ([]CorporateEmployeeModel, error) {
+	var employees []CorporateEmployeeModel
+	err := r.db.Where("corporate_id = ?", corporateID).Order("created_at ASC, id ASC").Find(&employees).Error
+	return employees, err
+}
+
+// GetEmployer returns the corporate a donor works for, or an empty string
+func (r *CorporateRepository) GetEmployer(donorID string) (string, error) {
+	var employees []CorporateEmployeeModel
+	if err := r.db.Where("donor_id = ?", donorID).Limit(1).Find(&employees).Error; err != nil || len(employees) == 0 {
+		return "", err
+	}
+	return employees[0].CorporateID, nil
+}
+
+// VendorRepository handles vendor registry database operations
+type VendorRepository struct {
+	*BaseRepository
+}
+
+func NewVendorRepository() *VendorRepository {
+	return &VendorRepository{NewBaseRepository()}
+}
+
+// Save upserts a vendor keyed by its GSTIN
+func (r *VendorRepository) Save(vendor *VendorModel) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "gstin"}},
+		DoUpdates: clause.AssignmentColumns([]string{"legal_name", "updated_at"}),
+	}).Create(vendor).Error
+}
+
+// AssignmentRepository handles auditor assignments and declared conflicts of interest
+type AssignmentRepository struct {
+	*BaseRepository
+}
+
+func NewAssignmentRepository() *AssignmentRepository {
+	return &AssignmentRepository{NewBaseRepository()}
+}
+
+// GetAssignments returns every auditor assignment, oldest first
+func (r *AssignmentRepository) GetAssignments() ([]AuditorAssignmentModel, error) {
+	var assignments []AuditorAssignmentModel
+	err := r.db.Order("created_at ASC, id ASC").Find(&assignments).Error
+	return assignments, err
+}
+
+// GetConflicts returns the conflicts of interest an auditor declared, oldest first
+func (r *AssignmentRepository) GetConflicts(auditorID string) ([]AuditorConflictModel, error) {
+	var conflicts []AuditorConflictModel
+	err := r.db.Where("auditor_id = ?", auditorID).Order("created_at ASC, id ASC").Find(&conflicts).Error
+	return conflicts, err
+}
+
+// DocumentRepository handles records of uploaded documents
+type DocumentRepository struct {
+	*BaseRepository
+}
+
+func NewDocumentRepository() *DocumentRepository {
+	return &DocumentRepository{NewBaseRepository()}
+}
+
+// GetDocuments returns every uploaded document, oldest first
+func (r *DocumentRepository) GetDocuments() ([]DocumentModel, error) {
+	var documents []DocumentModel
+	err := r.db.Order("created_at ASC, id ASC").Find(&documents).Error
+	return documents, err
+}
+
+// RatingRepository handles the history of NGO ratings
+type RatingRepository struct {
+	*BaseRepository
+}
+
+func NewRatingRepository() *RatingRepository {
+	return &RatingRepository{NewBaseRepository()}
+}
+
+// GetSnapshots returns every recorded rating, oldest first
+func (r *RatingRepository) GetSnapshots() ([]RatingSnapshotModel, error) {
+	var snapshots []RatingSnapshotModel
+	err := r.db.Order("created_at ASC, id ASC").Find(&snapshots).Error
+	return snapshots, err
+}
+
+// IdempotencyKeyRepository handles stored responses to idempotent requests
+type IdempotencyKeyRepository struct {
+	*BaseRepository
+}
+
+func NewIdempotencyKeyRepository() *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{NewBaseRepository()}
+}
+
+// Reserve claims a key for a request. It returns nil if the key was free, or
+// the existing record if another request holds it. Expired keys are freed.
+func (r *IdempotencyKeyRepository) Reserve(record *IdempotencyKeyModel) (*IdempotencyKeyModel, error) {
+	if err := r.db.Where("key = ? AND expires_at < ?", record.Key, time.Now()).Delete(&IdempotencyKeyModel{}).Error; err != nil {
+		return nil, err
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing IdempotencyKeyModel
+	if err := r.db.Where("key = ?", record.Key).First(&existing).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// Complete stores the response to a reserved key
+func (r *IdempotencyKeyRepository) Complete(key string, statusCode int, responseBody string) error {
+	return r.db.Model(&IdempotencyKeyModel{}).Where("key = ?", key).Updates(map[string]interface{}{
+		"status_code":   statusCode,
+		"response_body": responseBody,
+		"completed":     true,
+	}).Error
+}
+
+// Release frees a reserved key so the request can be retried
+func (r *IdempotencyKeyRepository) Release(key string) error {
+	return r.db.Where("key = ? AND completed = ?", key, false).Delete(&IdempotencyKeyModel{}).Error
+}
+
+// AnchorRepository handles block hashes anchored to Polygon
+type AnchorRepository struct {
+	*BaseRepository
+}
+
+func NewAnchorRepository() *AnchorRepository {
+	return &AnchorRepository{NewBaseRepository()}
+}
+
+// GetAnchors returns every anchor, oldest first
+func (r *AnchorRepository) GetAnchors() ([]AnchorModel, error) {
+	var anchors []AnchorModel
+	err := r.db.Order("anchored_at ASC, id ASC").Find(&anchors).Error
+	return anchors, err
+}
+
+// PendingAnchorRepository handles blocks awaiting a Polygon anchor
+type PendingAnchorRepository struct {
+	*BaseRepository
+}
+
+func NewPendingAnchorRepository() *PendingAnchorRepository {
+	return &PendingAnchorRepository{NewBaseRepository()}
+}
+
+// Save upserts a pending anchor keyed by its block hash
+func (r *PendingAnchorRepository) Save(pending *PendingAnchorModel) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "block_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "attempts", "last_attempt_at"}),
+	}).Create(pending).Error
+}
+
+// Delete removes a block's pending anchor once it is anchored
+func (r *PendingAnchorRepository) Delete(blockHash string) error {
+	return r.db.Where("block_hash = ?", blockHash).Delete(&PendingAnchorModel{}).Error
+}
+
+// GetPendingAnchors returns every pending anchor, oldest first
+func (r *PendingAnchorRepository) GetPendingAnchors() ([]PendingAnchorModel, error) {
+	var pending []PendingAnchorModel
+	err := r.db.Order("created_at ASC, id ASC").Find(&pending).Error
+	return pending, err
+}
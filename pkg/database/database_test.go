@@ -0,0 +1,45 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKeyRepository(t *testing.T) {
+	db := openTestDB(t)
+	migrator, err := NewMigrator(db, Migrations())
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	keys := NewRepositories(db).Idempotency
+
+	record := func(key string, ttl time.Duration) *IdempotencyKeyModel {
+		return &IdempotencyKeyModel{Key: key, RequestHash: "hash", ExpiresAt: time.Now().Add(ttl)}
+	}
+
+	if existing, err := keys.Reserve(record("k1", time.Hour)); err != nil || existing != nil {
+		t.Fatalf("Expected a free key to be reserved, got %+v (%v)", existing, err)
+	}
+	if existing, err := keys.Reserve(record("k1", time.Hour)); err != nil || existing == nil || existing.Completed {
+		t.Fatalf("Expected the in-flight reservation, got %+v (%v)", existing, err)
+	}
+
+	if err := keys.Complete("k1", 200, `{"success":true}`); err != nil {
+		t.Fatalf("Failed to complete key: %v", err)
+	}
+	if err := keys.Release("k1"); err != nil {
+		t.Fatalf("Failed to release key: %v", err)
+	}
+	existing, err := keys.Reserve(record("k1", time.Hour))
+	if err != nil || existing == nil || !existing.Completed || existing.StatusCode != 200 || existing.ResponseBody != `{"success":true}` {
+		t.Errorf("Expected the completed response to survive a release, got %+v (%v)", existing, err)
+	}
+
+	keys.Reserve(record("k2", -time.Minute))
+	if existing, err := keys.Reserve(record("k2", time.Hour)); err != nil || existing != nil {
+		t.Errorf("Expected an expired key to be reserved again, got %+v (%v)", existing, err)
+	}
+}
@@ -0,0 +1,310 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrChecksumMismatch is returned when an applied migration no longer matches its definition
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// Migration is a single versioned schema change. A migration is either written
+// in Go (Up/Down) or as SQL statements (UpSQL/DownSQL); SQL takes precedence.
+type Migration struct {
+	Version int64
+	Name    string
+	UpSQL   []string
+	DownSQL []string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// Checksum fingerprints the migration definition. For Go migrations only the
+// version and name are covered, so their bodies must never be edited once shipped.
+func (m Migration) Checksum() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\n%s\n", m.Version, m.Name)
+	for _, stmt := range m.UpSQL {
+		b.WriteString("up:" + strings.TrimSpace(stmt) + "\n")
+	}
+	for _, stmt := range m.DownSQL {
+		b.WriteString("down:" + strings.TrimSpace(stmt) + "\n")
+	}
+
+	hash := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+// reversible reports whether the migration can be rolled back
+func (m Migration) reversible() bool {
+	return len(m.DownSQL) > 0 || m.Down != nil
+}
+
+// SchemaMigration records an applied migration
+type SchemaMigration struct {
+	Version    int64     `json:"version" gorm:"primaryKey;autoIncrement:false"`
+	Name       string    `json:"name" gorm:"not null"`
+	Checksum   string    `json:"checksum" gorm:"not null"`
+	DurationMs int64     `json:"duration_ms"`
+	AppliedAt  time.Time `json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus describes a known or applied migration
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Checksum  string     `json:"checksum"`
+	Modified  bool       `json:"modified"` // Applied checksum differs from the definition
+	Missing   bool       `json:"missing"`  // Applied but no longer defined
+}
+
+// MigrationStep is a migration that was (or in dry-run mode would be) executed
+type MigrationStep struct {
+	Version    int64    `json:"version"`
+	Name       string   `json:"name"`
+	Direction  string   `json:"direction"` // up, down
+	Statements []string `json:"statements,omitempty"`
+}
+
+// Migrator applies versioned migrations and tracks them in schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	DryRun     bool
+}
+
+// NewMigrator creates a migrator for the given migrations, which must have unique versions
+func NewMigrator(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration %q has invalid version %d", m.Name, m.Version)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+		if len(m.UpSQL) == 0 && m.Up == nil {
+			return nil, fmt.Errorf("migration %d (%s) has no up step", m.Version, m.Name)
+		}
+	}
+
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+// ensureTable creates the schema_migrations table if needed
+func (m *Migrator) ensureTable() error {
+	if m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return nil
+	}
+	if m.DryRun {
+		return nil
+	}
+	return m.db.AutoMigrate(&SchemaMigration{})
+}
+
+func (m *Migrator) applied() (map[int64]SchemaMigration, error) {
+	result := make(map[int64]SchemaMigration)
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return result, nil
+	}
+
+	var rows []SchemaMigration
+	if err := m.db.Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+// Status lists all defined and applied migrations ordered by version
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	defined := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		defined[migration.Version] = true
+		status := MigrationStatus{
+			Version:  migration.Version,
+			Name:     migration.Name,
+			Checksum: migration.Checksum(),
+		}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = row.Checksum != status.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, row := range applied {
+		if defined[version] {
+			continue
+		}
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   row.Version,
+			Name:      row.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Checksum:  row.Checksum,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Verify checks that every applied migration still matches its definition
+func (m *Migrator) Verify() error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.Modified {
+			return fmt.Errorf("%w: migration %d (%s) was changed after being applied", ErrChecksumMismatch, status.Version, status.Name)
+		}
+		if status.Missing {
+			return fmt.Errorf("migration %d (%s) is applied but not defined in this build", status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+// Up applies pending migrations up to and including target (0 applies all)
+func (m *Migrator) Up(target int64) ([]MigrationStep, error) {
+	if err := m.Verify(); err != nil {
+		return nil, err
+	}
+	if err := m.ensureTable(); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]MigrationStep, 0)
+	for _, migration := range m.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		step := MigrationStep{Version: migration.Version, Name: migration.Name, Direction: "up", Statements: migration.UpSQL}
+		if !m.DryRun {
+			if err := m.run(migration, true); err != nil {
+				return steps, err
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// Down rolls back the given number of most recently applied migrations
+func (m *Migrator) Down(count int) ([]MigrationStep, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("rollback count must be positive")
+	}
+	if err := m.Verify(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]MigrationStep, 0, count)
+	for i := len(m.migrations) - 1; i >= 0 && len(steps) < count; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if !migration.reversible() {
+			return steps, fmt.Errorf("migration %d (%s) cannot be rolled back", migration.Version, migration.Name)
+		}
+
+		step := MigrationStep{Version: migration.Version, Name: migration.Name, Direction: "down", Statements: migration.DownSQL}
+		if !m.DryRun {
+			if err := m.run(migration, false); err != nil {
+				return steps, err
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// run executes one migration and records it in a single transaction
+func (m *Migrator) run(migration Migration, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	started := time.Now()
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		statements, fn := migration.DownSQL, migration.Down
+		if up {
+			statements, fn = migration.UpSQL, migration.Up
+		}
+
+		if len(statements) > 0 {
+			for _, stmt := range statements {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return err
+				}
+			}
+		} else if err := fn(tx); err != nil {
+			return err
+		}
+
+		if !up {
+			return tx.Delete(&SchemaMigration{}, migration.Version).Error
+		}
+		return tx.Create(&SchemaMigration{
+			Version:    migration.Version,
+			Name:       migration.Name,
+			Checksum:   migration.Checksum(),
+			DurationMs: time.Since(started).Milliseconds(),
+			AppliedAt:  time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d (%s) %s failed: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"ngo-transparency-platform/pkg/config"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Path = ":memory:"
+	config.AppConfig = cfg
+
+	db, err := OpenDatabase(cfg)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 2,
+			Name:    "add_notes",
+			UpSQL:   []string{"ALTER TABLE widgets ADD COLUMN notes TEXT"},
+			DownSQL: []string{"ALTER TABLE widgets DROP COLUMN notes"},
+		},
+		{
+			Version: 1,
+			Name:    "create_widgets",
+			UpSQL:   []string{"CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT NOT NULL)"},
+			DownSQL: []string{"DROP TABLE widgets"},
+		},
+	}
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	db := openTestDB(t)
+
+	migrator, err := NewMigrator(db, testMigrations())
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+
+	steps, err := migrator.Up(1)
+	if err != nil {
+		t.Fatalf("Failed to migrate up to version 1: %v", err)
+	}
+	if len(steps) != 1 || steps[0].Version != 1 {
+		t.Fatalf("Expected only version 1 to run, got %+v", steps)
+	}
+
+	steps, err = migrator.Up(0)
+	if err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+	if len(steps) != 1 || steps[0].Version != 2 {
+		t.Fatalf("Expected version 2 to run, got %+v", steps)
+	}
+	if !db.Migrator().HasColumn("widgets", "notes") {
+		t.Error("Expected notes column after migrating up")
+	}
+
+	if _, err := migrator.Down(1); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if db.Migrator().HasColumn("widgets", "notes") {
+		t.Error("Expected notes column to be dropped after rolling back")
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Failed to read status: %v", err)
+	}
+	if !statuses[0].Applied || statuses[1].Applied {
+		t.Errorf("Expected only version 1 applied, got %+v", statuses)
+	}
+}
+
+func TestMigratorDryRunChangesNothing(t *testing.T) {
+	db := openTestDB(t)
+
+	migrator, _ := NewMigrator(db, testMigrations())
+	migrator.DryRun = true
+
+	steps, err := migrator.Up(0)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(steps) != 2 || len(steps[0].Statements) != 1 {
+		t.Errorf("Expected two planned steps with statements, got %+v", steps)
+	}
+	if db.Migrator().HasTable("widgets") || db.Migrator().HasTable(&SchemaMigration{}) {
+		t.Error("Dry run should not create tables")
+	}
+}
+
+func TestMigratorDetectsChecksumMismatch(t *testing.T) {
+	db := openTestDB(t)
+
+	migrator, _ := NewMigrator(db, testMigrations())
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+
+	// Simulate a hotfix edit to an applied migration
+	edited := testMigrations()
+	edited[1].UpSQL = []string{"CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT)"}
+	migrator, _ = NewMigrator(db, edited)
+
+	if err := migrator.Verify(); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected checksum mismatch, got %v", err)
+	}
+	if _, err := migrator.Up(0); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected up to refuse edited migrations, got %v", err)
+	}
+}
+
+func TestMigrationsApplyCleanly(t *testing.T) {
+	DB = openTestDB(t)
+
+	if err := MigrateDatabase(); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+	// Running again is a no-op
+	if err := MigrateDatabase(); err != nil {
+		t.Fatalf("Failed to re-run migrations: %v", err)
+	}
+
+	migrator, _ := NewMigrator(DB, Migrations())
+	if _, err := migrator.Down(len(Migrations())); err != nil {
+		t.Fatalf("Failed to roll back all migrations: %v", err)
+	}
+	if DB.Migrator().HasTable(&NGOModel{}) {
+		t.Error("Expected baseline tables to be dropped")
+	}
+}
+
+func TestMigrationsMatchModels(t *testing.T) {
+	DB = openTestDB(t)
+
+	if err := MigrateDatabase(); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	// Migrations create tables from frozen snapshots, so a model changed
+	// without a migration leaves a column or index missing
+	models := []interface{}{
+		&User{}, &NGOModel{}, &DonorModel{}, &AuditorModel{}, &DonationModel{}, &ExpenditureModel{},
+		&AuditModel{}, &BlockchainBlockModel{}, &JournalEntryModel{}, &JournalLineModel{},
+		&PaymentIntentModel{}, &DonationReversalModel{}, &IdempotencyKeyModel{}, &CampaignModel{},
+		&RecurringMandateModel{}, &CorporateProfileModel{}, &CorporateEmployeeModel{}, &VendorModel{},
+		&AuditorConflictModel{}, &AuditorAssignmentModel{}, &DocumentModel{}, &RatingSnapshotModel{},
+		&AnchorModel{}, &PendingAnchorModel{},
+	}
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: DB}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("Failed to parse %T: %v", model, err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !DB.Migrator().HasColumn(model, field.DBName) {
+				t.Errorf("Expected column %s.%s after migrating", stmt.Schema.Table, field.DBName)
+			}
+		}
+		for _, index := range stmt.Schema.ParseIndexes() {
+			if !DB.Migrator().HasIndex(model, index.Name) {
+				t.Errorf("Expected index %s on %s after migrating", index.Name, stmt.Schema.Table)
+			}
+		}
+	}
+}
+
+func TestAmountsMigrateToMinorUnits(t *testing.T) {
+	DB = openTestDB(t)
+
+	migrator, _ := NewMigrator(DB, Migrations())
+	if _, err := migrator.Up(2); err != nil {
+		t.Fatalf("Failed to migrate to version 2: %v", err)
+	}
+
+	// Rows written by the float schema, amounts in rupees
+	if err := DB.Exec(`INSERT INTO donations (transaction_id, donor_id, ngo_id, amount, platform_fee, net_amount, payment_method, status)
+		VALUES ('DON1', 'DONOR001', 'NGO001', 1250.5, 12.51, 1237.99, 'upi', 'completed')`).Error; err != nil {
+		t.Fatalf("Failed to insert legacy donation: %v", err)
+	}
+
+	if _, err := migrator.Up(3); err != nil {
+		t.Fatalf("Failed to migrate amounts: %v", err)
+	}
+
+	var donation DonationModel
+	if err := DB.Where("transaction_id = ?", "DON1").First(&donation).Error; err != nil {
+		t.Fatalf("Failed to load donation: %v", err)
+	}
+	if donation.Amount.Minor() != 125050 || donation.PlatformFee.Minor() != 1251 || donation.NetAmount.Minor() != 123799 {
+		t.Errorf("Expected amounts in paise, got %+v", donation)
+	}
+
+	// Repositories query the latest schema
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatalf("Failed to migrate to latest: %v", err)
+	}
+	fees, err := NewRepositories(DB).Donations.SumPlatformFees()
+	if err != nil || fees.Minor() != 1251 {
+		t.Errorf("Expected 1251 paise in fees, got %v (%v)", fees, err)
+	}
+
+	if _, err := migrator.Down(len(Migrations()) - 2); err != nil {
+		t.Fatalf("Failed to roll back amounts: %v", err)
+	}
+	var amount float64
+	DB.Raw("SELECT amount FROM donations WHERE transaction_id = ?", "DON1").Scan(&amount)
+	if amount != 1250.5 {
+		t.Errorf("Expected rupee amount after rollback, got %v", amount)
+	}
+}
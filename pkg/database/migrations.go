@@ -0,0 +1,895 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migrations returns all schema migrations in version order. Never edit a
+// migration that has shipped; add a new one instead.
+func Migrations() []Migration {
+	return []Migration{
+		{
+			Version: 1,
+			Name:    "baseline_schema",
+			Up: func(tx *gorm.DB) error {
+				// Matches the schema previously created by AutoMigrate, so existing
+				// databases adopt the baseline without changes. Every model is frozen
+				// as a baseline* snapshot, so later model changes cannot alter it.
+				return tx.AutoMigrate(
+					&baselineUser{},
+					&baselineNGO{},
+					&baselineDonor{},
+					&baselineAuditor{},
+					&baselineDonation{},
+					&baselineExpenditure{},
+					&baselineAudit{},
+					&baselineBlock{},
+				)
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(
+					&baselineBlock{},
+					&baselineAudit{},
+					&baselineExpenditure{},
+					&baselineDonation{},
+					&baselineAuditor{},
+					&baselineDonor{},
+					&baselineNGO{},
+					&baselineUser{},
+				)
+			},
+		},
+		{
+			Version: 2,
+			Name:    "index_donations_and_expenditures",
+			UpSQL: []string{
+				"CREATE INDEX IF NOT EXISTS idx_donations_donor_id ON donations (donor_id)",
+				"CREATE INDEX IF NOT EXISTS idx_donations_ngo_id ON donations (ngo_id)",
+				"CREATE INDEX IF NOT EXISTS idx_expenditures_ngo_id ON expenditures (ngo_id)",
+				"CREATE INDEX IF NOT EXISTS idx_expenditures_status ON expenditures (status)",
+			},
+			DownSQL: []string{
+				"DROP INDEX IF EXISTS idx_expenditures_status",
+				"DROP INDEX IF EXISTS idx_expenditures_ngo_id",
+				"DROP INDEX IF EXISTS idx_donations_ngo_id",
+				"DROP INDEX IF EXISTS idx_donations_donor_id",
+			},
+		},
+		{
+			Version: 3,
+			Name:    "store_amounts_in_minor_units",
+			Up:      amountsToMinorUnits,
+			Down:    amountsToMajorUnits,
+		},
+		{
+			Version: 4,
+			Name:    "create_ledger",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&journalEntryV4{}, &journalLineV4{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&journalLineV4{}, &journalEntryV4{})
+			},
+		},
+		{
+			Version: 5,
+			Name:    "create_payment_intents",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&paymentIntentV5{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&paymentIntentV5{})
+			},
+		},
+		{
+			Version: 6,
+			Name:    "create_donation_reversals",
+			Up: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&donationReversalV6{}); err != nil {
+					return err
+				}
+				for _, stmt := range []string{
+					"ALTER TABLE donations ADD COLUMN reversed_amount bigint NOT NULL DEFAULT 0",
+					"ALTER TABLE donations ADD COLUMN reversed_fee bigint NOT NULL DEFAULT 0",
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Down: func(tx *gorm.DB) error {
+				for _, stmt := range []string{
+					"ALTER TABLE donations DROP COLUMN reversed_fee",
+					"ALTER TABLE donations DROP COLUMN reversed_amount",
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().DropTable(&donationReversalV6{})
+			},
+		},
+		{
+			Version: 7,
+			Name:    "create_idempotency_keys",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&idempotencyKeyV7{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&idempotencyKeyV7{})
+			},
+		},
+		{
+			Version: 8,
+			Name:    "create_campaigns",
+			Up: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&campaignV8{}); err != nil {
+					return err
+				}
+				for _, stmt := range []string{
+					"ALTER TABLE donations ADD COLUMN campaign_id varchar(64) NOT NULL DEFAULT ''",
+					"ALTER TABLE expenditures ADD COLUMN campaign_id varchar(64) NOT NULL DEFAULT ''",
+					"CREATE INDEX IF NOT EXISTS idx_donations_campaign_id ON donations (campaign_id)",
+					"CREATE INDEX IF NOT EXISTS idx_expenditures_campaign_id ON expenditures (campaign_id)",
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Down: func(tx *gorm.DB) error {
+				for _, stmt := range []string{
+					"DROP INDEX IF EXISTS idx_expenditures_campaign_id",
+					"DROP INDEX IF EXISTS idx_donations_campaign_id",
+					"ALTER TABLE expenditures DROP COLUMN campaign_id",
+					"ALTER TABLE donations DROP COLUMN campaign_id",
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().DropTable(&campaignV8{})
+			},
+		},
+		{
+			Version: 9,
+			Name:    "add_expenditure_funding",
+			UpSQL: []string{
+				"ALTER TABLE expenditures ADD COLUMN funding text",
+			},
+			DownSQL: []string{
+				"ALTER TABLE expenditures DROP COLUMN funding",
+			},
+		},
+		{
+			Version: 10,
+			Name:    "create_recurring_mandates",
+			Up: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&recurringMandateV10{}); err != nil {
+					return err
+				}
+				for _, stmt := range []string{
+					"ALTER TABLE donations ADD COLUMN mandate_id varchar(64) NOT NULL DEFAULT ''",
+					"CREATE INDEX IF NOT EXISTS idx_donations_mandate_id ON donations (mandate_id)",
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Down: func(tx *gorm.DB) error {
+				for _, stmt := range []string{
+					"DROP INDEX IF EXISTS idx_donations_mandate_id",
+					"ALTER TABLE donations DROP COLUMN mandate_id",
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().DropTable(&recurringMandateV10{})
+			},
+		},
+		{
+			Version: 11,
+			Name:    "create_corporate_matching",
+			Up: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&corporateProfileV11{}, &corporateEmployeeV11{}); err != nil {
+					return err
+				}
+				for _, stmt := range []string{
+					"ALTER TABLE donations ADD COLUMN match_for varchar(64) NOT NULL DEFAULT ''",
+					"CREATE INDEX IF NOT EXISTS idx_donations_match_for ON donations (match_for)",
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Down: func(tx *gorm.DB) error {
+				for _, stmt := range []string{
+					"DROP INDEX IF EXISTS idx_donations_match_for",
+					"ALTER TABLE donations DROP COLUMN match_for",
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().DropTable(&corporateEmployeeV11{}, &corporateProfileV11{})
+			},
+		},
+		{
+			Version: 12,
+			Name:    "create_vendors",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&vendorV12{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&vendorV12{})
+			},
+		},
+		{
+			Version: 13,
+			Name:    "add_expenditure_invoice_flags",
+			UpSQL: []string{
+				"ALTER TABLE expenditures ADD COLUMN invoice_flags text",
+			},
+			DownSQL: []string{
+				"ALTER TABLE expenditures DROP COLUMN invoice_flags",
+			},
+		},
+		{
+			Version: 14,
+			Name:    "add_expenditure_reviews",
+			UpSQL: []string{
+				"ALTER TABLE expenditures ADD COLUMN assigned_auditor_id varchar(64) NOT NULL DEFAULT ''",
+				"ALTER TABLE expenditures ADD COLUMN reviews text",
+				"ALTER TABLE audits ADD COLUMN decision varchar(32) NOT NULL DEFAULT ''",
+				"CREATE INDEX IF NOT EXISTS idx_expenditures_assigned_auditor_id ON expenditures (assigned_auditor_id)",
+			},
+			DownSQL: []string{
+				"DROP INDEX IF EXISTS idx_expenditures_assigned_auditor_id",
+				"ALTER TABLE audits DROP COLUMN decision",
+				"ALTER TABLE expenditures DROP COLUMN reviews",
+				"ALTER TABLE expenditures DROP COLUMN assigned_auditor_id",
+			},
+		},
+		{
+			Version: 15,
+			Name:    "create_auditor_assignments",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&auditorConflictV15{}, &auditorAssignmentV15{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&auditorAssignmentV15{}, &auditorConflictV15{})
+			},
+		},
+		{
+			Version: 16,
+			Name:    "add_expenditure_panels",
+			UpSQL: []string{
+				"ALTER TABLE expenditures ADD COLUMN panel text",
+				"ALTER TABLE expenditures ADD COLUMN required_approvals integer NOT NULL DEFAULT 0",
+				"ALTER TABLE expenditures ADD COLUMN consensus text",
+			},
+			DownSQL: []string{
+				"ALTER TABLE expenditures DROP COLUMN consensus",
+				"ALTER TABLE expenditures DROP COLUMN required_approvals",
+				"ALTER TABLE expenditures DROP COLUMN panel",
+			},
+		},
+		{
+			Version: 17,
+			Name:    "add_compliance_rules_versions",
+			UpSQL: []string{
+				"ALTER TABLE expenditures ADD COLUMN compliance_rules_version varchar(64) NOT NULL DEFAULT ''",
+				"ALTER TABLE audits ADD COLUMN rules_version varchar(64) NOT NULL DEFAULT ''",
+			},
+			DownSQL: []string{
+				"ALTER TABLE audits DROP COLUMN rules_version",
+				"ALTER TABLE expenditures DROP COLUMN compliance_rules_version",
+			},
+		},
+		{
+			Version: 18,
+			Name:    "add_expenditure_appeals",
+			UpSQL: []string{
+				"ALTER TABLE expenditures ADD COLUMN appeal text",
+			},
+			DownSQL: []string{
+				"ALTER TABLE expenditures DROP COLUMN appeal",
+			},
+		},
+		{
+			Version: 19,
+			Name:    "create_documents",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&documentV19{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&documentV19{})
+			},
+		},
+		{
+			Version: 20,
+			Name:    "add_expenditure_amendments",
+			UpSQL: []string{
+				"ALTER TABLE expenditures ADD COLUMN amendment text",
+			},
+			DownSQL: []string{
+				"ALTER TABLE expenditures DROP COLUMN amendment",
+			},
+		},
+		{
+			Version: 21,
+			Name:    "create_ngo_ratings",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&ratingSnapshotV21{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&ratingSnapshotV21{})
+			},
+		},
+		{
+			Version: 22,
+			Name:    "create_polygon_anchors",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&anchorV22{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&anchorV22{})
+			},
+		},
+		{
+			Version: 23,
+			Name:    "create_pending_polygon_anchors",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&pendingAnchorV23{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&pendingAnchorV23{})
+			},
+		},
+		{
+			Version: 24,
+			Name:    "add_payment_intent_refunds",
+			UpSQL: []string{
+				"ALTER TABLE payment_intents ADD COLUMN refund_id varchar(64) NOT NULL DEFAULT ''",
+			},
+			DownSQL: []string{
+				"ALTER TABLE payment_intents DROP COLUMN refund_id",
+			},
+		},
+	}
+}
+
+// amountColumns lists every monetary column by table
+var amountColumns = []struct{ table, column string }{
+	{"ngos", "total_donations_received"},
+	{"ngos", "total_expenditure_reported"},
+	{"donors", "total_donated"},
+	{"donors", "annual_donation_limit"},
+	{"donations", "amount"},
+	{"donations", "platform_fee"},
+	{"donations", "net_amount"},
+	{"expenditures", "amount"},
+}
+
+// amountsToMinorUnits converts rupee floats to integer paise
+func amountsToMinorUnits(tx *gorm.DB) error {
+	for _, c := range amountColumns {
+		var stmt string
+		switch tx.Dialector.Name() {
+		case "postgres":
+			stmt = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING ROUND(%s * 100)", c.table, c.column, c.column)
+		default:
+			// SQLite keeps REAL affinity on the column, which stores whole
+			// numbers exactly; money.Money scans them back as integers
+			stmt = fmt.Sprintf("UPDATE %s SET %s = ROUND(%s * 100)", c.table, c.column, c.column)
+		}
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	if tx.Dialector.Name() == "postgres" {
+		return tx.Exec("ALTER TABLE donors ALTER COLUMN annual_donation_limit SET DEFAULT 100000000").Error
+	}
+	return nil
+}
+
+// amountsToMajorUnits reverts amountsToMinorUnits
+func amountsToMajorUnits(tx *gorm.DB) error {
+	for _, c := range amountColumns {
+		var stmt string
+		switch tx.Dialector.Name() {
+		case "postgres":
+			stmt = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE double precision USING %s / 100.0", c.table, c.column, c.column)
+		default:
+			stmt = fmt.Sprintf("UPDATE %s SET %s = %s / 100.0", c.table, c.column, c.column)
+		}
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	if tx.Dialector.Name() == "postgres" {
+		return tx.Exec("ALTER TABLE donors ALTER COLUMN annual_donation_limit SET DEFAULT 1000000").Error
+	}
+	return nil
+}
+
+// baselineUser is User as of migration 1
+type baselineUser struct {
+	ID        uint   `gorm:"primaryKey"`
+	Email     string `gorm:"unique;not null"`
+	Password  string `gorm:"not null"`
+	UserType  string `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (baselineUser) TableName() string {
+	return "users"
+}
+
+// baselineNGO is NGOModel as of migration 1
+type baselineNGO struct {
+	ID                       uint         `gorm:"primaryKey"`
+	UserID                   uint         `gorm:"not null"`
+	User                     baselineUser `gorm:"foreignKey:UserID"`
+	NGOID                    string       `gorm:"unique;not null"`
+	Name                     string       `gorm:"not null"`
+	RegistrationNumber       string       `gorm:"unique;not null"`
+	Category                 string       `gorm:"not null"`
+	Rating                   float64      `gorm:"default:5.0"`
+	KYCVerified              bool         `gorm:"default:false"`
+	KYCData                  string       `gorm:"type:text"`
+	TotalDonationsReceived   float64      `gorm:"default:0"`
+	TotalExpenditureReported float64      `gorm:"default:0"`
+	TransparencyScore        int          `gorm:"default:100"`
+	PublicKey                string       `gorm:"not null"`
+	Certificates             string       `gorm:"type:text"`
+	MultiSigSigners          string       `gorm:"type:text"`
+	CreatedAt                time.Time
+	UpdatedAt                time.Time
+}
+
+func (baselineNGO) TableName() string {
+	return "ngos"
+}
+
+// baselineDonor is DonorModel as of migration 1
+type baselineDonor struct {
+	ID                  uint         `gorm:"primaryKey"`
+	UserID              uint         `gorm:"not null"`
+	User                baselineUser `gorm:"foreignKey:UserID"`
+	DonorID             string       `gorm:"unique;not null"`
+	KYCVerified         bool         `gorm:"default:false"`
+	KYCData             string       `gorm:"type:text"`
+	TotalDonated        float64      `gorm:"default:0"`
+	PreferredNGOs       string       `gorm:"type:text"`
+	AnnualDonationLimit float64      `gorm:"default:1000000"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (baselineDonor) TableName() string {
+	return "donors"
+}
+
+// baselineAuditor is AuditorModel as of migration 1
+type baselineAuditor struct {
+	ID                    uint         `gorm:"primaryKey"`
+	UserID                uint         `gorm:"not null"`
+	User                  baselineUser `gorm:"foreignKey:UserID"`
+	AuditorID             string       `gorm:"unique;not null"`
+	Name                  string       `gorm:"not null"`
+	Credentials           string       `gorm:"type:text"`
+	Specializations       string       `gorm:"type:text"`
+	Verified              bool         `gorm:"default:false"`
+	Rating                float64      `gorm:"default:5.0"`
+	PublicKey             string       `gorm:"not null"`
+	VerificationAuthority string
+	VerificationDate      *time.Time
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+func (baselineAuditor) TableName() string {
+	return "auditors"
+}
+
+// baselineDonation is DonationModel as of migration 1
+type baselineDonation struct {
+	ID            uint    `gorm:"primaryKey"`
+	TransactionID string  `gorm:"unique;not null"`
+	DonorID       string  `gorm:"not null"`
+	NGOID         string  `gorm:"not null"`
+	Amount        float64 `gorm:"not null"`
+	PlatformFee   float64 `gorm:"default:0"`
+	NetAmount     float64 `gorm:"not null"`
+	PaymentMethod string  `gorm:"not null"`
+	Status        string  `gorm:"not null;default:pending"`
+	BlockHash     string
+	PolygonTxHash string
+	ZKProofData   string `gorm:"type:text"`
+	EBillData     string `gorm:"type:text"`
+	TaxBenefit    string `gorm:"type:text"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   *time.Time
+}
+
+func (baselineDonation) TableName() string {
+	return "donations"
+}
+
+// baselineExpenditure is ExpenditureModel as of migration 1
+type baselineExpenditure struct {
+	ID                uint    `gorm:"primaryKey"`
+	TransactionID     string  `gorm:"unique;not null"`
+	NGOID             string  `gorm:"not null"`
+	Amount            float64 `gorm:"not null"`
+	Category          string  `gorm:"not null"`
+	Description       string  `gorm:"type:text"`
+	Status            string  `gorm:"not null;default:pending_validation"`
+	InvoiceDetails    string  `gorm:"type:text"`
+	Attachments       string  `gorm:"type:text"`
+	AuditorValidation string  `gorm:"type:text"`
+	ComplianceScore   float64 `gorm:"default:0"`
+	BlockHash         string
+	PolygonTxHash     string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (baselineExpenditure) TableName() string {
+	return "expenditures"
+}
+
+// baselineAudit is AuditModel as of migration 1
+type baselineAudit struct {
+	ID              uint    `gorm:"primaryKey"`
+	AuditID         string  `gorm:"unique;not null"`
+	ExpenditureID   string  `gorm:"not null"`
+	AuditorID       string  `gorm:"not null"`
+	ComplianceScore float64 `gorm:"not null"`
+	Findings        string  `gorm:"type:text"`
+	Recommendation  string  `gorm:"not null"`
+	AuditNotes      string  `gorm:"type:text"`
+	Signature       string  `gorm:"not null"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (baselineAudit) TableName() string {
+	return "audits"
+}
+
+// baselineBlock is BlockchainBlockModel as of migration 1
+type baselineBlock struct {
+	ID            uint   `gorm:"primaryKey"`
+	Index         int    `gorm:"not null"`
+	Hash          string `gorm:"unique;not null"`
+	PreviousHash  string `gorm:"not null"`
+	BlockType     string `gorm:"not null;index:idx_blocks_chain"`
+	NGOID         string `gorm:"not null;index:idx_blocks_chain"`
+	Data          string `gorm:"type:text"`
+	MerkleRoot    string `gorm:"not null"`
+	Nonce         int    `gorm:"default:0"`
+	Validated     bool   `gorm:"default:false"`
+	Validators    string `gorm:"type:text"`
+	TimestampNano int64  `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (baselineBlock) TableName() string {
+	return "blockchain_blocks"
+}
+
+// journalEntryV4 is JournalEntryModel as of migration 4
+type journalEntryV4 struct {
+	ID          uint   `gorm:"primaryKey"`
+	EntryID     string `gorm:"unique;not null"`
+	NGOID       string `gorm:"not null;index"`
+	Reference   string `gorm:"index"`
+	Description string
+	Lines       []journalLineV4 `gorm:"foreignKey:EntryID;references:EntryID"`
+	PostedAt    time.Time       `gorm:"not null"`
+	CreatedAt   time.Time
+}
+
+func (journalEntryV4) TableName() string {
+	return "journal_entries"
+}
+
+// journalLineV4 is JournalLineModel as of migration 4
+type journalLineV4 struct {
+	ID          uint   `gorm:"primaryKey"`
+	EntryID     string `gorm:"not null;index"`
+	Position    int    `gorm:"not null"`
+	AccountCode string `gorm:"not null"`
+	Debit       int64  `gorm:"type:bigint;not null;default:0"`
+	Credit      int64  `gorm:"type:bigint;not null;default:0"`
+}
+
+func (journalLineV4) TableName() string {
+	return "journal_lines"
+}
+
+// paymentIntentV5 is PaymentIntentModel as of migration 5
+type paymentIntentV5 struct {
+	ID               uint   `gorm:"primaryKey"`
+	IntentID         string `gorm:"unique;not null"`
+	DonorID          string `gorm:"not null;index"`
+	NGOID            string `gorm:"not null"`
+	Amount           int64  `gorm:"type:bigint;not null"`
+	PlatformFee      int64  `gorm:"type:bigint;not null"`
+	PaymentMethod    string `gorm:"not null"`
+	Status           string `gorm:"not null;default:created"`
+	Gateway          string `gorm:"not null"`
+	GatewayOrderID   string `gorm:"unique;not null"`
+	GatewayPaymentID string
+	CheckoutURL      string
+	CollectRequestID string
+	TransactionID    string `gorm:"not null"`
+	BlockHash        string
+	DonationData     string `gorm:"type:text"`
+	FailureReason    string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (paymentIntentV5) TableName() string {
+	return "payment_intents"
+}
+
+// donationReversalV6 is DonationReversalModel as of migration 6
+type donationReversalV6 struct {
+	ID                uint   `gorm:"primaryKey"`
+	ReversalID        string `gorm:"unique;not null"`
+	Kind              string `gorm:"not null"`
+	TransactionID     string `gorm:"not null;index"`
+	DonorID           string `gorm:"not null"`
+	NGOID             string `gorm:"not null;index"`
+	GrossAmount       int64  `gorm:"type:bigint;not null"`
+	PlatformFee       int64  `gorm:"type:bigint;not null"`
+	NetAmount         int64  `gorm:"type:bigint;not null"`
+	Reason            string `gorm:"type:text"`
+	GatewayReference  string `gorm:"index"`
+	OriginalBlockHash string `gorm:"not null"`
+	BlockHash         string `gorm:"not null"`
+	VoidedBillID      string
+	ReissuedEBill     string `gorm:"type:text"`
+	CreatedAt         time.Time
+}
+
+func (donationReversalV6) TableName() string {
+	return "donation_reversals"
+}
+
+// idempotencyKeyV7 is IdempotencyKeyModel as of migration 7
+type idempotencyKeyV7 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Key          string `gorm:"unique;not null"`
+	RequestHash  string `gorm:"not null"`
+	StatusCode   int
+	ResponseBody string    `gorm:"type:text"`
+	Completed    bool      `gorm:"default:false"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (idempotencyKeyV7) TableName() string {
+	return "idempotency_keys"
+}
+
+// campaignV8 is CampaignModel as of migration 8
+type campaignV8 struct {
+	ID           uint      `gorm:"primaryKey"`
+	CampaignID   string    `gorm:"unique;not null"`
+	NGOID        string    `gorm:"not null;index"`
+	Name         string    `gorm:"not null"`
+	Description  string    `gorm:"type:text"`
+	GoalAmount   int64     `gorm:"type:bigint;not null"`
+	RaisedAmount int64     `gorm:"type:bigint;not null;default:0"`
+	SpentAmount  int64     `gorm:"type:bigint;not null;default:0"`
+	Status       string    `gorm:"not null;default:active"`
+	StartDate    time.Time `gorm:"not null"`
+	EndDate      *time.Time
+	ClosedAt     *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (campaignV8) TableName() string {
+	return "campaigns"
+}
+
+// recurringMandateV10 is RecurringMandateModel as of migration 10
+type recurringMandateV10 struct {
+	ID             uint   `gorm:"primaryKey"`
+	MandateID      string `gorm:"unique;not null"`
+	DonorID        string `gorm:"not null;index"`
+	NGOID          string `gorm:"not null;index"`
+	CampaignID     string
+	Amount         int64     `gorm:"type:bigint;not null"`
+	PaymentMethod  string    `gorm:"not null"`
+	Frequency      string    `gorm:"not null"`
+	Status         string    `gorm:"not null;default:active;index"`
+	StartDate      time.Time `gorm:"not null"`
+	EndDate        *time.Time
+	Installment    int       `gorm:"not null;default:1"`
+	NextChargeDate time.Time `gorm:"not null"`
+	RetryAt        *time.Time
+	FailedAttempts int    `gorm:"not null;default:0"`
+	LastError      string `gorm:"type:text"`
+	ChargedCount   int    `gorm:"not null;default:0"`
+	MissedCount    int    `gorm:"not null;default:0"`
+	LastChargedAt  *time.Time
+	PausedAt       *time.Time
+	CancelledAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (recurringMandateV10) TableName() string {
+	return "recurring_mandates"
+}
+
+// corporateProfileV11 is CorporateProfileModel as of migration 11
+type corporateProfileV11 struct {
+	ID                 uint   `gorm:"primaryKey"`
+	DonorID            string `gorm:"unique;not null"`
+	CompanyName        string `gorm:"not null"`
+	CIN                string
+	MatchRatio         float64 `gorm:"not null;default:0"`
+	PerEmployeeCap     int64   `gorm:"type:bigint;not null;default:0"`
+	EligibleCategories string  `gorm:"type:text"`
+	MatchingActive     bool    `gorm:"not null;default:false"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (corporateProfileV11) TableName() string {
+	return "corporate_profiles"
+}
+
+// corporateEmployeeV11 is CorporateEmployeeModel as of migration 11
+type corporateEmployeeV11 struct {
+	ID          uint   `gorm:"primaryKey"`
+	CorporateID string `gorm:"not null;index"`
+	DonorID     string `gorm:"unique;not null"`
+	CreatedAt   time.Time
+}
+
+func (corporateEmployeeV11) TableName() string {
+	return "corporate_employees"
+}
+
+// vendorV12 is VendorModel as of migration 12
+type vendorV12 struct {
+	ID           uint   `gorm:"primaryKey"`
+	GSTIN        string `gorm:"unique;not null"`
+	LegalName    string `gorm:"not null"`
+	StateCode    string `gorm:"not null"`
+	PAN          string `gorm:"not null;index"`
+	RegisteredBy string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (vendorV12) TableName() string {
+	return "vendors"
+}
+
+// auditorConflictV15 is AuditorConflictModel as of migration 15
+type auditorConflictV15 struct {
+	ID        uint   `gorm:"primaryKey"`
+	AuditorID string `gorm:"not null;uniqueIndex:idx_auditor_conflicts_pair"`
+	NGOID     string `gorm:"not null;uniqueIndex:idx_auditor_conflicts_pair"`
+	Reason    string `gorm:"type:text;not null"`
+	CreatedAt time.Time
+}
+
+func (auditorConflictV15) TableName() string {
+	return "auditor_conflicts"
+}
+
+// auditorAssignmentV15 is AuditorAssignmentModel as of migration 15
+type auditorAssignmentV15 struct {
+	ID            uint   `gorm:"primaryKey"`
+	ExpenditureID string `gorm:"not null;index"`
+	NGOID         string `gorm:"not null;index"`
+	AuditorID     string `gorm:"not null;index"`
+	Category      string
+	Specialist    bool
+	Reason        string `gorm:"type:text"`
+	CreatedAt     time.Time
+}
+
+func (auditorAssignmentV15) TableName() string {
+	return "auditor_assignments"
+}
+
+// documentV19 is DocumentModel as of migration 19
+type documentV19 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Hash      string `gorm:"not null;uniqueIndex:idx_documents_hash_owner"`
+	OwnerID   string `gorm:"not null;uniqueIndex:idx_documents_hash_owner"`
+	OwnerType string `gorm:"not null"`
+	Filename  string `gorm:"not null"`
+	MIMEType  string `gorm:"not null"`
+	Size      int64  `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (documentV19) TableName() string {
+	return "documents"
+}
+
+// ratingSnapshotV21 is RatingSnapshotModel as of migration 21
+type ratingSnapshotV21 struct {
+	ID                uint    `gorm:"primaryKey"`
+	NGOID             string  `gorm:"not null;index"`
+	Model             string  `gorm:"not null"`
+	Rating            float64 `gorm:"not null"`
+	TransparencyScore int     `gorm:"not null"`
+	PeriodDays        int     `gorm:"not null"`
+	Factors           string  `gorm:"type:text"`
+	CreatedAt         time.Time
+}
+
+func (ratingSnapshotV21) TableName() string {
+	return "ngo_ratings"
+}
+
+// anchorV22 is AnchorModel as of migration 22
+type anchorV22 struct {
+	ID            uint   `gorm:"primaryKey"`
+	BlockHash     string `gorm:"unique;not null"`
+	NGOID         string `gorm:"not null;index"`
+	ChainType     string `gorm:"not null"`
+	PolygonTxHash string `gorm:"not null"`
+	DataHash      string
+	BlockNumber   int64
+	GasUsed       int64
+	Confirmations int
+	MaxFeeGwei    float64
+	GasPriceGwei  float64
+	CostWei       string `gorm:"not null"`
+	CostMATIC     float64
+	CostINR       float64
+	AnchoredAt    time.Time `gorm:"not null;index"`
+}
+
+func (anchorV22) TableName() string {
+	return "polygon_anchors"
+}
+
+// pendingAnchorV23 is PendingAnchorModel as of migration 23
+type pendingAnchorV23 struct {
+	ID            uint   `gorm:"primaryKey"`
+	BlockHash     string `gorm:"unique;not null"`
+	NGOID         string `gorm:"not null;index"`
+	ChainType     string `gorm:"not null"`
+	TransactionID string `gorm:"not null"`
+	AnchorData    string `gorm:"type:text"`
+	Reason        string `gorm:"type:text"`
+	Attempts      int    `gorm:"not null;default:0"`
+	LastAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+func (pendingAnchorV23) TableName() string {
+	return "pending_polygon_anchors"
+}
@@ -3,6 +3,8 @@ package database
 import (
 	"time"
 	"encoding/json"
+
+	"ngo-transparency-platform/pkg/money"
 )
 
 // User represents the base user model
@@ -17,39 +19,39 @@ type User struct {
 
 // NGOModel represents the database model for NGOs
 type NGOModel struct {
-	ID                     uint      `json:"id" gorm:"primaryKey"`
-	UserID                 uint      `json:"user_id" gorm:"not null"`
-	User                   User      `json:"user" gorm:"foreignKey:UserID"`
-	NGOID                  string    `json:"ngo_id" gorm:"unique;not null"`
-	Name                   string    `json:"name" gorm:"not null"`
-	RegistrationNumber     string    `json:"registration_number" gorm:"unique;not null"`
-	Category               string    `json:"category" gorm:"not null"`
-	Rating                 float64   `json:"rating" gorm:"default:5.0"`
-	KYCVerified            bool      `json:"kyc_verified" gorm:"default:false"`
-	KYCData                string    `json:"kyc_data" gorm:"type:text"` // JSON string
-	TotalDonationsReceived float64   `json:"total_donations_received" gorm:"default:0"`
-	TotalExpenditureReported float64 `json:"total_expenditure_reported" gorm:"default:0"`
-	TransparencyScore      int       `json:"transparency_score" gorm:"default:100"`
-	PublicKey              string    `json:"public_key" gorm:"not null"`
-	Certificates           string    `json:"certificates" gorm:"type:text"` // JSON string
-	MultiSigSigners        string    `json:"multisig_signers" gorm:"type:text"` // JSON string
-	CreatedAt              time.Time `json:"created_at"`
-	UpdatedAt              time.Time `json:"updated_at"`
+	ID                       uint        `json:"id" gorm:"primaryKey"`
+	UserID                   uint        `json:"user_id" gorm:"not null"`
+	User                     User        `json:"user" gorm:"foreignKey:UserID"`
+	NGOID                    string      `json:"ngo_id" gorm:"unique;not null"`
+	Name                     string      `json:"name" gorm:"not null"`
+	RegistrationNumber       string      `json:"registration_number" gorm:"unique;not null"`
+	Category                 string      `json:"category" gorm:"not null"`
+	Rating                   float64     `json:"rating" gorm:"default:5.0"`
+	KYCVerified              bool        `json:"kyc_verified" gorm:"default:false"`
+	KYCData                  string      `json:"kyc_data" gorm:"type:text"`                   // JSON string
+	TotalDonationsReceived   money.Money `json:"total_donations_received" gorm:"default:0"`   // Paise
+	TotalExpenditureReported money.Money `json:"total_expenditure_reported" gorm:"default:0"` // Paise
+	TransparencyScore        int         `json:"transparency_score" gorm:"default:100"`
+	PublicKey                string      `json:"public_key" gorm:"not null"`
+	Certificates             string      `json:"certificates" gorm:"type:text"`     // JSON string
+	MultiSigSigners          string      `json:"multisig_signers" gorm:"type:text"` // JSON string
+	CreatedAt                time.Time   `json:"created_at"`
+	UpdatedAt                time.Time   `json:"updated_at"`
 }
 
 // DonorModel represents the database model for Donors
 type DonorModel struct {
-	ID                  uint      `json:"id" gorm:"primaryKey"`
-	UserID              uint      `json:"user_id" gorm:"not null"`
-	User                User      `json:"user" gorm:"foreignKey:UserID"`
-	DonorID             string    `json:"donor_id" gorm:"unique;not null"`
-	KYCVerified         bool      `json:"kyc_verified" gorm:"default:false"`
-	KYCData             string    `json:"kyc_data" gorm:"type:text"` // JSON string
-	TotalDonated        float64   `json:"total_donated" gorm:"default:0"`
-	PreferredNGOs       string    `json:"preferred_ngos" gorm:"type:text"` // JSON string
-	AnnualDonationLimit float64   `json:"annual_donation_limit" gorm:"default:1000000"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                  uint        `json:"id" gorm:"primaryKey"`
+	UserID              uint        `json:"user_id" gorm:"not null"`
+	User                User        `json:"user" gorm:"foreignKey:UserID"`
+	DonorID             string      `json:"donor_id" gorm:"unique;not null"`
+	KYCVerified         bool        `json:"kyc_verified" gorm:"default:false"`
+	KYCData             string      `json:"kyc_data" gorm:"type:text"`                      // JSON string
+	TotalDonated        money.Money `json:"total_donated" gorm:"default:0"`                 // Paise
+	PreferredNGOs       string      `json:"preferred_ngos" gorm:"type:text"`                // JSON string
+	AnnualDonationLimit money.Money `json:"annual_donation_limit" gorm:"default:100000000"` // Paise, 10 lakh
+	CreatedAt           time.Time   `json:"created_at"`
+	UpdatedAt           time.Time   `json:"updated_at"`
 }
 
 // AuditorModel represents the database model for Auditors
@@ -72,42 +74,58 @@ type AuditorModel struct {
 
 // DonationModel represents the database model for Donations
 type DonationModel struct {
-	ID            uint      `json:"id" gorm:"primaryKey"`
-	TransactionID string    `json:"transaction_id" gorm:"unique;not null"`
-	DonorID       string    `json:"donor_id" gorm:"not null"`
-	NGOID         string    `json:"ngo_id" gorm:"not null"`
-	Amount        float64   `json:"amount" gorm:"not null"`
-	PlatformFee   float64   `json:"platform_fee" gorm:"default:0"`
-	NetAmount     float64   `json:"net_amount" gorm:"not null"`
-	PaymentMethod string    `json:"payment_method" gorm:"not null"`
-	Status        string    `json:"status" gorm:"not null;default:pending"`
-	BlockHash     string    `json:"block_hash"`
-	PolygonTxHash string    `json:"polygon_tx_hash"`
-	ZKProofData   string    `json:"zk_proof_data" gorm:"type:text"` // JSON string
-	EBillData     string    `json:"e_bill_data" gorm:"type:text"` // JSON string
-	TaxBenefit    string    `json:"tax_benefit" gorm:"type:text"` // JSON string
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	CompletedAt   *time.Time `json:"completed_at"`
+	ID             uint        `json:"id" gorm:"primaryKey"`
+	TransactionID  string      `json:"transaction_id" gorm:"unique;not null"`
+	DonorID        string      `json:"donor_id" gorm:"not null"`
+	NGOID          string      `json:"ngo_id" gorm:"not null"`
+	Amount         money.Money `json:"amount" gorm:"not null"`                    // Gross, paise
+	PlatformFee    money.Money `json:"platform_fee" gorm:"default:0"`             // Paise
+	NetAmount      money.Money `json:"net_amount" gorm:"not null"`                // Paise
+	ReversedAmount money.Money `json:"reversed_amount" gorm:"not null;default:0"` // Gross refunded or charged back, paise
+	ReversedFee    money.Money `json:"reversed_fee" gorm:"not null;default:0"`    // Platform fee returned, paise
+	PaymentMethod  string      `json:"payment_method" gorm:"not null"`
+	Status         string      `json:"status" gorm:"not null;default:pending"`
+	BlockHash      string      `json:"block_hash"`
+	PolygonTxHash  string      `json:"polygon_tx_hash"`
+	ZKProofData    string      `json:"zk_proof_data" gorm:"type:text"` // JSON string
+	EBillData      string      `json:"e_bill_data" gorm:"type:text"`   // JSON string
+	TaxBenefit     string      `json:"tax_benefit" gorm:"type:text"`   // JSON string
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	CompletedAt    *time.Time  `json:"completed_at"`
+	CampaignID     string      `json:"campaign_id" gorm:"index"` // Empty for unrestricted donations
+	MandateID      string      `json:"mandate_id" gorm:"index"`  // Empty for one-off donations
+	MatchFor       string      `json:"match_for" gorm:"index"`   // Employee donation a corporate donation matches
 }
 
 // ExpenditureModel represents the database model for Expenditures
 type ExpenditureModel struct {
-	ID                uint      `json:"id" gorm:"primaryKey"`
-	TransactionID     string    `json:"transaction_id" gorm:"unique;not null"`
-	NGOID             string    `json:"ngo_id" gorm:"not null"`
-	Amount            float64   `json:"amount" gorm:"not null"`
-	Category          string    `json:"category" gorm:"not null"`
-	Description       string    `json:"description" gorm:"type:text"`
-	Status            string    `json:"status" gorm:"not null;default:pending_validation"`
-	InvoiceDetails    string    `json:"invoice_details" gorm:"type:text"` // JSON string
-	Attachments       string    `json:"attachments" gorm:"type:text"` // JSON string
-	AuditorValidation string    `json:"auditor_validation" gorm:"type:text"` // JSON string
-	ComplianceScore   float64   `json:"compliance_score" gorm:"default:0"`
-	BlockHash         string    `json:"block_hash"`
-	PolygonTxHash     string    `json:"polygon_tx_hash"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                     uint        `json:"id" gorm:"primaryKey"`
+	TransactionID          string      `json:"transaction_id" gorm:"unique;not null"`
+	NGOID                  string      `json:"ngo_id" gorm:"not null"`
+	Amount                 money.Money `json:"amount" gorm:"not null"` // Paise
+	Category               string      `json:"category" gorm:"not null"`
+	Description            string      `json:"description" gorm:"type:text"`
+	Status                 string      `json:"status" gorm:"not null;default:pending_validation"`
+	InvoiceDetails         string      `json:"invoice_details" gorm:"type:text"`    // JSON string
+	Attachments            string      `json:"attachments" gorm:"type:text"`        // JSON string
+	AuditorValidation      string      `json:"auditor_validation" gorm:"type:text"` // JSON string
+	ComplianceScore        float64     `json:"compliance_score" gorm:"default:0"`
+	BlockHash              string      `json:"block_hash"`
+	PolygonTxHash          string      `json:"polygon_tx_hash"`
+	CreatedAt              time.Time   `json:"created_at"`
+	UpdatedAt              time.Time   `json:"updated_at"`
+	CampaignID             string      `json:"campaign_id" gorm:"index"`       // Empty for expenditures from unrestricted funds
+	Funding                string      `json:"funding" gorm:"type:text"`       // JSON string
+	InvoiceFlags           string      `json:"invoice_flags" gorm:"type:text"` // JSON string
+	AssignedAuditorID      string      `json:"assigned_auditor_id" gorm:"index"`
+	Reviews                string      `json:"reviews" gorm:"type:text"` // JSON string
+	Panel                  string      `json:"panel" gorm:"type:text"`   // JSON string
+	RequiredApprovals      int         `json:"required_approvals" gorm:"default:0"`
+	Consensus              string      `json:"consensus" gorm:"type:text"` // JSON string
+	ComplianceRulesVersion string      `json:"compliance_rules_version"`   // Rules the compliance score was calculated under
+	Appeal                 string      `json:"appeal" gorm:"type:text"`    // JSON string
+	Amendment              string      `json:"amendment" gorm:"type:text"` // JSON string
 }
 
 // AuditModel represents the database model for Audits
@@ -120,6 +138,8 @@ type AuditModel struct {
 	Findings        string    `json:"findings" gorm:"type:text"` // JSON string
 	Recommendation  string    `json:"recommendation" gorm:"not null"`
 	AuditNotes      string    `json:"audit_notes" gorm:"type:text"`
+	Decision        string    `json:"decision"`      // approve or reject; empty for automated audits
+	RulesVersion    string    `json:"rules_version"` // Compliance rules behind the automated score
 	Signature       string    `json:"signature" gorm:"not null"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
@@ -127,17 +147,260 @@ type AuditModel struct {
 
 // BlockchainBlockModel represents blockchain blocks in database
 type BlockchainBlockModel struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	Index         int       `json:"index" gorm:"not null"`
+	Hash          string    `json:"hash" gorm:"unique;not null"`
+	PreviousHash  string    `json:"previous_hash" gorm:"not null"`
+	BlockType     string    `json:"block_type" gorm:"not null;index:idx_blocks_chain"` // donation, expenditure
+	NGOID         string    `json:"ngo_id" gorm:"not null;index:idx_blocks_chain"`
+	Data          string    `json:"data" gorm:"type:text"` // JSON string
+	MerkleRoot    string    `json:"merkle_root" gorm:"not null"`
+	Nonce         int       `json:"nonce" gorm:"default:0"`
+	Validated     bool      `json:"validated" gorm:"default:false"`
+	Validators    string    `json:"validators" gorm:"type:text"`              // JSON string
+	TimestampNano int64     `json:"timestamp_nano" gorm:"not null;default:0"` // Block timestamp, needed to recompute the hash
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// JournalEntryModel represents a posted double-entry journal entry
+type JournalEntryModel struct {
+	ID          uint               `json:"id" gorm:"primaryKey"`
+	EntryID     string             `json:"entry_id" gorm:"unique;not null"`
+	NGOID       string             `json:"ngo_id" gorm:"not null;index"`
+	Reference   string             `json:"reference" gorm:"index"` // Source transaction ID
+	Description string             `json:"description"`
+	Lines       []JournalLineModel `json:"lines" gorm:"foreignKey:EntryID;references:EntryID"`
+	PostedAt    time.Time          `json:"posted_at" gorm:"not null"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// JournalLineModel represents one debit or credit of a journal entry
+type JournalLineModel struct {
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	EntryID     string      `json:"entry_id" gorm:"not null;index"`
+	Position    int         `json:"position" gorm:"not null"`
+	AccountCode string      `json:"account_code" gorm:"not null"`
+	Debit       money.Money `json:"debit" gorm:"not null;default:0"`  // Paise
+	Credit      money.Money `json:"credit" gorm:"not null;default:0"` // Paise
+}
+
+// PaymentIntentModel represents a donation awaiting confirmation from the payment gateway
+type PaymentIntentModel struct {
+	ID               uint        `json:"id" gorm:"primaryKey"`
+	IntentID         string      `json:"intent_id" gorm:"unique;not null"`
+	DonorID          string      `json:"donor_id" gorm:"not null;index"`
+	NGOID            string      `json:"ngo_id" gorm:"not null"`
+	Amount           money.Money `json:"amount" gorm:"not null"`       // Gross, paise
+	PlatformFee      money.Money `json:"platform_fee" gorm:"not null"` // Paise
+	PaymentMethod    string      `json:"payment_method" gorm:"not null"`
+	Status           string      `json:"status" gorm:"not null;default:created"`
+	Gateway          string      `json:"gateway" gorm:"not null"`
+	GatewayOrderID   string      `json:"gateway_order_id" gorm:"unique;not null"`
+	GatewayPaymentID string      `json:"gateway_payment_id"`
+	CheckoutURL      string      `json:"checkout_url"`
+	CollectRequestID string      `json:"collect_request_id"`
+	TransactionID    string      `json:"transaction_id" gorm:"not null"`
+	BlockHash        string      `json:"block_hash"`
+	DonationData     string      `json:"donation_data" gorm:"type:text"` // JSON string of the pending donation
+	FailureReason    string      `json:"failure_reason"`
+	RefundID         string      `json:"refund_id"` // Gateway refund of a captured payment that was not accepted
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// DonationReversalModel represents a refund or chargeback of a donation
+type DonationReversalModel struct {
+	ID                uint        `json:"id" gorm:"primaryKey"`
+	ReversalID        string      `json:"reversal_id" gorm:"unique;not null"`
+	Kind              string      `json:"kind" gorm:"not null"` // refund, chargeback
+	TransactionID     string      `json:"transaction_id" gorm:"not null;index"`
+	DonorID           string      `json:"donor_id" gorm:"not null"`
+	NGOID             string      `json:"ngo_id" gorm:"not null;index"`
+	GrossAmount       money.Money `json:"gross_amount" gorm:"not null"` // Paise
+	PlatformFee       money.Money `json:"platform_fee" gorm:"not null"` // Paise
+	NetAmount         money.Money `json:"net_amount" gorm:"not null"`   // Paise
+	Reason            string      `json:"reason" gorm:"type:text"`
+	GatewayReference  string      `json:"gateway_reference" gorm:"index"`
+	OriginalBlockHash string      `json:"original_block_hash" gorm:"not null"`
+	BlockHash         string      `json:"block_hash" gorm:"not null"`
+	VoidedBillID      string      `json:"voided_bill_id"`
+	ReissuedEBill     string      `json:"reissued_e_bill" gorm:"type:text"` // JSON string
+	CreatedAt         time.Time   `json:"created_at"`
+}
+
+// CampaignModel represents an NGO's campaign and its restricted funds
+type CampaignModel struct {
+	ID           uint        `json:"id" gorm:"primaryKey"`
+	CampaignID   string      `json:"campaign_id" gorm:"unique;not null"`
+	NGOID        string      `json:"ngo_id" gorm:"not null;index"`
+	Name         string      `json:"name" gorm:"not null"`
+	Description  string      `json:"description" gorm:"type:text"`
+	GoalAmount   money.Money `json:"goal_amount" gorm:"not null"`             // Paise
+	RaisedAmount money.Money `json:"raised_amount" gorm:"not null;default:0"` // Paise
+	SpentAmount  money.Money `json:"spent_amount" gorm:"not null;default:0"`  // Paise
+	Status       string      `json:"status" gorm:"not null;default:active"`
+	StartDate    time.Time   `json:"start_date" gorm:"not null"`
+	EndDate      *time.Time  `json:"end_date"`
+	ClosedAt     *time.Time  `json:"closed_at"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// RecurringMandateModel represents a donor's recurring donation mandate
+type RecurringMandateModel struct {
+	ID             uint        `json:"id" gorm:"primaryKey"`
+	MandateID      string      `json:"mandate_id" gorm:"unique;not null"`
+	DonorID        string      `json:"donor_id" gorm:"not null;index"`
+	NGOID          string      `json:"ngo_id" gorm:"not null;index"`
+	CampaignID     string      `json:"campaign_id"`
+	Amount         money.Money `json:"amount" gorm:"not null"` // Paise
+	PaymentMethod  string      `json:"payment_method" gorm:"not null"`
+	Frequency      string      `json:"frequency" gorm:"not null"` // monthly, quarterly, annual
+	Status         string      `json:"status" gorm:"not null;default:active;index"`
+	StartDate      time.Time   `json:"start_date" gorm:"not null"`
+	EndDate        *time.Time  `json:"end_date"`
+	Installment    int         `json:"installment" gorm:"not null;default:1"`
+	NextChargeDate time.Time   `json:"next_charge_date" gorm:"not null"`
+	RetryAt        *time.Time  `json:"retry_at"`
+	FailedAttempts int         `json:"failed_attempts" gorm:"not null;default:0"`
+	LastError      string      `json:"last_error" gorm:"type:text"`
+	ChargedCount   int         `json:"charged_count" gorm:"not null;default:0"`
+	MissedCount    int         `json:"missed_count" gorm:"not null;default:0"`
+	LastChargedAt  *time.Time  `json:"last_charged_at"`
+	PausedAt       *time.Time  `json:"paused_at"`
+	CancelledAt    *time.Time  `json:"cancelled_at"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// CorporateProfileModel represents the company behind a corporate donor and
+// its rule for matching employee donations
+type CorporateProfileModel struct {
+	ID                 uint        `json:"id" gorm:"primaryKey"`
+	DonorID            string      `json:"donor_id" gorm:"unique;not null"`
+	CompanyName        string      `json:"company_name" gorm:"not null"`
+	CIN                string      `json:"cin"`
+	MatchRatio         float64     `json:"match_ratio" gorm:"not null;default:0"`
+	PerEmployeeCap     money.Money `json:"per_employee_cap" gorm:"not null;default:0"` // Paise per calendar year, 0 for no cap
+	EligibleCategories string      `json:"eligible_categories" gorm:"type:text"`       // JSON string
+	MatchingActive     bool        `json:"matching_active" gorm:"not null;default:false"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+}
+
+// CorporateEmployeeModel links an employee donor to the corporate donor that
+// matches their donations
+type CorporateEmployeeModel struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	CorporateID string    `json:"corporate_id" gorm:"not null;index"`
+	DonorID     string    `json:"donor_id" gorm:"unique;not null"` // A donor has at most one employer
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AuditorConflictModel represents a conflict of interest an auditor declared with an NGO
+type AuditorConflictModel struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	AuditorID string    `json:"auditor_id" gorm:"not null;uniqueIndex:idx_auditor_conflicts_pair"`
+	NGOID     string    `json:"ngo_id" gorm:"not null;uniqueIndex:idx_auditor_conflicts_pair"`
+	Reason    string    `json:"reason" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditorAssignmentModel records an auditor being assigned an expenditure to review
+type AuditorAssignmentModel struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	ExpenditureID string    `json:"expenditure_id" gorm:"not null;index"`
+	NGOID         string    `json:"ngo_id" gorm:"not null;index"`
+	AuditorID     string    `json:"auditor_id" gorm:"not null;index"`
+	Category      string    `json:"category"`
+	Specialist    bool      `json:"specialist"`
+	Reason        string    `json:"reason" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DocumentModel records a file uploaded to the document store. The same
+// contents uploaded by several entities share a stored file.
+type DocumentModel struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Hash      string    `json:"hash" gorm:"not null;uniqueIndex:idx_documents_hash_owner"` // Hex SHA-256 of the contents
+	OwnerID   string    `json:"owner_id" gorm:"not null;uniqueIndex:idx_documents_hash_owner"`
+	OwnerType string    `json:"owner_type" gorm:"not null"`
+	Filename  string    `json:"filename" gorm:"not null"`
+	MIMEType  string    `json:"mime_type" gorm:"not null"`
+	Size      int64     `json:"size" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RatingSnapshotModel records an NGO's rating each time it moves, with the
+// factors it was made of
+type RatingSnapshotModel struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	NGOID             string    `json:"ngo_id" gorm:"not null;index"`
+	Model             string    `json:"model" gorm:"not null"`
+	Rating            float64   `json:"rating" gorm:"not null"`
+	TransparencyScore int       `json:"transparency_score" gorm:"not null"`
+	PeriodDays        int       `json:"period_days" gorm:"not null"`
+	Factors           string    `json:"factors" gorm:"type:text"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// AnchorModel records a block hash anchored to Polygon and what anchoring it cost
+type AnchorModel struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	BlockHash     string    `json:"block_hash" gorm:"unique;not null"`
+	NGOID         string    `json:"ngo_id" gorm:"not null;index"`
+	ChainType     string    `json:"chain_type" gorm:"not null"` // donation, expenditure
+	PolygonTxHash string    `json:"polygon_tx_hash" gorm:"not null"`
+	DataHash      string    `json:"data_hash"`
+	BlockNumber   int64     `json:"block_number"`
+	GasUsed       int64     `json:"gas_used"`
+	Confirmations int       `json:"confirmations"`
+	MaxFeeGwei    float64   `json:"max_fee_gwei"`
+	GasPriceGwei  float64   `json:"gas_price_gwei"`
+	CostWei       string    `json:"cost_wei" gorm:"not null"` // Decimal string, wei overflow int64
+	CostMATIC     float64   `json:"cost_matic"`
+	CostINR       float64   `json:"cost_inr"`
+	AnchoredAt    time.Time `json:"anchored_at" gorm:"not null;index"`
+}
+
+// PendingAnchorModel records a committed block that could not be anchored to
+// Polygon yet, and why, so that anchoring it is retried
+type PendingAnchorModel struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	BlockHash     string    `json:"block_hash" gorm:"unique;not null"`
+	NGOID         string    `json:"ngo_id" gorm:"not null;index"`
+	ChainType     string    `json:"chain_type" gorm:"not null"`     // donation, expenditure
+	TransactionID string    `json:"transaction_id" gorm:"not null"` // Donation or expenditure the block holds
+	AnchorData    string    `json:"anchor_data" gorm:"type:text"`   // JSON data anchored with the block hash
+	Reason        string    `json:"reason" gorm:"type:text"`
+	Attempts      int       `json:"attempts" gorm:"not null;default:0"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// VendorModel represents a GST-registered supplier in the platform-wide vendor registry
+type VendorModel struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	GSTIN        string    `json:"gstin" gorm:"unique;not null"`
+	LegalName    string    `json:"legal_name" gorm:"not null"`
+	StateCode    string    `json:"state_code" gorm:"not null"`
+	PAN          string    `json:"pan" gorm:"not null;index"`
+	RegisteredBy string    `json:"registered_by"` // NGO ID
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// IdempotencyKeyModel stores the response to a request sent with an
+// Idempotency-Key header so retries can be answered without repeating it
+type IdempotencyKeyModel struct {
 	ID           uint      `json:"id" gorm:"primaryKey"`
-	Index        int       `json:"index" gorm:"not null"`
-	Hash         string    `json:"hash" gorm:"unique;not null"`
-	PreviousHash string    `json:"previous_hash" gorm:"not null"`
-	BlockType    string    `json:"block_type" gorm:"not null"` // donation, expenditure
-	NGOID        string    `json:"ngo_id" gorm:"not null"`
-	Data         string    `json:"data" gorm:"type:text"` // JSON string
-	MerkleRoot   string    `json:"merkle_root" gorm:"not null"`
-	Nonce        int       `json:"nonce" gorm:"default:0"`
-	Validated    bool      `json:"validated" gorm:"default:false"`
-	Validators   string    `json:"validators" gorm:"type:text"` // JSON string
+	Key          string    `json:"key" gorm:"unique;not null"` // Hash of the caller, route and client key
+	RequestHash  string    `json:"request_hash" gorm:"not null"`
+	StatusCode   int       `json:"status_code"`
+	ResponseBody string    `json:"response_body" gorm:"type:text"`
+	Completed    bool      `json:"completed" gorm:"default:false"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"not null;index"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -171,6 +434,14 @@ func (BlockchainBlockModel) TableName() string {
 	return "blockchain_blocks"
 }
 
+func (JournalEntryModel) TableName() string {
+	return "journal_entries"
+}
+
+func (JournalLineModel) TableName() string {
+	return "journal_lines"
+}
+
 // Helper methods for JSON marshaling/unmarshaling
 func (n *NGOModel) SetKYCData(data interface{}) error {
 	jsonData, err := json.Marshal(data)
@@ -206,4 +477,60 @@ func (d *DonorModel) GetKYCData() (map[string]interface{}, error) {
 	}
 	err := json.Unmarshal([]byte(d.KYCData), &data)
 	return data, err
-}
\ No newline at end of file
+}
+
+func (PaymentIntentModel) TableName() string {
+	return "payment_intents"
+}
+
+func (DonationReversalModel) TableName() string {
+	return "donation_reversals"
+}
+
+func (IdempotencyKeyModel) TableName() string {
+	return "idempotency_keys"
+}
+
+func (CampaignModel) TableName() string {
+	return "campaigns"
+}
+
+func (RecurringMandateModel) TableName() string {
+	return "recurring_mandates"
+}
+
+func (CorporateProfileModel) TableName() string {
+	return "corporate_profiles"
+}
+
+func (CorporateEmployeeModel) TableName() string {
+	return "corporate_employees"
+}
+
+func (VendorModel) TableName() string {
+	return "vendors"
+}
+
+func (AuditorConflictModel) TableName() string {
+	return "auditor_conflicts"
+}
+
+func (AuditorAssignmentModel) TableName() string {
+	return "auditor_assignments"
+}
+
+func (DocumentModel) TableName() string {
+	return "documents"
+}
+
+func (RatingSnapshotModel) TableName() string {
+	return "ngo_ratings"
+}
+
+func (AnchorModel) TableName() string {
+	return "polygon_anchors"
+}
+
+func (PendingAnchorModel) TableName() string {
+	return "pending_polygon_anchors"
+}
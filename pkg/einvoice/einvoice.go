@@ -0,0 +1,429 @@
+// Package einvoice imports GST e-invoices. Suppliers report each invoice in
+// the INV-01 JSON schema to an Invoice Registration Portal (IRP), which
+// assigns it an Invoice Reference Number (IRN) and returns the invoice and a
+// QR code payload, each signed as an RS256 JWT. A Verifier checks those
+// signatures against the IRP's public key, so an imported invoice is known to
+// have been registered by its supplier exactly as recorded.
+package einvoice
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"ngo-transparency-platform/pkg/gst"
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Dates in e-invoices are Indian Standard Time
+var ist = time.FixedZone("IST", 5*60*60+30*60)
+
+// Layouts of document dates (DocDtls.Dt) and IRP timestamps (AckDt, IrnDt)
+const (
+	documentDateLayout = "02/01/2006"
+	timestampLayout    = "2006-01-02 15:04:05"
+)
+
+// Invoice is an e-invoice in the INV-01 schema, with the fields the platform
+// records. Invoices returned by the IRP also carry their IRN and
+// acknowledgement.
+type Invoice struct {
+	Version    string          `json:"Version"`
+	Irn        string          `json:"Irn,omitempty"`
+	AckNo      json.Number     `json:"AckNo,omitempty"`
+	AckDt      string          `json:"AckDt,omitempty"`
+	TranDtls   TransactionInfo `json:"TranDtls"`
+	DocDtls    DocumentInfo    `json:"DocDtls"`
+	SellerDtls Party           `json:"SellerDtls"`
+	BuyerDtls  Party           `json:"BuyerDtls"`
+	ItemList   []Item          `json:"ItemList"`
+	ValDtls    Values          `json:"ValDtls"`
+}
+
+// TransactionInfo is the INV-01 TranDtls block
+type TransactionInfo struct {
+	TaxSch string `json:"TaxSch"`
+	SupTyp string `json:"SupTyp"`
+}
+
+// DocumentInfo is the INV-01 DocDtls block
+type DocumentInfo struct {
+	Typ string `json:"Typ"` // INV, CRN or DBN
+	No  string `json:"No"`
+	Dt  string `json:"Dt"` // dd/mm/yyyy
+}
+
+// Party is a seller or buyer in the INV-01 schema
+type Party struct {
+	Gstin string `json:"Gstin"`
+	LglNm string `json:"LglNm"`
+	TrdNm string `json:"TrdNm,omitempty"`
+	Addr1 string `json:"Addr1,omitempty"`
+	Loc   string `json:"Loc,omitempty"`
+	Pin   int    `json:"Pin,omitempty"`
+	Stcd  string `json:"Stcd,omitempty"`
+}
+
+// Item is a line of the INV-01 ItemList
+type Item struct {
+	SlNo       string      `json:"SlNo"`
+	PrdDesc    string      `json:"PrdDesc,omitempty"`
+	HsnCd      string      `json:"HsnCd"`
+	Qty        float64     `json:"Qty,omitempty"`
+	Unit       string      `json:"Unit,omitempty"`
+	AssAmt     money.Money `json:"AssAmt"`
+	GstRt      float64     `json:"GstRt"`
+	TotItemVal money.Money `json:"TotItemVal"`
+}
+
+// Values is the INV-01 ValDtls block
+type Values struct {
+	AssVal    money.Money `json:"AssVal"`
+	CgstVal   money.Money `json:"CgstVal"`
+	SgstVal   money.Money `json:"SgstVal"`
+	IgstVal   money.Money `json:"IgstVal"`
+	TotInvVal money.Money `json:"TotInvVal"`
+}
+
+// QRCode is the payload of the QR code printed on an e-invoice
+type QRCode struct {
+	SellerGstin string      `json:"SellerGstin"`
+	BuyerGstin  string      `json:"BuyerGstin"`
+	DocNo       string      `json:"DocNo"`
+	DocTyp      string      `json:"DocTyp"`
+	DocDt       string      `json:"DocDt"`
+	TotInvVal   money.Money `json:"TotInvVal"`
+	ItemCnt     int         `json:"ItemCnt"`
+	MainHsnCode string      `json:"MainHsnCode"`
+	Irn         string      `json:"Irn"`
+	IrnDt       string      `json:"IrnDt"`
+}
+
+// Record is an invoice whose registration with the IRP has been verified
+type Record struct {
+	IRN           string      `json:"irn"`
+	AckNo         string      `json:"ack_no,omitempty"`
+	RegisteredAt  time.Time   `json:"registered_at"` // When the IRP assigned the IRN
+	DocumentType  string      `json:"document_type"`
+	InvoiceNumber string      `json:"invoice_number"`
+	InvoiceDate   time.Time   `json:"invoice_date"`
+	SellerGSTIN   string      `json:"seller_gstin"`
+	SellerName    string      `json:"seller_name,omitempty"` // Not carried by the QR code
+	BuyerGSTIN    string      `json:"buyer_gstin"`
+	Total         money.Money `json:"total"`
+	ItemCount     int         `json:"item_count"`
+	MainHSNCode   string      `json:"main_hsn_code,omitempty"`
+	SignedQRCode  string      `json:"signed_qr_code,omitempty"` // Lets anyone verify the registration again
+}
+
+// Response is the IRP's response to registering an invoice, as suppliers
+// pass it on
+type Response struct {
+	AckNo         json.Number `json:"AckNo"`
+	AckDt         string      `json:"AckDt"`
+	Irn           string      `json:"Irn"`
+	SignedInvoice string      `json:"SignedInvoice"`
+	SignedQRCode  string      `json:"SignedQRCode"`
+}
+
+// Parse decodes and validates an INV-01 e-invoice
+func Parse(data []byte) (*Invoice, error) {
+	var invoice Invoice
+	if err := json.Unmarshal(data, &invoice); err != nil {
+		return nil, fmt.Errorf("invalid e-invoice: %w", err)
+	}
+	if err := invoice.validate(); err != nil {
+		return nil, fmt.Errorf("invalid e-invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// validate checks the fields the platform records
+func (i *Invoice) validate() error {
+	if strings.TrimSpace(i.Version) == "" {
+		return fmt.Errorf("Version is required")
+	}
+	if strings.TrimSpace(i.DocDtls.No) == "" {
+		return fmt.Errorf("DocDtls.No is required")
+	}
+	if _, err := parseDocumentDate(i.DocDtls.Dt); err != nil {
+		return err
+	}
+	if !gst.Valid(i.SellerDtls.Gstin) {
+		return fmt.Errorf("invalid SellerDtls.Gstin %q", i.SellerDtls.Gstin)
+	}
+	if strings.TrimSpace(i.SellerDtls.LglNm) == "" {
+		return fmt.Errorf("SellerDtls.LglNm is required")
+	}
+	if !gst.Valid(i.BuyerDtls.Gstin) {
+		return fmt.Errorf("invalid BuyerDtls.Gstin %q", i.BuyerDtls.Gstin)
+	}
+	if len(i.ItemList) == 0 {
+		return fmt.Errorf("ItemList is empty")
+	}
+	if !i.ValDtls.TotInvVal.IsPositive() {
+		return fmt.Errorf("ValDtls.TotInvVal must be positive")
+	}
+	return nil
+}
+
+// Verifier checks documents signed by the IRP
+type Verifier struct {
+	key *rsa.PublicKey
+}
+
+// NewVerifier returns a Verifier for documents signed with key
+func NewVerifier(key *rsa.PublicKey) *Verifier {
+	return &Verifier{key: key}
+}
+
+// LoadVerifier reads the IRP's public key from a PEM file
+func LoadVerifier(path string) (*Verifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read IRP public key: %w", err)
+	}
+	key, err := ParsePublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(key), nil
+}
+
+// ParsePublicKey parses an RSA public key, or a certificate holding one, in PEM
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid IRP public key: no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "CERTIFICATE":
+		var certificate *x509.Certificate
+		if certificate, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = certificate.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid IRP public key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid IRP public key: not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// VerifyQRCode checks the signature of a QR code payload and decodes it
+func (v *Verifier) VerifyQRCode(token string) (*QRCode, error) {
+	var qr QRCode
+	if err := v.verify(token, &qr); err != nil {
+		return nil, fmt.Errorf("invalid signed QR code: %w", err)
+	}
+	if qr.Irn == "" || qr.DocNo == "" || !gst.Valid(qr.SellerGstin) {
+		return nil, fmt.Errorf("invalid signed QR code: IRN, document number and seller GSTIN are required")
+	}
+	return &qr, nil
+}
+
+// VerifyInvoice checks the signature of a signed invoice and decodes it
+func (v *Verifier) VerifyInvoice(token string) (*Invoice, error) {
+	var invoice Invoice
+	if err := v.verify(token, &invoice); err != nil {
+		return nil, fmt.Errorf("invalid signed invoice: %w", err)
+	}
+	if invoice.Irn == "" {
+		return nil, fmt.Errorf("invalid signed invoice: IRN is required")
+	}
+	if err := invoice.validate(); err != nil {
+		return nil, fmt.Errorf("invalid signed invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// Import verifies an e-invoice and returns its record. The document may be
+// the IRP's response, whose signed invoice is verified, or an INV-01 invoice,
+// which must come with the signed QR code printed on it. The QR code alone
+// also suffices, though it does not name the seller. Whatever is given must
+// describe the same invoice.
+func (v *Verifier) Import(document []byte, signedQRCode string) (*Record, error) {
+	signedQRCode = strings.TrimSpace(signedQRCode)
+
+	var invoice *Invoice
+	if len(strings.TrimSpace(string(document))) > 0 {
+		var response Response
+		if err := json.Unmarshal(document, &response); err != nil {
+			return nil, fmt.Errorf("invalid e-invoice: %w", err)
+		}
+
+		if response.SignedInvoice != "" {
+			signed, err := v.VerifyInvoice(response.SignedInvoice)
+			if err != nil {
+				return nil, err
+			}
+			if response.Irn != "" && response.Irn != signed.Irn {
+				return nil, fmt.Errorf("e-invoice IRN does not match its signed invoice")
+			}
+			invoice = signed
+			if signedQRCode == "" {
+				signedQRCode = response.SignedQRCode
+			}
+		} else {
+			parsed, err := Parse(document)
+			if err != nil {
+				return nil, err
+			}
+			if signedQRCode == "" {
+				return nil, fmt.Errorf("an unsigned e-invoice needs its signed QR code")
+			}
+			invoice = parsed
+		}
+	}
+	if invoice == nil && signedQRCode == "" {
+		return nil, fmt.Errorf("an e-invoice or signed QR code is required")
+	}
+
+	var qr *QRCode
+	if signedQRCode != "" {
+		var err error
+		if qr, err = v.VerifyQRCode(signedQRCode); err != nil {
+			return nil, err
+		}
+		if invoice != nil {
+			if err := matchQRCode(invoice, qr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return newRecord(invoice, qr, signedQRCode)
+}
+
+// verify checks an IRP token's RS256 signature and decodes the JSON held in
+// its data claim into target
+func (v *Verifier) verify(token string, target interface{}) error {
+	if v == nil || v.key == nil {
+		return fmt.Errorf("no IRP public key configured")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(strings.TrimSpace(token), claims, func(*jwt.Token) (interface{}, error) {
+		return v.key, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		return err
+	}
+
+	data, ok := claims["data"].(string)
+	if !ok {
+		return fmt.Errorf("data claim is missing")
+	}
+	return json.Unmarshal([]byte(data), target)
+}
+
+// matchQRCode checks that a QR code was issued for an invoice
+func matchQRCode(invoice *Invoice, qr *QRCode) error {
+	switch {
+	case invoice.Irn != "" && invoice.Irn != qr.Irn:
+		return fmt.Errorf("QR code IRN does not match the e-invoice")
+	case !strings.EqualFold(invoice.SellerDtls.Gstin, qr.SellerGstin):
+		return fmt.Errorf("QR code seller GSTIN does not match the e-invoice")
+	case !strings.EqualFold(invoice.BuyerDtls.Gstin, qr.BuyerGstin):
+		return fmt.Errorf("QR code buyer GSTIN does not match the e-invoice")
+	case invoice.DocDtls.No != qr.DocNo || invoice.DocDtls.Dt != qr.DocDt:
+		return fmt.Errorf("QR code document number or date does not match the e-invoice")
+	case !invoice.ValDtls.TotInvVal.Equal(qr.TotInvVal):
+		return fmt.Errorf("QR code total does not match the e-invoice")
+	}
+	return nil
+}
+
+// newRecord builds the record of a verified invoice, its QR code or both
+func newRecord(invoice *Invoice, qr *QRCode, signedQRCode string) (*Record, error) {
+	record := &Record{SignedQRCode: signedQRCode}
+	if invoice != nil {
+		record.IRN = invoice.Irn
+		record.AckNo = invoice.AckNo.String()
+		record.DocumentType = invoice.DocDtls.Typ
+		record.InvoiceNumber = invoice.DocDtls.No
+		record.SellerGSTIN = invoice.SellerDtls.Gstin
+		record.SellerName = invoice.SellerDtls.LglNm
+		record.BuyerGSTIN = invoice.BuyerDtls.Gstin
+		record.Total = invoice.ValDtls.TotInvVal
+		record.ItemCount = len(invoice.ItemList)
+		record.MainHSNCode = invoice.ItemList[0].HsnCd
+
+		date, err := parseDocumentDate(invoice.DocDtls.Dt)
+		if err != nil {
+			return nil, err
+		}
+		record.InvoiceDate = date
+		if invoice.AckDt != "" {
+			if record.RegisteredAt, err = parseTimestamp(invoice.AckDt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if qr != nil {
+		if record.IRN == "" {
+			record.IRN = qr.Irn
+			record.DocumentType = qr.DocTyp
+			record.InvoiceNumber = qr.DocNo
+			record.SellerGSTIN = qr.SellerGstin
+			record.BuyerGSTIN = qr.BuyerGstin
+			record.Total = qr.TotInvVal
+			record.ItemCount = qr.ItemCnt
+			record.MainHSNCode = qr.MainHsnCode
+
+			date, err := parseDocumentDate(qr.DocDt)
+			if err != nil {
+				return nil, err
+			}
+			record.InvoiceDate = date
+		}
+		if qr.IrnDt != "" {
+			registeredAt, err := parseTimestamp(qr.IrnDt)
+			if err != nil {
+				return nil, err
+			}
+			record.RegisteredAt = registeredAt
+		}
+	}
+
+	if record.IRN == "" {
+		return nil, fmt.Errorf("e-invoice has not been registered: IRN is missing")
+	}
+	record.SellerGSTIN = strings.ToUpper(record.SellerGSTIN)
+	record.BuyerGSTIN = strings.ToUpper(record.BuyerGSTIN)
+	return record, nil
+}
+
+func parseDocumentDate(s string) (time.Time, error) {
+	date, err := time.ParseInLocation(documentDateLayout, strings.TrimSpace(s), ist)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid document date %q: expected dd/mm/yyyy", s)
+	}
+	return date, nil
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	timestamp, err := time.ParseInLocation(timestampLayout, strings.TrimSpace(s), ist)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid IRP timestamp %q", s)
+	}
+	return timestamp, nil
+}
@@ -0,0 +1,199 @@
+package einvoice
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"ngo-transparency-platform/pkg/money"
+)
+
+const sampleInvoice = `{
+	"Version": "1.1",
+	"TranDtls": {"TaxSch": "GST", "SupTyp": "B2B"},
+	"DocDtls": {"Typ": "INV", "No": "VND/2024/118", "Dt": "15/03/2024"},
+	"SellerDtls": {"Gstin": "27AAPFU0939F1ZV", "LglNm": "Pune Stationers Pvt Ltd", "Loc": "Pune", "Pin": 411001, "Stcd": "27"},
+	"BuyerDtls": {"Gstin": "29AAGCB7383J1Z4", "LglNm": "Bright Futures Foundation", "Stcd": "29"},
+	"ItemList": [
+		{"SlNo": "1", "PrdDesc": "Notebooks", "HsnCd": "4820", "Qty": 100, "AssAmt": 10000, "GstRt": 18, "TotItemVal": 11800}
+	],
+	"ValDtls": {"AssVal": 10000, "IgstVal": 1800, "TotInvVal": 11800}
+}`
+
+const sampleIRN = "a5c12dca80e743321740b1b4d9d4b1a1c8c2a2bb04d3d1b3ad3a7cf2fb0b0c5d"
+
+func sampleQRCode() QRCode {
+	return QRCode{
+		SellerGstin: "27AAPFU0939F1ZV",
+		BuyerGstin:  "29AAGCB7383J1Z4",
+		DocNo:       "VND/2024/118",
+		DocTyp:      "INV",
+		DocDt:       "15/03/2024",
+		TotInvVal:   money.FromMajor(11800),
+		ItemCnt:     1,
+		MainHsnCode: "4820",
+		Irn:         sampleIRN,
+		IrnDt:       "2024-03-15 11:42:00",
+	}
+}
+
+// sign signs payload the way the IRP does, as JSON in the data claim
+func sign(t *testing.T, key *rsa.PrivateKey, payload interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Failed to encode payload: %v", err)
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"data": string(data), "iss": "NIC"}).SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign payload: %v", err)
+	}
+	return token
+}
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key
+}
+
+func TestImportInvoiceWithQRCode(t *testing.T) {
+	key := newKey(t)
+	verifier := NewVerifier(&key.PublicKey)
+	qr := sign(t, key, sampleQRCode())
+
+	record, err := verifier.Import([]byte(sampleInvoice), qr)
+	if err != nil {
+		t.Fatalf("Failed to import e-invoice: %v", err)
+	}
+	if record.IRN != sampleIRN || record.InvoiceNumber != "VND/2024/118" || record.SellerName != "Pune Stationers Pvt Ltd" || record.SignedQRCode != qr {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if !record.Total.Equal(money.FromMajor(11800)) || record.InvoiceDate.Format("2006-01-02") != "2024-03-15" || record.RegisteredAt.IsZero() {
+		t.Errorf("Unexpected total or dates in %+v", record)
+	}
+
+	if _, err := verifier.Import([]byte(sampleInvoice), ""); err == nil {
+		t.Errorf("Expected an unsigned invoice without its QR code to be refused")
+	}
+
+	// A QR code for another invoice does not vouch for this one
+	other := sampleQRCode()
+	other.TotInvVal = money.FromMajor(1180)
+	if _, err := verifier.Import([]byte(sampleInvoice), sign(t, key, other)); err == nil {
+		t.Errorf("Expected a QR code with a different total to be refused")
+	}
+}
+
+func TestImportRejectsForgedSignatures(t *testing.T) {
+	key := newKey(t)
+	verifier := NewVerifier(&key.PublicKey)
+
+	if _, err := verifier.Import(nil, sign(t, newKey(t), sampleQRCode())); err == nil {
+		t.Errorf("Expected a QR code signed with another key to be refused")
+	}
+
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"data": "{}"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	if _, err := verifier.VerifyQRCode(hmac); err == nil {
+		t.Errorf("Expected a QR code signed with HS256 to be refused")
+	}
+}
+
+func TestImportIRPResponse(t *testing.T) {
+	key := newKey(t)
+	verifier := NewVerifier(&key.PublicKey)
+
+	var signed map[string]interface{}
+	if err := json.Unmarshal([]byte(sampleInvoice), &signed); err != nil {
+		t.Fatalf("Failed to decode invoice: %v", err)
+	}
+	signed["Irn"] = sampleIRN
+	signed["AckNo"] = 112410012345678
+	signed["AckDt"] = "2024-03-15 11:42:00"
+	response, err := json.Marshal(Response{
+		AckNo:         "112410012345678",
+		AckDt:         "2024-03-15 11:42:00",
+		Irn:           sampleIRN,
+		SignedInvoice: sign(t, key, signed),
+		SignedQRCode:  sign(t, key, sampleQRCode()),
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode response: %v", err)
+	}
+
+	record, err := verifier.Import(response, "")
+	if err != nil {
+		t.Fatalf("Failed to import IRP response: %v", err)
+	}
+	if record.IRN != sampleIRN || record.AckNo != "112410012345678" || record.SignedQRCode == "" || record.SellerGSTIN != "27AAPFU0939F1ZV" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+
+	// The IRN beside the signed invoice must be the one signed
+	var tampered Response
+	if err := json.Unmarshal(response, &tampered); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	tampered.Irn = "0" + sampleIRN[1:]
+	encoded, _ := json.Marshal(tampered)
+	if _, err := verifier.Import(encoded, ""); err == nil {
+		t.Errorf("Expected a response with a different IRN to be refused")
+	}
+}
+
+func TestImportQRCodeOnly(t *testing.T) {
+	key := newKey(t)
+	record, err := NewVerifier(&key.PublicKey).Import(nil, sign(t, key, sampleQRCode()))
+	if err != nil {
+		t.Fatalf("Failed to import QR code: %v", err)
+	}
+	if record.IRN != sampleIRN || record.SellerName != "" || record.BuyerGSTIN != "29AAGCB7383J1Z4" || record.ItemCount != 1 {
+		t.Errorf("Unexpected record %+v", record)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key := newKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to encode key: %v", err)
+	}
+
+	parsed, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil || !parsed.Equal(&key.PublicKey) {
+		t.Fatalf("Failed to parse public key: %v", err)
+	}
+	if _, err := ParsePublicKey([]byte("not a key")); err == nil {
+		t.Errorf("Expected a malformed key to be refused")
+	}
+}
+
+func TestParseRejectsIncompleteInvoices(t *testing.T) {
+	var invoice map[string]interface{}
+	if err := json.Unmarshal([]byte(sampleInvoice), &invoice); err != nil {
+		t.Fatalf("Failed to decode invoice: %v", err)
+	}
+	invoice["SellerDtls"] = map[string]interface{}{"Gstin": "27AAPFU0939F1ZW", "LglNm": "Pune Stationers Pvt Ltd"}
+	encoded, _ := json.Marshal(invoice)
+	if _, err := Parse(encoded); err == nil {
+		t.Errorf("Expected an invalid seller GSTIN to be refused")
+	}
+
+	invoice["DocDtls"] = map[string]interface{}{"Typ": "INV", "No": "VND/2024/118", "Dt": "2024-03-15"}
+	encoded, _ = json.Marshal(invoice)
+	if _, err := Parse(encoded); err == nil {
+		t.Errorf("Expected a malformed document date to be refused")
+	}
+}
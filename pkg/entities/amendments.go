@@ -0,0 +1,331 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/consensus"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// ExpenditureVersion is a version of a recorded expenditure read from the
+// NGO's expenditure chain: the original block, or an amendment of it
+type ExpenditureVersion struct {
+	Expenditure *transactions.ExpenditureTransaction
+	Block       *blockchain.Block
+}
+
+// recordedExpenditure holds the fields of an expenditure or amendment block.
+// Blocks loaded from the database hold decoded JSON, so all blocks are read
+// through a JSON round trip.
+type recordedExpenditure struct {
+	Type                   string                             `json:"type"`
+	TransactionID          string                             `json:"transaction_id"`
+	Amount                 money.Money                        `json:"amount"`
+	AmendedAmount          *money.Money                       `json:"amended_amount"`
+	Category               string                             `json:"category"`
+	Description            string                             `json:"description"`
+	InvoiceDetails         transactions.InvoiceDetails        `json:"invoice_details"`
+	Attachments            []transactions.Attachment          `json:"attachments"`
+	AuditorValidation      *transactions.AuditorValidation    `json:"auditor_validation"`
+	ComplianceScore        float64                            `json:"compliance_score"`
+	ComplianceRulesVersion string                             `json:"compliance_rules_version"`
+	Timestamp              time.Time                          `json:"timestamp"`
+	CampaignID             string                             `json:"campaign_id"`
+	Funding                []transactions.FundAllocation      `json:"funding"`
+	InvoiceFlags           []transactions.InvoiceFlag         `json:"invoice_flags"`
+	Reviews                []transactions.ExpenditureReview   `json:"reviews"`
+	Consensus              *consensus.Outcome                 `json:"consensus"`
+	Appeal                 *transactions.ExpenditureAppeal    `json:"appeal"`
+	Amendment              *transactions.ExpenditureAmendment `json:"amendment"`
+}
+
+// ExpenditureVersions returns the versions of an expenditure recorded in the
+// NGO's chain, oldest first: the block it was mined in, then every amendment
+// decided, including those rejected. Each version holds the expenditure as
+// it stood once the block was added; a rejected amendment holds the version
+// the NGO asked for.
+func (ngo *NGO) ExpenditureVersions(transactionID string) ([]ExpenditureVersion, error) {
+	versions, _, err := ngo.expenditureVersions(func(blockData map[string]interface{}) bool {
+		return blockData["transaction_id"] == transactionID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(versions[transactionID]) == 0 {
+		return nil, ErrExpenditureNotFound
+	}
+	return versions[transactionID], nil
+}
+
+// RecordedExpenditure returns the current version of an expenditure recorded
+// in the NGO's chain, with every recorded amendment applied, and the block of
+// that version
+func (ngo *NGO) RecordedExpenditure(transactionID string) (*transactions.ExpenditureTransaction, *blockchain.Block, error) {
+	versions, err := ngo.ExpenditureVersions(transactionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := currentVersion(versions)
+	return current.Expenditure, current.Block, nil
+}
+
+// RecordedExpenditures returns the current version of every expenditure
+// recorded in the NGO's chain, in the order they were recorded
+func (ngo *NGO) RecordedExpenditures() ([]*transactions.ExpenditureTransaction, error) {
+	versions, order, err := ngo.expenditureVersions(func(map[string]interface{}) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	expenditures := make([]*transactions.ExpenditureTransaction, 0, len(order))
+	for _, transactionID := range order {
+		expenditures = append(expenditures, currentVersion(versions[transactionID]).Expenditure)
+	}
+	return expenditures, nil
+}
+
+// expenditureVersions reads the expenditure and amendment blocks matched in
+// one pass over the chain. It returns the versions by transaction ID and the
+// transaction IDs in the order the expenditures were recorded.
+func (ngo *NGO) expenditureVersions(match func(blockData map[string]interface{}) bool) (map[string][]ExpenditureVersion, []string, error) {
+	blocks := ngo.ExpenditureBlockchain.FindBlocks(func(block *blockchain.Block) bool {
+		return isExpenditureBlock(block) && match(block.Data.(map[string]interface{}))
+	})
+
+	versions := make(map[string][]ExpenditureVersion)
+	order := make([]string, 0)
+	current := make(map[string]*transactions.ExpenditureTransaction)
+	for _, block := range blocks {
+		var recorded recordedExpenditure
+		encoded, err := json.Marshal(block.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read expenditure block %s: %w", block.Hash, err)
+		}
+		if err := json.Unmarshal(encoded, &recorded); err != nil {
+			return nil, nil, fmt.Errorf("failed to read expenditure block %s: %w", block.Hash, err)
+		}
+
+		expenditure := &transactions.ExpenditureTransaction{
+			TransactionID:          recorded.TransactionID,
+			NGOID:                  ngo.NGOID,
+			Amount:                 recorded.Amount,
+			Category:               recorded.Category,
+			Description:            recorded.Description,
+			Timestamp:              recorded.Timestamp,
+			InvoiceDetails:         recorded.InvoiceDetails,
+			Attachments:            recorded.Attachments,
+			Status:                 "validated",
+			AuditorValidation:      recorded.AuditorValidation,
+			ComplianceScore:        recorded.ComplianceScore,
+			ComplianceRulesVersion: recorded.ComplianceRulesVersion,
+			CampaignID:             recorded.CampaignID,
+			Funding:                recorded.Funding,
+			InvoiceFlags:           recorded.InvoiceFlags,
+			Reviews:                recorded.Reviews,
+			Consensus:              recorded.Consensus,
+			Appeal:                 recorded.Appeal,
+			Amendment:              recorded.Amendment,
+		}
+		if recorded.Type == "expenditure_amendment" {
+			previous := current[recorded.TransactionID]
+			if previous == nil || recorded.AmendedAmount == nil || recorded.Amendment == nil {
+				return nil, nil, fmt.Errorf("amendment block %s does not follow a recorded expenditure", block.Hash)
+			}
+			expenditure.Amount = *recorded.AmendedAmount
+			expenditure.Appeal = previous.Appeal
+			expenditure.Funding = previous.Funding
+			if recorded.Amendment.Status == transactions.AmendmentRecorded {
+				expenditure.Funding = mergeFunding(previous.Funding, recorded.Funding)
+				current[recorded.TransactionID] = expenditure
+			} else {
+				expenditure.Status = "rejected"
+			}
+		} else {
+			current[recorded.TransactionID] = expenditure
+			order = append(order, recorded.TransactionID)
+		}
+		versions[recorded.TransactionID] = append(versions[recorded.TransactionID], ExpenditureVersion{Expenditure: expenditure, Block: block})
+	}
+	return versions, order, nil
+}
+
+// currentVersion returns the latest version not rejected
+func currentVersion(versions []ExpenditureVersion) ExpenditureVersion {
+	for i := len(versions) - 1; i > 0; i-- {
+		if versions[i].Expenditure.IsValidated() {
+			return versions[i]
+		}
+	}
+	return versions[0]
+}
+
+// RecordAmendment appends a block recording the decided amendment of an
+// expenditure already in the chain. The block references the original
+// block and the version amended. A recorded amendment carries the change in
+// amount, so the chain's totals stay correct, and the change in the
+// donations paying for the expenditure; a rejected one records the request
+// and the auditors' decision only. The expenditure's funding must be that of
+// the version amended.
+func (ngo *NGO) RecordAmendment(expenditure *transactions.ExpenditureTransaction) (*ProcessResult, error) {
+	amendment := expenditure.Amendment
+	if amendment == nil || amendment.Status == transactions.AmendmentPending {
+		return nil, fmt.Errorf("expenditure has no decided amendment")
+	}
+	recorded := amendment.Status == transactions.AmendmentRecorded
+	recordedAmendment := *amendment
+
+	var campaign *Campaign
+	if expenditure.CampaignID != "" {
+		var exists bool
+		if campaign, exists = ngo.Campaigns[expenditure.CampaignID]; !exists {
+			return nil, ErrCampaignNotFound
+		}
+	}
+
+	blockData := map[string]interface{}{
+		"type":                     "expenditure_amendment",
+		"transaction_id":           expenditure.TransactionID,
+		"amendment":                recordedAmendment,
+		"amended_amount":           expenditure.Amount,
+		"currency":                 "INR",
+		"category":                 expenditure.Category,
+		"description":              expenditure.Description,
+		"invoice_details":          expenditure.InvoiceDetails,
+		"compliance_score":         expenditure.ComplianceScore,
+		"compliance_rules_version": expenditure.ComplianceRulesVersion,
+		"timestamp":                expenditure.Timestamp,
+		"attachments":              ngo.extractAttachmentHashes(expenditure.Attachments),
+	}
+
+	var funding []transactions.FundAllocation
+	if recorded {
+		if !expenditure.VerifyGSTIN(expenditure.InvoiceDetails.GSTIN) {
+			return nil, fmt.Errorf("invalid GSTIN")
+		}
+		if expenditure.ComplianceScore < 60 {
+			return nil, fmt.Errorf("low compliance score: %.1f%%. Minimum required: 60%%", expenditure.ComplianceScore)
+		}
+		if campaign != nil && amendment.AmountChange.Cmp(campaign.RestrictedBalance()) > 0 {
+			return nil, fmt.Errorf("amendment exceeds campaign's restricted balance of ₹%s", campaign.RestrictedBalance())
+		}
+		if !amendment.AmountChange.IsZero() {
+			var err error
+			if funding, err = ngo.amendFunding(expenditure.CampaignID, expenditure.Funding, amendment.AmountChange); err != nil {
+				return nil, err
+			}
+			blockData["amount"] = amendment.AmountChange
+			blockData["funding"] = funding
+		}
+	}
+	if campaign != nil {
+		blockData["campaign_id"] = campaign.CampaignID
+	}
+	if expenditure.AuditorValidation != nil {
+		blockData["auditor_validation"] = expenditure.AuditorValidation
+	}
+	if len(expenditure.InvoiceFlags) > 0 {
+		blockData["invoice_flags"] = expenditure.InvoiceFlags
+	}
+	if len(expenditure.Reviews) > 0 {
+		blockData["reviews"] = expenditure.Reviews
+	}
+	if expenditure.Consensus != nil {
+		blockData["consensus"] = expenditure.Consensus
+	}
+
+	block := blockchain.NewBlock(
+		ngo.ExpenditureBlockchain.GetChainLength(),
+		time.Now(),
+		blockData,
+		ngo.ExpenditureBlockchain.GetLatestBlock().Hash,
+		"expenditure",
+	)
+
+	// Validate block with the signature of every auditor who re-audited the
+	// amendment, recording those outvoted as dissenting
+	block.Validate()
+	votes := expenditure.Votes()
+	for _, vote := range votes {
+		validationType := "auditor"
+		if (vote.Decision == transactions.DecisionApprove) != recorded {
+			validationType = "auditor_dissent"
+		}
+		block.AddValidator(vote.ReviewerID, vote.Signature, validationType)
+	}
+	if len(votes) == 0 && expenditure.AuditorValidation != nil {
+		block.AddValidator(
+			expenditure.AuditorValidation.AuditorID,
+			expenditure.AuditorValidation.Signature,
+			"auditor",
+		)
+	}
+
+	if !ngo.ExpenditureBlockchain.AddBlock(block) {
+		return nil, fmt.Errorf("failed to add amendment block to blockchain")
+	}
+	if recorded {
+		ngo.TotalExpenditureReported = ngo.TotalExpenditureReported.Add(amendment.AmountChange)
+		if campaign != nil {
+			campaign.SpentAmount = campaign.SpentAmount.Add(amendment.AmountChange)
+		}
+		expenditure.Funding = mergeFunding(expenditure.Funding, funding)
+	}
+
+	return &ProcessResult{
+		Success:       true,
+		BlockHash:     block.Hash,
+		TransactionID: expenditure.TransactionID,
+		BlockIndex:    block.Index,
+	}, nil
+}
+
+// amendFunding works out the change in the donations paying for an
+// expenditure whose amount changes. An increase is drawn like a new
+// expenditure; a decrease releases the donations allocated last first.
+func (ngo *NGO) amendFunding(campaignID string, funding []transactions.FundAllocation, change money.Money) ([]transactions.FundAllocation, error) {
+	if change.IsPositive() {
+		return ngo.AllocateFunding(campaignID, change, nil, nil)
+	}
+
+	adjustments := make([]transactions.FundAllocation, 0)
+	release := change.Neg()
+	for i := len(funding) - 1; i >= 0 && release.IsPositive(); i-- {
+		share := money.Min(release, funding[i].Amount)
+		if !share.IsPositive() {
+			continue
+		}
+		adjustments = append(adjustments, transactions.FundAllocation{DonationID: funding[i].DonationID, Amount: share.Neg()})
+		release = release.Sub(share)
+	}
+	return adjustments, nil
+}
+
+// mergeFunding applies adjustments to an expenditure's funding, dropping
+// donations no longer paying for it
+func mergeFunding(funding, adjustments []transactions.FundAllocation) []transactions.FundAllocation {
+	merged := append([]transactions.FundAllocation(nil), funding...)
+	for _, adjustment := range adjustments {
+		found := false
+		for i := range merged {
+			if merged[i].DonationID == adjustment.DonationID {
+				merged[i].Amount = merged[i].Amount.Add(adjustment.Amount)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, adjustment)
+		}
+	}
+
+	result := make([]transactions.FundAllocation, 0, len(merged))
+	for _, allocation := range merged {
+		if allocation.Amount.IsPositive() {
+			result = append(result, allocation)
+		}
+	}
+	return result
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"ngo-transparency-platform/pkg/compliance"
 	"ngo-transparency-platform/pkg/transactions"
 	"strings"
 	"time"
@@ -16,12 +17,23 @@ type AuditResult struct {
 	AuditorID       string    `json:"auditor_id"`
 	Timestamp       time.Time `json:"timestamp"`
 	ComplianceScore float64   `json:"compliance_score"`
+	RulesVersion    string    `json:"compliance_rules_version,omitempty"` // Version of the compliance rules behind the automated score
 	Findings        []string  `json:"findings"`
 	Recommendation  string    `json:"recommendation"`
 	AuditNotes      string    `json:"audit_notes"`
+	Decision        string    `json:"decision,omitempty"` // Reviewer's approve or reject; empty for automated audits
 	Signature       string    `json:"signature"`
 }
 
+// Approved reports whether the audit approved the expenditure: the
+// reviewer's decision when there is one, otherwise the recommendation
+func (r AuditResult) Approved() bool {
+	if r.Decision != "" {
+		return r.Decision == transactions.DecisionApprove
+	}
+	return strings.Contains(strings.ToLower(r.Recommendation), "approve")
+}
+
 // AuditorStats represents auditor statistics
 type AuditorStats struct {
 	AuditorID              string    `json:"auditor_id"`
@@ -49,6 +61,17 @@ type Auditor struct {
 	PublicKey             string        `json:"public_key"`
 	VerificationAuthority string        `json:"verification_authority,omitempty"`
 	VerificationDate      *time.Time    `json:"verification_date,omitempty"`
+	// NGOs the auditor has declared an interest in and may not review
+	Conflicts []ConflictOfInterest `json:"conflicts,omitempty"`
+}
+
+// ConflictOfInterest is an auditor's declared interest in an NGO, such as a
+// relative on its board or a paid engagement, that bars them from reviewing
+// its expenditures
+type ConflictOfInterest struct {
+	NGOID      string    `json:"ngo_id"`
+	Reason     string    `json:"reason"`
+	DeclaredAt time.Time `json:"declared_at"`
 }
 
 // NewAuditor creates a new auditor instance
@@ -84,20 +107,39 @@ func (a *Auditor) VerifyCredentials(verificationAuthority string) bool {
 	return a.Verified
 }
 
-// AuditExpenditure performs an audit on an expenditure transaction
-func (a *Auditor) AuditExpenditure(expenditure *transactions.ExpenditureTransaction, auditNotes string) *AuditResult {
+// AuditExpenditure performs an audit on an expenditure transaction under rules
+func (a *Auditor) AuditExpenditure(expenditure *transactions.ExpenditureTransaction, auditNotes string, rules *compliance.RuleSet) *AuditResult {
+	return a.audit(expenditure, auditNotes, "", expenditure.ComplianceScore, rules)
+}
+
+// ReviewExpenditure records the auditor's decision to approve or reject an
+// expenditure, together with the findings of the automated checks. The
+// auditor's own compliance score replaces the automated one when given.
+func (a *Auditor) ReviewExpenditure(expenditure *transactions.ExpenditureTransaction, approve bool, notes string, score *float64, rules *compliance.RuleSet) *AuditResult {
+	decision := transactions.DecisionReject
+	if approve {
+		decision = transactions.DecisionApprove
+	}
+	complianceScore := expenditure.ComplianceScore
+	if score != nil {
+		complianceScore = *score
+	}
+	return a.audit(expenditure, notes, decision, complianceScore, rules)
+}
+
+func (a *Auditor) audit(expenditure *transactions.ExpenditureTransaction, auditNotes, decision string, complianceScore float64, rules *compliance.RuleSet) *AuditResult {
 	// Generate audit ID - handle variable length auditorIDs
 	auditorIDPart := a.AuditorID
 	if len(a.AuditorID) > 8 {
 		auditorIDPart = a.AuditorID[:8]
 	}
-	auditID := fmt.Sprintf("AUD-%d-%s", time.Now().Unix(), auditorIDPart)
+	auditID := fmt.Sprintf("AUD-%d-%s", time.Now().UnixNano(), auditorIDPart)
 
-	findings := a.generateFindings(expenditure)
-	recommendation := a.generateRecommendation(expenditure)
+	findings := a.generateFindings(expenditure, rules)
+	recommendation := a.generateRecommendation(expenditure, rules)
 
 	// Generate signature
-	signatureData := fmt.Sprintf("%s%s%s", auditID, expenditure.TransactionID, a.AuditorID)
+	signatureData := fmt.Sprintf("%s%s%s%s", auditID, expenditure.TransactionID, a.AuditorID, decision)
 	hash := sha256.Sum256([]byte(signatureData))
 	signature := hex.EncodeToString(hash[:])
 
@@ -106,10 +148,12 @@ func (a *Auditor) AuditExpenditure(expenditure *transactions.ExpenditureTransact
 		ExpenditureID:   expenditure.TransactionID,
 		AuditorID:       a.AuditorID,
 		Timestamp:       time.Now(),
-		ComplianceScore: expenditure.ComplianceScore,
+		ComplianceScore: complianceScore,
+		RulesVersion:    expenditure.ComplianceRulesVersion,
 		Findings:        findings,
 		Recommendation:  recommendation,
 		AuditNotes:      auditNotes,
+		Decision:        decision,
 		Signature:       signature,
 	}
 
@@ -118,11 +162,11 @@ func (a *Auditor) AuditExpenditure(expenditure *transactions.ExpenditureTransact
 }
 
 // generateFindings generates audit findings based on expenditure analysis
-func (a *Auditor) generateFindings(expenditure *transactions.ExpenditureTransaction) []string {
+func (a *Auditor) generateFindings(expenditure *transactions.ExpenditureTransaction, rules *compliance.RuleSet) []string {
 	var findings []string
 
 	if !expenditure.VerifyGSTIN(expenditure.InvoiceDetails.GSTIN) {
-		findings = append(findings, "Invalid GSTIN format")
+		findings = append(findings, "Invalid GSTIN")
 	}
 
 	if expenditure.InvoiceDetails.BankTransactionID == "" && expenditure.InvoiceDetails.ChequeNumber == "" {
@@ -139,13 +183,21 @@ func (a *Auditor) generateFindings(expenditure *transactions.ExpenditureTransact
 
 	// Check for vendor GSTIN if available
 	if expenditure.InvoiceDetails.VendorGSTIN != "" && !expenditure.VerifyGSTIN(expenditure.InvoiceDetails.VendorGSTIN) {
-		findings = append(findings, "Invalid vendor GSTIN format")
+		findings = append(findings, "Invalid vendor GSTIN")
+	}
+
+	for _, flag := range expenditure.InvoiceFlags {
+		findings = append(findings, flag.Description())
+	}
+
+	if appeal := expenditure.Appeal; appeal != nil && appeal.OriginalDecision != nil {
+		findings = append(findings, fmt.Sprintf("Appeal against rejection by %s: %s", appeal.OriginalDecision.AuditorID, appeal.Reason))
 	}
 
 	// Check invoice age
 	daysDiff := time.Since(expenditure.InvoiceDetails.InvoiceDate).Hours() / 24
-	if daysDiff > 90 {
-		findings = append(findings, fmt.Sprintf("Invoice is older than 90 days (%.0f days)", daysDiff))
+	if maxAge := rules.MaxInvoiceAgeDays(expenditure.Category); maxAge > 0 && daysDiff > float64(maxAge) {
+		findings = append(findings, fmt.Sprintf("Invoice is older than %d days (%.0f days)", maxAge, daysDiff))
 	}
 
 	// Check for missing critical information
@@ -160,24 +212,10 @@ func (a *Auditor) generateFindings(expenditure *transactions.ExpenditureTransact
 	return findings
 }
 
-// generateRecommendation generates a recommendation based on compliance score and findings
-func (a *Auditor) generateRecommendation(expenditure *transactions.ExpenditureTransaction) string {
-	score := expenditure.ComplianceScore
-
-	switch {
-	case score >= 90:
-		return "Approve - Excellent compliance"
-	case score >= 80:
-		return "Approve - Good compliance with minor observations"
-	case score >= 70:
-		return "Approve with conditions - Address noted observations"
-	case score >= 60:
-		return "Conditional approval - Requires additional documentation"
-	case score >= 50:
-		return "Review required - Significant compliance gaps"
-	default:
-		return "Reject - Insufficient compliance and documentation"
-	}
+// generateRecommendation generates a recommendation from the compliance
+// score using the audit bands of rules
+func (a *Auditor) generateRecommendation(expenditure *transactions.ExpenditureTransaction, rules *compliance.RuleSet) string {
+	return rules.AuditRecommendation(expenditure.ComplianceScore)
 }
 
 // GetAuditorStats returns comprehensive statistics about the auditor
@@ -186,7 +224,7 @@ func (a *Auditor) GetAuditorStats() AuditorStats {
 	totalComplianceScore := 0.0
 
 	for _, audit := range a.AuditHistory {
-		if strings.Contains(strings.ToLower(audit.Recommendation), "approve") {
+		if audit.Approved() {
 			approvedCount++
 		}
 		totalComplianceScore += audit.ComplianceScore
@@ -268,7 +306,7 @@ func (a *Auditor) UpdateRating() float64 {
 	totalScore := 0.0
 
 	for _, audit := range a.AuditHistory {
-		if strings.Contains(strings.ToLower(audit.Recommendation), "approve") {
+		if audit.Approved() {
 			approvedCount++
 		}
 		totalScore += audit.ComplianceScore
@@ -315,6 +353,31 @@ func (a *Auditor) UpdateRating() float64 {
 	return rating
 }
 
+// DeclareConflict records a conflict of interest with an NGO
+func (a *Auditor) DeclareConflict(ngoID, reason string) (*ConflictOfInterest, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("conflict of interest reason is required")
+	}
+	if a.HasConflict(ngoID) {
+		return nil, fmt.Errorf("conflict of interest already declared")
+	}
+
+	conflict := ConflictOfInterest{NGOID: ngoID, Reason: reason, DeclaredAt: time.Now()}
+	a.Conflicts = append(a.Conflicts, conflict)
+	return &conflict, nil
+}
+
+// HasConflict checks if the auditor has declared a conflict of interest with an NGO
+func (a *Auditor) HasConflict(ngoID string) bool {
+	for _, conflict := range a.Conflicts {
+		if conflict.NGOID == ngoID {
+			return true
+		}
+	}
+	return false
+}
+
 // HasSpecialization checks if the auditor has a specific specialization
 func (a *Auditor) HasSpecialization(specialization string) bool {
 	for _, spec := range a.Specializations {
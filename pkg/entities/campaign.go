@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Campaign statuses
+const (
+	CampaignActive = "active" // Accepting donations between its start and end dates
+	CampaignClosed = "closed" // No longer accepting donations; its balance can still be spent
+)
+
+// Campaign is a project of an NGO with a fundraising goal. Donations earmarked
+// to a campaign are restricted funds that only its expenditures may draw on.
+type Campaign struct {
+	CampaignID   string      `json:"campaign_id"`
+	NGOID        string      `json:"ngo_id"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description"`
+	GoalAmount   money.Money `json:"goal_amount"`
+	StartDate    time.Time   `json:"start_date"`
+	EndDate      *time.Time  `json:"end_date,omitempty"` // Open-ended when nil
+	Status       string      `json:"status"`
+	RaisedAmount money.Money `json:"raised_amount"` // Net earmarked donations, less reversals
+	SpentAmount  money.Money `json:"spent_amount"`
+	CreatedAt    time.Time   `json:"created_at"`
+	ClosedAt     *time.Time  `json:"closed_at,omitempty"`
+}
+
+// CampaignUtilization summarises how much of a campaign's funds have been raised and spent
+type CampaignUtilization struct {
+	CampaignID        string      `json:"campaign_id"`
+	Name              string      `json:"name"`
+	Status            string      `json:"status"`
+	GoalAmount        money.Money `json:"goal_amount"`
+	RaisedAmount      money.Money `json:"raised_amount"`
+	SpentAmount       money.Money `json:"spent_amount"`
+	RestrictedBalance money.Money `json:"restricted_balance"`
+	GoalProgress      string      `json:"goal_progress"`
+	UtilizationRate   string      `json:"utilization_rate"`
+}
+
+// CampaignReport lists the expenditures a campaign's funds were spent on
+type CampaignReport struct {
+	CampaignUtilization
+	Description  string                   `json:"description"`
+	StartDate    time.Time                `json:"start_date"`
+	EndDate      *time.Time               `json:"end_date,omitempty"`
+	Expenditures []map[string]interface{} `json:"expenditures"`
+}
+
+// NewCampaign creates an active campaign
+func NewCampaign(ngoID, name, description string, goalAmount money.Money, startDate time.Time, endDate *time.Time) (*Campaign, error) {
+	if name == "" {
+		return nil, fmt.Errorf("campaign name is required")
+	}
+	if !goalAmount.IsPositive() {
+		return nil, fmt.Errorf("campaign goal must be positive")
+	}
+	if startDate.IsZero() {
+		startDate = time.Now()
+	}
+	if endDate != nil && !endDate.After(startDate) {
+		return nil, fmt.Errorf("campaign must end after it starts")
+	}
+
+	randomBytes := make([]byte, 12)
+	rand.Read(randomBytes)
+
+	return &Campaign{
+		CampaignID:   "CMP_" + hex.EncodeToString(randomBytes),
+		NGOID:        ngoID,
+		Name:         name,
+		Description:  description,
+		GoalAmount:   goalAmount,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Status:       CampaignActive,
+		RaisedAmount: money.Zero(),
+		SpentAmount:  money.Zero(),
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// AcceptsDonations reports whether the campaign is active and running at the given time
+func (c *Campaign) AcceptsDonations(at time.Time) bool {
+	if c.Status != CampaignActive || at.Before(c.StartDate) {
+		return false
+	}
+	return c.EndDate == nil || !at.After(*c.EndDate)
+}
+
+// RestrictedBalance returns the earmarked funds not yet spent
+func (c *Campaign) RestrictedBalance() money.Money {
+	return c.RaisedAmount.Sub(c.SpentAmount)
+}
+
+// Close stops the campaign from accepting donations
+func (c *Campaign) Close() {
+	now := time.Now()
+	c.Status = CampaignClosed
+	c.ClosedAt = &now
+}
+
+// Utilization returns the campaign's progress towards its goal and how much
+// of what it raised has been spent
+func (c *Campaign) Utilization() CampaignUtilization {
+	return CampaignUtilization{
+		CampaignID:        c.CampaignID,
+		Name:              c.Name,
+		Status:            c.Status,
+		GoalAmount:        c.GoalAmount,
+		RaisedAmount:      c.RaisedAmount,
+		SpentAmount:       c.SpentAmount,
+		RestrictedBalance: c.RestrictedBalance(),
+		GoalProgress:      fmt.Sprintf("%.2f%%", c.RaisedAmount.Ratio(c.GoalAmount)*100),
+		UtilizationRate:   fmt.Sprintf("%.2f%%", c.SpentAmount.Ratio(c.RaisedAmount)*100),
+	}
+}
+
+// AddCampaign starts a campaign of the NGO
+func (ngo *NGO) AddCampaign(campaign *Campaign) error {
+	if campaign.NGOID != ngo.NGOID {
+		return fmt.Errorf("campaign belongs to another NGO")
+	}
+	if _, exists := ngo.Campaigns[campaign.CampaignID]; exists {
+		return fmt.Errorf("campaign already exists")
+	}
+	ngo.Campaigns[campaign.CampaignID] = campaign
+	return nil
+}
+
+// GetCampaign returns one of the NGO's campaigns
+func (ngo *NGO) GetCampaign(campaignID string) (*Campaign, bool) {
+	campaign, exists := ngo.Campaigns[campaignID]
+	return campaign, exists
+}
+
+// CampaignUtilizations returns the utilization of each of the NGO's campaigns, oldest first
+func (ngo *NGO) CampaignUtilizations() []CampaignUtilization {
+	utilizations := make([]CampaignUtilization, 0, len(ngo.Campaigns))
+	for _, campaign := range ngo.sortedCampaigns() {
+		utilizations = append(utilizations, campaign.Utilization())
+	}
+	return utilizations
+}
+
+// CampaignReport returns a campaign's utilization and the expenditures
+// recorded against it
+func (ngo *NGO) CampaignReport(campaignID string) (*CampaignReport, error) {
+	campaign, exists := ngo.Campaigns[campaignID]
+	if !exists {
+		return nil, ErrCampaignNotFound
+	}
+
+	blocks := ngo.ExpenditureBlockchain.FindBlocks(func(block *blockchain.Block) bool {
+		blockData, ok := block.Data.(map[string]interface{})
+		return ok && blockData["type"] == "expenditure" && blockData["campaign_id"] == campaignID
+	})
+
+	// Expenditures are reported as last amended
+	expenditures := make([]map[string]interface{}, 0, len(blocks))
+	for _, block := range blocks {
+		blockData := block.Data.(map[string]interface{})
+		transactionID, _ := blockData["transaction_id"].(string)
+		expenditure, latest, err := ngo.RecordedExpenditure(transactionID)
+		if err != nil {
+			return nil, err
+		}
+		entry := map[string]interface{}{
+			"transaction_id": transactionID,
+			"amount":         expenditure.Amount,
+			"category":       expenditure.Category,
+			"description":    expenditure.Description,
+			"timestamp":      blockData["timestamp"],
+			"block_hash":     block.Hash,
+		}
+		if latest != block {
+			entry["amended_block_hash"] = latest.Hash
+		}
+		expenditures = append(expenditures, entry)
+	}
+
+	return &CampaignReport{
+		CampaignUtilization: campaign.Utilization(),
+		Description:         campaign.Description,
+		StartDate:           campaign.StartDate,
+		EndDate:             campaign.EndDate,
+		Expenditures:        expenditures,
+	}, nil
+}
+
+// blockCampaign returns the campaign a donation or expenditure block is earmarked to
+func (ngo *NGO) blockCampaign(block *blockchain.Block) *Campaign {
+	blockData, ok := block.Data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	campaignID, _ := blockData["campaign_id"].(string)
+	return ngo.Campaigns[campaignID]
+}
+
+func (ngo *NGO) sortedCampaigns() []*Campaign {
+	campaigns := make([]*Campaign, 0, len(ngo.Campaigns))
+	for _, campaign := range ngo.Campaigns {
+		campaigns = append(campaigns, campaign)
+	}
+	sort.Slice(campaigns, func(i, j int) bool {
+		if campaigns[i].CreatedAt.Equal(campaigns[j].CreatedAt) {
+			return campaigns[i].CampaignID < campaigns[j].CampaignID
+		}
+		return campaigns[i].CreatedAt.Before(campaigns[j].CreatedAt)
+	})
+	return campaigns
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// MatchingRule decides how much a company adds to each employee donation
+type MatchingRule struct {
+	Ratio              float64     `json:"ratio"`               // Rupees matched per rupee donated, e.g. 1 for 1:1
+	PerEmployeeCap     money.Money `json:"per_employee_cap"`    // Most matched per employee in a calendar year; zero for no cap
+	EligibleCategories []string    `json:"eligible_categories"` // NGO categories matched; empty matches every NGO
+	Active             bool        `json:"active"`
+}
+
+// CorporateProfile makes a donor a company that can match its employees'
+// donations and report its CSR spend
+type CorporateProfile struct {
+	CompanyName  string       `json:"company_name"`
+	CIN          string       `json:"cin,omitempty"` // Corporate Identity Number
+	MatchingRule MatchingRule `json:"matching_rule"`
+	Employees    []string     `json:"employees"` // Donor IDs, oldest first
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// Validate checks the rule's ratio and cap
+func (r MatchingRule) Validate() error {
+	if r.Ratio < 0 || r.Ratio > 10 {
+		return fmt.Errorf("matching ratio must be between 0 and 10")
+	}
+	if r.Active && r.Ratio == 0 {
+		return fmt.Errorf("active matching rule needs a positive ratio")
+	}
+	if r.PerEmployeeCap.IsNegative() {
+		return fmt.Errorf("per-employee cap cannot be negative")
+	}
+	return nil
+}
+
+// Eligible reports whether donations to an NGO of the given category are matched
+func (r MatchingRule) Eligible(category string) bool {
+	if len(r.EligibleCategories) == 0 {
+		return true
+	}
+	for _, eligible := range r.EligibleCategories {
+		if strings.EqualFold(eligible, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAmount returns the match for a donation, rounded down to the paisa and
+// limited to what remains of the employee's cap after alreadyMatched
+func (r MatchingRule) MatchAmount(donated, alreadyMatched money.Money) (money.Money, error) {
+	if !r.Active {
+		return money.Zero(), nil
+	}
+	match, err := donated.Percent(money.BasisPoints(r.Ratio), money.RoundDown)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if r.PerEmployeeCap.IsPositive() {
+		match = money.Min(match, money.Max(r.PerEmployeeCap.Sub(alreadyMatched), money.Zero()))
+	}
+	return match, nil
+}
+
+// NewCorporateProfile creates a corporate profile with the given matching rule
+func NewCorporateProfile(companyName, cin string, rule MatchingRule) (*CorporateProfile, error) {
+	if companyName == "" {
+		return nil, fmt.Errorf("company name is required")
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return &CorporateProfile{
+		CompanyName:  companyName,
+		CIN:          cin,
+		MatchingRule: rule,
+		Employees:    make([]string, 0),
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// HasEmployee reports whether a donor is one of the company's employees
+func (c *CorporateProfile) HasEmployee(donorID string) bool {
+	for _, employeeID := range c.Employees {
+		if employeeID == donorID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCorporate reports whether the donor is a company
+func (d *Donor) IsCorporate() bool {
+	return d.Corporate != nil
+}
+
+// MatchedFor returns how much the corporate donor has matched of an
+// employee's donations made in the given calendar year
+func (d *Donor) MatchedFor(employee *Donor, year int) money.Money {
+	employeeDonations := make(map[string]bool, len(employee.DonationHistory))
+	for _, record := range employee.DonationHistory {
+		employeeDonations[record.TransactionID] = true
+	}
+
+	matched := money.Zero()
+	for _, record := range d.DonationHistory {
+		if record.MatchFor != "" && employeeDonations[record.MatchFor] && record.Timestamp.Year() == year {
+			matched = matched.Add(record.GrossAmount)
+		}
+	}
+	return matched
+}
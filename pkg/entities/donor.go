@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"ngo-transparency-platform/pkg/crypto"
+	"ngo-transparency-platform/pkg/money"
 	"ngo-transparency-platform/pkg/transactions"
 	"time"
 )
@@ -20,58 +21,66 @@ type DonorKYCData struct {
 
 // DonationRecord represents a single donation record
 type DonationRecord struct {
-	TransactionID string                            `json:"transaction_id"`
-	NGOID         string                            `json:"ngo_id"`
-	Amount        float64                           `json:"amount"`
-	Timestamp     time.Time                         `json:"timestamp"`
-	EBill         *transactions.EBill               `json:"e_bill"`
-	ZKProof       *crypto.ZKProof            `json:"zk_proof"`
-	TaxBenefit    transactions.TaxBenefit          `json:"tax_benefit"`
+	TransactionID string                  `json:"transaction_id"`
+	NGOID         string                  `json:"ngo_id"`
+	Amount        money.Money             `json:"amount"` // Net of platform fee and reversals
+	GrossAmount   money.Money             `json:"gross_amount"`
+	PlatformFee   money.Money             `json:"platform_fee"`
+	Timestamp     time.Time               `json:"timestamp"`
+	EBill         *transactions.EBill     `json:"e_bill"`
+	ZKProof       *crypto.ZKProof         `json:"zk_proof"`
+	TaxBenefit    transactions.TaxBenefit `json:"tax_benefit"`
+	CampaignID    string                  `json:"campaign_id,omitempty"`
+	MandateID     string                  `json:"mandate_id,omitempty"` // Recurring mandate that made the donation
+	MatchFor      string                  `json:"match_for,omitempty"`  // Employee donation a corporate donation matches
 }
 
 // TaxBenefitSummary represents annual tax benefit summary
 type TaxBenefitSummary struct {
-	Year              int                   `json:"year"`
-	TotalDonated      float64              `json:"total_donated"`
-	TotalDeductible   float64              `json:"total_deductible"`
-	EstimatedTaxSaving float64             `json:"estimated_tax_saving"`
-	Donations         []DonationRecord     `json:"donations"`
+	Year               int              `json:"year"`
+	TotalDonated       money.Money      `json:"total_donated"`
+	TotalDeductible    money.Money      `json:"total_deductible"`
+	EstimatedTaxSaving money.Money      `json:"estimated_tax_saving"`
+	Donations          []DonationRecord `json:"donations"`
 }
 
 // DonationLimit represents donation limit checking result
 type DonationLimit struct {
-	CanDonate         bool    `json:"can_donate"`
-	CurrentYearTotal  float64 `json:"current_year_total"`
-	Limit             float64 `json:"limit"`
-	RemainingLimit    float64 `json:"remaining_limit"`
+	CanDonate        bool        `json:"can_donate"`
+	CurrentYearTotal money.Money `json:"current_year_total"`
+	Limit            money.Money `json:"limit"`
+	RemainingLimit   money.Money `json:"remaining_limit"`
 }
 
 // DonorStats represents donor statistics
 type DonorStats struct {
-	DonorID              string    `json:"donor_id"`
-	KYCVerified          bool      `json:"kyc_verified"`
-	VerificationLevel    string    `json:"verification_level"`
-	TotalDonated         float64   `json:"total_donated"`
-	DonationCount        int       `json:"donation_count"`
-	CurrentYearDonations float64   `json:"current_year_donations"`
-	CurrentYearCount     int       `json:"current_year_count"`
-	PreferredNGOsCount   int       `json:"preferred_ngos_count"`
-	AverageDonation      float64   `json:"average_donation"`
-	MemberSince          time.Time `json:"member_since"`
-	AnnualLimit          float64   `json:"annual_limit"`
+	DonorID              string      `json:"donor_id"`
+	KYCVerified          bool        `json:"kyc_verified"`
+	VerificationLevel    string      `json:"verification_level"`
+	TotalDonated         money.Money `json:"total_donated"`
+	DonationCount        int         `json:"donation_count"`
+	CurrentYearDonations money.Money `json:"current_year_donations"`
+	CurrentYearCount     int         `json:"current_year_count"`
+	PreferredNGOsCount   int         `json:"preferred_ngos_count"`
+	AverageDonation      money.Money `json:"average_donation"`
+	MemberSince          time.Time   `json:"member_since"`
+	AnnualLimit          money.Money `json:"annual_limit"`
 }
 
 // Donor represents a donor in the system
 type Donor struct {
-	DonorID         string            `json:"donor_id"`
-	KYCVerified     bool              `json:"kyc_verified"`
-	KYCData         DonorKYCData      `json:"kyc_data"`
-	DonationHistory []DonationRecord  `json:"donation_history"`
-	TotalDonated    float64           `json:"total_donated"`
-	PreferredNGOs   []string          `json:"preferred_ngos"`
-	TaxBenefits     []TaxBenefitSummary `json:"tax_benefits"`
-	CreatedAt       time.Time         `json:"created_at"`
-	AnnualDonationLimit float64       `json:"annual_donation_limit"`
+	DonorID             string                       `json:"donor_id"`
+	KYCVerified         bool                         `json:"kyc_verified"`
+	KYCData             DonorKYCData                 `json:"kyc_data"`
+	DonationHistory     []DonationRecord             `json:"donation_history"`
+	TotalDonated        money.Money                  `json:"total_donated"`
+	PreferredNGOs       []string                     `json:"preferred_ngos"`
+	TaxBenefits         []TaxBenefitSummary          `json:"tax_benefits"`
+	CreatedAt           time.Time                    `json:"created_at"`
+	AnnualDonationLimit money.Money                  `json:"annual_donation_limit"`
+	Mandates            map[string]*RecurringMandate `json:"mandates"`
+	Corporate           *CorporateProfile            `json:"corporate,omitempty"`   // Set when the donor is a company
+	EmployerID          string                       `json:"employer_id,omitempty"` // Corporate donor that matches this donor's donations
 }
 
 // NewDonor creates a new donor instance
@@ -79,7 +88,7 @@ func NewDonor(donorID string, kycData map[string]interface{}) *Donor {
 	// Create KYC data hash
 	kycDocHash := ""
 	documentsSubmitted := []string{}
-	annualLimit := 1000000.0 // 10 lakh default
+	annualLimit := money.INR(1000000 * 100) // 10 lakh default
 
 	if docs, ok := kycData["documents"]; ok {
 		if docsList, ok := docs.([]string); ok {
@@ -90,8 +99,13 @@ func NewDonor(donorID string, kycData map[string]interface{}) *Donor {
 		}
 	}
 
-	if limit, ok := kycData["annual_limit"].(float64); ok {
+	switch limit := kycData["annual_limit"].(type) {
+	case money.Money:
 		annualLimit = limit
+	case float64:
+		annualLimit = money.FromMajor(limit)
+	case int:
+		annualLimit = money.INR(int64(limit) * 100)
 	}
 
 	return &Donor{
@@ -102,11 +116,12 @@ func NewDonor(donorID string, kycData map[string]interface{}) *Donor {
 			DocumentsSubmitted: documentsSubmitted,
 		},
 		DonationHistory:     make([]DonationRecord, 0),
-		TotalDonated:        0,
+		TotalDonated:        money.Zero(),
 		PreferredNGOs:       make([]string, 0),
 		TaxBenefits:         make([]TaxBenefitSummary, 0),
 		CreatedAt:           time.Now(),
 		AnnualDonationLimit: annualLimit,
+		Mandates:            make(map[string]*RecurringMandate),
 	}
 }
 
@@ -124,31 +139,90 @@ func (d *Donor) VerifyKYC(authorityID, verificationLevel string) bool {
 
 	// Update annual limit based on verification level
 	if verificationLevel == "premium" {
-		d.AnnualDonationLimit = 5000000 // 50 lakh for premium KYC
+		d.AnnualDonationLimit = money.INR(5000000 * 100) // 50 lakh for premium KYC
 	}
 
 	return d.KYCVerified
 }
 
-// AddDonation adds a donation record to the donor's history
-func (d *Donor) AddDonation(donation *transactions.DonationTransaction) {
+// AddDonation adds a donation record to the donor's history. The donation
+// amount is net of platformFee, which was charged on top as part of grossAmount.
+func (d *Donor) AddDonation(donation *transactions.DonationTransaction, grossAmount, platformFee money.Money) {
 	donationRecord := DonationRecord{
 		TransactionID: donation.TransactionID,
 		NGOID:         donation.NGOID,
 		Amount:        donation.Amount,
+		GrossAmount:   grossAmount,
+		PlatformFee:   platformFee,
 		Timestamp:     donation.Timestamp,
 		EBill:         donation.EBill,
 		ZKProof:       donation.ZKProof,
 		TaxBenefit:    donation.EBill.TaxBenefit,
+		CampaignID:    donation.CampaignID,
+		MandateID:     donation.MandateID,
+		MatchFor:      donation.MatchFor,
 	}
 
+	d.AddDonationRecord(donationRecord)
+}
+
+// AddDonationRecord adds an existing donation record, e.g. one loaded from storage
+func (d *Donor) AddDonationRecord(donationRecord DonationRecord) {
 	d.DonationHistory = append(d.DonationHistory, donationRecord)
-	d.TotalDonated += donation.Amount
+	d.TotalDonated = d.TotalDonated.Add(donationRecord.Amount)
 
 	// Add to tax benefits for the current year
 	d.updateTaxBenefits(donationRecord)
 }
 
+// GetDonation returns the donation record with the given transaction ID
+func (d *Donor) GetDonation(transactionID string) (*DonationRecord, bool) {
+	for i := range d.DonationHistory {
+		if d.DonationHistory[i].TransactionID == transactionID {
+			return &d.DonationHistory[i], true
+		}
+	}
+	return nil, false
+}
+
+// ApplyReversal reduces a donation by a refund or chargeback. A partially
+// reversed donation carries the reissued e-bill; a fully reversed one leaves
+// the history, so it no longer counts towards the annual limit or tax benefits.
+func (d *Donor) ApplyReversal(reversal *transactions.DonationReversal) error {
+	record, exists := d.GetDonation(reversal.TransactionID)
+	if !exists {
+		return ErrDonationNotFound
+	}
+	if reversal.GrossAmount.Cmp(record.GrossAmount) > 0 || reversal.Amount.Cmp(record.Amount) > 0 {
+		return fmt.Errorf("reversal exceeds remaining donation amount")
+	}
+
+	record.GrossAmount = record.GrossAmount.Sub(reversal.GrossAmount)
+	record.PlatformFee = record.PlatformFee.Sub(reversal.PlatformFee)
+	record.Amount = record.Amount.Sub(reversal.Amount)
+	d.TotalDonated = d.TotalDonated.Sub(reversal.Amount)
+
+	if record.GrossAmount.IsZero() {
+		history := make([]DonationRecord, 0, len(d.DonationHistory)-1)
+		for _, donation := range d.DonationHistory {
+			if donation.TransactionID != reversal.TransactionID {
+				history = append(history, donation)
+			}
+		}
+		d.DonationHistory = history
+	} else if reversal.ReissuedEBill != nil {
+		record.EBill = reversal.ReissuedEBill
+		record.TaxBenefit = reversal.ReissuedEBill.TaxBenefit
+	}
+
+	d.TaxBenefits = make([]TaxBenefitSummary, 0)
+	for _, donation := range d.DonationHistory {
+		d.updateTaxBenefits(donation)
+	}
+
+	return nil
+}
+
 // updateTaxBenefits updates the tax benefits summary
 func (d *Donor) updateTaxBenefits(donation DonationRecord) {
 	year := donation.Timestamp.Year()
@@ -174,9 +248,9 @@ func (d *Donor) updateTaxBenefits(donation DonationRecord) {
 
 	// Add donation to this year's summary
 	yearBenefit.Donations = append(yearBenefit.Donations, donation)
-	yearBenefit.TotalDonated += donation.Amount
-	yearBenefit.TotalDeductible += donation.TaxBenefit.DeductibleAmount
-	yearBenefit.EstimatedTaxSaving += donation.TaxBenefit.TaxSaving
+	yearBenefit.TotalDonated = yearBenefit.TotalDonated.Add(donation.Amount)
+	yearBenefit.TotalDeductible = yearBenefit.TotalDeductible.Add(donation.TaxBenefit.DeductibleAmount)
+	yearBenefit.EstimatedTaxSaving = yearBenefit.EstimatedTaxSaving.Add(donation.TaxBenefit.TaxSaving)
 }
 
 // AddPreferredNGO adds an NGO to the preferred list
@@ -231,18 +305,18 @@ func (d *Donor) GetAnnualTaxBenefits(year int) *TaxBenefitSummary {
 }
 
 // CheckDonationLimit checks if the donor can make a donation of the specified amount
-func (d *Donor) CheckDonationLimit(amount float64) DonationLimit {
+func (d *Donor) CheckDonationLimit(amount money.Money) DonationLimit {
 	currentYear := time.Now().Year()
-	yearlyTotal := 0.0
+	yearlyTotal := money.Zero()
 
 	for _, donation := range d.DonationHistory {
 		if donation.Timestamp.Year() == currentYear {
-			yearlyTotal += donation.Amount
+			yearlyTotal = yearlyTotal.Add(donation.Amount)
 		}
 	}
 
-	canDonate := (yearlyTotal + amount) <= d.AnnualDonationLimit
-	remainingLimit := d.AnnualDonationLimit - yearlyTotal
+	canDonate := yearlyTotal.Add(amount).Cmp(d.AnnualDonationLimit) <= 0
+	remainingLimit := d.AnnualDonationLimit.Sub(yearlyTotal)
 
 	return DonationLimit{
 		CanDonate:        canDonate,
@@ -255,19 +329,20 @@ func (d *Donor) CheckDonationLimit(amount float64) DonationLimit {
 // GetDonorStats returns comprehensive donor statistics
 func (d *Donor) GetDonorStats() DonorStats {
 	currentYear := time.Now().Year()
-	currentYearTotal := 0.0
+	currentYearTotal := money.Zero()
 	currentYearCount := 0
 
 	for _, donation := range d.DonationHistory {
 		if donation.Timestamp.Year() == currentYear {
-			currentYearTotal += donation.Amount
+			currentYearTotal = currentYearTotal.Add(donation.Amount)
 			currentYearCount++
 		}
 	}
 
-	averageDonation := 0.0
+	averageDonation := money.Zero()
 	if len(d.DonationHistory) > 0 {
-		averageDonation = d.TotalDonated / float64(len(d.DonationHistory))
+		// Dividing by a positive count cannot fail
+		averageDonation, _ = d.TotalDonated.Div(int64(len(d.DonationHistory)), money.RoundHalfUp)
 	}
 
 	verificationLevel := "none"
@@ -314,9 +389,9 @@ func (d *Donor) GetDonationsByDateRange(startDate, endDate time.Time) []Donation
 }
 
 // GetMonthlyDonationSummary returns monthly donation summary for the current year
-func (d *Donor) GetMonthlyDonationSummary() map[string]float64 {
+func (d *Donor) GetMonthlyDonationSummary() map[string]money.Money {
 	currentYear := time.Now().Year()
-	monthlySummary := make(map[string]float64)
+	monthlySummary := make(map[string]money.Money)
 
 	// Initialize all months
 	months := []string{
@@ -324,14 +399,14 @@ func (d *Donor) GetMonthlyDonationSummary() map[string]float64 {
 		"July", "August", "September", "October", "November", "December",
 	}
 	for _, month := range months {
-		monthlySummary[month] = 0.0
+		monthlySummary[month] = money.Zero()
 	}
 
 	// Calculate monthly totals
 	for _, donation := range d.DonationHistory {
 		if donation.Timestamp.Year() == currentYear {
 			monthName := donation.Timestamp.Month().String()
-			monthlySummary[monthName] += donation.Amount
+			monthlySummary[monthName] = monthlySummary[monthName].Add(donation.Amount)
 		}
 	}
 
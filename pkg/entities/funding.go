@@ -0,0 +1,242 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// DonationTrace follows a donation to the expenditures it paid for
+type DonationTrace struct {
+	TransactionID string              `json:"transaction_id"`
+	NGOID         string              `json:"ngo_id"`
+	CampaignID    string              `json:"campaign_id,omitempty"`
+	BlockHash     string              `json:"block_hash"`
+	Amount        money.Money         `json:"amount"`    // Received by the NGO, net of the platform fee
+	Reversed      money.Money         `json:"reversed"`  // Refunded or charged back
+	Allocated     money.Money         `json:"allocated"` // Spent on expenditures
+	Unspent       money.Money         `json:"unspent"`
+	Expenditures  []FundedExpenditure `json:"expenditures"`
+}
+
+// FundedExpenditure is an expenditure block and the part of it a donation paid for
+type FundedExpenditure struct {
+	TransactionID     string      `json:"transaction_id"`
+	BlockHash         string      `json:"block_hash"`
+	BlockIndex        int         `json:"block_index"`
+	Amount            money.Money `json:"amount"` // Paid by the traced donation, negative where an amendment released it
+	ExpenditureAmount money.Money `json:"expenditure_amount"`
+	Category          string      `json:"category"`
+	Description       string      `json:"description"`
+	CampaignID        string      `json:"campaign_id,omitempty"`
+	Timestamp         time.Time   `json:"timestamp"`
+}
+
+// donationBalance tracks how much of a donation is still unspent
+type donationBalance struct {
+	transactionID string
+	campaignID    string
+	block         *blockchain.Block
+	amount        money.Money
+	reversed      money.Money
+	allocated     money.Money
+}
+
+func (b *donationBalance) available() money.Money {
+	return money.Max(money.Zero(), b.amount.Sub(b.reversed).Sub(b.allocated))
+}
+
+// AllocateFunding works out which donations pay for an expenditure. Requested
+// allocations are drawn first and the rest comes from the oldest donations
+// that still have unspent funds. A campaign's expenditures draw only on
+// donations earmarked to it, and other expenditures only on unrestricted
+// donations. Any part not covered by donations is left unallocated. Funds
+// reserved by expenditures awaiting review count as spent.
+func (ngo *NGO) AllocateFunding(campaignID string, amount money.Money, requested, reserved []transactions.FundAllocation) ([]transactions.FundAllocation, error) {
+	balances, order := ngo.donationBalances()
+	for _, allocation := range reserved {
+		if balance, exists := balances[allocation.DonationID]; exists {
+			balance.allocated = balance.allocated.Add(allocation.Amount)
+		}
+	}
+
+	funding := make([]transactions.FundAllocation, 0)
+	allocate := func(balance *donationBalance, share money.Money) {
+		balance.allocated = balance.allocated.Add(share)
+		for i := range funding {
+			if funding[i].DonationID == balance.transactionID {
+				funding[i].Amount = funding[i].Amount.Add(share)
+				return
+			}
+		}
+		funding = append(funding, transactions.FundAllocation{DonationID: balance.transactionID, Amount: share})
+	}
+
+	remaining := amount
+	for _, allocation := range requested {
+		balance, exists := balances[allocation.DonationID]
+		if !exists {
+			return nil, fmt.Errorf("funding donation %s not found", allocation.DonationID)
+		}
+		if balance.campaignID != campaignID {
+			return nil, fmt.Errorf("donation %s is not available to this expenditure's campaign", allocation.DonationID)
+		}
+		if !allocation.Amount.IsPositive() {
+			return nil, fmt.Errorf("funding amount must be positive")
+		}
+		if allocation.Amount.Cmp(balance.available()) > 0 {
+			return nil, fmt.Errorf("donation %s has only ₹%s unspent", allocation.DonationID, balance.available())
+		}
+		if allocation.Amount.Cmp(remaining) > 0 {
+			return nil, fmt.Errorf("funding exceeds expenditure amount")
+		}
+		allocate(balance, allocation.Amount)
+		remaining = remaining.Sub(allocation.Amount)
+	}
+
+	for _, balance := range order {
+		if !remaining.IsPositive() {
+			break
+		}
+		if balance.campaignID != campaignID || !balance.available().IsPositive() {
+			continue
+		}
+		share := money.Min(remaining, balance.available())
+		allocate(balance, share)
+		remaining = remaining.Sub(share)
+	}
+
+	return funding, nil
+}
+
+// TraceDonation returns every expenditure a donation paid for
+func (ngo *NGO) TraceDonation(transactionID string) (*DonationTrace, error) {
+	balances, _ := ngo.donationBalances()
+	balance, exists := balances[transactionID]
+	if !exists {
+		return nil, ErrDonationNotFound
+	}
+
+	expenditures := make([]FundedExpenditure, 0)
+	for _, block := range ngo.ExpenditureBlockchain.FindBlocks(isExpenditureBlock) {
+		blockData := block.Data.(map[string]interface{})
+		for _, allocation := range blockFunding(blockData) {
+			if allocation.DonationID != transactionID {
+				continue
+			}
+			expenditureAmount, _ := blockAmount(blockData)
+			if amended, ok := amountValue(blockData["amended_amount"]); ok {
+				// Amendments record the change in amount, traced with the new total
+				expenditureAmount = amended
+			}
+			expenditure := FundedExpenditure{
+				BlockHash:         block.Hash,
+				BlockIndex:        block.Index,
+				Amount:            allocation.Amount,
+				ExpenditureAmount: expenditureAmount,
+				Timestamp:         block.Timestamp,
+			}
+			expenditure.TransactionID, _ = blockData["transaction_id"].(string)
+			expenditure.Category, _ = blockData["category"].(string)
+			expenditure.Description, _ = blockData["description"].(string)
+			expenditure.CampaignID, _ = blockData["campaign_id"].(string)
+			expenditures = append(expenditures, expenditure)
+		}
+	}
+
+	return &DonationTrace{
+		TransactionID: transactionID,
+		NGOID:         ngo.NGOID,
+		CampaignID:    balance.campaignID,
+		BlockHash:     balance.block.Hash,
+		Amount:        balance.amount,
+		Reversed:      balance.reversed,
+		Allocated:     balance.allocated,
+		Unspent:       balance.available(),
+		Expenditures:  expenditures,
+	}, nil
+}
+
+// donationBalances replays the NGO's chains to find how much of each
+// donation has been reversed and spent. It returns the balances by
+// transaction ID and in the order the donations were received.
+func (ngo *NGO) donationBalances() (map[string]*donationBalance, []*donationBalance) {
+	balances := make(map[string]*donationBalance)
+	order := make([]*donationBalance, 0)
+
+	for _, block := range ngo.DonationBlockchain.FindBlocks(isDonationBlock) {
+		blockData := block.Data.(map[string]interface{})
+		transactionID, _ := blockData["transaction_id"].(string)
+		amount, _ := blockAmount(blockData)
+
+		switch blockData["type"] {
+		case "donation":
+			campaignID, _ := blockData["campaign_id"].(string)
+			balance := &donationBalance{
+				transactionID: transactionID,
+				campaignID:    campaignID,
+				block:         block,
+				amount:        amount,
+				reversed:      money.Zero(),
+				allocated:     money.Zero(),
+			}
+			balances[transactionID] = balance
+			order = append(order, balance)
+		case "donation_reversal":
+			if balance, exists := balances[transactionID]; exists {
+				balance.reversed = balance.reversed.Add(amount)
+			}
+		}
+	}
+
+	for _, block := range ngo.ExpenditureBlockchain.FindBlocks(isExpenditureBlock) {
+		for _, allocation := range blockFunding(block.Data.(map[string]interface{})) {
+			if balance, exists := balances[allocation.DonationID]; exists {
+				balance.allocated = balance.allocated.Add(allocation.Amount)
+			}
+		}
+	}
+
+	return balances, order
+}
+
+// isDonationBlock matches donations and their reversals
+func isDonationBlock(block *blockchain.Block) bool {
+	blockData, ok := block.Data.(map[string]interface{})
+	return ok && (blockData["type"] == "donation" || blockData["type"] == "donation_reversal")
+}
+
+// isExpenditureBlock matches expenditures and their amendments
+func isExpenditureBlock(block *blockchain.Block) bool {
+	blockData, ok := block.Data.(map[string]interface{})
+	return ok && (blockData["type"] == "expenditure" || blockData["type"] == "expenditure_amendment")
+}
+
+// blockFunding reads the donations recorded as paying for an expenditure
+// block. Blocks loaded from the database hold them as decoded JSON.
+func blockFunding(blockData map[string]interface{}) []transactions.FundAllocation {
+	switch funding := blockData["funding"].(type) {
+	case []transactions.FundAllocation:
+		return funding
+	case []interface{}:
+		allocations := make([]transactions.FundAllocation, 0, len(funding))
+		for _, item := range funding {
+			fields, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			donationID, _ := fields["donation_id"].(string)
+			amount, ok := blockAmount(fields)
+			if donationID == "" || !ok {
+				continue
+			}
+			allocations = append(allocations, transactions.FundAllocation{DonationID: donationID, Amount: amount})
+		}
+		return allocations
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,329 @@
+package entities
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Mandate frequencies
+const (
+	FrequencyMonthly   = "monthly"
+	FrequencyQuarterly = "quarterly"
+	FrequencyAnnual    = "annual"
+)
+
+// Mandate statuses
+const (
+	MandateActive    = "active"
+	MandatePaused    = "paused"
+	MandateCancelled = "cancelled"
+	MandateCompleted = "completed" // No installments left before its end date
+)
+
+// RecurringMandate is a donor's standing instruction to donate the same
+// amount to an NGO every month, quarter or year
+type RecurringMandate struct {
+	MandateID      string      `json:"mandate_id"`
+	DonorID        string      `json:"donor_id"`
+	NGOID          string      `json:"ngo_id"`
+	CampaignID     string      `json:"campaign_id,omitempty"`
+	Amount         money.Money `json:"amount"` // Gross amount of each installment
+	PaymentMethod  string      `json:"payment_method"`
+	Frequency      string      `json:"frequency"`
+	Status         string      `json:"status"`
+	StartDate      time.Time   `json:"start_date"`
+	EndDate        *time.Time  `json:"end_date,omitempty"` // Runs until cancelled when nil
+	Installment    int         `json:"installment"`        // Number of the next installment, from 1
+	NextChargeDate time.Time   `json:"next_charge_date"`   // When the next installment is scheduled
+	RetryAt        *time.Time  `json:"retry_at,omitempty"` // When a failed installment is attempted again
+	FailedAttempts int         `json:"failed_attempts"`    // Failed attempts at the next installment
+	LastError      string      `json:"last_error,omitempty"`
+	ChargedCount   int         `json:"charged_count"`
+	MissedCount    int         `json:"missed_count"` // Installments skipped after exhausting retries
+	LastChargedAt  *time.Time  `json:"last_charged_at,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	PausedAt       *time.Time  `json:"paused_at,omitempty"`
+	CancelledAt    *time.Time  `json:"cancelled_at,omitempty"`
+}
+
+// AnnualReceipt consolidates a donor's donations to one NGO over a calendar
+// year into a single receipt for tax filing
+type AnnualReceipt struct {
+	ReceiptNumber      string        `json:"receipt_number"`
+	Year               int           `json:"year"`
+	NGOID              string        `json:"ngo_id"`
+	NGOName            string        `json:"ngo_name"`
+	DonorHash          string        `json:"donor_hash"`
+	Donations          []ReceiptLine `json:"donations"`
+	TotalDonated       money.Money   `json:"total_donated"`
+	TotalDeductible    money.Money   `json:"total_deductible"`
+	EstimatedTaxSaving money.Money   `json:"estimated_tax_saving"`
+	Signature          string        `json:"signature"`
+}
+
+// ReceiptLine is one donation on an annual receipt
+type ReceiptLine struct {
+	TransactionID string      `json:"transaction_id"`
+	ReceiptNumber string      `json:"receipt_number"` // Of the donation's own e-bill
+	Date          time.Time   `json:"date"`
+	Amount        money.Money `json:"amount"`
+	Deductible    money.Money `json:"deductible"`
+	MandateID     string      `json:"mandate_id,omitempty"`
+}
+
+// NewRecurringMandate creates an active mandate whose first installment is due on startDate
+func NewRecurringMandate(donorID, ngoID, campaignID string, amount money.Money, paymentMethod, frequency string, startDate time.Time, endDate *time.Time) (*RecurringMandate, error) {
+	if frequencyMonths(frequency) == 0 {
+		return nil, fmt.Errorf("unsupported frequency: %s", frequency)
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("mandate amount must be positive")
+	}
+	if startDate.IsZero() {
+		startDate = time.Now()
+	}
+	if startDate.Before(time.Now().Truncate(24 * time.Hour)) {
+		return nil, fmt.Errorf("mandate cannot start in the past")
+	}
+	if endDate != nil && endDate.Before(startDate) {
+		return nil, fmt.Errorf("mandate must end after it starts")
+	}
+
+	randomBytes := make([]byte, 12)
+	rand.Read(randomBytes)
+
+	return &RecurringMandate{
+		MandateID:      "MDT_" + hex.EncodeToString(randomBytes),
+		DonorID:        donorID,
+		NGOID:          ngoID,
+		CampaignID:     campaignID,
+		Amount:         amount,
+		PaymentMethod:  paymentMethod,
+		Frequency:      frequency,
+		Status:         MandateActive,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		Installment:    1,
+		NextChargeDate: startDate,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// DueAt returns when the next attempt at charging the mandate is due
+func (m *RecurringMandate) DueAt() time.Time {
+	if m.RetryAt != nil {
+		return *m.RetryAt
+	}
+	return m.NextChargeDate
+}
+
+// IsDue reports whether the mandate is active and its next attempt is due at the given time
+func (m *RecurringMandate) IsDue(at time.Time) bool {
+	return m.Status == MandateActive && !at.Before(m.DueAt())
+}
+
+// RecordCharge records a successful installment and schedules the next one
+func (m *RecurringMandate) RecordCharge(at time.Time) {
+	m.ChargedCount++
+	m.LastChargedAt = &at
+	m.LastError = ""
+	m.advance()
+}
+
+// RecordFailure records a failed attempt at the next installment. It is
+// retried after retryDelay until maxRetries retries have failed, and is then
+// skipped in favour of the following installment.
+func (m *RecurringMandate) RecordFailure(reason string, at time.Time, maxRetries int, retryDelay time.Duration) {
+	m.FailedAttempts++
+	m.LastError = reason
+	if m.FailedAttempts > maxRetries {
+		m.MissedCount++
+		m.advance()
+		return
+	}
+	retryAt := at.Add(retryDelay)
+	m.RetryAt = &retryAt
+}
+
+// Pause stops the mandate from being charged until it is resumed
+func (m *RecurringMandate) Pause(at time.Time) error {
+	if m.Status != MandateActive {
+		return fmt.Errorf("only active mandates can be paused")
+	}
+	m.Status = MandatePaused
+	m.PausedAt = &at
+	return nil
+}
+
+// Resume reactivates a paused mandate. Installments scheduled while it was
+// paused are not charged.
+func (m *RecurringMandate) Resume(at time.Time) error {
+	if m.Status != MandatePaused {
+		return fmt.Errorf("only paused mandates can be resumed")
+	}
+	m.Status = MandateActive
+	m.PausedAt = nil
+	m.RetryAt = nil
+	m.FailedAttempts = 0
+	for m.Status == MandateActive && m.NextChargeDate.Before(at) {
+		m.advance()
+	}
+	return nil
+}
+
+// Cancel permanently stops the mandate
+func (m *RecurringMandate) Cancel(at time.Time) error {
+	if m.Status == MandateCancelled || m.Status == MandateCompleted {
+		return fmt.Errorf("mandate already %s", m.Status)
+	}
+	m.Status = MandateCancelled
+	m.CancelledAt = &at
+	m.RetryAt = nil
+	return nil
+}
+
+// advance schedules the next installment, completing the mandate once the
+// next installment would fall after its end date
+func (m *RecurringMandate) advance() {
+	m.Installment++
+	m.NextChargeDate = addMonths(m.StartDate, (m.Installment-1)*frequencyMonths(m.Frequency))
+	m.RetryAt = nil
+	m.FailedAttempts = 0
+	if m.EndDate != nil && m.NextChargeDate.After(*m.EndDate) {
+		m.Status = MandateCompleted
+	}
+}
+
+// frequencyMonths returns the months between installments, or 0 for an unknown frequency
+func frequencyMonths(frequency string) int {
+	switch frequency {
+	case FrequencyMonthly:
+		return 1
+	case FrequencyQuarterly:
+		return 3
+	case FrequencyAnnual:
+		return 12
+	}
+	return 0
+}
+
+// addMonths adds months to t, keeping its day of month but clamping it to the
+// last day of shorter months, so a mandate started on the 31st is charged on
+// the 30th in April rather than on the 1st of May
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if lastDay := first.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// AddMandate registers a recurring mandate of the donor
+func (d *Donor) AddMandate(mandate *RecurringMandate) error {
+	if mandate.DonorID != d.DonorID {
+		return fmt.Errorf("mandate belongs to another donor")
+	}
+	if _, exists := d.Mandates[mandate.MandateID]; exists {
+		return fmt.Errorf("mandate already exists")
+	}
+	d.Mandates[mandate.MandateID] = mandate
+	return nil
+}
+
+// GetMandate returns one of the donor's mandates
+func (d *Donor) GetMandate(mandateID string) (*RecurringMandate, bool) {
+	mandate, exists := d.Mandates[mandateID]
+	return mandate, exists
+}
+
+// SortedMandates returns the donor's mandates, oldest first
+func (d *Donor) SortedMandates() []*RecurringMandate {
+	mandates := make([]*RecurringMandate, 0, len(d.Mandates))
+	for _, mandate := range d.Mandates {
+		mandates = append(mandates, mandate)
+	}
+	sort.Slice(mandates, func(i, j int) bool {
+		if mandates[i].CreatedAt.Equal(mandates[j].CreatedAt) {
+			return mandates[i].MandateID < mandates[j].MandateID
+		}
+		return mandates[i].CreatedAt.Before(mandates[j].CreatedAt)
+	})
+	return mandates
+}
+
+// AnnualReceipts consolidates the donor's donations in a calendar year into
+// one receipt per NGO, ordered by NGO ID
+func (d *Donor) AnnualReceipts(year int) []AnnualReceipt {
+	donorHashBytes := sha256.Sum256([]byte(d.DonorID))
+	donorHash := hex.EncodeToString(donorHashBytes[:])
+
+	byNGO := make(map[string]*AnnualReceipt)
+	for _, donation := range d.DonationHistory {
+		if donation.Timestamp.Year() != year || !donation.Amount.IsPositive() {
+			continue
+		}
+		receipt, exists := byNGO[donation.NGOID]
+		if !exists {
+			receipt = &AnnualReceipt{
+				ReceiptNumber:      fmt.Sprintf("ACR-%d-%s-%s", year, donation.NGOID, strings.ToUpper(donorHash[:8])),
+				Year:               year,
+				NGOID:              donation.NGOID,
+				DonorHash:          donorHash,
+				Donations:          make([]ReceiptLine, 0),
+				TotalDonated:       money.Zero(),
+				TotalDeductible:    money.Zero(),
+				EstimatedTaxSaving: money.Zero(),
+			}
+			byNGO[donation.NGOID] = receipt
+		}
+
+		line := ReceiptLine{
+			TransactionID: donation.TransactionID,
+			Date:          donation.Timestamp,
+			Amount:        donation.Amount,
+			Deductible:    donation.TaxBenefit.DeductibleAmount,
+			MandateID:     donation.MandateID,
+		}
+		if donation.EBill != nil {
+			line.ReceiptNumber = donation.EBill.ReceiptNumber
+		}
+		receipt.Donations = append(receipt.Donations, line)
+		receipt.TotalDonated = receipt.TotalDonated.Add(donation.Amount)
+		receipt.TotalDeductible = receipt.TotalDeductible.Add(donation.TaxBenefit.DeductibleAmount)
+		receipt.EstimatedTaxSaving = receipt.EstimatedTaxSaving.Add(donation.TaxBenefit.TaxSaving)
+	}
+
+	receipts := make([]AnnualReceipt, 0, len(byNGO))
+	for _, receipt := range byNGO {
+		receipt.Signature = receipt.signature()
+		receipts = append(receipts, *receipt)
+	}
+	sort.Slice(receipts, func(i, j int) bool { return receipts[i].NGOID < receipts[j].NGOID })
+	return receipts
+}
+
+// signature hashes everything on the receipt except the NGO's display name
+// and the signature itself
+func (r *AnnualReceipt) signature() string {
+	signatureData, _ := json.Marshal(map[string]interface{}{
+		"receipt_number":       r.ReceiptNumber,
+		"year":                 r.Year,
+		"ngo_id":               r.NGOID,
+		"donor_hash":           r.DonorHash,
+		"donations":            r.Donations,
+		"total_donated":        r.TotalDonated,
+		"total_deductible":     r.TotalDeductible,
+		"estimated_tax_saving": r.EstimatedTaxSaving,
+	})
+	hash := sha256.Sum256(signatureData)
+	return hex.EncodeToString(hash[:])
+}
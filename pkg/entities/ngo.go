@@ -3,14 +3,24 @@ package entities
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"ngo-transparency-platform/pkg/blockchain"
 	"ngo-transparency-platform/pkg/crypto"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/rating"
 	"ngo-transparency-platform/pkg/transactions"
 	"time"
 )
 
+// Errors returned for records an NGO or donor does not hold
+var (
+	ErrCampaignNotFound    = errors.New("campaign not found")
+	ErrDonationNotFound    = errors.New("donation not found")
+	ErrExpenditureNotFound = errors.New("expenditure not found")
+)
+
 // KYCData represents KYC information for NGO
 type KYCData struct {
 	Verified             bool      `json:"verified"`
@@ -37,46 +47,51 @@ type ProcessResult struct {
 
 // RatingDetails represents detailed rating information
 type RatingDetails struct {
-	Rating              float64 `json:"rating"`
-	TransparencyScore   int     `json:"transparency_score"`
-	UtilizationRate     string  `json:"utilization_rate"`
-	GapPercentage       string  `json:"gap_percentage"`
-	TotalDonations      float64 `json:"total_donations"`
-	TotalExpenditures   float64 `json:"total_expenditures"`
-	PeriodDays          int     `json:"period_days"`
-	DocumentationQuality string  `json:"documentation_quality"`
+	Rating               float64                `json:"rating"`
+	TransparencyScore    int                    `json:"transparency_score"`
+	UtilizationRate      string                 `json:"utilization_rate"`
+	GapPercentage        string                 `json:"gap_percentage"`
+	TotalDonations       money.Money            `json:"total_donations"`
+	TotalExpenditures    money.Money            `json:"total_expenditures"`
+	PeriodDays           int                    `json:"period_days"`
+	DocumentationQuality string                 `json:"documentation_quality"`
+	Campaigns            []CampaignUtilization  `json:"campaigns,omitempty"`
+	Model                string                 `json:"model"`
+	Factors              []rating.Factor        `json:"factors"` // What the rating is made of
+	Peers                *rating.PeerComparison `json:"peers,omitempty"`
 }
 
 // FinancialSummary represents financial summary information
 type FinancialSummary struct {
-	Period             string             `json:"period"`
-	TotalDonations     float64            `json:"total_donations"`
-	TotalExpenditures  float64            `json:"total_expenditures"`
-	DonationCount      int                `json:"donation_count"`
-	ExpenditureCount   int                `json:"expenditure_count"`
-	CategoryBreakdown  map[string]float64 `json:"category_breakdown"`
-	AverageDonation    float64            `json:"average_donation"`
-	MonthlyAverage     map[string]float64 `json:"monthly_average"`
+	Period            string                 `json:"period"`
+	TotalDonations    money.Money            `json:"total_donations"`
+	TotalExpenditures money.Money            `json:"total_expenditures"`
+	DonationCount     int                    `json:"donation_count"`
+	ExpenditureCount  int                    `json:"expenditure_count"`
+	CategoryBreakdown map[string]money.Money `json:"category_breakdown"`
+	AverageDonation   money.Money            `json:"average_donation"`
+	MonthlyAverage    map[string]money.Money `json:"monthly_average"`
 }
 
 // NGO represents a non-governmental organization
 type NGO struct {
-	NGOID                    string                       `json:"ngo_id"`
-	Name                     string                       `json:"name"`
-	RegistrationNumber       string                       `json:"registration_number"`
-	Category                 string                       `json:"category"`
-	Rating                   float64                      `json:"rating"`
-	KYCData                  KYCData                      `json:"kyc_data"`
-	DonationBlockchain       *blockchain.Blockchain       `json:"donation_blockchain"`
-	ExpenditureBlockchain    *blockchain.Blockchain       `json:"expenditure_blockchain"`
-	MultiSigWallet           *crypto.MultiSigWallet       `json:"multi_sig_wallet"`
-	TotalDonationsReceived   float64                      `json:"total_donations_received"`
-	TotalExpenditureReported float64                      `json:"total_expenditure_reported"`
-	TransparencyScore        int                          `json:"transparency_score"`
-	CreatedAt                time.Time                    `json:"created_at"`
-	LastAuditDate            *time.Time                   `json:"last_audit_date"`
-	Certificates             []Certificate                `json:"certificates"`
-	PublicKey                string                       `json:"public_key"`
+	NGOID                    string                 `json:"ngo_id"`
+	Name                     string                 `json:"name"`
+	RegistrationNumber       string                 `json:"registration_number"`
+	Category                 string                 `json:"category"`
+	Rating                   float64                `json:"rating"`
+	KYCData                  KYCData                `json:"kyc_data"`
+	DonationBlockchain       *blockchain.Blockchain `json:"donation_blockchain"`
+	ExpenditureBlockchain    *blockchain.Blockchain `json:"expenditure_blockchain"`
+	MultiSigWallet           *crypto.MultiSigWallet `json:"multi_sig_wallet"`
+	TotalDonationsReceived   money.Money            `json:"total_donations_received"`
+	TotalExpenditureReported money.Money            `json:"total_expenditure_reported"`
+	TransparencyScore        int                    `json:"transparency_score"`
+	CreatedAt                time.Time              `json:"created_at"`
+	LastAuditDate            *time.Time             `json:"last_audit_date"`
+	Certificates             []Certificate          `json:"certificates"`
+	PublicKey                string                 `json:"public_key"`
+	Campaigns                map[string]*Campaign   `json:"campaigns"`
 }
 
 // NewNGO creates a new NGO instance
@@ -107,12 +122,13 @@ func NewNGO(ngoID, name, registrationNumber, category string, kycData map[string
 		DonationBlockchain:       blockchain.NewBlockchain(ngoID, "donation", 2),
 		ExpenditureBlockchain:    blockchain.NewBlockchain(ngoID, "expenditure", 2),
 		MultiSigWallet:           crypto.NewMultiSigWallet(2),
-		TotalDonationsReceived:   0,
-		TotalExpenditureReported: 0,
+		TotalDonationsReceived:   money.Zero(),
+		TotalExpenditureReported: money.Zero(),
 		TransparencyScore:        100,
 		CreatedAt:                time.Now(),
 		Certificates:             make([]Certificate, 0),
 		PublicKey:                publicKey,
+		Campaigns:                make(map[string]*Campaign),
 	}
 
 	// Add multi-sig signers
@@ -141,10 +157,18 @@ func (ngo *NGO) ProcessDonation(donation *transactions.DonationTransaction) (*Pr
 	}
 
 	// Verify ZK proof
-	if !crypto.VerifyProof(donation.ZKProof, donation.Amount, donation.Timestamp) {
+	if !crypto.VerifyProof(donation.ZKProof, donation.Amount.Float64(), donation.Timestamp) {
 		return nil, fmt.Errorf("invalid zero-knowledge proof")
 	}
 
+	var campaign *Campaign
+	if donation.CampaignID != "" {
+		var exists bool
+		if campaign, exists = ngo.Campaigns[donation.CampaignID]; !exists {
+			return nil, ErrCampaignNotFound
+		}
+	}
+
 	// Create block data
 	blockData := map[string]interface{}{
 		"type":           "donation",
@@ -157,6 +181,9 @@ func (ngo *NGO) ProcessDonation(donation *transactions.DonationTransaction) (*Pr
 		"timestamp":      donation.Timestamp,
 		"payment_method": donation.PaymentMethod,
 	}
+	if campaign != nil {
+		blockData["campaign_id"] = campaign.CampaignID
+	}
 
 	block := blockchain.NewBlock(
 		ngo.DonationBlockchain.GetChainLength(),
@@ -172,7 +199,10 @@ func (ngo *NGO) ProcessDonation(donation *transactions.DonationTransaction) (*Pr
 	block.AddValidator("zk_system", donation.ZKProof.Proof, "zkproof")
 
 	if ngo.DonationBlockchain.AddBlock(block) {
-		ngo.TotalDonationsReceived += donation.Amount
+		ngo.TotalDonationsReceived = ngo.TotalDonationsReceived.Add(donation.Amount)
+		if campaign != nil {
+			campaign.RaisedAmount = campaign.RaisedAmount.Add(donation.Amount)
+		}
 		donation.MarkComplete()
 
 		return &ProcessResult{
@@ -188,34 +218,120 @@ func (ngo *NGO) ProcessDonation(donation *transactions.DonationTransaction) (*Pr
 	}
 }
 
-// ProcessExpenditure processes an expenditure transaction
+// ReverseDonation appends a block reversing all or part of a donation. The
+// original block is left untouched; the reversal block references its hash.
+func (ngo *NGO) ReverseDonation(reversal *transactions.DonationReversal) (*ProcessResult, error) {
+	original := ngo.DonationBlockchain.GetBlockByHash(reversal.OriginalBlockHash)
+	if original == nil {
+		return nil, fmt.Errorf("original donation block not found")
+	}
+	if reversal.Amount.Cmp(ngo.TotalDonationsReceived) > 0 {
+		return nil, fmt.Errorf("reversal exceeds donations received")
+	}
+
+	// A reversal takes the money back out of the campaign it was earmarked to
+	campaign := ngo.blockCampaign(original)
+	if campaign != nil {
+		reversal.CampaignID = campaign.CampaignID
+	}
+
+	blockData := map[string]interface{}{
+		"type":                "donation_reversal",
+		"reversal_id":         reversal.ReversalID,
+		"kind":                reversal.Kind,
+		"transaction_id":      reversal.TransactionID,
+		"original_block_hash": reversal.OriginalBlockHash,
+		"donor_hash":          ngo.generateDonorHash(reversal.DonorID),
+		"amount":              reversal.Amount,
+		"gross_amount":        reversal.GrossAmount,
+		"currency":            "INR",
+		"reason":              reversal.Reason,
+		"gateway_reference":   reversal.GatewayReference,
+		"voided_bill_id":      reversal.VoidedBillID,
+		"reissued_e_bill":     reversal.ReissuedEBill,
+		"timestamp":           reversal.Timestamp,
+	}
+	if campaign != nil {
+		blockData["campaign_id"] = campaign.CampaignID
+	}
+
+	block := blockchain.NewBlock(
+		ngo.DonationBlockchain.GetChainLength(),
+		time.Now(),
+		blockData,
+		ngo.DonationBlockchain.GetLatestBlock().Hash,
+		"donation",
+	)
+	block.Validate()
+	if reversal.ReissuedEBill != nil {
+		block.AddValidator("ebill_system", reversal.ReissuedEBill.Signature, "ebill")
+	}
+
+	if !ngo.DonationBlockchain.AddBlock(block) {
+		return nil, fmt.Errorf("failed to add block to blockchain")
+	}
+	ngo.TotalDonationsReceived = ngo.TotalDonationsReceived.Sub(reversal.Amount)
+	if campaign != nil {
+		campaign.RaisedAmount = campaign.RaisedAmount.Sub(reversal.Amount)
+	}
+	reversal.BlockHash = block.Hash
+
+	return &ProcessResult{
+		Success:       true,
+		BlockHash:     block.Hash,
+		TransactionID: reversal.TransactionID,
+		BlockIndex:    block.Index,
+		EBill:         reversal.ReissuedEBill,
+	}, nil
+}
+
+// ProcessExpenditure records an expenditure approved by its auditors, with
+// the funding allocated when it was submitted. The auditors' decision and
+// score stand; the invoice, funds and campaign balance are checked when the
+// expenditure is submitted for review.
 func (ngo *NGO) ProcessExpenditure(expenditure *transactions.ExpenditureTransaction) (*ProcessResult, error) {
 	if expenditure.AuditorValidation == nil || !expenditure.AuditorValidation.IsValid {
 		return nil, fmt.Errorf("expenditure not validated by auditor")
 	}
 
-	if !expenditure.VerifyGSTIN(expenditure.InvoiceDetails.GSTIN) {
-		return nil, fmt.Errorf("invalid GSTIN format")
-	}
-
-	// Check compliance score
-	if expenditure.ComplianceScore < 60 {
-		return nil, fmt.Errorf("low compliance score: %.1f%%. Minimum required: 60%%", expenditure.ComplianceScore)
+	var campaign *Campaign
+	if expenditure.CampaignID != "" {
+		var exists bool
+		if campaign, exists = ngo.Campaigns[expenditure.CampaignID]; !exists {
+			return nil, ErrCampaignNotFound
+		}
 	}
 
 	// Create block data
 	blockData := map[string]interface{}{
-		"type":               "expenditure",
-		"transaction_id":     expenditure.TransactionID,
-		"amount":             expenditure.Amount,
-		"currency":           "INR",
-		"category":           expenditure.Category,
-		"description":        expenditure.Description,
-		"invoice_details":    expenditure.InvoiceDetails,
-		"auditor_validation": expenditure.AuditorValidation,
-		"compliance_score":   expenditure.ComplianceScore,
-		"timestamp":          expenditure.Timestamp,
-		"attachments":        ngo.extractAttachmentHashes(expenditure.Attachments),
+		"type":                     "expenditure",
+		"transaction_id":           expenditure.TransactionID,
+		"amount":                   expenditure.Amount,
+		"currency":                 "INR",
+		"category":                 expenditure.Category,
+		"description":              expenditure.Description,
+		"invoice_details":          expenditure.InvoiceDetails,
+		"auditor_validation":       expenditure.AuditorValidation,
+		"compliance_score":         expenditure.ComplianceScore,
+		"compliance_rules_version": expenditure.ComplianceRulesVersion,
+		"timestamp":                expenditure.Timestamp,
+		"attachments":              ngo.extractAttachmentHashes(expenditure.Attachments),
+		"funding":                  expenditure.Funding,
+	}
+	if campaign != nil {
+		blockData["campaign_id"] = campaign.CampaignID
+	}
+	if len(expenditure.InvoiceFlags) > 0 {
+		blockData["invoice_flags"] = expenditure.InvoiceFlags
+	}
+	if len(expenditure.Reviews) > 0 {
+		blockData["reviews"] = expenditure.Reviews
+	}
+	if expenditure.Consensus != nil {
+		blockData["consensus"] = expenditure.Consensus
+	}
+	if expenditure.Appeal != nil {
+		blockData["appeal"] = expenditure.Appeal
 	}
 
 	block := blockchain.NewBlock(
@@ -226,16 +342,30 @@ func (ngo *NGO) ProcessExpenditure(expenditure *transactions.ExpenditureTransact
 		"expenditure",
 	)
 
-	// Validate block with auditor signature
+	// Validate block with the signature of every auditor who decided it,
+	// recording dissenting panel members as well
 	block.Validate()
-	block.AddValidator(
-		expenditure.AuditorValidation.AuditorID,
-		expenditure.AuditorValidation.Signature,
-		"auditor",
-	)
+	votes := expenditure.Votes()
+	for _, vote := range votes {
+		validationType := "auditor"
+		if vote.Decision != transactions.DecisionApprove {
+			validationType = "auditor_dissent"
+		}
+		block.AddValidator(vote.ReviewerID, vote.Signature, validationType)
+	}
+	if len(votes) == 0 {
+		block.AddValidator(
+			expenditure.AuditorValidation.AuditorID,
+			expenditure.AuditorValidation.Signature,
+			"auditor",
+		)
+	}
 
 	if ngo.ExpenditureBlockchain.AddBlock(block) {
-		ngo.TotalExpenditureReported += expenditure.Amount
+		ngo.TotalExpenditureReported = ngo.TotalExpenditureReported.Add(expenditure.Amount)
+		if campaign != nil {
+			campaign.SpentAmount = campaign.SpentAmount.Add(expenditure.Amount)
+		}
 
 		return &ProcessResult{
 			Success:       true,
@@ -248,74 +378,95 @@ func (ngo *NGO) ProcessExpenditure(expenditure *transactions.ExpenditureTransact
 	}
 }
 
-// CalculateRating calculates the NGO's rating based on recent activity
-func (ngo *NGO) CalculateRating(periodDays int) RatingDetails {
-	periodMs := time.Duration(periodDays) * 24 * time.Hour
-	startDate := time.Now().Add(-periodMs)
-
-	donations := ngo.DonationBlockchain.GetBlocksByDateRange(startDate, time.Now())
-	expenditures := ngo.ExpenditureBlockchain.GetBlocksByDateRange(startDate, time.Now())
-
-	totalDonations := ngo.sumBlockAmounts(donations)
-	totalExpenditures := ngo.sumBlockAmounts(expenditures)
-
-	utilizationRate := 0.0
-	if totalDonations > 0 {
-		utilizationRate = totalExpenditures / totalDonations
+// RecordDeniedAppeal appends a block recording that a new panel upheld the
+// rejection of an appealed expenditure. The block references the original
+// decision and carries no amount, as nothing was spent.
+func (ngo *NGO) RecordDeniedAppeal(expenditure *transactions.ExpenditureTransaction) (*ProcessResult, error) {
+	if expenditure.Appeal == nil || expenditure.Appeal.Outcome != transactions.AppealDenied {
+		return nil, fmt.Errorf("expenditure has no denied appeal")
 	}
 
-	gap := math.Abs(totalDonations - totalExpenditures)
-	gapPercentage := 0.0
-	if totalDonations > 0 {
-		gapPercentage = (gap / totalDonations) * 100
+	blockData := map[string]interface{}{
+		"type":               "expenditure_appeal",
+		"transaction_id":     expenditure.TransactionID,
+		"outcome":            expenditure.Appeal.Outcome,
+		"claimed_amount":     expenditure.Amount,
+		"currency":           "INR",
+		"category":           expenditure.Category,
+		"description":        expenditure.Description,
+		"invoice_details":    expenditure.InvoiceDetails,
+		"auditor_validation": expenditure.AuditorValidation,
+		"appeal":             expenditure.Appeal,
+		"reviews":            expenditure.Reviews,
+		"timestamp":          expenditure.Timestamp,
+		"attachments":        ngo.extractAttachmentHashes(expenditure.Attachments),
 	}
-
-	// Rating calculation (1.0 to 5.0)
-	rating := 5.0
-
-	// Penalize large gaps
-	if gapPercentage > 50 {
-		rating -= 2.0
-	} else if gapPercentage > 30 {
-		rating -= 1.0
-	} else if gapPercentage > 15 {
-		rating -= 0.5
+	if expenditure.Consensus != nil {
+		blockData["consensus"] = expenditure.Consensus
 	}
 
-	// Reward optimal utilization (60-85% is ideal)
-	if utilizationRate >= 0.6 && utilizationRate <= 0.85 {
-		rating += 0.5
-	} else if utilizationRate < 0.3 || utilizationRate > 0.95 {
-		rating -= 0.5
-	}
+	block := blockchain.NewBlock(
+		ngo.ExpenditureBlockchain.GetChainLength(),
+		time.Now(),
+		blockData,
+		ngo.ExpenditureBlockchain.GetLatestBlock().Hash,
+		"expenditure",
+	)
 
-	// Transparency and documentation bonus
-	documentationQuality := ngo.calculateDocumentationQuality()
-	rating += documentationQuality * 0.5
+	// Validate block with the signature of every auditor who decided the
+	// appeal, recording those who would have granted it as dissenting
+	block.Validate()
+	for _, vote := range expenditure.Votes() {
+		validationType := "auditor"
+		if vote.Decision == transactions.DecisionApprove {
+			validationType = "auditor_dissent"
+		}
+		block.AddValidator(vote.ReviewerID, vote.Signature, validationType)
+	}
 
-	// KYC verification bonus
-	if ngo.KYCData.Verified {
-		rating += 0.2
+	if !ngo.ExpenditureBlockchain.AddBlock(block) {
+		return nil, fmt.Errorf("failed to add appeal block to blockchain")
 	}
+	return &ProcessResult{
+		Success:       true,
+		BlockHash:     block.Hash,
+		TransactionID: expenditure.TransactionID,
+		BlockIndex:    block.Index,
+	}, nil
+}
 
-	// Certificate bonus
-	if len(ngo.Certificates) > 0 {
-		rating += 0.3
+// RatingInputs gathers what the NGO is rated on: donations and spending in
+// the past periodDays and the documentation of its recent expenditures
+func (ngo *NGO) RatingInputs(periodDays int, now time.Time) rating.Inputs {
+	startDate := now.Add(-time.Duration(periodDays) * 24 * time.Hour)
+
+	return rating.Inputs{
+		Donations:     blockFlows(ngo.DonationBlockchain.GetBlocksByDateRange(startDate, now)),
+		Expenditures:  blockFlows(ngo.ExpenditureBlockchain.GetBlocksByDateRange(startDate, now)),
+		Documentation: ngo.documentationScores(),
+		KYCVerified:   ngo.KYCData.Verified,
+		Certificates:  len(ngo.Certificates),
 	}
+}
 
-	rating = math.Max(1.0, math.Min(5.0, rating))
-	ngo.Rating = rating
-	ngo.TransparencyScore = int(math.Round((rating / 5.0) * 100))
+// ApplyRating makes a model's result the NGO's rating and returns its details
+func (ngo *NGO) ApplyRating(result rating.Result, inputs rating.Inputs, periodDays int) RatingDetails {
+	ngo.Rating = result.Rating
+	ngo.TransparencyScore = int(math.Round((result.Rating / 5.0) * 100))
 
 	return RatingDetails{
-		Rating:               rating,
+		Rating:               result.Rating,
 		TransparencyScore:    ngo.TransparencyScore,
-		UtilizationRate:      fmt.Sprintf("%.2f%%", utilizationRate*100),
-		GapPercentage:        fmt.Sprintf("%.2f%%", gapPercentage),
-		TotalDonations:       totalDonations,
-		TotalExpenditures:    totalExpenditures,
-		PeriodDays:          periodDays,
-		DocumentationQuality: fmt.Sprintf("%.1f%%", documentationQuality*100),
+		UtilizationRate:      fmt.Sprintf("%.2f%%", result.UtilizationRate*100),
+		GapPercentage:        fmt.Sprintf("%.2f%%", result.GapPercentage),
+		TotalDonations:       rating.Total(inputs.Donations),
+		TotalExpenditures:    rating.Total(inputs.Expenditures),
+		PeriodDays:           periodDays,
+		DocumentationQuality: fmt.Sprintf("%.1f%%", result.DocumentationQuality*100),
+		Campaigns:            ngo.CampaignUtilizations(),
+		Model:                result.Model,
+		Factors:              result.Factors,
+		Peers:                result.Peers,
 	}
 }
 
@@ -349,12 +500,12 @@ func (ngo *NGO) GetFinancialSummary(months int) FinancialSummary {
 	expenditures := ngo.ExpenditureBlockchain.GetBlocksByDateRange(startDate, time.Now())
 
 	// Category-wise expenditure breakdown
-	categoryBreakdown := make(map[string]float64)
+	categoryBreakdown := make(map[string]money.Money)
 	for _, block := range expenditures {
 		if blockData, ok := block.Data.(map[string]interface{}); ok {
 			if category, ok := blockData["category"].(string); ok {
-				if amount, ok := blockData["amount"].(float64); ok {
-					categoryBreakdown[category] += amount
+				if amount, ok := blockAmount(blockData); ok {
+					categoryBreakdown[category] = categoryBreakdown[category].Add(amount)
 				}
 			}
 		}
@@ -363,14 +514,19 @@ func (ngo *NGO) GetFinancialSummary(months int) FinancialSummary {
 	totalDonations := ngo.sumBlockAmounts(donations)
 	totalExpenditures := ngo.sumBlockAmounts(expenditures)
 	
-	averageDonation := 0.0
+	averageDonation := money.Zero()
 	if len(donations) > 0 {
-		averageDonation = totalDonations / float64(len(donations))
+		// Dividing by a positive count cannot fail
+		averageDonation, _ = totalDonations.Div(int64(len(donations)), money.RoundHalfUp)
 	}
 
-	monthlyAverage := map[string]float64{
-		"donations":    totalDonations / float64(months),
-		"expenditures": totalExpenditures / float64(months),
+	monthlyAverage := map[string]money.Money{
+		"donations":    money.Zero(),
+		"expenditures": money.Zero(),
+	}
+	if months > 0 {
+		monthlyAverage["donations"], _ = totalDonations.Div(int64(months), money.RoundHalfUp)
+		monthlyAverage["expenditures"], _ = totalExpenditures.Div(int64(months), money.RoundHalfUp)
 	}
 
 	return FinancialSummary{
@@ -404,31 +560,53 @@ func (ngo *NGO) extractAttachmentHashes(attachments []transactions.Attachment) [
 	return result
 }
 
-func (ngo *NGO) sumBlockAmounts(blocks []*blockchain.Block) float64 {
-	total := 0.0
+func (ngo *NGO) sumBlockAmounts(blocks []*blockchain.Block) money.Money {
+	return rating.Total(blockFlows(blocks))
+}
+
+// blockFlows reads the amounts of blocks, counting reversals against donations
+func blockFlows(blocks []*blockchain.Block) []rating.Flow {
+	flows := make([]rating.Flow, 0, len(blocks))
 	for _, block := range blocks {
 		if blockData, ok := block.Data.(map[string]interface{}); ok {
-			if amount, ok := blockData["amount"].(float64); ok {
-				total += amount
+			if amount, ok := blockAmount(blockData); ok {
+				if blockData["type"] == "donation_reversal" {
+					amount = amount.Neg()
+				}
+				flows = append(flows, rating.Flow{Amount: amount, Timestamp: block.Timestamp})
 			}
 		}
 	}
-	return total
+	return flows
 }
 
-func (ngo *NGO) calculateDocumentationQuality() float64 {
-	recentBlocks := ngo.ExpenditureBlockchain.GetRecentBlocks(10)
-	if len(recentBlocks) == 0 {
-		return 1.0 // Perfect score if no expenditures yet
+// blockAmount reads the amount recorded in block data. Block data holds amounts
+// as JSON numbers in rupees, which convert back to paise exactly.
+func blockAmount(blockData map[string]interface{}) (money.Money, bool) {
+	return amountValue(blockData["amount"])
+}
+
+// amountValue reads an amount held in block data
+func amountValue(value interface{}) (money.Money, bool) {
+	switch amount := value.(type) {
+	case float64:
+		return money.FromMajor(amount), true
+	case money.Money:
+		return amount, true
+	default:
+		return money.Money{}, false
 	}
+}
 
-	score := 0.0
-	validBlocks := 0
+// documentationScores scores how well each of the NGO's recent expenditures
+// was documented
+func (ngo *NGO) documentationScores() []rating.Score {
+	recentBlocks := ngo.ExpenditureBlockchain.GetRecentBlocks(10)
+	scores := make([]rating.Score, 0, len(recentBlocks))
 
 	for _, block := range recentBlocks {
 		if blockData, ok := block.Data.(map[string]interface{}); ok {
 			if blockType, ok := blockData["type"].(string); ok && blockType == "expenditure" {
-				validBlocks++
 				blockScore := 0.0
 
 				// Basic invoice details (40%)
@@ -468,13 +646,10 @@ func (ngo *NGO) calculateDocumentationQuality() float64 {
 					blockScore += 0.1
 				}
 
-				score += blockScore
+				scores = append(scores, rating.Score{Value: math.Min(1.0, blockScore), Timestamp: block.Timestamp})
 			}
 		}
 	}
 
-	if validBlocks == 0 {
-		return 1.0
-	}
-	return math.Min(1.0, score/float64(validBlocks))
+	return scores
 }
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/gst"
+)
+
+// Vendor is a GST-registered supplier that NGOs pay. The registry is shared by
+// all NGOs and keyed by GSTIN.
+type Vendor struct {
+	GSTIN        string    `json:"gstin"`
+	LegalName    string    `json:"legal_name"`
+	StateCode    string    `json:"state_code"`
+	State        string    `json:"state"`
+	PAN          string    `json:"pan"`
+	RegisteredBy string    `json:"registered_by"` // NGO that first registered or paid the vendor
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewVendor creates a vendor after validating its GSTIN
+func NewVendor(gstin, legalName, registeredBy string) (*Vendor, error) {
+	parsed, err := gst.Parse(gstin)
+	if err != nil {
+		return nil, err
+	}
+	legalName = strings.TrimSpace(legalName)
+	if legalName == "" {
+		return nil, fmt.Errorf("vendor legal name is required")
+	}
+
+	return &Vendor{
+		GSTIN:        parsed.Number,
+		LegalName:    legalName,
+		StateCode:    parsed.StateCode,
+		State:        parsed.State,
+		PAN:          parsed.PAN,
+		RegisteredBy: registeredBy,
+		CreatedAt:    time.Now(),
+	}, nil
+}
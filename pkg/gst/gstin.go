@@ -0,0 +1,113 @@
+// Package gst validates Goods and Services Tax Identification Numbers. A
+// GSTIN is a two-digit state code, the holder's ten-character PAN, an entity
+// number for multiple registrations under the same PAN, the letter Z and a
+// check character computed over the first fourteen characters.
+package gst
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var gstinPattern = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+
+// States maps GST state codes to state and union territory names
+var States = map[string]string{
+	"01": "Jammu and Kashmir",
+	"02": "Himachal Pradesh",
+	"03": "Punjab",
+	"04": "Chandigarh",
+	"05": "Uttarakhand",
+	"06": "Haryana",
+	"07": "Delhi",
+	"08": "Rajasthan",
+	"09": "Uttar Pradesh",
+	"10": "Bihar",
+	"11": "Sikkim",
+	"12": "Arunachal Pradesh",
+	"13": "Nagaland",
+	"14": "Manipur",
+	"15": "Mizoram",
+	"16": "Tripura",
+	"17": "Meghalaya",
+	"18": "Assam",
+	"19": "West Bengal",
+	"20": "Jharkhand",
+	"21": "Odisha",
+	"22": "Chhattisgarh",
+	"23": "Madhya Pradesh",
+	"24": "Gujarat",
+	"25": "Daman and Diu",
+	"26": "Dadra and Nagar Haveli and Daman and Diu",
+	"27": "Maharashtra",
+	"28": "Andhra Pradesh (before reorganisation)",
+	"29": "Karnataka",
+	"30": "Goa",
+	"31": "Lakshadweep",
+	"32": "Kerala",
+	"33": "Tamil Nadu",
+	"34": "Puducherry",
+	"35": "Andaman and Nicobar Islands",
+	"36": "Telangana",
+	"37": "Andhra Pradesh",
+	"38": "Ladakh",
+	"97": "Other Territory",
+	"99": "Centre Jurisdiction",
+}
+
+// GSTIN is a validated GST registration number and the parts encoded in it
+type GSTIN struct {
+	Number       string `json:"gstin"`
+	StateCode    string `json:"state_code"`
+	State        string `json:"state"`
+	PAN          string `json:"pan"`
+	EntityNumber string `json:"entity_number"` // Registration of the PAN within the state, 1-9 then A-Z
+}
+
+// Parse validates a GSTIN's format, state code and check character. Spaces
+// around the number and lower case letters are accepted.
+func Parse(number string) (*GSTIN, error) {
+	number = strings.ToUpper(strings.TrimSpace(number))
+	if !gstinPattern.MatchString(number) {
+		return nil, fmt.Errorf("invalid GSTIN format")
+	}
+
+	state, known := States[number[:2]]
+	if !known {
+		return nil, fmt.Errorf("invalid GSTIN state code %s", number[:2])
+	}
+
+	if check := CheckCharacter(number[:14]); check != number[14] {
+		return nil, fmt.Errorf("invalid GSTIN check character")
+	}
+
+	return &GSTIN{
+		Number:       number,
+		StateCode:    number[:2],
+		State:        state,
+		PAN:          number[2:12],
+		EntityNumber: number[12:13],
+	}, nil
+}
+
+// Valid reports whether number is a well-formed GSTIN with a correct check character
+func Valid(number string) bool {
+	_, err := Parse(number)
+	return err == nil
+}
+
+// CheckCharacter computes the check character of the first fourteen
+// characters of a GSTIN. Each character's value is multiplied by 1 and 2
+// alternately; the quotient and remainder of each product by 36 are summed,
+// and the check character is the value that brings the sum to a multiple of 36.
+func CheckCharacter(prefix string) byte {
+	sum := 0
+	for i := 0; i < len(prefix); i++ {
+		product := strings.IndexByte(alphabet, prefix[i]) * (i%2 + 1)
+		sum += product/36 + product%36
+	}
+	return alphabet[(36-sum%36)%36]
+}
@@ -0,0 +1,35 @@
+package gst
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	gstin, err := Parse(" 27aapfu0939f1zv ")
+	if err != nil {
+		t.Fatalf("Failed to parse GSTIN: %v", err)
+	}
+	if gstin.Number != "27AAPFU0939F1ZV" || gstin.State != "Maharashtra" || gstin.PAN != "AAPFU0939F" || gstin.EntityNumber != "1" {
+		t.Errorf("Unexpected GSTIN %+v", gstin)
+	}
+}
+
+func TestParseRejectsInvalidNumbers(t *testing.T) {
+	for _, number := range []string{
+		"",
+		"27AAPFU0939F1ZW", // Wrong check character
+		"27AAPFU0939F1Z",  // Too short
+		"27AAPFU0939F0ZV", // Entity number cannot be 0
+		"27AAPFU0939F1XV", // Fourteenth character must be Z
+		"40AAPFU0939F1ZK", // Unknown state code
+		"2AAAPFU0939F1ZV", // Malformed state code
+	} {
+		if Valid(number) {
+			t.Errorf("Expected %q to be invalid", number)
+		}
+	}
+}
+
+func TestCheckCharacter(t *testing.T) {
+	if check := CheckCharacter("29AAGCB7383J1Z"); check != '4' {
+		t.Errorf("Expected check character 4, got %c", check)
+	}
+}
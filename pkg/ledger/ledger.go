@@ -0,0 +1,358 @@
+// Package ledger implements double-entry bookkeeping for NGOs. Every NGO has
+// its own chart of accounts, and every donation and expenditure is recorded as
+// balanced journal entries from which trial balances and balance sheets are
+// derived.
+package ledger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// AccountType classifies an account for reporting
+type AccountType string
+
+const (
+	Asset     AccountType = "asset"
+	Liability AccountType = "liability"
+	NetAssets AccountType = "net_assets"
+	Revenue   AccountType = "revenue"
+	Expense   AccountType = "expense"
+)
+
+// DebitNormal reports whether the account type increases with debits
+func (t AccountType) DebitNormal() bool {
+	return t == Asset || t == Expense
+}
+
+// Account codes in every NGO's chart of accounts
+const (
+	AccountBank                = "1000"
+	AccountDonationsReceivable = "1100"
+	AccountVendorPayables      = "2000"
+	AccountRestrictedFunds     = "3000"
+	AccountProgramExpenses     = "5000"
+	AccountPlatformFees        = "5100"
+)
+
+// Account is a single account in a chart of accounts
+type Account struct {
+	Code string      `json:"code"`
+	Name string      `json:"name"`
+	Type AccountType `json:"type"`
+}
+
+// DefaultChart returns the chart of accounts opened for every NGO
+func DefaultChart() []Account {
+	return []Account{
+		{Code: AccountBank, Name: "Bank", Type: Asset},
+		{Code: AccountDonationsReceivable, Name: "Donations Receivable", Type: Asset},
+		{Code: AccountVendorPayables, Name: "Vendor Payables", Type: Liability},
+		{Code: AccountRestrictedFunds, Name: "Restricted Funds", Type: NetAssets},
+		{Code: AccountProgramExpenses, Name: "Program Expenses", Type: Expense},
+		{Code: AccountPlatformFees, Name: "Platform Fees", Type: Expense},
+	}
+}
+
+// Line is one debit or credit of a journal entry
+type Line struct {
+	Account string      `json:"account"`
+	Debit   money.Money `json:"debit"`
+	Credit  money.Money `json:"credit"`
+}
+
+// JournalEntry is a balanced set of debits and credits posted together
+type JournalEntry struct {
+	EntryID     string    `json:"entry_id"`
+	NGOID       string    `json:"ngo_id"`
+	Reference   string    `json:"reference"` // Source transaction ID
+	Description string    `json:"description"`
+	Lines       []Line    `json:"lines"`
+	PostedAt    time.Time `json:"posted_at"`
+}
+
+// Totals returns the sum of debits and credits of the entry
+func (e *JournalEntry) Totals() (debits, credits money.Money) {
+	debits, credits = money.Zero(), money.Zero()
+	for _, line := range e.Lines {
+		debits = debits.Add(line.Debit)
+		credits = credits.Add(line.Credit)
+	}
+	return debits, credits
+}
+
+// Ledger holds the books of all NGOs
+type Ledger struct {
+	charts   map[string]map[string]Account
+	balances map[string]map[string]money.Money // Debit minus credit per account
+	entries  map[string][]*JournalEntry
+	entryIDs map[string]bool
+	mutex    sync.RWMutex
+}
+
+// NewLedger creates an empty ledger
+func NewLedger() *Ledger {
+	return &Ledger{
+		charts:   make(map[string]map[string]Account),
+		balances: make(map[string]map[string]money.Money),
+		entries:  make(map[string][]*JournalEntry),
+		entryIDs: make(map[string]bool),
+	}
+}
+
+// OpenBooks creates the default chart of accounts for an NGO if it has none
+func (l *Ledger) OpenBooks(ngoID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, exists := l.charts[ngoID]; exists {
+		return
+	}
+
+	chart := make(map[string]Account)
+	balances := make(map[string]money.Money)
+	for _, account := range DefaultChart() {
+		chart[account.Code] = account
+		balances[account.Code] = money.Zero()
+	}
+	l.charts[ngoID] = chart
+	l.balances[ngoID] = balances
+}
+
+// HasBooks reports whether books are open for the NGO
+func (l *Ledger) HasBooks(ngoID string) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	_, exists := l.charts[ngoID]
+	return exists
+}
+
+// Validate checks that the entries could be posted without posting them
+func (l *Ledger) Validate(entries ...*JournalEntry) error {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.validate(entries)
+}
+
+// Post validates and posts the entries. Either all entries are posted or none.
+func (l *Ledger) Post(entries ...*JournalEntry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := l.validate(entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		balances := l.balances[entry.NGOID]
+		for _, line := range entry.Lines {
+			balances[line.Account] = balances[line.Account].Add(line.Debit).Sub(line.Credit)
+		}
+		l.entries[entry.NGOID] = append(l.entries[entry.NGOID], entry)
+		l.entryIDs[entry.EntryID] = true
+	}
+
+	return nil
+}
+
+func (l *Ledger) validate(entries []*JournalEntry) error {
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.EntryID == "" {
+			return fmt.Errorf("journal entry has no ID")
+		}
+		if l.entryIDs[entry.EntryID] || seen[entry.EntryID] {
+			return fmt.Errorf("journal entry %s already posted", entry.EntryID)
+		}
+		seen[entry.EntryID] = true
+
+		chart, exists := l.charts[entry.NGOID]
+		if !exists {
+			return fmt.Errorf("no books open for NGO %s", entry.NGOID)
+		}
+		if len(entry.Lines) < 2 {
+			return fmt.Errorf("journal entry %s needs at least two lines", entry.EntryID)
+		}
+
+		for _, line := range entry.Lines {
+			if _, exists := chart[line.Account]; !exists {
+				return fmt.Errorf("journal entry %s uses unknown account %s", entry.EntryID, line.Account)
+			}
+			if line.Debit.IsNegative() || line.Credit.IsNegative() {
+				return fmt.Errorf("journal entry %s has a negative amount", entry.EntryID)
+			}
+			if line.Debit.IsZero() == line.Credit.IsZero() {
+				return fmt.Errorf("journal entry %s has a line that is not exactly one of debit or credit", entry.EntryID)
+			}
+		}
+
+		debits, credits := entry.Totals()
+		if !debits.Equal(credits) {
+			return fmt.Errorf("journal entry %s is unbalanced: debits %s, credits %s", entry.EntryID, debits, credits)
+		}
+	}
+	return nil
+}
+
+// Balance returns an account balance in its normal direction
+func (l *Ledger) Balance(ngoID, code string) (money.Money, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	account, exists := l.charts[ngoID][code]
+	if !exists {
+		return money.Money{}, fmt.Errorf("account %s not found for NGO %s", code, ngoID)
+	}
+	return normalBalance(account, l.balances[ngoID][code]), nil
+}
+
+// Entries returns the NGO's journal entries in posting order
+func (l *Ledger) Entries(ngoID string) []JournalEntry {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	entries := make([]JournalEntry, 0, len(l.entries[ngoID]))
+	for _, entry := range l.entries[ngoID] {
+		entries = append(entries, *entry)
+	}
+	return entries
+}
+
+// TrialBalanceRow is one account of a trial balance
+type TrialBalanceRow struct {
+	Account
+	Debit  money.Money `json:"debit"`
+	Credit money.Money `json:"credit"`
+}
+
+// TrialBalance lists every account balance on its debit or credit side
+type TrialBalance struct {
+	NGOID        string            `json:"ngo_id"`
+	Rows         []TrialBalanceRow `json:"rows"`
+	TotalDebits  money.Money       `json:"total_debits"`
+	TotalCredits money.Money       `json:"total_credits"`
+	Balanced     bool              `json:"balanced"`
+	AsOf         time.Time         `json:"as_of"`
+}
+
+// TrialBalance computes the NGO's trial balance
+func (l *Ledger) TrialBalance(ngoID string) (*TrialBalance, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	chart, exists := l.charts[ngoID]
+	if !exists {
+		return nil, fmt.Errorf("no books open for NGO %s", ngoID)
+	}
+
+	tb := &TrialBalance{
+		NGOID:        ngoID,
+		TotalDebits:  money.Zero(),
+		TotalCredits: money.Zero(),
+		AsOf:         time.Now(),
+	}
+	for _, account := range sortedAccounts(chart) {
+		row := TrialBalanceRow{Account: account, Debit: money.Zero(), Credit: money.Zero()}
+		balance := l.balances[ngoID][account.Code]
+		if balance.IsNegative() {
+			row.Credit = balance.Neg()
+		} else {
+			row.Debit = balance
+		}
+		tb.TotalDebits = tb.TotalDebits.Add(row.Debit)
+		tb.TotalCredits = tb.TotalCredits.Add(row.Credit)
+		tb.Rows = append(tb.Rows, row)
+	}
+	tb.Balanced = tb.TotalDebits.Equal(tb.TotalCredits)
+
+	return tb, nil
+}
+
+// BalanceSheetLine is an account balance in its normal direction
+type BalanceSheetLine struct {
+	Account
+	Balance money.Money `json:"balance"`
+}
+
+// BalanceSheet reports assets against liabilities and net assets. Revenue
+// less expenses for the period is carried into net assets as the surplus.
+type BalanceSheet struct {
+	NGOID            string             `json:"ngo_id"`
+	Assets           []BalanceSheetLine `json:"assets"`
+	Liabilities      []BalanceSheetLine `json:"liabilities"`
+	NetAssets        []BalanceSheetLine `json:"net_assets"`
+	Surplus          money.Money        `json:"surplus"`
+	TotalAssets      money.Money        `json:"total_assets"`
+	TotalLiabilities money.Money        `json:"total_liabilities"`
+	TotalNetAssets   money.Money        `json:"total_net_assets"`
+	Balanced         bool               `json:"balanced"`
+	AsOf             time.Time          `json:"as_of"`
+}
+
+// BalanceSheet computes the NGO's balance sheet
+func (l *Ledger) BalanceSheet(ngoID string) (*BalanceSheet, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	chart, exists := l.charts[ngoID]
+	if !exists {
+		return nil, fmt.Errorf("no books open for NGO %s", ngoID)
+	}
+
+	bs := &BalanceSheet{
+		NGOID:            ngoID,
+		Assets:           []BalanceSheetLine{},
+		Liabilities:      []BalanceSheetLine{},
+		NetAssets:        []BalanceSheetLine{},
+		Surplus:          money.Zero(),
+		TotalAssets:      money.Zero(),
+		TotalLiabilities: money.Zero(),
+		TotalNetAssets:   money.Zero(),
+		AsOf:             time.Now(),
+	}
+	for _, account := range sortedAccounts(chart) {
+		line := BalanceSheetLine{Account: account, Balance: normalBalance(account, l.balances[ngoID][account.Code])}
+		switch account.Type {
+		case Asset:
+			bs.Assets = append(bs.Assets, line)
+			bs.TotalAssets = bs.TotalAssets.Add(line.Balance)
+		case Liability:
+			bs.Liabilities = append(bs.Liabilities, line)
+			bs.TotalLiabilities = bs.TotalLiabilities.Add(line.Balance)
+		case NetAssets:
+			bs.NetAssets = append(bs.NetAssets, line)
+			bs.TotalNetAssets = bs.TotalNetAssets.Add(line.Balance)
+		case Revenue:
+			bs.Surplus = bs.Surplus.Add(line.Balance)
+		case Expense:
+			bs.Surplus = bs.Surplus.Sub(line.Balance)
+		}
+	}
+	bs.TotalNetAssets = bs.TotalNetAssets.Add(bs.Surplus)
+	bs.Balanced = bs.TotalAssets.Equal(bs.TotalLiabilities.Add(bs.TotalNetAssets))
+
+	return bs, nil
+}
+
+// normalBalance converts a debit-minus-credit balance to the account's normal side
+func normalBalance(account Account, balance money.Money) money.Money {
+	if account.Type.DebitNormal() {
+		return balance
+	}
+	return balance.Neg()
+}
+
+func sortedAccounts(chart map[string]Account) []Account {
+	accounts := make([]Account, 0, len(chart))
+	for _, account := range chart {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Code < accounts[j].Code })
+	return accounts
+}
@@ -0,0 +1,148 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+func TestDonationAndExpenditureBalance(t *testing.T) {
+	l := NewLedger()
+	l.OpenBooks("NGO001")
+
+	now := time.Now()
+	if err := l.Post(DonationEntries("NGO001", "DON1", money.INR(100000), money.INR(1000), now)...); err != nil {
+		t.Fatalf("Failed to post donation: %v", err)
+	}
+	if err := l.Post(ExpenditureEntries("NGO001", "EXP1", "education", money.INR(30000), now)...); err != nil {
+		t.Fatalf("Failed to post expenditure: %v", err)
+	}
+
+	tb, err := l.TrialBalance("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to compute trial balance: %v", err)
+	}
+	if !tb.Balanced || tb.TotalDebits.Minor() != 100000 {
+		t.Errorf("Expected balanced trial balance with ₹1000 debits, got %+v", tb)
+	}
+
+	bank, _ := l.Balance("NGO001", AccountBank)
+	receivable, _ := l.Balance("NGO001", AccountDonationsReceivable)
+	payables, _ := l.Balance("NGO001", AccountVendorPayables)
+	if bank.Minor() != 69000 || !receivable.IsZero() || !payables.IsZero() {
+		t.Errorf("Unexpected balances: bank %s, receivable %s, payables %s", bank, receivable, payables)
+	}
+
+	bs, err := l.BalanceSheet("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to compute balance sheet: %v", err)
+	}
+	if !bs.Balanced || bs.TotalAssets.Minor() != 69000 || bs.Surplus.Minor() != -31000 {
+		t.Errorf("Unexpected balance sheet: %+v", bs)
+	}
+}
+
+func TestPostRejectsInvalidEntries(t *testing.T) {
+	l := NewLedger()
+	l.OpenBooks("NGO001")
+
+	unbalanced := &JournalEntry{
+		EntryID: "E1",
+		NGOID:   "NGO001",
+		Lines: []Line{
+			{Account: AccountBank, Debit: money.INR(100), Credit: money.Zero()},
+			{Account: AccountRestrictedFunds, Debit: money.Zero(), Credit: money.INR(99)},
+		},
+	}
+	if err := l.Post(unbalanced); err == nil {
+		t.Error("Expected unbalanced entry to be rejected")
+	}
+
+	unknownAccount := &JournalEntry{
+		EntryID: "E2",
+		NGOID:   "NGO001",
+		Lines: []Line{
+			{Account: "9999", Debit: money.INR(100), Credit: money.Zero()},
+			{Account: AccountRestrictedFunds, Debit: money.Zero(), Credit: money.INR(100)},
+		},
+	}
+	if err := l.Post(unknownAccount); err == nil {
+		t.Error("Expected unknown account to be rejected")
+	}
+
+	entries := DonationEntries("NGO001", "DON1", money.INR(100), money.INR(1), time.Now())
+	if err := l.Post(entries...); err != nil {
+		t.Fatalf("Failed to post donation: %v", err)
+	}
+	if err := l.Post(entries...); err == nil {
+		t.Error("Expected duplicate entries to be rejected")
+	}
+
+	if err := l.Post(DonationEntries("NGO002", "DON2", money.INR(100), money.INR(1), time.Now())...); err == nil {
+		t.Error("Expected entries for an NGO without books to be rejected")
+	}
+
+	if got := len(l.Entries("NGO001")); got != 2 {
+		t.Errorf("Expected only the valid donation entries to be posted, got %d", got)
+	}
+}
+
+func TestDonationReversalBalances(t *testing.T) {
+	l := NewLedger()
+	l.OpenBooks("NGO001")
+
+	now := time.Now()
+	if err := l.Post(DonationEntries("NGO001", "DON1", money.INR(100000), money.INR(1000), now)...); err != nil {
+		t.Fatalf("Failed to post donation: %v", err)
+	}
+	if err := l.Post(DonationReversalEntries("NGO001", "REV1", "DON1", money.INR(40000), money.INR(400), now)...); err != nil {
+		t.Fatalf("Failed to post reversal: %v", err)
+	}
+
+	bank, _ := l.Balance("NGO001", AccountBank)
+	fees, _ := l.Balance("NGO001", AccountPlatformFees)
+	funds, _ := l.Balance("NGO001", AccountRestrictedFunds)
+	if bank.Minor() != 59400 || fees.Minor() != 600 || funds.Minor() != 60000 {
+		t.Errorf("Unexpected balances after reversal: bank %s, fees %s, funds %s", bank, fees, funds)
+	}
+
+	tb, err := l.TrialBalance("NGO001")
+	if err != nil || !tb.Balanced {
+		t.Errorf("Expected balanced trial balance, got %+v (%v)", tb, err)
+	}
+}
+
+func TestExpenditureAmendmentBalances(t *testing.T) {
+	l := NewLedger()
+	l.OpenBooks("NGO001")
+
+	now := time.Now()
+	if err := l.Post(DonationEntries("NGO001", "DON1", money.INR(100000), money.Zero(), now)...); err != nil {
+		t.Fatalf("Failed to post donation: %v", err)
+	}
+	if err := l.Post(ExpenditureEntries("NGO001", "EXP1", "education", money.INR(30000), now)...); err != nil {
+		t.Fatalf("Failed to post expenditure: %v", err)
+	}
+	if err := l.Post(ExpenditureAmendmentEntries("NGO001", "AMD1", "EXP1", "education", money.INR(5000), now)...); err != nil {
+		t.Fatalf("Failed to post increase: %v", err)
+	}
+	if err := l.Post(ExpenditureAmendmentEntries("NGO001", "AMD2", "EXP1", "education", money.INR(-15000), now)...); err != nil {
+		t.Fatalf("Failed to post decrease: %v", err)
+	}
+	if entries := ExpenditureAmendmentEntries("NGO001", "AMD3", "EXP1", "education", money.Zero(), now); len(entries) != 0 {
+		t.Errorf("Expected no entries for an unchanged amount, got %d", len(entries))
+	}
+
+	bank, _ := l.Balance("NGO001", AccountBank)
+	expenses, _ := l.Balance("NGO001", AccountProgramExpenses)
+	payables, _ := l.Balance("NGO001", AccountVendorPayables)
+	if bank.Minor() != 80000 || expenses.Minor() != 20000 || !payables.IsZero() {
+		t.Errorf("Unexpected balances: bank %s, expenses %s, payables %s", bank, expenses, payables)
+	}
+	for _, entry := range l.Entries("NGO001") {
+		if entry.EntryID == "AMD1:expense" && entry.Reference != "EXP1" {
+			t.Errorf("Expected the amendment to reference the expenditure, got %s", entry.Reference)
+		}
+	}
+}
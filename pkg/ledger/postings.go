@@ -0,0 +1,151 @@
+package ledger
+
+import (
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// DonationEntries records a donation of grossAmount of which platformFee is
+// withheld at source. The pledge is first recognised as restricted funds
+// against a receivable, which settlement then clears into the bank net of fees.
+func DonationEntries(ngoID, transactionID string, grossAmount, platformFee money.Money, at time.Time) []*JournalEntry {
+	receipt := &JournalEntry{
+		EntryID:     transactionID + ":receipt",
+		NGOID:       ngoID,
+		Reference:   transactionID,
+		Description: "Donation received",
+		Lines: []Line{
+			{Account: AccountDonationsReceivable, Debit: grossAmount, Credit: money.Zero()},
+			{Account: AccountRestrictedFunds, Debit: money.Zero(), Credit: grossAmount},
+		},
+		PostedAt: at,
+	}
+
+	settlementLines := []Line{
+		{Account: AccountBank, Debit: grossAmount.Sub(platformFee), Credit: money.Zero()},
+	}
+	if platformFee.IsPositive() {
+		settlementLines = append(settlementLines, Line{Account: AccountPlatformFees, Debit: platformFee, Credit: money.Zero()})
+	}
+	settlementLines = append(settlementLines, Line{Account: AccountDonationsReceivable, Debit: money.Zero(), Credit: grossAmount})
+
+	settlement := &JournalEntry{
+		EntryID:     transactionID + ":settlement",
+		NGOID:       ngoID,
+		Reference:   transactionID,
+		Description: "Donation settled net of platform fee",
+		Lines:       settlementLines,
+		PostedAt:    at,
+	}
+
+	return []*JournalEntry{receipt, settlement}
+}
+
+// DonationReversalEntries records the return of grossAmount of a donation to
+// the donor. The platform returns its platformFee share, so the bank pays out
+// only the remainder and the restricted funds shrink by the full amount.
+func DonationReversalEntries(ngoID, reversalID, transactionID string, grossAmount, platformFee money.Money, at time.Time) []*JournalEntry {
+	lines := []Line{
+		{Account: AccountRestrictedFunds, Debit: grossAmount, Credit: money.Zero()},
+	}
+	if netAmount := grossAmount.Sub(platformFee); netAmount.IsPositive() {
+		lines = append(lines, Line{Account: AccountBank, Debit: money.Zero(), Credit: netAmount})
+	}
+	if platformFee.IsPositive() {
+		lines = append(lines, Line{Account: AccountPlatformFees, Debit: money.Zero(), Credit: platformFee})
+	}
+
+	return []*JournalEntry{{
+		EntryID:     reversalID + ":reversal",
+		NGOID:       ngoID,
+		Reference:   transactionID,
+		Description: "Donation reversed",
+		Lines:       lines,
+		PostedAt:    at,
+	}}
+}
+
+// ExpenditureEntries records an approved expenditure as a program expense owed
+// to the vendor, followed by payment of the vendor from the bank
+func ExpenditureEntries(ngoID, transactionID, category string, amount money.Money, at time.Time) []*JournalEntry {
+	description := "Program expense"
+	if category != "" {
+		description += ": " + category
+	}
+
+	expense := &JournalEntry{
+		EntryID:     transactionID + ":expense",
+		NGOID:       ngoID,
+		Reference:   transactionID,
+		Description: description,
+		Lines: []Line{
+			{Account: AccountProgramExpenses, Debit: amount, Credit: money.Zero()},
+			{Account: AccountVendorPayables, Debit: money.Zero(), Credit: amount},
+		},
+		PostedAt: at,
+	}
+
+	payment := &JournalEntry{
+		EntryID:     transactionID + ":payment",
+		NGOID:       ngoID,
+		Reference:   transactionID,
+		Description: "Vendor paid",
+		Lines: []Line{
+			{Account: AccountVendorPayables, Debit: amount, Credit: money.Zero()},
+			{Account: AccountBank, Debit: money.Zero(), Credit: amount},
+		},
+		PostedAt: at,
+	}
+
+	return []*JournalEntry{expense, payment}
+}
+
+// ExpenditureAmendmentEntries records a correction of change to the amount
+// of a recorded expenditure. An increase is posted like an expenditure of the
+// difference; a decrease reverses that much of the expense, with the vendor
+// refunding the overpayment to the bank.
+func ExpenditureAmendmentEntries(ngoID, amendmentID, transactionID, category string, change money.Money, at time.Time) []*JournalEntry {
+	if change.IsZero() {
+		return nil
+	}
+	if change.IsPositive() {
+		entries := ExpenditureEntries(ngoID, amendmentID, category, change, at)
+		for _, entry := range entries {
+			entry.Reference = transactionID
+			entry.Description += " (amended)"
+		}
+		return entries
+	}
+
+	amount := change.Abs()
+	refund := &JournalEntry{
+		EntryID:     amendmentID + ":refund",
+		NGOID:       ngoID,
+		Reference:   transactionID,
+		Description: "Vendor refunded overpayment",
+		Lines: []Line{
+			{Account: AccountBank, Debit: amount, Credit: money.Zero()},
+			{Account: AccountVendorPayables, Debit: money.Zero(), Credit: amount},
+		},
+		PostedAt: at,
+	}
+
+	description := "Program expense reduced"
+	if category != "" {
+		description += ": " + category
+	}
+	reduction := &JournalEntry{
+		EntryID:     amendmentID + ":reduction",
+		NGOID:       ngoID,
+		Reference:   transactionID,
+		Description: description,
+		Lines: []Line{
+			{Account: AccountVendorPayables, Debit: amount, Credit: money.Zero()},
+			{Account: AccountProgramExpenses, Debit: money.Zero(), Credit: amount},
+		},
+		PostedAt: at,
+	}
+
+	return []*JournalEntry{refund, reduction}
+}
@@ -0,0 +1,224 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a retryable request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// memoryIdempotencyPurgeInterval is how often the memory store drops expired keys
+	memoryIdempotencyPurgeInterval = time.Minute
+)
+
+// IdempotencyRecord is a key reserved by a request and, once it completes,
+// the response to replay for retries
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Body        []byte
+	Completed   bool
+	ExpiresAt   time.Time
+}
+
+// IdempotencyStore persists idempotency keys and their responses
+type IdempotencyStore interface {
+	// Reserve claims record.Key. It returns nil if the key was free or had
+	// expired, or the record currently holding the key.
+	Reserve(record *IdempotencyRecord) (*IdempotencyRecord, error)
+	// Complete stores the response to a reserved key
+	Complete(key string, statusCode int, body []byte) error
+	// Release frees a reserved key so the request can be retried
+	Release(key string) error
+}
+
+// MemoryIdempotencyStore keeps idempotency keys in memory. It is used when
+// no database is configured and does not survive a restart. Expired keys
+// are dropped as new ones are reserved.
+type MemoryIdempotencyStore struct {
+	records  map[string]*IdempotencyRecord
+	purgedAt time.Time
+	mutex    sync.Mutex
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]*IdempotencyRecord)}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(record *IdempotencyRecord) (*IdempotencyRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.purgedAt) >= memoryIdempotencyPurgeInterval {
+		s.purgeExpired(now)
+	}
+
+	if existing, exists := s.records[record.Key]; exists && now.Before(existing.ExpiresAt) {
+		held := *existing
+		return &held, nil
+	}
+	reserved := *record
+	s.records[record.Key] = &reserved
+	return nil, nil
+}
+
+// purgeExpired drops every key that expired before now
+func (s *MemoryIdempotencyStore) purgeExpired(now time.Time) {
+	for key, record := range s.records {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+	s.purgedAt = now
+}
+
+func (s *MemoryIdempotencyStore) Complete(key string, statusCode int, body []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if record, exists := s.records[key]; exists {
+		record.StatusCode = statusCode
+		record.Body = body
+		record.Completed = true
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if record, exists := s.records[key]; exists && !record.Completed {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// Idempotency middleware replays the stored response when a request is
+// retried with the same Idempotency-Key. Keys are scoped to the
+// authenticated user and route, so it must run after authentication.
+// Reusing a key with a different body is rejected, as is a retry while the
+// first request is still in flight. Server errors and handler panics
+// release the key so the request can be retried.
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientKey := c.GetHeader(IdempotencyKeyHeader)
+		if clientKey == "" {
+			c.Next()
+			return
+		}
+		if len(clientKey) > maxIdempotencyKeyLength {
+			ErrorResponseWithDetails(c, http.StatusBadRequest, "invalid_idempotency_key",
+				fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength), nil)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			ErrorResponseWithDetails(c, http.StatusBadRequest, "invalid_request", "Failed to read request body", nil)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID, _ := c.Get("user_id")
+		record := &IdempotencyRecord{
+			Key:         hashParts(fmt.Sprintf("%v", userID), c.Request.Method, c.FullPath(), clientKey),
+			RequestHash: hashParts(string(body)),
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+
+		existing, err := store.Reserve(record)
+		if err != nil {
+			logIdempotencyError(err, "Failed to reserve idempotency key")
+			ErrorResponseWithDetails(c, http.StatusInternalServerError, "idempotency_store_error", "Failed to check idempotency key", nil)
+			c.Abort()
+			return
+		}
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				ErrorResponseWithDetails(c, http.StatusUnprocessableEntity, "idempotency_key_reused",
+					"Idempotency-Key was already used for a different request", nil)
+			case !existing.Completed:
+				ErrorResponseWithDetails(c, http.StatusConflict, "idempotency_key_in_use",
+					"A request with this Idempotency-Key is still being processed", nil)
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, "application/json; charset=utf-8", existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		defer func() {
+			// Free the key before the panic reaches the recovery middleware
+			if r := recover(); r != nil {
+				if err := store.Release(record.Key); err != nil {
+					logIdempotencyError(err, "Failed to release idempotency key")
+				}
+				panic(r)
+			}
+		}()
+		c.Next()
+
+		if status := recorder.Status(); status >= http.StatusInternalServerError {
+			err = store.Release(record.Key)
+		} else {
+			err = store.Complete(record.Key, status, recorder.body.Bytes())
+		}
+		if err != nil {
+			logIdempotencyError(err, "Failed to store idempotent response")
+		}
+	}
+}
+
+// responseRecorder copies the response body so it can be stored for replay
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func logIdempotencyError(err error, message string) {
+	if Logger != nil {
+		Logger.WithFields(logrus.Fields{"error": err.Error()}).Error(message)
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusPanic makes the handler of newIdempotentRouter panic instead of responding
+const statusPanic = -1
+
+// newIdempotentRouter serves POST /donations behind the idempotency
+// middleware, counting how often the handler runs and failing on demand
+func newIdempotentRouter(calls *int, status *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ interface{}) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.POST("/donations", func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+	}, Idempotency(NewMemoryIdempotencyStore(), time.Hour), func(c *gin.Context) {
+		*calls++
+		if *status == statusPanic {
+			panic("handler failed")
+		}
+		c.JSON(*status, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func postDonation(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/donations", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	calls, status := 0, http.StatusOK
+	router := newIdempotentRouter(&calls, &status)
+
+	first := postDonation(router, "key-1", `{"amount":100}`)
+	retry := postDonation(router, "key-1", `{"amount":100}`)
+	if calls != 1 {
+		t.Fatalf("Expected the handler to run once, ran %d times", calls)
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() || retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected replay of %d %s, got %d %s", first.Code, first.Body, retry.Code, retry.Body)
+	}
+
+	if w := postDonation(router, "key-1", `{"amount":200}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a reused key, got %d", w.Code)
+	}
+	postDonation(router, "", `{"amount":100}`)
+	postDonation(router, "key-2", `{"amount":100}`)
+	if calls != 3 {
+		t.Errorf("Expected requests without or with a new key to run, ran %d times", calls)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	calls, status := 0, http.StatusInternalServerError
+	router := newIdempotentRouter(&calls, &status)
+
+	postDonation(router, "key-1", `{"amount":100}`)
+	status = http.StatusOK
+	if w := postDonation(router, "key-1", `{"amount":100}`); w.Code != http.StatusOK || calls != 2 {
+		t.Errorf("Expected a retry after a server error to run, got %d after %d calls", w.Code, calls)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	calls, status := 0, statusPanic
+	router := newIdempotentRouter(&calls, &status)
+
+	if w := postDonation(router, "key-1", `{"amount":100}`); w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected the panic to be recovered as a 500, got %d", w.Code)
+	}
+	status = http.StatusOK
+	if w := postDonation(router, "key-1", `{"amount":100}`); w.Code != http.StatusOK || calls != 2 {
+		t.Errorf("Expected a retry after a panic to run, got %d after %d calls", w.Code, calls)
+	}
+}
+
+func TestMemoryIdempotencyStoreReservations(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	record := &IdempotencyRecord{Key: "k", RequestHash: "h", ExpiresAt: time.Now().Add(time.Hour)}
+	if existing, _ := store.Reserve(record); existing != nil {
+		t.Fatal("Expected a free key to be reserved")
+	}
+	if existing, _ := store.Reserve(record); existing == nil || existing.Completed {
+		t.Fatalf("Expected the in-flight reservation, got %+v", existing)
+	}
+
+	expired := &IdempotencyRecord{Key: "old", RequestHash: "h", ExpiresAt: time.Now().Add(-time.Minute)}
+	store.Reserve(expired)
+	if existing, _ := store.Reserve(expired); existing != nil {
+		t.Errorf("Expected an expired key to be reserved again, got %+v", existing)
+	}
+}
+
+func TestMemoryIdempotencyStorePurgesExpiredKeys(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	store.Reserve(&IdempotencyRecord{Key: "old", RequestHash: "h", ExpiresAt: time.Now().Add(-time.Minute)})
+	store.Reserve(&IdempotencyRecord{Key: "live", RequestHash: "h", ExpiresAt: time.Now().Add(time.Hour)})
+
+	store.purgedAt = time.Now().Add(-memoryIdempotencyPurgeInterval)
+	store.Reserve(&IdempotencyRecord{Key: "new", RequestHash: "h", ExpiresAt: time.Now().Add(time.Hour)})
+	if _, exists := store.records["old"]; exists || len(store.records) != 2 {
+		t.Errorf("Expected only the expired key to be purged, have %d keys", len(store.records))
+	}
+}
@@ -0,0 +1,369 @@
+// Package money provides a fixed-point monetary amount stored as integer minor
+// units (paise for INR), so sums and fee splits never drift.
+//
+// Only INR is supported. Amounts are serialized and stored as bare numbers
+// without a currency, so New rejects every other currency rather than let one
+// be silently read back as INR.
+//
+// Rounding rules used across the platform:
+//   - Platform fees round half up to the nearest minor unit; the net amount is
+//     gross minus fee, so fee + net always equals gross exactly.
+//   - Tax deductions and tax savings round down, so a receipt never overstates
+//     a donor's benefit.
+//   - Averages and other derived display values round half up.
+//   - Amounts parsed from input with more than two decimals round half up.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the only supported currency
+const DefaultCurrency = "INR"
+
+// minorPerMajor is the number of minor units in one major unit
+const minorPerMajor = 100
+
+// Rounding selects how fractional minor units are resolved
+type Rounding int
+
+const (
+	// RoundHalfUp rounds to the nearest minor unit, ties away from zero
+	RoundHalfUp Rounding = iota
+	// RoundDown truncates towards zero
+	RoundDown
+	// RoundHalfEven rounds to the nearest minor unit, ties to even
+	RoundHalfEven
+)
+
+// Money is an amount in integer minor units of a currency
+type Money struct {
+	minor    int64
+	currency string
+}
+
+// New creates an amount from minor units of currency, which must be INR
+func New(minor int64, currency string) (Money, error) {
+	if normalizeCurrency(currency) != DefaultCurrency {
+		return Money{}, fmt.Errorf("unsupported currency %q: only %s is supported", currency, DefaultCurrency)
+	}
+	return INR(minor), nil
+}
+
+// INR creates an INR amount from paise
+func INR(paise int64) Money {
+	return Money{minor: paise, currency: DefaultCurrency}
+}
+
+// Zero returns a zero amount in the default currency
+func Zero() Money {
+	return INR(0)
+}
+
+// FromMajor converts a major-unit float (e.g. rupees) to Money, rounding half up
+// to the nearest minor unit. Use only at boundaries where amounts arrive as floats.
+func FromMajor(major float64) Money {
+	return INR(int64(math.Round(major * minorPerMajor)))
+}
+
+// decimalAmount matches a plain decimal amount of at most two decimal places
+var decimalAmount = regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`)
+
+// Parse parses a decimal string such as "1250.50" into an INR amount. Only
+// plain decimals of at most two decimal places are accepted; fractions such
+// as "1/3" and exponents such as "1e3" are not.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, fmt.Errorf("empty amount")
+	}
+	if !decimalAmount.MatchString(s) {
+		return Money{}, fmt.Errorf("invalid amount: %q", s)
+	}
+
+	rat, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount: %q", s)
+	}
+
+	minor := rat.Mul(rat, big.NewRat(minorPerMajor, 1))
+	value, err := roundRat(minor, RoundHalfUp)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	return INR(value), nil
+}
+
+// Minor returns the amount in minor units
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+// Currency returns the ISO currency code
+func (m Money) Currency() string {
+	return normalizeCurrency(m.currency)
+}
+
+// Float64 returns the amount in major units. Only use for display or ratios.
+func (m Money) Float64() float64 {
+	return float64(m.minor) / minorPerMajor
+}
+
+// String formats the amount in major units with two decimals, e.g. "1250.50"
+func (m Money) String() string {
+	sign := ""
+	minor := m.minor
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/minorPerMajor, minor%minorPerMajor)
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.minor > 0
+}
+
+// IsNegative reports whether the amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.minor < 0
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) Money {
+	return Money{minor: m.minor + other.minor, currency: m.mustMatch(other)}
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) Money {
+	return Money{minor: m.minor - other.minor, currency: m.mustMatch(other)}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{minor: -m.minor, currency: m.currency}
+}
+
+// Abs returns the absolute value of m
+func (m Money) Abs() Money {
+	if m.minor < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Cmp compares m and other, returning -1, 0 or +1
+func (m Money) Cmp(other Money) int {
+	m.mustMatch(other)
+	switch {
+	case m.minor < other.minor:
+		return -1
+	case m.minor > other.minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Equal reports whether m and other are the same amount and currency
+func (m Money) Equal(other Money) bool {
+	return m.minor == other.minor && m.Currency() == other.Currency()
+}
+
+// MulRatio returns m * numerator / denominator, rounded with the given mode.
+// It fails on a zero denominator or a result beyond the range of Money.
+func (m Money) MulRatio(numerator, denominator int64, mode Rounding) (Money, error) {
+	if denominator == 0 {
+		return Money{}, fmt.Errorf("money: division by zero")
+	}
+
+	rat := new(big.Rat).SetFrac(
+		new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(numerator)),
+		big.NewInt(denominator),
+	)
+	value, err := roundRat(rat, mode)
+	if err != nil {
+		return Money{}, fmt.Errorf("money: %w", err)
+	}
+	return Money{minor: value, currency: m.currency}, nil
+}
+
+// Percent returns basisPoints/10000 of m, e.g. Percent(100, ...) is 1%
+func (m Money) Percent(basisPoints int64, mode Rounding) (Money, error) {
+	return m.MulRatio(basisPoints, 10000, mode)
+}
+
+// Div divides m into n parts, rounded with the given mode
+func (m Money) Div(n int64, mode Rounding) (Money, error) {
+	return m.MulRatio(1, n, mode)
+}
+
+// Ratio returns m / other as a float, or 0 when other is zero
+func (m Money) Ratio(other Money) float64 {
+	if other.minor == 0 {
+		return 0
+	}
+	return float64(m.minor) / float64(other.minor)
+}
+
+// Min returns the smaller of a and b
+func Min(a, b Money) Money {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of a and b
+func Max(a, b Money) Money {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// Sum adds all amounts
+func Sum(amounts ...Money) Money {
+	total := Money{}
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	return total
+}
+
+// BasisPoints converts a fractional rate (0.01 for 1%) to basis points
+func BasisPoints(rate float64) int64 {
+	return int64(math.Round(rate * 10000))
+}
+
+// MarshalJSON encodes the amount as a JSON number in major units
+func (m Money) MarshalJSON() ([]byte, error) {
+	s := m.String()
+	// Drop trailing zeros so whole amounts encode as integers
+	s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		s = "0"
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a decimal string in major units
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(data))
+	if raw == "null" {
+		return nil
+	}
+	if strings.HasPrefix(raw, `"`) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		raw = s
+	}
+
+	parsed, err := Parse(raw)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount as integer minor units
+func (m Money) Value() (driver.Value, error) {
+	return m.minor, nil
+}
+
+// Scan reads integer minor units from the database
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = Zero()
+	case int64:
+		*m = INR(v)
+	case float64:
+		// SQLite may keep REAL affinity for converted columns
+		if v != math.Trunc(v) {
+			return fmt.Errorf("money: fractional minor units %v", v)
+		}
+		*m = INR(int64(v))
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T", value)
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	minor, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return fmt.Errorf("money: invalid minor units %q", s)
+	}
+	*m = INR(minor)
+	return nil
+}
+
+// GormDataType stores amounts in a 64-bit integer column
+func (Money) GormDataType() string {
+	return "bigint"
+}
+
+func (m Money) mustMatch(other Money) string {
+	// Zero values without a currency take the other operand's currency
+	if m.currency == "" {
+		return other.currency
+	}
+	if other.currency == "" || other.currency == m.currency {
+		return m.currency
+	}
+	panic(fmt.Sprintf("money: currency mismatch %s vs %s", m.currency, other.currency))
+}
+
+func normalizeCurrency(currency string) string {
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return strings.ToUpper(currency)
+}
+
+// roundRat rounds a rational number of minor units to an integer
+func roundRat(r *big.Rat, mode Rounding) (int64, error) {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 && mode != RoundDown {
+		// Compare 2*|rem| with den to find ties and upper halves
+		twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+		cmp := twice.Cmp(den)
+		roundAway := cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1))
+		if roundAway {
+			if num.Sign() < 0 {
+				quo.Sub(quo, big.NewInt(1))
+			} else {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+	}
+
+	if !quo.IsInt64() {
+		return 0, fmt.Errorf("amount out of range")
+	}
+	return quo.Int64(), nil
+}
@@ -0,0 +1,131 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		minor int64
+	}{
+		{"1250.50", 125050},
+		{"0.05", 5},
+		{"-10.25", -1025},
+		{"100", 10000},
+		{" 99.9 ", 9990},
+	}
+
+	for _, test := range tests {
+		m, err := Parse(test.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", test.input, err)
+		}
+		if m.Minor() != test.minor {
+			t.Errorf("Parse(%q) = %d paise, expected %d", test.input, m.Minor(), test.minor)
+		}
+	}
+
+	for _, input := range []string{"12,50", "1/3", "1e3", "0.005", "1.", ".5", "+5", "0x10", "Inf", "NaN"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Expected error parsing %q", input)
+		}
+	}
+}
+
+func TestFeeSplitNeverDrifts(t *testing.T) {
+	fee := BasisPoints(0.01)
+	for minor := int64(1); minor < 10000; minor++ {
+		gross := INR(minor)
+		platformFee, err := gross.Percent(fee, RoundHalfUp)
+		if err != nil {
+			t.Fatalf("Percent failed: %v", err)
+		}
+		net := gross.Sub(platformFee)
+		if !platformFee.Add(net).Equal(gross) {
+			t.Fatalf("Fee %s + net %s != gross %s", platformFee, net, gross)
+		}
+	}
+
+	// 1% of ₹0.50 is half a paisa and rounds up
+	if got, _ := INR(50).Percent(fee, RoundHalfUp); got.Minor() != 1 {
+		t.Errorf("Expected fee of 1 paisa, got %d", got.Minor())
+	}
+}
+
+func TestRoundingModes(t *testing.T) {
+	tests := []struct {
+		amount   Money
+		mode     Rounding
+		expected int64
+	}{
+		{INR(25), RoundHalfUp, 13},
+		{INR(25), RoundDown, 12},
+		{INR(25), RoundHalfEven, 12},
+		{INR(-25), RoundHalfUp, -13},
+	}
+
+	for _, test := range tests {
+		got, err := test.amount.Div(2, test.mode)
+		if err != nil {
+			t.Fatalf("Div failed: %v", err)
+		}
+		if got.Minor() != test.expected {
+			t.Errorf("%d / 2 with mode %d: expected %d, got %d", test.amount.Minor(), test.mode, test.expected, got.Minor())
+		}
+	}
+}
+
+func TestMulRatioErrors(t *testing.T) {
+	if _, err := INR(100).Div(0, RoundHalfUp); err == nil {
+		t.Error("Expected error dividing by zero")
+	}
+	if _, err := INR(math.MaxInt64).MulRatio(2, 1, RoundHalfUp); err == nil {
+		t.Error("Expected error for a result beyond the range of Money")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(map[string]Money{"a": INR(125050), "b": INR(10000), "c": Zero()})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"a":1250.5,"b":100,"c":0}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	var decoded struct {
+		Number Money `json:"number"`
+		Text   Money `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 99.99, "text": "0.10"}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Number.Minor() != 9999 || decoded.Text.Minor() != 10 {
+		t.Errorf("Unexpected decoded amounts: %+v", decoded)
+	}
+}
+
+func TestScan(t *testing.T) {
+	var m Money
+	for _, value := range []interface{}{int64(500), float64(500), []byte("500"), "500"} {
+		if err := m.Scan(value); err != nil || m.Minor() != 500 {
+			t.Errorf("Scan(%v) = %d, %v", value, m.Minor(), err)
+		}
+	}
+	if err := m.Scan(12.5); err == nil {
+		t.Error("Expected error scanning fractional minor units")
+	}
+}
+
+func TestNewSupportsOnlyINR(t *testing.T) {
+	if _, err := New(100, "USD"); err == nil {
+		t.Error("Expected error creating an amount in another currency")
+	}
+	m, err := New(100, "inr")
+	if err != nil || !m.Equal(INR(100)) {
+		t.Errorf("New(100, \"inr\") = %s, %v", m, err)
+	}
+}
@@ -0,0 +1,204 @@
+package payments
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// FakeGatewayName identifies the fake gateway in configuration and records
+const FakeGatewayName = "fake"
+
+// FakeGateway is an in-process gateway for development and tests. It records
+// orders and produces the same signed webhooks a hosted gateway would send,
+// so the full payment flow can be exercised without network access.
+type FakeGateway struct {
+	secret      string
+	checkoutURL string
+	orders      map[string]fakeOrder
+	mutex       sync.Mutex
+}
+
+type fakeOrder struct {
+	request   OrderRequest
+	settled   bool
+	paymentID string      // Set once captured
+	reversed  money.Money // Refunded or charged back so far
+}
+
+// NewFakeGateway creates a fake gateway that signs webhooks with secret and
+// points checkout redirects at checkoutBaseURL
+func NewFakeGateway(secret, checkoutBaseURL string) *FakeGateway {
+	return &FakeGateway{
+		secret:      secret,
+		checkoutURL: strings.TrimRight(checkoutBaseURL, "/"),
+		orders:      make(map[string]fakeOrder),
+	}
+}
+
+// Name implements Gateway
+func (g *FakeGateway) Name() string {
+	return FakeGatewayName
+}
+
+// CreateOrder implements Gateway
+func (g *FakeGateway) CreateOrder(req OrderRequest) (*Order, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("order amount must be positive")
+	}
+	if !ValidMethod(req.Method) {
+		return nil, fmt.Errorf("unsupported payment method: %s", req.Method)
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	order := &Order{OrderID: fakeID("order"), CreatedAt: time.Now()}
+	if req.Method == MethodUPI && req.VPA != "" {
+		order.CollectRequestID = fakeID("collect")
+	} else {
+		order.CheckoutURL = g.checkoutURL + "/" + order.OrderID
+	}
+	g.orders[order.OrderID] = fakeOrder{request: req}
+
+	return order, nil
+}
+
+// ParseWebhook implements Gateway
+func (g *FakeGateway) ParseWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	if err := VerifyWebhook(g.secret, payload, signature, DefaultSignatureTolerance, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	return &event, nil
+}
+
+// Capture simulates the donor completing payment and returns the signed
+// payment.captured webhook the gateway would deliver
+func (g *FakeGateway) Capture(orderID string) ([]byte, string, error) {
+	return g.settle(orderID, EventPaymentCaptured, "")
+}
+
+// Fail simulates a declined or abandoned payment and returns the signed
+// payment.failed webhook the gateway would deliver
+func (g *FakeGateway) Fail(orderID, reason string) ([]byte, string, error) {
+	return g.settle(orderID, EventPaymentFailed, reason)
+}
+
+// Refund implements Gateway
+func (g *FakeGateway) Refund(req RefundRequest) (*Refund, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.reverse(req.OrderID, req.Amount); err != nil {
+		return nil, err
+	}
+	return &Refund{
+		RefundID:  fakeID("rfnd"),
+		PaymentID: g.orders[req.OrderID].paymentID,
+		Amount:    req.Amount,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Chargeback simulates the donor's bank reversing amount of a captured
+// payment and returns the signed payment.chargeback webhook
+func (g *FakeGateway) Chargeback(orderID string, amount money.Money, reason string) ([]byte, string, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if err := g.reverse(orderID, amount); err != nil {
+		return nil, "", err
+	}
+	order := g.orders[orderID]
+
+	return g.event(WebhookEvent{
+		EventID:       fakeID("evt"),
+		Type:          EventPaymentChargeback,
+		OrderID:       orderID,
+		Reference:     order.request.Reference,
+		PaymentID:     order.paymentID,
+		Amount:        amount,
+		Method:        order.request.Method,
+		DisputeID:     fakeID("dsp"),
+		DisputeReason: reason,
+		CreatedAt:     time.Now(),
+	})
+}
+
+// reverse records a refund or chargeback against a captured order
+func (g *FakeGateway) reverse(orderID string, amount money.Money) error {
+	order, exists := g.orders[orderID]
+	if !exists {
+		return fmt.Errorf("order not found: %s", orderID)
+	}
+	if order.paymentID == "" {
+		return fmt.Errorf("order not captured: %s", orderID)
+	}
+	if !amount.IsPositive() || order.reversed.Add(amount).Cmp(order.request.Amount) > 0 {
+		return fmt.Errorf("amount exceeds the unreversed payment of order %s", orderID)
+	}
+	order.reversed = order.reversed.Add(amount)
+	g.orders[orderID] = order
+	return nil
+}
+
+// Sign signs an arbitrary payload with the gateway secret
+func (g *FakeGateway) Sign(payload []byte) string {
+	return SignWebhook(g.secret, payload, time.Now())
+}
+
+func (g *FakeGateway) settle(orderID, eventType, reason string) ([]byte, string, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	order, exists := g.orders[orderID]
+	if !exists {
+		return nil, "", fmt.Errorf("order not found: %s", orderID)
+	}
+	if order.settled {
+		return nil, "", fmt.Errorf("order already settled: %s", orderID)
+	}
+	order.settled = true
+	if eventType == EventPaymentCaptured {
+		order.paymentID = fakeID("pay")
+	}
+	g.orders[orderID] = order
+
+	return g.event(WebhookEvent{
+		EventID:       fakeID("evt"),
+		Type:          eventType,
+		OrderID:       orderID,
+		Reference:     order.request.Reference,
+		Amount:        order.request.Amount,
+		Method:        order.request.Method,
+		PaymentID:     order.paymentID,
+		FailureReason: reason,
+		CreatedAt:     time.Now(),
+	})
+}
+
+// event encodes and signs a webhook event
+func (g *FakeGateway) event(event WebhookEvent) ([]byte, string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, g.Sign(payload), nil
+}
+
+func fakeID(prefix string) string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return prefix + "_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,102 @@
+// Package payments integrates donation payment gateways. A gateway order is
+// created for every payment intent and the gateway later reports the outcome
+// through an HMAC-signed webhook.
+package payments
+
+import (
+	"errors"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Payment methods accepted for donations
+const (
+	MethodUPI        = "upi"
+	MethodCard       = "card"
+	MethodNetBanking = "netbanking"
+)
+
+// Webhook event types
+const (
+	EventPaymentCaptured   = "payment.captured"
+	EventPaymentFailed     = "payment.failed"
+	EventPaymentChargeback = "payment.chargeback" // The donor's bank reversed a captured payment
+)
+
+var (
+	// ErrInvalidSignature is returned when a webhook signature does not verify
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrStaleWebhook is returned when a webhook timestamp is outside the tolerance
+	ErrStaleWebhook = errors.New("webhook timestamp outside tolerance")
+)
+
+// OrderRequest describes the payment a donor is about to make
+type OrderRequest struct {
+	Reference string      // Payment intent ID, echoed back in webhooks
+	Amount    money.Money // Gross amount charged to the donor
+	Method    string
+	VPA       string // Donor's UPI address for a collect request
+}
+
+// Order is a gateway order awaiting payment. Card and net banking payments
+// redirect the donor to CheckoutURL; UPI payments either redirect or, when a
+// VPA was given, raise a collect request on the donor's UPI app.
+type Order struct {
+	OrderID          string    `json:"order_id"`
+	CheckoutURL      string    `json:"checkout_url,omitempty"`
+	CollectRequestID string    `json:"collect_request_id,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// WebhookEvent is a verified payment outcome reported by a gateway
+type WebhookEvent struct {
+	EventID       string      `json:"event_id"`
+	Type          string      `json:"type"`
+	OrderID       string      `json:"order_id"`
+	Reference     string      `json:"reference"`
+	PaymentID     string      `json:"payment_id"`
+	Amount        money.Money `json:"amount"`
+	Method        string      `json:"method"`
+	FailureReason string      `json:"failure_reason,omitempty"`
+	DisputeID     string      `json:"dispute_id,omitempty"`     // Set on chargebacks
+	DisputeReason string      `json:"dispute_reason,omitempty"` // Set on chargebacks
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// RefundRequest asks the gateway to return part or all of a captured payment
+type RefundRequest struct {
+	OrderID   string
+	PaymentID string
+	Amount    money.Money
+	Reason    string
+}
+
+// Refund is a refund accepted by the gateway
+type Refund struct {
+	RefundID  string      `json:"refund_id"`
+	PaymentID string      `json:"payment_id"`
+	Amount    money.Money `json:"amount"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Gateway is a payment gateway that donations are collected through
+type Gateway interface {
+	// Name identifies the gateway in stored payment records
+	Name() string
+	// CreateOrder registers a payment with the gateway
+	CreateOrder(req OrderRequest) (*Order, error)
+	// ParseWebhook verifies a webhook's signature header and decodes its event
+	ParseWebhook(payload []byte, signature string) (*WebhookEvent, error)
+	// Refund returns part or all of a captured payment to the donor
+	Refund(req RefundRequest) (*Refund, error)
+}
+
+// ValidMethod reports whether method is an accepted payment method
+func ValidMethod(method string) bool {
+	switch method {
+	case MethodUPI, MethodCard, MethodNetBanking:
+		return true
+	}
+	return false
+}
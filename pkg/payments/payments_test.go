@@ -0,0 +1,122 @@
+package payments
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	payload := []byte(`{"type":"payment.captured"}`)
+	now := time.Now()
+	header := SignWebhook("secret", payload, now)
+
+	if err := VerifyWebhook("secret", payload, header, DefaultSignatureTolerance, now); err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+	if err := VerifyWebhook("other", payload, header, DefaultSignatureTolerance, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected wrong secret to fail, got %v", err)
+	}
+	if err := VerifyWebhook("secret", []byte(`{"type":"payment.failed"}`), header, DefaultSignatureTolerance, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected tampered payload to fail, got %v", err)
+	}
+	if err := VerifyWebhook("secret", payload, "v1=deadbeef", DefaultSignatureTolerance, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected header without timestamp to fail, got %v", err)
+	}
+	if err := VerifyWebhook("secret", payload, header, DefaultSignatureTolerance, now.Add(10*time.Minute)); !errors.Is(err, ErrStaleWebhook) {
+		t.Errorf("Expected replayed webhook to be stale, got %v", err)
+	}
+
+	// During secret rotation the gateway sends one signature per secret
+	rotated := SignWebhook("old", payload, now) + ",v1=" + strings.Split(header, "v1=")[1]
+	if err := VerifyWebhook("secret", payload, rotated, DefaultSignatureTolerance, now); err != nil {
+		t.Errorf("Expected any matching v1 signature to verify, got %v", err)
+	}
+}
+
+func TestFakeGatewayOrders(t *testing.T) {
+	gateway := NewFakeGateway("secret", "http://localhost/checkout/")
+
+	order, err := gateway.CreateOrder(OrderRequest{Reference: "PI_1", Amount: money.INR(50000), Method: MethodCard})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	if order.CheckoutURL != "http://localhost/checkout/"+order.OrderID || order.CollectRequestID != "" {
+		t.Errorf("Expected checkout redirect, got %+v", order)
+	}
+
+	collect, err := gateway.CreateOrder(OrderRequest{Reference: "PI_2", Amount: money.INR(50000), Method: MethodUPI, VPA: "donor@upi"})
+	if err != nil {
+		t.Fatalf("Failed to create UPI order: %v", err)
+	}
+	if collect.CollectRequestID == "" || collect.CheckoutURL != "" {
+		t.Errorf("Expected UPI collect request, got %+v", collect)
+	}
+
+	if _, err := gateway.CreateOrder(OrderRequest{Amount: money.INR(50000), Method: "cash"}); err == nil {
+		t.Error("Expected unsupported method to be rejected")
+	}
+
+	payload, signature, err := gateway.Capture(order.OrderID)
+	if err != nil {
+		t.Fatalf("Failed to capture: %v", err)
+	}
+	event, err := gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("Failed to parse webhook: %v", err)
+	}
+	if event.Type != EventPaymentCaptured || event.Reference != "PI_1" || event.PaymentID == "" || !event.Amount.Equal(money.INR(50000)) {
+		t.Errorf("Unexpected captured event: %+v", event)
+	}
+	if _, _, err := gateway.Fail(order.OrderID, "declined"); err == nil {
+		t.Error("Expected a settled order not to be settled again")
+	}
+
+	payload, signature, err = gateway.Fail(collect.OrderID, "collect request expired")
+	if err != nil {
+		t.Fatalf("Failed to fail order: %v", err)
+	}
+	var failed WebhookEvent
+	json.Unmarshal(payload, &failed)
+	if failed.Type != EventPaymentFailed || failed.FailureReason != "collect request expired" {
+		t.Errorf("Unexpected failed event: %+v", failed)
+	}
+	if _, err := gateway.ParseWebhook(payload, signature+"0"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected altered signature to fail, got %v", err)
+	}
+}
+
+func TestFakeGatewayReversals(t *testing.T) {
+	gateway := NewFakeGateway("secret", "http://localhost/checkout")
+
+	order, _ := gateway.CreateOrder(OrderRequest{Reference: "PI_1", Amount: money.INR(50000), Method: MethodCard})
+	if _, err := gateway.Refund(RefundRequest{OrderID: order.OrderID, Amount: money.INR(100)}); err == nil {
+		t.Error("Expected refund of an uncaptured order to fail")
+	}
+	gateway.Capture(order.OrderID)
+
+	refund, err := gateway.Refund(RefundRequest{OrderID: order.OrderID, Amount: money.INR(20000)})
+	if err != nil || refund.RefundID == "" || refund.PaymentID == "" {
+		t.Fatalf("Expected refund, got %+v (%v)", refund, err)
+	}
+
+	payload, signature, err := gateway.Chargeback(order.OrderID, money.INR(30000), "not recognised")
+	if err != nil {
+		t.Fatalf("Failed to charge back: %v", err)
+	}
+	event, err := gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("Failed to parse chargeback: %v", err)
+	}
+	if event.Type != EventPaymentChargeback || event.DisputeID == "" || event.PaymentID != refund.PaymentID || !event.Amount.Equal(money.INR(30000)) {
+		t.Errorf("Unexpected chargeback event: %+v", event)
+	}
+
+	if _, err := gateway.Refund(RefundRequest{OrderID: order.OrderID, Amount: money.INR(1)}); err == nil {
+		t.Error("Expected refund beyond the captured amount to fail")
+	}
+}
@@ -0,0 +1,79 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the HTTP header that carries the webhook signature
+const SignatureHeader = "X-Webhook-Signature"
+
+// DefaultSignatureTolerance is how old a signed webhook may be before it is
+// rejected as a possible replay
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// SignWebhook signs a webhook payload in the form "t=<unix>,v1=<hex>", where
+// v1 is the HMAC-SHA256 of "<unix>.<payload>" under the shared secret
+func SignWebhook(secret string, payload []byte, timestamp time.Time) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + computeSignature(secret, unix, payload)
+}
+
+// VerifyWebhook checks a signature produced by SignWebhook. Any of several v1
+// values may match, which lets the gateway sign with old and new secrets
+// while a secret is being rotated.
+func VerifyWebhook(secret string, payload []byte, header string, tolerance time.Duration, now time.Time) error {
+	var unix string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if unix == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrInvalidSignature)
+	}
+
+	expected := computeSignature(secret, unix, payload)
+	valid := false
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	// Only checked once the timestamp is known to be authentic
+	age := now.Sub(time.Unix(seconds, 0))
+	if tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return ErrStaleWebhook
+	}
+	return nil
+}
+
+func computeSignature(secret, unix string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,432 @@
+package platform
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/consensus"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/ledger"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// AmendmentSubmission is an NGO's correction of a recorded expenditure: the
+// reason for it, the fields it changes and the hashes of documents the NGO
+// uploaded to attach
+type AmendmentSubmission struct {
+	Reason string `json:"reason"`
+	transactions.ExpenditureChanges
+	Attachments []string `json:"attachments,omitempty"`
+}
+
+// AmendExpenditure corrects an expenditure recorded in an NGO's expenditure
+// blockchain. The amendment references the expenditure's original block and
+// the version it amends, and carries the fields changed and the reason.
+// Changes to the amount or vendor are re-audited by a panel formed under the
+// platform's AssignmentPolicy and ConsensusPolicy as for a new submission;
+// other corrections are recorded at once. Recorded amendments are appended
+// to the chain and a change in amount is posted to the ledger. The original
+// block is never changed.
+func (p *NGOTransparencyPlatform) AmendExpenditure(ngoID, expenditureID string, submission AmendmentSubmission) (map[string]interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+	if pending, exists := p.PendingExpenditures[expenditureID]; exists && pending.NGOID == ngoID {
+		if isAmendmentUnderReview(pending) {
+			return nil, fmt.Errorf("expenditure already has an amendment awaiting re-audit")
+		}
+		return nil, fmt.Errorf("only recorded expenditures can be amended")
+	}
+	if rejected, exists := p.RejectedExpenditures[expenditureID]; exists && rejected.NGOID == ngoID {
+		return nil, fmt.Errorf("only recorded expenditures can be amended")
+	}
+	versions, err := ngo.ExpenditureVersions(expenditureID)
+	if err != nil {
+		return nil, ErrExpenditureNotFound
+	}
+	current, block, err := ngo.RecordedExpenditure(expenditureID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(submission.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required to amend an expenditure")
+	}
+	if submission.Amount != nil && !submission.Amount.IsPositive() {
+		return nil, fmt.Errorf("invalid amount")
+	}
+	if submission.Category != nil && strings.TrimSpace(*submission.Category) == "" {
+		return nil, fmt.Errorf("category cannot be empty")
+	}
+
+	expenditure := copyExpenditure(current)
+	changes, requiresReaudit := expenditure.Amend(submission.ExpenditureChanges, p.ComplianceRules)
+
+	attachments, err := p.documentAttachments(ngoID, submission.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	attached := attachmentHashes(expenditure.Attachments)
+	for _, attachment := range attachments {
+		if !expenditure.HasAttachment(attachment.Hash) {
+			expenditure.AddAttachment(attachment.Filename, attachment.Hash, attachment.Type, p.ComplianceRules)
+		}
+	}
+	if len(expenditure.Attachments) > len(attached) {
+		changes = append(changes, transactions.FieldChange{Field: "attachments", Old: attached, New: attachmentHashes(expenditure.Attachments)})
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("amendment changes nothing")
+	}
+
+	recordedVersions := 0
+	for _, version := range versions {
+		if version.Expenditure.IsValidated() {
+			recordedVersions++
+		}
+	}
+	amendment := transactions.NewExpenditureAmendment(recordedVersions+1, reason, versions[0].Block.Hash, block.Hash)
+	amendment.Changes = changes
+	amendment.AmountChange = expenditure.Amount.Sub(current.Amount)
+	amendment.RequiresReaudit = requiresReaudit
+
+	// Check the campaign can cover an increase before it is queued
+	if expenditure.CampaignID != "" && amendment.AmountChange.IsPositive() {
+		campaign, exists := ngo.GetCampaign(expenditure.CampaignID)
+		if !exists {
+			return nil, ErrCampaignNotFound
+		}
+		if amendment.AmountChange.Cmp(campaign.RestrictedBalance()) > 0 {
+			return nil, fmt.Errorf("amendment exceeds campaign's restricted balance of ₹%s", campaign.RestrictedBalance())
+		}
+	}
+
+	// Reject invoices already paid and flag likely duplicates for the reviewer
+	invoice, indexInvoice, err := p.checkInvoice(expenditure)
+	if err != nil {
+		return nil, err
+	}
+
+	if !requiresReaudit {
+		decidedAt := amendment.RequestedAt
+		amendment.Status = transactions.AmendmentRecorded
+		amendment.DecidedAt = &decidedAt
+		// No auditor reviewed the correction, so none signs its block
+		expenditure.Amendment = amendment
+		expenditure.AuditorValidation = nil
+		expenditure.Reviews = nil
+		expenditure.Consensus = nil
+		return p.recordAmendment(ngo, expenditure, invoice, indexInvoice, nil, nil)
+	}
+
+	threshold := p.ConsensusPolicy.For(expenditure.Amount)
+	records, err := p.assignAuditors(expenditure, threshold.Panel, threshold.Required, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	panel := make([]string, 0, len(records))
+	for _, record := range records {
+		panel = append(panel, record.AuditorID)
+	}
+	expenditure.FileAmendment(amendment, panel, threshold.Required)
+
+	err = p.persist(func(tx *database.Repositories) error {
+		model, err := expenditureToModel(expenditure, "", "")
+		if err != nil {
+			return err
+		}
+		if err := tx.Expenditures.Save(model); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := tx.Assignments.Create(assignmentToModel(record)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist amendment: %w", err)
+	}
+
+	p.PendingExpenditures[expenditure.TransactionID] = expenditure
+	p.Assignments = append(p.Assignments, records...)
+
+	view := p.pendingExpenditureView(expenditure)
+	view["assignments"] = records
+	return view, nil
+}
+
+// GetExpenditureHistory returns every version of a recorded expenditure,
+// oldest first: the original, then each amendment with the fields it
+// changed, its reason and whether it was recorded, rejected or awaits
+// re-audit. When ngoID is given, only that NGO's expenditures are found.
+func (p *NGOTransparencyPlatform) GetExpenditureHistory(ngoID, expenditureID string) (map[string]interface{}, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for id, ngo := range p.NGOs {
+		if ngoID != "" && id != ngoID {
+			continue
+		}
+		versions, err := ngo.ExpenditureVersions(expenditureID)
+		if err != nil {
+			continue
+		}
+
+		history := make([]map[string]interface{}, 0, len(versions)+1)
+		current := 1
+		for _, version := range versions {
+			view := expenditureVersionView(version.Expenditure)
+			view["block_hash"] = version.Block.Hash
+			view["block_index"] = version.Block.Index
+			view["recorded_at"] = version.Block.Timestamp
+			if version.Expenditure.Amendment == nil {
+				view["version"] = 1
+			} else if version.Expenditure.IsValidated() {
+				current = version.Expenditure.Amendment.Version
+			}
+			history = append(history, view)
+		}
+		if pending, exists := p.PendingExpenditures[expenditureID]; exists && isAmendmentUnderReview(pending) {
+			view := expenditureVersionView(pending)
+			view["status"] = pending.Status
+			view["panel"] = pending.Panel
+			history = append(history, view)
+		}
+
+		return map[string]interface{}{
+			"transaction_id":  expenditureID,
+			"ngo_id":          id,
+			"current_version": current,
+			"versions":        history,
+		}, nil
+	}
+	return nil, ErrExpenditureNotFound
+}
+
+// approveAmendment records an amendment the re-audit panel approved
+func (p *NGOTransparencyPlatform) approveAmendment(auditor *entities.Auditor, expenditure *transactions.ExpenditureTransaction, notes string, score float64, outcome consensus.Outcome) (map[string]interface{}, error) {
+	ngo, exists := p.NGOs[expenditure.NGOID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+
+	// Invoices may have been used by expenditures approved since the amendment
+	invoice, indexInvoice, err := p.checkInvoice(expenditure)
+	if err != nil {
+		return nil, err
+	}
+
+	auditResult := p.castVote(auditor, expenditure, true, notes, score, outcome)
+	expenditure.ValidateByAuditor(auditor.AuditorID, true, notes, &outcome.Score)
+	return p.recordAmendment(ngo, expenditure, invoice, indexInvoice, auditor, auditResult)
+}
+
+// rejectAmendment records the re-audit panel's rejection of an amendment in
+// the NGO's chain. The version amended stands.
+func (p *NGOTransparencyPlatform) rejectAmendment(auditor *entities.Auditor, expenditure *transactions.ExpenditureTransaction, notes string, score float64, outcome consensus.Outcome) (map[string]interface{}, error) {
+	ngo, exists := p.NGOs[expenditure.NGOID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+
+	auditResult := p.castVote(auditor, expenditure, false, notes, score, outcome)
+	expenditure.ValidateByAuditor(auditor.AuditorID, false, notes, &outcome.Score)
+
+	result, err := ngo.RecordAmendment(expenditure)
+	if err != nil {
+		p.reloadAuditor(auditor.AuditorID)
+		return nil, err
+	}
+	block := ngo.ExpenditureBlockchain.GetLatestBlock()
+
+	restored, _, err := ngo.RecordedExpenditure(expenditure.TransactionID)
+	if err != nil {
+		p.reloadNGO(ngo.NGOID)
+		p.reloadAuditor(auditor.AuditorID)
+		return nil, err
+	}
+	restored.Amendment = expenditure.Amendment
+	restored.Reviews = expenditure.Reviews
+	restored.Panel = expenditure.Panel
+	restored.AssignedAuditorID = expenditure.AssignedAuditorID
+	restored.RequiredApprovals = expenditure.RequiredApprovals
+	restored.Consensus = expenditure.Consensus
+
+	err = p.persist(func(tx *database.Repositories) error {
+		if err := saveExpenditureAudit(tx, restored, auditResult, auditor, "", ""); err != nil {
+			return err
+		}
+		return saveBlock(tx, ngo.NGOID, block)
+	})
+	if err != nil {
+		p.reloadNGO(ngo.NGOID)
+		p.reloadAuditor(auditor.AuditorID)
+		return nil, fmt.Errorf("failed to persist rejected amendment: %w", err)
+	}
+
+	delete(p.PendingExpenditures, expenditure.TransactionID)
+	p.SystemStats.TotalTransactions++
+
+	return map[string]interface{}{
+		"success":        result.Success,
+		"status":         restored.Status,
+		"transaction_id": result.TransactionID,
+		"block_hash":     result.BlockHash,
+		"block_index":    result.BlockIndex,
+		"audit_result":   auditResult,
+		"consensus":      expenditure.Consensus,
+		"amendment":      expenditure.Amendment,
+	}, nil
+}
+
+// recordAmendment appends a recorded amendment to the NGO's chain and posts
+// its change in amount to the ledger. auditor and auditResult are nil for
+// amendments recorded without re-audit.
+func (p *NGOTransparencyPlatform) recordAmendment(ngo *entities.NGO, expenditure *transactions.ExpenditureTransaction, invoice transactions.InvoiceRecord, indexInvoice bool, auditor *entities.Auditor, auditResult *entities.AuditResult) (map[string]interface{}, error) {
+	amendment := expenditure.Amendment
+	reloadAuditor := func() {
+		if auditor != nil {
+			p.reloadAuditor(auditor.AuditorID)
+		}
+	}
+
+	entries := ledger.ExpenditureAmendmentEntries(ngo.NGOID, amendment.AmendmentID, expenditure.TransactionID, expenditure.Category, amendment.AmountChange, time.Now())
+	if err := p.Ledger.Validate(entries...); err != nil {
+		reloadAuditor()
+		return nil, fmt.Errorf("failed to record amendment in ledger: %w", err)
+	}
+
+	result, err := ngo.RecordAmendment(expenditure)
+	if err != nil {
+		// Drop the unrecorded audit from the cached auditor history
+		reloadAuditor()
+		return nil, err
+	}
+	block := ngo.ExpenditureBlockchain.GetLatestBlock()
+
+	// Suppliers first paid through a corrected invoice join the vendor registry
+	var vendor *entities.Vendor
+	if indexInvoice {
+		vendor = p.invoiceVendor(ngo.NGOID, expenditure.InvoiceDetails)
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		if auditResult != nil {
+			if err := saveExpenditureAudit(tx, expenditure, auditResult, auditor, "", ""); err != nil {
+				return err
+			}
+		} else {
+			model, err := expenditureToModel(expenditure, "", "")
+			if err != nil {
+				return err
+			}
+			if err := tx.Expenditures.Save(model); err != nil {
+				return err
+			}
+		}
+		if err := saveBlock(tx, ngo.NGOID, block); err != nil {
+			return err
+		}
+		if err := saveJournalEntries(tx, entries); err != nil {
+			return err
+		}
+		if vendor != nil {
+			if err := tx.Vendors.Save(vendorToModel(vendor)); err != nil {
+				return err
+			}
+		}
+		return saveNGO(tx, ngo)
+	})
+	if err != nil {
+		p.reloadNGO(ngo.NGOID)
+		reloadAuditor()
+		return nil, fmt.Errorf("failed to persist amendment: %w", err)
+	}
+
+	delete(p.PendingExpenditures, expenditure.TransactionID)
+	p.invoices.Remove(expenditure.TransactionID)
+	if indexInvoice {
+		p.invoices.Add(invoice)
+	}
+	if vendor != nil {
+		p.Vendors[vendor.GSTIN] = vendor
+	}
+
+	if err := p.Ledger.Post(entries...); err != nil {
+		return nil, fmt.Errorf("failed to post amendment to ledger: %w", err)
+	}
+
+	p.SystemStats.TotalTransactions++
+	p.SystemStats.TotalExpenditures = p.SystemStats.TotalExpenditures.Add(amendment.AmountChange)
+
+	view := map[string]interface{}{
+		"success":        result.Success,
+		"status":         expenditure.Status,
+		"transaction_id": result.TransactionID,
+		"block_hash":     result.BlockHash,
+		"block_index":    result.BlockIndex,
+		"amendment":      amendment,
+		"invoice_flags":  expenditure.InvoiceFlags,
+	}
+	if auditResult != nil {
+		view["audit_result"] = auditResult
+		view["consensus"] = expenditure.Consensus
+	}
+	return view, nil
+}
+
+// expenditureVersionView describes a version of an expenditure for its history
+func expenditureVersionView(expenditure *transactions.ExpenditureTransaction) map[string]interface{} {
+	view := map[string]interface{}{
+		"status":           expenditure.Status,
+		"amount":           expenditure.Amount,
+		"category":         expenditure.Category,
+		"description":      expenditure.Description,
+		"invoice":          expenditure.GetInvoiceInfo(),
+		"attachments":      expenditure.Attachments,
+		"compliance_score": expenditure.ComplianceScore,
+		"funding":          expenditure.Funding,
+	}
+	if expenditure.AuditorValidation != nil {
+		view["auditor_validation"] = expenditure.AuditorValidation
+	}
+	if amendment := expenditure.Amendment; amendment != nil {
+		view["version"] = amendment.Version
+		view["amendment_id"] = amendment.AmendmentID
+		view["amendment_status"] = amendment.Status
+		view["reason"] = amendment.Reason
+		view["changes"] = amendment.Changes
+		view["amount_change"] = amendment.AmountChange
+		view["requires_reaudit"] = amendment.RequiresReaudit
+		view["original_block_hash"] = amendment.OriginalBlockHash
+		view["previous_block_hash"] = amendment.PreviousBlockHash
+		view["requested_at"] = amendment.RequestedAt
+		if amendment.DecidedAt != nil {
+			view["decided_at"] = amendment.DecidedAt
+		}
+	}
+	return view
+}
+
+// isAmendmentUnderReview reports whether a queued expenditure is an amendment
+// awaiting re-audit rather than a new submission or appeal
+func isAmendmentUnderReview(expenditure *transactions.ExpenditureTransaction) bool {
+	return expenditure.Amendment != nil && expenditure.Amendment.Status == transactions.AmendmentPending
+}
+
+func attachmentHashes(attachments []transactions.Attachment) []string {
+	hashes := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		hashes = append(hashes, attachment.Hash)
+	}
+	return hashes
+}
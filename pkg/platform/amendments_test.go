@@ -0,0 +1,214 @@
+package platform
+
+import (
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/ledger"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+func TestAmendExpenditureWithoutReaudit(t *testing.T) {
+	p, repos := newAppealPlatform(t)
+	invoiceDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	recorded, err := recordExpenditure(t, p, "NGO001", vendorExpenditure("BB-101", 300, invoiceDate))
+	if err != nil {
+		t.Fatalf("Failed to record expenditure: %v", err)
+	}
+	id, originalHash := recorded["transaction_id"].(string), recorded["block_hash"].(string)
+
+	description, number := "Textbooks for class 5", "BB-110"
+	if _, err := p.AmendExpenditure("NGO001", id, AmendmentSubmission{ExpenditureChanges: transactions.ExpenditureChanges{Description: &description}}); err == nil {
+		t.Errorf("Expected an amendment without a reason to be refused")
+	}
+	if _, err := p.AmendExpenditure("NGO002", id, AmendmentSubmission{Reason: "Typo", ExpenditureChanges: transactions.ExpenditureChanges{Description: &description}}); err == nil {
+		t.Errorf("Expected another NGO's amendment to be refused")
+	}
+	if _, err := p.AmendExpenditure("NGO001", id, AmendmentSubmission{Reason: "Nothing"}); err == nil {
+		t.Errorf("Expected an amendment changing nothing to be refused")
+	}
+
+	amended, err := p.AmendExpenditure("NGO001", id, AmendmentSubmission{
+		Reason:             "Invoice number was mistyped",
+		ExpenditureChanges: transactions.ExpenditureChanges{Description: &description, InvoiceNumber: &number},
+	})
+	if err != nil {
+		t.Fatalf("Failed to amend expenditure: %v", err)
+	}
+	if amended["status"] != "validated" || amended["block_hash"] == nil || amended["block_hash"] == originalHash {
+		t.Fatalf("Expected the correction to be recorded at once, got %+v", amended)
+	}
+
+	chain := p.NGOs["NGO001"].ExpenditureBlockchain
+	var block struct {
+		Type      string                            `json:"type"`
+		Amount    *float64                          `json:"amount"`
+		Amendment transactions.ExpenditureAmendment `json:"amendment"`
+	}
+	decodeBlockData(t, chain.GetLatestBlock(), &block)
+	if block.Type != "expenditure_amendment" || block.Amount != nil || block.Amendment.OriginalBlockHash != originalHash || block.Amendment.RequiresReaudit {
+		t.Fatalf("Expected an amendment block referencing the original, got %+v", block)
+	}
+	if len(block.Amendment.Changes) != 2 || block.Amendment.Changes[0].Field != "description" || block.Amendment.Changes[1].Old != "BB-101" {
+		t.Errorf("Expected the changed fields in the block, got %+v", block.Amendment.Changes)
+	}
+	if !chain.IsChainValid() || !p.NGOs["NGO001"].TotalExpenditureReported.Equal(money.FromMajor(300)) {
+		t.Errorf("Expected a valid chain and an unchanged total")
+	}
+
+	// The corrected invoice number is now paid, and the old one free
+	if _, err := p.SubmitExpenditure("NGO001", vendorExpenditure("BB-110", 120, invoiceDate)); err == nil {
+		t.Errorf("Expected the corrected invoice number to count as paid")
+	}
+	if _, err := p.SubmitExpenditure("NGO001", vendorExpenditure("BB-101", 120, invoiceDate)); err != nil {
+		t.Errorf("Expected the mistyped invoice number to be free, got %v", err)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	history, err := reloaded.GetExpenditureHistory("NGO001", id)
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	versions := history["versions"].([]map[string]interface{})
+	if history["current_version"] != 2 || len(versions) != 2 || versions[1]["description"] != description || versions[0]["block_hash"] != originalHash {
+		t.Fatalf("Expected two versions of the expenditure, got %+v", history)
+	}
+	if _, err := reloaded.GetExpenditureHistory("NGO002", id); err == nil {
+		t.Errorf("Expected another NGO's expenditure history to be hidden")
+	}
+}
+
+func TestAmendExpenditureAmountReaudited(t *testing.T) {
+	p, repos := newAppealPlatform(t)
+	recorded, err := recordExpenditure(t, p, "NGO001", invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books"}))
+	if err != nil {
+		t.Fatalf("Failed to record expenditure: %v", err)
+	}
+	id := recorded["transaction_id"].(string)
+	chainLength := p.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength()
+
+	amount := money.FromMajor(250)
+	amended, err := p.AmendExpenditure("NGO001", id, AmendmentSubmission{
+		Reason:             "Vendor refunded a damaged carton",
+		ExpenditureChanges: transactions.ExpenditureChanges{Amount: &amount},
+	})
+	if err != nil {
+		t.Fatalf("Failed to amend expenditure: %v", err)
+	}
+	reviewer := amended["assigned_auditor_id"].(string)
+	if amended["status"] != "under_amendment" || p.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength() != chainLength {
+		t.Fatalf("Expected the amount change to await re-audit, got %+v", amended)
+	}
+	if _, err := p.AmendExpenditure("NGO001", id, AmendmentSubmission{Reason: "Again", ExpenditureChanges: transactions.ExpenditureChanges{Amount: &amount}}); err == nil {
+		t.Errorf("Expected a second amendment to wait for the first")
+	}
+
+	// The amendment under re-audit survives a restart
+	reloaded := newReloadedPlatform(t, repos)
+	pending := reloaded.PendingExpenditures[id]
+	if pending == nil || !pending.IsUnderAmendment() || pending.Amendment == nil || !pending.Amount.Equal(amount) {
+		t.Fatalf("Expected the amendment to be reloaded, got %+v", pending)
+	}
+
+	result, err := reloaded.ReviewExpenditure(reviewer, id, transactions.DecisionApprove, "Credit note checked", nil)
+	if err != nil {
+		t.Fatalf("Failed to re-audit amendment: %v", err)
+	}
+	if result["status"] != "validated" {
+		t.Fatalf("Expected the amendment to be recorded, got %+v", result)
+	}
+
+	ngo := reloaded.NGOs["NGO001"]
+	var block struct {
+		Type          string                        `json:"type"`
+		Amount        float64                       `json:"amount"`
+		AmendedAmount float64                       `json:"amended_amount"`
+		Funding       []transactions.FundAllocation `json:"funding"`
+	}
+	decodeBlockData(t, ngo.ExpenditureBlockchain.GetLatestBlock(), &block)
+	if block.Type != "expenditure_amendment" || block.Amount != -50 || block.AmendedAmount != 250 {
+		t.Fatalf("Expected the change in amount in the chain, got %+v", block)
+	}
+	if len(block.Funding) != 1 || !block.Funding[0].Amount.Equal(money.FromMajor(-50)) {
+		t.Errorf("Expected ₹50 of funding released, got %+v", block.Funding)
+	}
+	if !ngo.TotalExpenditureReported.Equal(amount) {
+		t.Errorf("Expected ₹250 reported, got %s", ngo.TotalExpenditureReported)
+	}
+	if expenses, _ := reloaded.Ledger.Balance("NGO001", ledger.AccountProgramExpenses); !expenses.Equal(amount) {
+		t.Errorf("Expected ₹250 of program expenses, got %s", expenses)
+	}
+	trace, err := reloaded.TraceDonation(block.Funding[0].DonationID)
+	if err != nil {
+		t.Fatalf("Failed to trace donation: %v", err)
+	}
+	if !trace.Allocated.Equal(amount) {
+		t.Errorf("Expected the donation to fund ₹250, got %+v", trace)
+	}
+
+	history, err := reloaded.GetExpenditureHistory("", id)
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	versions := history["versions"].([]map[string]interface{})
+	if history["current_version"] != 2 || len(versions) != 2 || versions[1]["amendment_status"] != transactions.AmendmentRecorded {
+		t.Fatalf("Expected the re-audited amendment as the current version, got %+v", history)
+	}
+}
+
+func TestAmendmentRejectedOnReaudit(t *testing.T) {
+	p, repos := newAppealPlatform(t)
+	recorded, err := recordExpenditure(t, p, "NGO001", invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books"}))
+	if err != nil {
+		t.Fatalf("Failed to record expenditure: %v", err)
+	}
+	id := recorded["transaction_id"].(string)
+
+	vendor := "Another Vendor"
+	amended, err := p.AmendExpenditure("NGO001", id, AmendmentSubmission{
+		Reason:             "Paid a different supplier",
+		ExpenditureChanges: transactions.ExpenditureChanges{VendorName: &vendor},
+	})
+	if err != nil {
+		t.Fatalf("Failed to amend expenditure: %v", err)
+	}
+	if amended["status"] != "under_amendment" {
+		t.Fatalf("Expected a change of vendor to await re-audit, got %+v", amended)
+	}
+
+	result, err := p.ReviewExpenditure(amended["assigned_auditor_id"].(string), id, transactions.DecisionReject, "No invoice from the new vendor", nil)
+	if err != nil {
+		t.Fatalf("Failed to re-audit amendment: %v", err)
+	}
+	if result["status"] != "validated" || result["block_hash"] == nil {
+		t.Fatalf("Expected the rejection recorded and the expenditure to stand, got %+v", result)
+	}
+
+	ngo := p.NGOs["NGO001"]
+	var block struct {
+		Amount    *float64                          `json:"amount"`
+		Amendment transactions.ExpenditureAmendment `json:"amendment"`
+	}
+	decodeBlockData(t, ngo.ExpenditureBlockchain.GetLatestBlock(), &block)
+	if block.Amount != nil || block.Amendment.Status != transactions.AmendmentRejected {
+		t.Fatalf("Expected a rejected amendment with no amount in the chain, got %+v", block)
+	}
+	current, _, err := ngo.RecordedExpenditure(id)
+	if err != nil || current.InvoiceDetails.VendorName == vendor {
+		t.Fatalf("Expected the original vendor to stand, got %+v, %v", current, err)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	history, err := reloaded.GetExpenditureHistory("NGO001", id)
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	versions := history["versions"].([]map[string]interface{})
+	if history["current_version"] != 1 || len(versions) != 2 || versions[1]["amendment_status"] != transactions.AmendmentRejected {
+		t.Fatalf("Expected the rejected amendment in the history, got %+v", history)
+	}
+	if _, err := reloaded.AmendExpenditure("NGO001", id, AmendmentSubmission{Reason: "Vendor invoice attached", ExpenditureChanges: transactions.ExpenditureChanges{VendorName: &vendor}}); err != nil {
+		t.Errorf("Expected the expenditure to be amendable again, got %v", err)
+	}
+}
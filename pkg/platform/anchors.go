@@ -0,0 +1,281 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/polygon"
+)
+
+// PendingAnchor is a committed block that has not been anchored to Polygon
+// yet, such as one refused by the daily spend cap
+type PendingAnchor struct {
+	BlockHash     string                 `json:"block_hash"`
+	NGOID         string                 `json:"ngo_id"`
+	ChainType     string                 `json:"chain_type"`     // donation, expenditure
+	TransactionID string                 `json:"transaction_id"` // Donation or expenditure the block holds
+	Data          map[string]interface{} `json:"data"`
+	Reason        string                 `json:"reason"` // Why the last attempt failed
+	Attempts      int                    `json:"attempts"`
+	LastAttemptAt time.Time              `json:"last_attempt_at"`
+	CreatedAt     time.Time              `json:"created_at"`
+	anchor        *polygon.AnchorResult  // Sent, but not yet saved
+}
+
+// AnchorRetry is the outcome of retrying a pending anchor
+type AnchorRetry struct {
+	BlockHash     string `json:"block_hash"`
+	NGOID         string `json:"ngo_id"`
+	PolygonTxHash string `json:"polygon_tx_hash,omitempty"`
+	Success       bool   `json:"success"`
+	Error         string `json:"error,omitempty"`
+}
+
+// anchorBlock anchors a committed block's hash to Polygon, when configured,
+// and stores the anchor so that its cost still counts towards the daily spend
+// cap after a restart. The stored donation or expenditure is linked to the
+// anchor. A block that cannot be anchored is kept pending, with the reason,
+// and the error is returned; RetryPendingAnchors anchors it later. The caller
+// must hold the write lock.
+func (p *NGOTransparencyPlatform) anchorBlock(blockHash, ngoID, chainType, transactionID string, data map[string]interface{}) (*polygon.AnchorResult, error) {
+	if p.PolygonIntegration == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	return p.sendAnchor(&PendingAnchor{
+		BlockHash:     blockHash,
+		NGOID:         ngoID,
+		ChainType:     chainType,
+		TransactionID: transactionID,
+		Data:          data,
+		CreatedAt:     now,
+	}, now)
+}
+
+// sendAnchor anchors a pending block and stores the anchor, keeping the block
+// pending if either fails. The caller must hold the write lock.
+func (p *NGOTransparencyPlatform) sendAnchor(pending *PendingAnchor, now time.Time) (*polygon.AnchorResult, error) {
+	pending.Attempts++
+	pending.LastAttemptAt = now
+
+	if pending.anchor == nil {
+		anchor, err := p.PolygonIntegration.AnchorBlockHash(pending.BlockHash, pending.NGOID, pending.ChainType, pending.Data)
+		if err != nil {
+			pending.Reason = err.Error()
+			p.pendingAnchors[pending.BlockHash] = pending
+			if saveErr := p.persist(func(tx *database.Repositories) error {
+				model, err := pendingAnchorToModel(pending)
+				if err != nil {
+					return err
+				}
+				return tx.Pending.Save(model)
+			}); saveErr != nil {
+				return nil, fmt.Errorf("block not anchored to Polygon: %w (failed to save pending anchor: %v)", err, saveErr)
+			}
+			return nil, fmt.Errorf("block not anchored to Polygon: %w", err)
+		}
+		pending.anchor = anchor
+	}
+
+	if err := p.persist(func(tx *database.Repositories) error {
+		if err := tx.Anchors.Create(anchorToModel(pending.BlockHash, pending.anchor)); err != nil {
+			return err
+		}
+		if err := linkAnchor(tx, pending.ChainType, pending.TransactionID, pending.anchor.PolygonTxHash); err != nil {
+			return err
+		}
+		return tx.Pending.Delete(pending.BlockHash)
+	}); err != nil {
+		// The anchor was sent, so only saving it is retried
+		pending.Reason = fmt.Sprintf("failed to save anchor: %v", err)
+		p.pendingAnchors[pending.BlockHash] = pending
+		return pending.anchor, fmt.Errorf("failed to save Polygon anchor: %w", err)
+	}
+
+	delete(p.pendingAnchors, pending.BlockHash)
+	return pending.anchor, nil
+}
+
+// linkAnchor records the Polygon transaction on the donation or expenditure a
+// block holds
+func linkAnchor(tx *database.Repositories, chainType, transactionID, polygonTxHash string) error {
+	switch chainType {
+	case "donation":
+		return tx.Donations.SetPolygonTxHash(transactionID, polygonTxHash)
+	case "expenditure":
+		return tx.Expenditures.SetPolygonTxHash(transactionID, polygonTxHash)
+	}
+	return fmt.Errorf("unknown chain type %q", chainType)
+}
+
+// RetryPendingAnchors anchors the pending blocks, oldest first. It stops at the
+// first block refused by the daily spend cap, since the rest would be refused
+// too until the next day.
+func (p *NGOTransparencyPlatform) RetryPendingAnchors(now time.Time) []AnchorRetry {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.PolygonIntegration == nil {
+		return nil
+	}
+
+	retries := make([]AnchorRetry, 0, len(p.pendingAnchors))
+	for _, pending := range p.sortedPendingAnchors("") {
+		retry := AnchorRetry{BlockHash: pending.BlockHash, NGOID: pending.NGOID}
+		anchor, err := p.sendAnchor(pending, now)
+		if err != nil {
+			retry.Error = err.Error()
+		} else {
+			retry.Success = true
+		}
+		if anchor != nil {
+			retry.PolygonTxHash = anchor.PolygonTxHash
+		}
+		retries = append(retries, retry)
+
+		if errors.Is(err, polygon.ErrDailySpendCapExceeded) {
+			break
+		}
+	}
+	return retries
+}
+
+// GetPendingAnchors returns the blocks not yet anchored to Polygon, oldest
+// first, for one NGO or for every NGO when ngoID is empty
+func (p *NGOTransparencyPlatform) GetPendingAnchors(ngoID string) []PendingAnchor {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	pending := p.sortedPendingAnchors(ngoID)
+	result := make([]PendingAnchor, len(pending))
+	for i, anchor := range pending {
+		result[i] = *anchor
+	}
+	return result
+}
+
+// sortedPendingAnchors returns the pending anchors, oldest first. The caller
+// must hold the lock.
+func (p *NGOTransparencyPlatform) sortedPendingAnchors(ngoID string) []*PendingAnchor {
+	pending := make([]*PendingAnchor, 0, len(p.pendingAnchors))
+	for _, anchor := range p.pendingAnchors {
+		if ngoID == "" || anchor.NGOID == ngoID {
+			pending = append(pending, anchor)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].CreatedAt.Equal(pending[j].CreatedAt) {
+			return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+		}
+		return pending[i].BlockHash < pending[j].BlockHash
+	})
+	return pending
+}
+
+// restoreAnchors loads the stored anchors into the Polygon integration, and
+// the blocks still pending an anchor. The caller must hold the write lock.
+func (p *NGOTransparencyPlatform) restoreAnchors() error {
+	if p.repos == nil || p.PolygonIntegration == nil {
+		return nil
+	}
+
+	models, err := p.repos.Anchors.GetAnchors()
+	if err != nil {
+		return fmt.Errorf("failed to load Polygon anchors: %w", err)
+	}
+	anchors := make(map[string]polygon.AnchorResult, len(models))
+	for i := range models {
+		anchors[models[i].BlockHash] = anchorFromModel(&models[i])
+	}
+	p.PolygonIntegration.RestoreAnchors(anchors)
+
+	pendingModels, err := p.repos.Pending.GetPendingAnchors()
+	if err != nil {
+		return fmt.Errorf("failed to load pending Polygon anchors: %w", err)
+	}
+	for i := range pendingModels {
+		pending, err := pendingAnchorFromModel(&pendingModels[i])
+		if err != nil {
+			return err
+		}
+		p.pendingAnchors[pending.BlockHash] = pending
+	}
+	return nil
+}
+
+func anchorToModel(blockHash string, anchor *polygon.AnchorResult) *database.AnchorModel {
+	return &database.AnchorModel{
+		BlockHash:     blockHash,
+		NGOID:         anchor.NGOID,
+		ChainType:     anchor.ChainType,
+		PolygonTxHash: anchor.PolygonTxHash,
+		DataHash:      anchor.DataHash,
+		BlockNumber:   anchor.BlockNumber,
+		GasUsed:       anchor.GasUsed,
+		Confirmations: anchor.Confirmations,
+		MaxFeeGwei:    anchor.MaxFeeGwei,
+		GasPriceGwei:  anchor.GasPriceGwei,
+		CostWei:       anchor.CostWei,
+		CostMATIC:     anchor.CostMATIC,
+		CostINR:       anchor.CostINR,
+		AnchoredAt:    anchor.Timestamp,
+	}
+}
+
+func anchorFromModel(model *database.AnchorModel) polygon.AnchorResult {
+	return polygon.AnchorResult{
+		PolygonTxHash: model.PolygonTxHash,
+		DataHash:      model.DataHash,
+		Timestamp:     model.AnchoredAt,
+		BlockNumber:   model.BlockNumber,
+		GasUsed:       model.GasUsed,
+		Confirmations: model.Confirmations,
+		NGOID:         model.NGOID,
+		ChainType:     model.ChainType,
+		MaxFeeGwei:    model.MaxFeeGwei,
+		GasPriceGwei:  model.GasPriceGwei,
+		CostWei:       model.CostWei,
+		CostMATIC:     model.CostMATIC,
+		CostINR:       model.CostINR,
+	}
+}
+
+func pendingAnchorToModel(pending *PendingAnchor) (*database.PendingAnchorModel, error) {
+	data, err := marshalField(pending.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &database.PendingAnchorModel{
+		BlockHash:     pending.BlockHash,
+		NGOID:         pending.NGOID,
+		ChainType:     pending.ChainType,
+		TransactionID: pending.TransactionID,
+		AnchorData:    data,
+		Reason:        pending.Reason,
+		Attempts:      pending.Attempts,
+		LastAttemptAt: pending.LastAttemptAt,
+		CreatedAt:     pending.CreatedAt,
+	}, nil
+}
+
+func pendingAnchorFromModel(model *database.PendingAnchorModel) (*PendingAnchor, error) {
+	var data map[string]interface{}
+	if err := unmarshalField(model.AnchorData, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse pending anchor of block %s: %w", model.BlockHash, err)
+	}
+	return &PendingAnchor{
+		BlockHash:     model.BlockHash,
+		NGOID:         model.NGOID,
+		ChainType:     model.ChainType,
+		TransactionID: model.TransactionID,
+		Data:          data,
+		Reason:        model.Reason,
+		Attempts:      model.Attempts,
+		LastAttemptAt: model.LastAttemptAt,
+		CreatedAt:     model.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package platform
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/polygon"
+)
+
+func TestAnchorSpendSurvivesRestart(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	if err := p.InitializePolygon("", "key", 100000, polygon.GweiToWei(30)); err != nil {
+		t.Fatalf("Failed to initialize Polygon: %v", err)
+	}
+
+	result := deposit(t, p, money.INR(100000))
+	blockHash := result["block_hash"].(string)
+	anchor, exists := p.PolygonIntegration.Anchors[blockHash]
+	if !exists {
+		t.Fatalf("Expected the donation block to be anchored")
+	}
+	// The block is anchored once stored, and the stored donation linked to the anchor
+	if model, err := repos.Donations.GetByTransactionID(result["transaction_id"].(string)); err != nil || model.PolygonTxHash != anchor.PolygonTxHash {
+		t.Errorf("Expected the donation to record Polygon transaction %s, got %+v (%v)", anchor.PolygonTxHash, model, err)
+	}
+	spent := p.PolygonIntegration.GasStrategy.SpentOn(anchor.Timestamp)
+	if spent.String() != anchor.CostWei {
+		t.Fatalf("Expected the anchor's cost of %s wei to be spent, got %s", anchor.CostWei, spent)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	if err := reloaded.InitializePolygon("", "key", 100000, polygon.GweiToWei(30)); err != nil {
+		t.Fatalf("Failed to initialize Polygon: %v", err)
+	}
+	if !reloaded.PolygonIntegration.VerifyAnchoredHash(blockHash).Exists {
+		t.Error("Expected the anchor to survive a restart")
+	}
+	if got := reloaded.PolygonIntegration.GasStrategy.SpentOn(anchor.Timestamp); got.Cmp(spent) != 0 {
+		t.Errorf("Expected the day's spend of %s wei to survive a restart, got %s", spent, got)
+	}
+}
+
+func TestRefusedAnchorIsRetried(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	if err := p.InitializePolygon("", "key", 100000, polygon.GweiToWei(30)); err != nil {
+		t.Fatalf("Failed to initialize Polygon: %v", err)
+	}
+	policy := polygon.DefaultGasPolicy()
+	policy.DailySpendCap = big.NewInt(1)
+	p.PolygonIntegration.SetGasStrategy(polygon.NewGasStrategy("", polygon.GweiToWei(30), policy))
+
+	// The donation stands, and its block waits for an anchor
+	result := deposit(t, p, money.INR(100000))
+	if reason, _ := result["anchor_error"].(string); !strings.Contains(reason, polygon.ErrDailySpendCapExceeded.Error()) {
+		t.Errorf("Expected the spend cap to be reported, got %q", reason)
+	}
+	blockHash := result["block_hash"].(string)
+	pending := p.GetPendingAnchors("NGO001")
+	if len(pending) != 1 || pending[0].BlockHash != blockHash || pending[0].Reason == "" {
+		t.Fatalf("Expected the block to be pending with a reason, got %+v", pending)
+	}
+
+	// Pending anchors survive a restart
+	reloaded := newReloadedPlatform(t, repos)
+	if err := reloaded.InitializePolygon("", "key", 100000, polygon.GweiToWei(30)); err != nil {
+		t.Fatalf("Failed to initialize Polygon: %v", err)
+	}
+	reloaded.PolygonIntegration.SetGasStrategy(polygon.NewGasStrategy("", polygon.GweiToWei(30), policy))
+	if pending := reloaded.GetPendingAnchors(""); len(pending) != 1 || pending[0].Attempts != 1 {
+		t.Fatalf("Expected the pending anchor to survive a restart, got %+v", pending)
+	}
+
+	// Retries stop while the cap is reached
+	if retries := reloaded.RetryPendingAnchors(time.Now()); len(retries) != 1 || retries[0].Success {
+		t.Fatalf("Expected the retry to be refused, got %+v", retries)
+	}
+
+	// and anchor the block once the budget frees up
+	reloaded.PolygonIntegration.SetGasStrategy(polygon.NewGasStrategy("", polygon.GweiToWei(30), polygon.DefaultGasPolicy()))
+	retries := reloaded.RetryPendingAnchors(time.Now())
+	if len(retries) != 1 || !retries[0].Success {
+		t.Fatalf("Expected the block to be anchored, got %+v", retries)
+	}
+	if !reloaded.PolygonIntegration.VerifyAnchoredHash(blockHash).Exists {
+		t.Error("Expected the block to be anchored")
+	}
+	if pending := reloaded.GetPendingAnchors(""); len(pending) != 0 {
+		t.Errorf("Expected no pending anchors, got %+v", pending)
+	}
+	if stored, err := repos.Pending.GetPendingAnchors(); err != nil || len(stored) != 0 {
+		t.Errorf("Expected the stored pending anchor to be removed, got %+v (%v)", stored, err)
+	}
+	if model, err := repos.Donations.GetByTransactionID(result["transaction_id"].(string)); err != nil || model.PolygonTxHash != retries[0].PolygonTxHash {
+		t.Errorf("Expected the donation to record Polygon transaction %s, got %+v (%v)", retries[0].PolygonTxHash, model, err)
+	}
+}
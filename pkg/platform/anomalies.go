@@ -0,0 +1,120 @@
+package platform
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/anomaly"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// NGOAnomalies is the result of scanning one NGO's expenditure chain
+type NGOAnomalies struct {
+	NGOID     string          `json:"ngo_id"`
+	RiskScore float64         `json:"risk_score"` // Highest risk among the alerts
+	Alerts    []anomaly.Alert `json:"alerts"`
+}
+
+// GetAnomalies scans an NGO's recorded expenditures for anomalies
+func (p *NGOTransparencyPlatform) GetAnomalies(ngoID string) (*NGOAnomalies, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+
+	alerts := anomaly.Detect(p.anomalyConfig(), chainExpenditures(ngo), time.Now())
+	return &NGOAnomalies{NGOID: ngoID, RiskScore: anomaly.RiskScore(alerts), Alerts: alerts}, nil
+}
+
+// GetAnomalyReport scans every NGO's recorded expenditures and returns the
+// NGOs with alerts, riskiest first
+func (p *NGOTransparencyPlatform) GetAnomalyReport() []*NGOAnomalies {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	config := p.anomalyConfig()
+	now := time.Now()
+	report := make([]*NGOAnomalies, 0)
+	for ngoID, ngo := range p.NGOs {
+		alerts := anomaly.Detect(config, chainExpenditures(ngo), now)
+		if len(alerts) > 0 {
+			report = append(report, &NGOAnomalies{NGOID: ngoID, RiskScore: anomaly.RiskScore(alerts), Alerts: alerts})
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].RiskScore != report[j].RiskScore {
+			return report[i].RiskScore > report[j].RiskScore
+		}
+		return report[i].NGOID < report[j].NGOID
+	})
+	return report
+}
+
+// pendingAnomalies scans an NGO's recorded expenditures together with a
+// pending one and returns the alerts concerning it
+func (p *NGOTransparencyPlatform) pendingAnomalies(pending *transactions.ExpenditureTransaction) []anomaly.Alert {
+	ngo, exists := p.NGOs[pending.NGOID]
+	if !exists {
+		return nil
+	}
+
+	expenditures := append(chainExpenditures(ngo), anomaly.Expenditure{
+		TransactionID: pending.TransactionID,
+		Amount:        pending.Amount,
+		Category:      pending.Category,
+		VendorGSTIN:   anomalyVendor(pending.InvoiceDetails),
+		Timestamp:     pending.Timestamp,
+	})
+
+	alerts := make([]anomaly.Alert, 0)
+	for _, alert := range anomaly.Detect(p.anomalyConfig(), expenditures, time.Now()) {
+		if alert.Involves(pending.TransactionID) {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// anomalyConfig returns the platform's AnomalyConfig, watching for invoices
+// split under the consensus thresholds unless it names its own
+func (p *NGOTransparencyPlatform) anomalyConfig() anomaly.Config {
+	config := p.AnomalyConfig
+	if len(config.SplitThresholds) == 0 {
+		for _, threshold := range p.ConsensusPolicy.Thresholds {
+			config.SplitThresholds = append(config.SplitThresholds, threshold.MinAmount)
+		}
+	}
+	return config
+}
+
+// chainExpenditures reads the expenditures recorded in an NGO's expenditure
+// chain, as last amended
+func chainExpenditures(ngo *entities.NGO) []anomaly.Expenditure {
+	recorded, err := ngo.RecordedExpenditures()
+	if err != nil {
+		return nil
+	}
+
+	expenditures := make([]anomaly.Expenditure, 0, len(recorded))
+	for _, expenditure := range recorded {
+		expenditures = append(expenditures, anomaly.Expenditure{
+			TransactionID: expenditure.TransactionID,
+			Amount:        expenditure.Amount,
+			Category:      expenditure.Category,
+			VendorGSTIN:   anomalyVendor(expenditure.InvoiceDetails),
+			Timestamp:     expenditure.Timestamp,
+		})
+	}
+	return expenditures
+}
+
+// anomalyVendor returns the vendor of an invoice
+func anomalyVendor(invoice transactions.InvoiceDetails) string {
+	return strings.ToUpper(strings.TrimSpace(invoice.VendorGSTIN))
+}
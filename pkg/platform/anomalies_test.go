@@ -0,0 +1,55 @@
+package platform
+
+import (
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/anomaly"
+	"ngo-transparency-platform/pkg/money"
+)
+
+func TestExpenditureAnomalies(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+	p.AnomalyConfig.VendorMinSample = 2
+
+	deposit(t, p, money.INR(1000000))
+
+	if report := p.GetAnomalyReport(); len(report) != 0 {
+		t.Fatalf("Expected no anomalies without expenditures, got %+v", report)
+	}
+
+	invoiceDate := time.Now().Add(-24 * time.Hour)
+	for i, invoiceNumber := range []string{"INV-A1", "INV-A2"} {
+		if _, err := recordExpenditure(t, p, "NGO001", vendorExpenditure(invoiceNumber, 300.0+float64(i)*25, invoiceDate)); err != nil {
+			t.Fatalf("Failed to record expenditure: %v", err)
+		}
+	}
+
+	anomalies, err := p.GetAnomalies("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to get anomalies: %v", err)
+	}
+	if len(anomalies.Alerts) != 1 || anomalies.Alerts[0].Kind != anomaly.KindVendorConcentration || anomalies.RiskScore != 100 {
+		t.Fatalf("Expected every payment to one vendor to be flagged, got %+v", anomalies)
+	}
+	if report := p.GetAnomalyReport(); len(report) != 1 || report[0].NGOID != "NGO001" {
+		t.Errorf("Expected the NGO in the anomaly report, got %+v", report)
+	}
+	if _, err := p.GetAnomalies("NGO999"); err == nil {
+		t.Errorf("Expected anomalies of an unknown NGO to be refused")
+	}
+
+	submitted, err := p.SubmitExpenditure("NGO001", vendorExpenditure("INV-A3", 280.0, invoiceDate))
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+	queue := p.GetPendingExpenditures("AUD001")
+	if len(queue) != 1 || queue[0]["transaction_id"] != submitted["transaction_id"] {
+		t.Fatalf("Expected the expenditure in the auditor's queue, got %+v", queue)
+	}
+	alerts := queue[0]["anomaly_alerts"].([]anomaly.Alert)
+	if len(alerts) != 1 || !alerts[0].Involves(submitted["transaction_id"].(string)) || queue[0]["risk_score"] != 100.0 {
+		t.Errorf("Expected the pending expenditure's anomalies in the queue, got %+v", queue[0])
+	}
+}
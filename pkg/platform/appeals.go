@@ -0,0 +1,139 @@
+package platform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// AppealSubmission is an NGO's appeal against the rejection of an
+// expenditure. Payment references and documents given replace those
+// submitted; attachments, the hashes of documents the NGO uploaded, are
+// added to them.
+type AppealSubmission struct {
+	Reason            string   `json:"reason"`
+	BankTransactionID string   `json:"bank_transaction_id,omitempty"`
+	ChequeNumber      string   `json:"cheque_number,omitempty"`
+	Documents         []string `json:"documents,omitempty"`
+	Attachments       []string `json:"attachments,omitempty"`
+}
+
+// AppealExpenditure reopens a rejected expenditure of an NGO for review by a
+// new panel of auditors, none of whom was on the panel that rejected it. The
+// panel is formed under the platform's AssignmentPolicy and ConsensusPolicy
+// as for a new submission. The expenditure keeps its ID and history; the
+// new panel's decision is final and is recorded in the NGO's expenditure
+// blockchain with a reference to the rejection appealed against.
+func (p *NGOTransparencyPlatform) AppealExpenditure(ngoID, expenditureID string, submission AppealSubmission) (map[string]interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	rejected, exists := p.RejectedExpenditures[expenditureID]
+	if !exists || rejected.NGOID != ngoID {
+		if pending, exists := p.PendingExpenditures[expenditureID]; exists && pending.NGOID == ngoID {
+			return nil, fmt.Errorf("only rejected expenditures can be appealed")
+		}
+		return nil, ErrExpenditureNotFound
+	}
+	if rejected.Appeal != nil {
+		return nil, fmt.Errorf("expenditure has already been appealed")
+	}
+	reason := strings.TrimSpace(submission.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required to appeal a rejection")
+	}
+
+	expenditure := copyExpenditure(rejected)
+	if submission.BankTransactionID != "" {
+		expenditure.InvoiceDetails.BankTransactionID = submission.BankTransactionID
+	}
+	if submission.ChequeNumber != "" {
+		expenditure.InvoiceDetails.ChequeNumber = submission.ChequeNumber
+	}
+	if len(submission.Documents) > 0 {
+		expenditure.InvoiceDetails.Documents = submission.Documents
+	}
+	attachments, err := p.documentAttachments(ngoID, submission.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	for _, attachment := range attachments {
+		if !expenditure.HasAttachment(attachment.Hash) {
+			expenditure.AddAttachment(attachment.Filename, attachment.Hash, attachment.Type, p.ComplianceRules)
+		}
+	}
+
+	// Funds and invoices may have been taken since the rejection
+	if err := p.reserveExpenditure(p.NGOs[ngoID], expenditure, expenditure.Funding); err != nil {
+		return nil, err
+	}
+
+	// The rejecting panel is still assigned, so it is passed over
+	threshold := p.ConsensusPolicy.For(expenditure.Amount)
+	records, err := p.assignAuditors(expenditure, threshold.Panel, threshold.Required, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	panel := make([]string, 0, len(records))
+	for _, record := range records {
+		panel = append(panel, record.AuditorID)
+	}
+	expenditure.FileAppeal(reason, panel, threshold.Required, p.ComplianceRules)
+
+	err = p.persist(func(tx *database.Repositories) error {
+		model, err := expenditureToModel(expenditure, "", "")
+		if err != nil {
+			return err
+		}
+		if err := tx.Expenditures.Save(model); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := tx.Assignments.Create(assignmentToModel(record)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist appeal: %w", err)
+	}
+
+	delete(p.RejectedExpenditures, expenditure.TransactionID)
+	p.PendingExpenditures[expenditure.TransactionID] = expenditure
+	p.Assignments = append(p.Assignments, records...)
+
+	view := p.pendingExpenditureView(expenditure)
+	view["assignments"] = records
+	return view, nil
+}
+
+// GetNGORejectedExpenditures returns an NGO's rejected expenditures, oldest
+// first, including those whose rejection was upheld on appeal
+func (p *NGOTransparencyPlatform) GetNGORejectedExpenditures(ngoID string) []map[string]interface{} {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var rejected []*transactions.ExpenditureTransaction
+	for _, expenditure := range p.RejectedExpenditures {
+		if expenditure.NGOID == ngoID {
+			rejected = append(rejected, expenditure)
+		}
+	}
+	sort.Slice(rejected, func(i, j int) bool {
+		return rejected[i].Timestamp.Before(rejected[j].Timestamp)
+	})
+
+	views := make([]map[string]interface{}, 0, len(rejected))
+	for _, expenditure := range rejected {
+		view := expenditure.GetTransactionSummary()
+		view["invoice"] = expenditure.GetInvoiceInfo()
+		view["appealable"] = expenditure.Appeal == nil
+		views = append(views, view)
+	}
+	return views
+}
@@ -0,0 +1,165 @@
+package platform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// rejectedExpenditure submits an expenditure for NGO001 and has its
+// auditor reject it, returning its ID and the rejecting auditor
+func rejectedExpenditure(t *testing.T, p *NGOTransparencyPlatform) (string, string) {
+	t.Helper()
+
+	submitted, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books"}))
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+	id := submitted["transaction_id"].(string)
+	auditorID := submitted["assigned_auditor_id"].(string)
+	if _, err := p.ReviewExpenditure(auditorID, id, transactions.DecisionReject, "No payment proof", nil); err != nil {
+		t.Fatalf("Failed to reject expenditure: %v", err)
+	}
+	return id, auditorID
+}
+
+func newAppealPlatform(t *testing.T) (*NGOTransparencyPlatform, *database.Repositories) {
+	t.Helper()
+
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+	registerAuditor(t, p, repos, "AUD002", []string{"financial"})
+	deposit(t, p, money.INR(100000))
+	return p, repos
+}
+
+func TestExpenditureAppealGranted(t *testing.T) {
+	p, repos := newAppealPlatform(t)
+	id, originalAuditor := rejectedExpenditure(t, p)
+
+	rejected := p.GetNGORejectedExpenditures("NGO001")
+	if len(rejected) != 1 || rejected[0]["transaction_id"] != id || rejected[0]["appealable"] != true {
+		t.Fatalf("Expected the rejection to be appealable, got %+v", rejected)
+	}
+	if _, err := p.AppealExpenditure("NGO001", id, AppealSubmission{}); err == nil {
+		t.Errorf("Expected an appeal without a reason to be refused")
+	}
+	if _, err := p.AppealExpenditure("NGO002", id, AppealSubmission{Reason: "Paid"}); err == nil {
+		t.Errorf("Expected another NGO's appeal to be refused")
+	}
+
+	statement := uploadedPDF(t, p, "NGO001", "ngo", "statement.pdf")
+	appealed, err := p.AppealExpenditure("NGO001", id, AppealSubmission{
+		Reason:            "Payment was made by bank transfer",
+		BankTransactionID: "UTR123456",
+		Attachments:       []string{statement.Hash},
+	})
+	if err != nil {
+		t.Fatalf("Failed to appeal expenditure: %v", err)
+	}
+	reviewer := appealed["assigned_auditor_id"].(string)
+	if appealed["status"] != "under_appeal" || appealed["transaction_id"] != id || reviewer == originalAuditor {
+		t.Fatalf("Expected a different auditor to re-review the expenditure, got %+v", appealed)
+	}
+	if len(p.GetNGORejectedExpenditures("NGO001")) != 0 {
+		t.Errorf("Expected the appealed expenditure to leave the rejected list")
+	}
+	if _, err := p.ReviewExpenditure(originalAuditor, id, transactions.DecisionApprove, "", nil); err == nil {
+		t.Errorf("Expected the rejecting auditor to be kept off the appeal")
+	}
+
+	// The appeal under review survives a restart
+	reloaded := newReloadedPlatform(t, repos)
+	pending := reloaded.PendingExpenditures[id]
+	if pending == nil || !pending.IsUnderAppeal() || pending.Appeal == nil || len(pending.Attachments) != 1 {
+		t.Fatalf("Expected the appeal to be reloaded, got %+v", pending)
+	}
+
+	result, err := reloaded.ReviewExpenditure(reviewer, id, transactions.DecisionApprove, "Payment confirmed", nil)
+	if err != nil {
+		t.Fatalf("Failed to review appeal: %v", err)
+	}
+	if result["status"] != "validated" {
+		t.Fatalf("Expected the appeal to approve the expenditure, got %+v", result)
+	}
+
+	block := reloaded.NGOs["NGO001"].ExpenditureBlockchain.GetLatestBlock()
+	var recorded struct {
+		Type          string                         `json:"type"`
+		TransactionID string                         `json:"transaction_id"`
+		Appeal        transactions.ExpenditureAppeal `json:"appeal"`
+	}
+	decodeBlockData(t, block, &recorded)
+	if recorded.Type != "expenditure" || recorded.TransactionID != id || recorded.Appeal.Outcome != transactions.AppealGranted {
+		t.Fatalf("Expected the granted appeal in the chain, got %+v", recorded)
+	}
+	if recorded.Appeal.OriginalDecision == nil || recorded.Appeal.OriginalDecision.AuditorID != originalAuditor || recorded.Appeal.OriginalDecision.Signature == "" {
+		t.Errorf("Expected the block to reference the original rejection, got %+v", recorded.Appeal)
+	}
+	if !reloaded.NGOs["NGO001"].TotalExpenditureReported.Equal(money.FromMajor(300)) {
+		t.Errorf("Expected the expenditure to be reported, got %s", reloaded.NGOs["NGO001"].TotalExpenditureReported)
+	}
+}
+
+func TestExpenditureAppealDenied(t *testing.T) {
+	p, repos := newAppealPlatform(t)
+	id, _ := rejectedExpenditure(t, p)
+
+	appealed, err := p.AppealExpenditure("NGO001", id, AppealSubmission{Reason: "Invoice attached", Documents: []string{"invoice.pdf", "receipt.pdf"}})
+	if err != nil {
+		t.Fatalf("Failed to appeal expenditure: %v", err)
+	}
+	if _, err := p.AppealExpenditure("NGO001", id, AppealSubmission{Reason: "Again"}); err == nil {
+		t.Errorf("Expected an expenditure under appeal not to be appealed again")
+	}
+
+	chainLength := p.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength()
+	result, err := p.ReviewExpenditure(appealed["assigned_auditor_id"].(string), id, transactions.DecisionReject, "Receipt does not match", nil)
+	if err != nil {
+		t.Fatalf("Failed to review appeal: %v", err)
+	}
+	if result["status"] != "rejected" || result["block_hash"] == nil {
+		t.Fatalf("Expected the denied appeal to be recorded, got %+v", result)
+	}
+
+	chain := p.NGOs["NGO001"].ExpenditureBlockchain
+	if chain.GetChainLength() != chainLength+1 || !chain.IsChainValid() {
+		t.Fatalf("Expected one more block in a valid chain")
+	}
+	var recorded struct {
+		Type   string                         `json:"type"`
+		Amount *float64                       `json:"amount"`
+		Appeal transactions.ExpenditureAppeal `json:"appeal"`
+	}
+	decodeBlockData(t, chain.GetLatestBlock(), &recorded)
+	if recorded.Type != "expenditure_appeal" || recorded.Amount != nil || recorded.Appeal.Outcome != transactions.AppealDenied || recorded.Appeal.OriginalDecision == nil {
+		t.Errorf("Expected the denied appeal block to reference the rejection without spending, got %+v", recorded)
+	}
+	if !p.NGOs["NGO001"].TotalExpenditureReported.IsZero() {
+		t.Errorf("Expected nothing reported as spent, got %s", p.NGOs["NGO001"].TotalExpenditureReported)
+	}
+
+	rejected := newReloadedPlatform(t, repos).GetNGORejectedExpenditures("NGO001")
+	if len(rejected) != 1 || rejected[0]["appealable"] != false {
+		t.Fatalf("Expected the rejection to be final, got %+v", rejected)
+	}
+	if _, err := p.AppealExpenditure("NGO001", id, AppealSubmission{Reason: "Again"}); err == nil {
+		t.Errorf("Expected a second appeal to be refused")
+	}
+}
+
+func decodeBlockData(t *testing.T, block *blockchain.Block, target interface{}) {
+	t.Helper()
+
+	encoded, err := json.Marshal(block.Data)
+	if err != nil {
+		t.Fatalf("Failed to encode block data: %v", err)
+	}
+	if err := json.Unmarshal(encoded, target); err != nil {
+		t.Fatalf("Failed to decode block data: %v", err)
+	}
+}
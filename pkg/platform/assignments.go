@@ -0,0 +1,206 @@
+package platform
+
+import (
+	"fmt"
+	"time"
+
+	"ngo-transparency-platform/pkg/assignment"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// AuditorAssignment records which auditor was assigned an expenditure and why
+type AuditorAssignment struct {
+	ExpenditureID string    `json:"expenditure_id"`
+	NGOID         string    `json:"ngo_id"`
+	AuditorID     string    `json:"auditor_id"`
+	Category      string    `json:"category"`
+	Specialist    bool      `json:"specialist"`
+	Reason        string    `json:"reason"`
+	AssignedAt    time.Time `json:"assigned_at"`
+}
+
+// DeclareConflict records an auditor's conflict of interest with an NGO. The
+// auditor is never assigned the NGO's expenditures again, and those already
+// awaiting the auditor's review are reassigned.
+func (p *NGOTransparencyPlatform) DeclareConflict(auditorID, ngoID, reason string) (*entities.ConflictOfInterest, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	auditor, exists := p.Auditors[auditorID]
+	if !exists {
+		return nil, ErrAuditorNotFound
+	}
+	if _, exists := p.NGOs[ngoID]; !exists {
+		return nil, ErrNGONotFound
+	}
+
+	conflict, err := auditor.DeclareConflict(ngoID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Give this auditor's place on the panels of the NGO's pending expenditures to someone else
+	now := time.Now()
+	var reassigned []*transactions.ExpenditureTransaction
+	var assignments []*AuditorAssignment
+	for _, pending := range p.PendingExpenditures {
+		if pending.NGOID != ngoID || !pending.IsAssignedTo(auditorID) {
+			continue
+		}
+		records, err := p.assignAuditors(pending, 1, 1, now)
+		if err != nil {
+			p.reloadAuditor(auditorID)
+			return nil, fmt.Errorf("cannot reassign expenditure %s: %w", pending.TransactionID, err)
+		}
+		expenditure := copyExpenditure(pending)
+		replaceReviewer(expenditure, auditorID, records[0].AuditorID)
+		if expenditure.Consensus != nil {
+			outcome := p.tally(expenditure, nil)
+			expenditure.Consensus = &outcome
+		}
+		reassigned = append(reassigned, expenditure)
+		assignments = append(assignments, records[0])
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		if err := tx.Assignments.Create(&database.AuditorConflictModel{
+			AuditorID: auditorID,
+			NGOID:     ngoID,
+			Reason:    conflict.Reason,
+			CreatedAt: conflict.DeclaredAt,
+		}); err != nil {
+			return err
+		}
+		for i, expenditure := range reassigned {
+			model, err := expenditureToModel(expenditure, "", "")
+			if err != nil {
+				return err
+			}
+			if err := tx.Expenditures.Save(model); err != nil {
+				return err
+			}
+			if err := tx.Assignments.Create(assignmentToModel(assignments[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		p.reloadAuditor(auditorID)
+		return nil, fmt.Errorf("failed to persist conflict of interest: %w", err)
+	}
+
+	for i, expenditure := range reassigned {
+		p.PendingExpenditures[expenditure.TransactionID] = expenditure
+		p.Assignments = append(p.Assignments, assignments[i])
+	}
+	return conflict, nil
+}
+
+// GetAssignments returns the expenditures an auditor has been assigned, newest first
+func (p *NGOTransparencyPlatform) GetAssignments(auditorID string) []*AuditorAssignment {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	assignments := make([]*AuditorAssignment, 0)
+	for i := len(p.Assignments) - 1; i >= 0; i-- {
+		if p.Assignments[i].AuditorID == auditorID {
+			assignments = append(assignments, p.Assignments[i])
+		}
+	}
+	return assignments
+}
+
+// assignAuditors chooses up to panel auditors to review an expenditure under
+// the platform's AssignmentPolicy, passing over those already on its panel.
+// It fails unless at least required auditors are eligible. The returned
+// records are not yet stored.
+func (p *NGOTransparencyPlatform) assignAuditors(expenditure *transactions.ExpenditureTransaction, panel, required int, now time.Time) ([]*AuditorAssignment, error) {
+	yearAgo := now.AddDate(-1, 0, 0)
+
+	candidates := make([]assignment.Candidate, 0, len(p.Auditors))
+	for auditorID, auditor := range p.Auditors {
+		if !auditor.Verified || expenditure.IsAssignedTo(auditorID) {
+			continue
+		}
+		candidate := assignment.Candidate{
+			AuditorID:       auditorID,
+			Specializations: auditor.Specializations,
+			Conflicted:      auditor.HasConflict(expenditure.NGOID),
+		}
+		for _, pending := range p.PendingExpenditures {
+			if pending.IsAssignedTo(auditorID) && !pending.HasVoted(auditorID) && pending.TransactionID != expenditure.TransactionID {
+				candidate.OpenReviews++
+			}
+		}
+		for _, record := range p.Assignments {
+			if record.AuditorID == auditorID && record.NGOID == expenditure.NGOID && record.AssignedAt.After(yearAgo) {
+				candidate.ReviewsOfNGO++
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	choices := assignment.Rank(p.AssignmentPolicy, expenditure.Category, candidates)
+	if len(choices) == 0 {
+		return nil, fmt.Errorf("no eligible auditor available for this expenditure")
+	}
+	if len(choices) < required {
+		return nil, fmt.Errorf("only %d eligible auditors available, %d required for this expenditure", len(choices), required)
+	}
+	if len(choices) > panel {
+		choices = choices[:panel]
+	}
+
+	records := make([]*AuditorAssignment, 0, len(choices))
+	for _, choice := range choices {
+		records = append(records, &AuditorAssignment{
+			ExpenditureID: expenditure.TransactionID,
+			NGOID:         expenditure.NGOID,
+			AuditorID:     choice.AuditorID,
+			Category:      expenditure.Category,
+			Specialist:    choice.Specialist,
+			Reason:        choice.Reason(),
+			AssignedAt:    now,
+		})
+	}
+	return records, nil
+}
+
+// replaceReviewer gives an auditor's place on an expenditure's panel to another
+func replaceReviewer(expenditure *transactions.ExpenditureTransaction, auditorID, replacementID string) {
+	for i, reviewer := range expenditure.Panel {
+		if reviewer == auditorID {
+			expenditure.Panel[i] = replacementID
+		}
+	}
+	if expenditure.AssignedAuditorID == auditorID {
+		expenditure.AssignedAuditorID = replacementID
+	}
+}
+
+func assignmentToModel(record *AuditorAssignment) *database.AuditorAssignmentModel {
+	return &database.AuditorAssignmentModel{
+		ExpenditureID: record.ExpenditureID,
+		NGOID:         record.NGOID,
+		AuditorID:     record.AuditorID,
+		Category:      record.Category,
+		Specialist:    record.Specialist,
+		Reason:        record.Reason,
+		CreatedAt:     record.AssignedAt,
+	}
+}
+
+func assignmentFromModel(model *database.AuditorAssignmentModel) *AuditorAssignment {
+	return &AuditorAssignment{
+		ExpenditureID: model.ExpenditureID,
+		NGOID:         model.NGOID,
+		AuditorID:     model.AuditorID,
+		Category:      model.Category,
+		Specialist:    model.Specialist,
+		Reason:        model.Reason,
+		AssignedAt:    model.CreatedAt,
+	}
+}
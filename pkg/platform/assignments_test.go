@@ -0,0 +1,75 @@
+package platform
+
+import (
+	"testing"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+func TestExpenditureAssignment(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos) // AUD001, a financial generalist
+	registerAuditor(t, p, repos, "AUD002", []string{"education"})
+	registerAuditor(t, p, repos, "AUD003", []string{"Education", "financial"})
+	p.AssignmentPolicy.MaxReviewsPerNGO = 1
+
+	deposit(t, p, money.INR(500000))
+
+	submit := func() map[string]interface{} {
+		t.Helper()
+		submitted, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 100.0, "category": "education", "description": "Books"}))
+		if err != nil {
+			t.Fatalf("Failed to submit expenditure: %v", err)
+		}
+		return submitted
+	}
+
+	// Specialists first, then the generalist once both have rotated off the NGO
+	var assigned, expenditureIDs []string
+	for i := 0; i < 3; i++ {
+		submitted := submit()
+		assigned = append(assigned, submitted["assigned_auditor_id"].(string))
+		expenditureIDs = append(expenditureIDs, submitted["transaction_id"].(string))
+	}
+	if assigned[0] != "AUD002" || assigned[1] != "AUD003" || assigned[2] != "AUD001" {
+		t.Errorf("Expected AUD002, AUD003 then AUD001, got %v", assigned)
+	}
+	if _, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 100.0, "category": "education", "description": "Books"})); err == nil {
+		t.Error("Expected no auditor to be eligible once all have rotated off the NGO")
+	}
+
+	// A conflict cannot be declared while its review has nowhere to go
+	if _, err := p.DeclareConflict("AUD003", "NGO001", "Spouse is a trustee"); err == nil {
+		t.Error("Expected the conflict to be refused while its review cannot be reassigned")
+	}
+	if p.Auditors["AUD003"].HasConflict("NGO001") {
+		t.Error("Expected the refused conflict to be dropped")
+	}
+
+	p.AssignmentPolicy.MaxReviewsPerNGO = 12
+	if _, err := p.DeclareConflict("AUD003", "NGO001", "Spouse is a trustee"); err != nil {
+		t.Fatalf("Failed to declare conflict: %v", err)
+	}
+	if queue := p.GetPendingExpenditures("AUD003"); len(queue) != 0 {
+		t.Errorf("Expected AUD003's review to be reassigned, got %d", len(queue))
+	}
+	if queue := p.GetPendingExpenditures("AUD002"); len(queue) != 2 {
+		t.Errorf("Expected the review to move to the other specialist, got %d", len(queue))
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	if !reloaded.Auditors["AUD003"].HasConflict("NGO001") {
+		t.Error("Expected the conflict to survive a reload")
+	}
+	if len(reloaded.Assignments) != 4 {
+		t.Errorf("Expected four recorded assignments, got %d", len(reloaded.Assignments))
+	}
+	history := reloaded.GetAssignments("AUD002")
+	if len(history) != 2 || history[1].ExpenditureID != expenditureIDs[0] || history[0].ExpenditureID != expenditureIDs[1] || !history[0].Specialist || history[0].Reason == "" {
+		t.Errorf("Expected AUD002's two assignments newest first, got %+v", history)
+	}
+	submitted, err := reloaded.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 100.0, "category": "education", "description": "Books"}))
+	if err != nil || submitted["assigned_auditor_id"] == "AUD003" {
+		t.Errorf("Expected the conflicted auditor to be passed over, got %v (%v)", submitted["assigned_auditor_id"], err)
+	}
+}
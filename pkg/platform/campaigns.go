@@ -0,0 +1,147 @@
+package platform
+
+import (
+	"fmt"
+	"time"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/money"
+)
+
+// CreateCampaign starts a campaign that donations to the NGO can be earmarked to
+func (p *NGOTransparencyPlatform) CreateCampaign(ngoID, name, description string, goalAmount money.Money, startDate time.Time, endDate *time.Time) (*entities.Campaign, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+	if !ngo.KYCData.Verified {
+		return nil, fmt.Errorf("NGO KYC not verified")
+	}
+
+	campaign, err := entities.NewCampaign(ngoID, name, description, goalAmount, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		return tx.Campaigns.Create(campaignToModel(campaign))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist campaign: %w", err)
+	}
+
+	if err := ngo.AddCampaign(campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// CloseCampaign stops a campaign from accepting donations. Its restricted
+// balance can still be spent.
+func (p *NGOTransparencyPlatform) CloseCampaign(ngoID, campaignID string) (*entities.Campaign, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+	campaign, exists := ngo.GetCampaign(campaignID)
+	if !exists {
+		return nil, ErrCampaignNotFound
+	}
+	if campaign.Status == entities.CampaignClosed {
+		return nil, fmt.Errorf("campaign already closed")
+	}
+
+	closed := *campaign
+	closed.Close()
+
+	err := p.persist(func(tx *database.Repositories) error {
+		return tx.Campaigns.Save(campaignToModel(&closed))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist campaign: %w", err)
+	}
+
+	*campaign = closed
+	return campaign, nil
+}
+
+// GetCampaigns returns the utilization of each of an NGO's campaigns
+func (p *NGOTransparencyPlatform) GetCampaigns(ngoID string) ([]entities.CampaignUtilization, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+	return ngo.CampaignUtilizations(), nil
+}
+
+// GetCampaignReport returns a campaign's utilization and the expenditures
+// its restricted funds were spent on
+func (p *NGOTransparencyPlatform) GetCampaignReport(campaignID string) (*entities.CampaignReport, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, ngo := range p.NGOs {
+		if _, exists := ngo.GetCampaign(campaignID); exists {
+			return ngo.CampaignReport(campaignID)
+		}
+	}
+	return nil, ErrCampaignNotFound
+}
+
+// donorCampaigns returns the utilization of each campaign a donor has given
+// to, with how much they donated to it
+func (p *NGOTransparencyPlatform) donorCampaigns(donor *entities.Donor) []map[string]interface{} {
+	donated := make(map[string]money.Money)
+	order := make([]string, 0)
+	for _, record := range donor.DonationHistory {
+		if record.CampaignID == "" {
+			continue
+		}
+		if _, seen := donated[record.CampaignID]; !seen {
+			order = append(order, record.CampaignID)
+		}
+		donated[record.CampaignID] = donated[record.CampaignID].Add(record.Amount)
+	}
+
+	campaigns := make([]map[string]interface{}, 0, len(order))
+	for _, campaignID := range order {
+		for _, ngo := range p.NGOs {
+			if campaign, exists := ngo.GetCampaign(campaignID); exists {
+				campaigns = append(campaigns, map[string]interface{}{
+					"ngo_id":      ngo.NGOID,
+					"ngo_name":    ngo.Name,
+					"donated":     donated[campaignID],
+					"utilization": campaign.Utilization(),
+				})
+				break
+			}
+		}
+	}
+	return campaigns
+}
+
+// checkCampaignOpen verifies that the NGO's campaign, if one is given, is
+// accepting donations
+func checkCampaignOpen(ngo *entities.NGO, campaignID string) error {
+	if campaignID == "" {
+		return nil
+	}
+	campaign, exists := ngo.GetCampaign(campaignID)
+	if !exists {
+		return ErrCampaignNotFound
+	}
+	if !campaign.AcceptsDonations(time.Now()) {
+		return fmt.Errorf("campaign is not accepting donations")
+	}
+	return nil
+}
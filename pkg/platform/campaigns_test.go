@@ -0,0 +1,105 @@
+package platform
+
+import (
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+)
+
+func TestCampaignRestrictedFunds(t *testing.T) {
+	p, repos, gateway := newPaymentsPlatform(t)
+	verifiedAuditor(t, p, repos)
+
+	campaign, err := p.CreateCampaign("NGO001", "School Library", "Books for rural schools", money.INR(500000), time.Time{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+	if _, err := p.CreatePaymentIntent("DONOR001", "NGO001", "CMP_missing", money.INR(10000), payments.MethodCard, ""); err == nil {
+		t.Error("Expected a donation to an unknown campaign to be rejected")
+	}
+
+	intent := settledIntent(t, p, gateway, campaign.CampaignID, money.INR(100000))
+	if _, err := p.ProcessDonation("DONOR001", "NGO001", money.INR(100000), "upi"); err != nil {
+		t.Fatalf("Failed to process unrestricted donation: %v", err)
+	}
+	if !campaign.RaisedAmount.Equal(money.INR(99000)) {
+		t.Fatalf("Expected ₹990 raised, got ₹%s", campaign.RaisedAmount)
+	}
+	if history := p.Donors["DONOR001"].DonationHistory; history[0].CampaignID != campaign.CampaignID || history[1].CampaignID != "" {
+		t.Errorf("Expected only the first donation to be earmarked, got %+v", history)
+	}
+
+	overspend := invoiced(map[string]interface{}{"amount": 1000.0, "category": "education", "description": "Books", "campaign_id": campaign.CampaignID})
+	if _, err := recordExpenditure(t, p, "NGO001", overspend); err == nil {
+		t.Error("Expected an expenditure beyond the restricted balance to be rejected")
+	}
+	spend := invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books", "campaign_id": campaign.CampaignID})
+	if _, err := recordExpenditure(t, p, "NGO001", spend); err != nil {
+		t.Fatalf("Failed to process campaign expenditure: %v", err)
+	}
+	if !campaign.SpentAmount.Equal(money.INR(30000)) || !campaign.RestrictedBalance().Equal(money.INR(69000)) {
+		t.Errorf("Expected ₹300 spent and ₹690 restricted, got %+v", campaign.Utilization())
+	}
+
+	if _, err := p.RefundDonation("NGO001", intent.TransactionID, money.INR(80000), "duplicate payment"); err == nil {
+		t.Error("Expected a refund of already spent restricted funds to be rejected")
+	}
+	if _, err := p.RefundDonation("NGO001", intent.TransactionID, money.INR(20000), "duplicate payment"); err != nil {
+		t.Fatalf("Failed to refund donation: %v", err)
+	}
+	if !campaign.RaisedAmount.Equal(money.INR(79200)) {
+		t.Errorf("Expected ₹792 raised after the refund, got ₹%s", campaign.RaisedAmount)
+	}
+
+	report, err := p.GetCampaignReport(campaign.CampaignID)
+	if err != nil {
+		t.Fatalf("Failed to get campaign report: %v", err)
+	}
+	if len(report.Expenditures) != 1 || report.Expenditures[0]["description"] != "Books" {
+		t.Errorf("Expected the report to list the campaign's expenditure, got %+v", report.Expenditures)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	restored, exists := reloaded.NGOs["NGO001"].GetCampaign(campaign.CampaignID)
+	if !exists {
+		t.Fatal("Expected the campaign to survive a reload")
+	}
+	if !restored.RaisedAmount.Equal(campaign.RaisedAmount) || !restored.SpentAmount.Equal(campaign.SpentAmount) {
+		t.Errorf("Expected ₹%s raised and ₹%s spent after reload, got %+v", campaign.RaisedAmount, campaign.SpentAmount, restored.Utilization())
+	}
+	if reloaded.Donors["DONOR001"].DonationHistory[0].CampaignID != campaign.CampaignID {
+		t.Error("Expected the donation to stay earmarked after reload")
+	}
+}
+
+func TestClosedCampaignRejectsDonations(t *testing.T) {
+	p, repos := newTestPlatform(t)
+
+	if _, err := p.CreateCampaign("NGO001", "Flood Relief", "", money.Zero(), time.Time{}, nil); err == nil {
+		t.Error("Expected a campaign without a goal to be rejected")
+	}
+	campaign, err := p.CreateCampaign("NGO001", "Flood Relief", "", money.INR(1000000), time.Time{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+	if _, err := p.CloseCampaign("NGO002", campaign.CampaignID); err == nil {
+		t.Error("Expected another NGO's close to be rejected")
+	}
+	if _, err := p.CloseCampaign("NGO001", campaign.CampaignID); err != nil {
+		t.Fatalf("Failed to close campaign: %v", err)
+	}
+	if _, err := p.CreatePaymentIntent("DONOR001", "NGO001", campaign.CampaignID, money.INR(10000), payments.MethodCard, ""); err == nil {
+		t.Error("Expected a closed campaign to reject donations")
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	campaigns, err := reloaded.GetCampaigns("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to list campaigns: %v", err)
+	}
+	if len(campaigns) != 1 || campaigns[0].Status != "closed" {
+		t.Errorf("Expected the closed campaign after reload, got %+v", campaigns)
+	}
+}
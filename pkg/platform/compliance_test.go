@@ -0,0 +1,55 @@
+package platform
+
+import (
+	"testing"
+
+	"ngo-transparency-platform/pkg/compliance"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+func TestComplianceRulesVersionIsRecorded(t *testing.T) {
+	rules, err := compliance.Parse([]byte(`
+version: "strict-2025"
+checks:
+  - {id: invoice_number, weight: 40}
+  - {id: payment_proof, weight: 60}
+recommendations: [{min_score: 0, recommendation: "Review"}]
+audit_recommendations: [{min_score: 0, recommendation: "Approve after review"}]
+`), "yaml")
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+	p, repos := newTestPlatform(t)
+	p.ComplianceRules = rules
+	verifiedAuditor(t, p, repos)
+	deposit(t, p, money.INR(100000))
+
+	submitted, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books", "bank_transaction_id": "UTR9001"}))
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+	breakdown := submitted["compliance_breakdown"].(map[string]interface{})
+	if submitted["compliance_score"] != 100.0 || breakdown["rules_version"] != "strict-2025" || len(breakdown["components"].(map[string]interface{})) != 2 {
+		t.Fatalf("Expected the expenditure scored under the configured rules, got %+v", submitted)
+	}
+
+	result, err := p.ReviewExpenditure("AUD001", submitted["transaction_id"].(string), transactions.DecisionApprove, "", nil)
+	if err != nil {
+		t.Fatalf("Failed to approve expenditure: %v", err)
+	}
+	block := p.NGOs["NGO001"].ExpenditureBlockchain.GetLatestBlock()
+	if block.Hash != result["block_hash"] || block.Data.(map[string]interface{})["compliance_rules_version"] != "strict-2025" {
+		t.Errorf("Expected the rules version in the block, got %+v", block.Data)
+	}
+
+	// A platform under other rules loads stored scores with the version they were calculated under
+	reloaded := newReloadedPlatform(t, repos)
+	audits := reloaded.Auditors["AUD001"].AuditHistory
+	if len(audits) != 1 || audits[0].RulesVersion != "strict-2025" || audits[0].Recommendation != "Approve after review" {
+		t.Errorf("Expected the audit to keep its rules version, got %+v", audits)
+	}
+	if model, err := repos.Expenditures.GetByTransactionID(submitted["transaction_id"].(string)); err != nil || model.ComplianceRulesVersion != "strict-2025" {
+		t.Errorf("Expected the stored expenditure to keep its rules version, got %+v (%v)", model, err)
+	}
+}
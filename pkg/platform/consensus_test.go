@@ -0,0 +1,121 @@
+package platform
+
+import (
+	"testing"
+
+	"ngo-transparency-platform/pkg/consensus"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// newPanelPlatform has three financial auditors and requires 2 of 3 of them
+// to approve expenditures from ₹1,000
+func newPanelPlatform(t *testing.T) (*NGOTransparencyPlatform, map[string]interface{}) {
+	t.Helper()
+
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+	registerAuditor(t, p, repos, "AUD002", []string{"financial"})
+	registerAuditor(t, p, repos, "AUD003", []string{"financial"})
+	p.ConsensusPolicy = consensus.Policy{
+		Thresholds:     []consensus.Threshold{{MinAmount: money.FromMajor(1000), Required: 2, Panel: 3}},
+		MaxScoreSpread: 20,
+	}
+
+	deposit(t, p, money.INR(500000))
+	submitted, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 1500.0, "category": "education", "description": "Laptops"}))
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+	return p, submitted
+}
+
+func TestPanelApprovesHighValueExpenditure(t *testing.T) {
+	p, submitted := newPanelPlatform(t)
+	id := submitted["transaction_id"].(string)
+
+	if panel, _ := submitted["panel"].([]string); len(panel) != 3 || submitted["required_approvals"] != 2 {
+		t.Fatalf("Expected a panel of three needing two approvals, got %+v", submitted)
+	}
+	for _, auditorID := range []string{"AUD001", "AUD002", "AUD003"} {
+		if queue := p.GetPendingExpenditures(auditorID); len(queue) != 1 {
+			t.Errorf("Expected the expenditure in %s's queue, got %d", auditorID, len(queue))
+		}
+	}
+
+	score := func(s float64) *float64 { return &s }
+	if _, err := p.ReviewExpenditure("AUD001", id, transactions.DecisionApprove, "", score(90)); err != nil {
+		t.Fatalf("Failed to record first approval: %v", err)
+	}
+	if p.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength() != 1 {
+		t.Error("Expected one approval not to decide the expenditure")
+	}
+	if _, err := p.ReviewExpenditure("AUD001", id, transactions.DecisionApprove, "", nil); err == nil {
+		t.Error("Expected a second vote by the same auditor to be refused")
+	}
+	if queue := p.GetPendingExpenditures("AUD001"); len(queue) != 0 {
+		t.Errorf("Expected the expenditure to leave the voter's queue, got %d", len(queue))
+	}
+
+	if _, err := p.ReviewExpenditure("AUD002", id, transactions.DecisionReject, "Quotes were not compared", score(40)); err != nil {
+		t.Fatalf("Failed to record rejection: %v", err)
+	}
+	if escalated := p.GetEscalatedExpenditures(); len(escalated) != 1 || escalated[0]["transaction_id"] != id {
+		t.Errorf("Expected the split panel to be escalated, got %+v", escalated)
+	}
+
+	// Votes survive a restart
+	reloaded := newReloadedPlatform(t, p.repos)
+	if pending := reloaded.PendingExpenditures[id]; pending == nil || len(pending.Votes()) != 2 || pending.Consensus == nil || !pending.Consensus.Escalated {
+		t.Fatalf("Expected both votes and the escalation after reload, got %+v", pending)
+	}
+
+	result, err := reloaded.ReviewExpenditure("AUD003", id, transactions.DecisionApprove, "", score(80))
+	if err != nil {
+		t.Fatalf("Failed to record deciding approval: %v", err)
+	}
+	if result["status"] != "validated" {
+		t.Fatalf("Expected two approvals to validate the expenditure, got %+v", result)
+	}
+
+	block := reloaded.NGOs["NGO001"].ExpenditureBlockchain.GetLatestBlock()
+	approvals, dissents := block.GetValidatorsByType("auditor"), block.GetValidatorsByType("auditor_dissent")
+	if block.Hash != result["block_hash"] || len(approvals) != 2 || len(dissents) != 1 || dissents[0].ValidatorID != "AUD002" {
+		t.Errorf("Expected every panel member's signed decision in the block, got %+v", block.Validators)
+	}
+	for _, validator := range append(approvals, dissents...) {
+		if validator.Signature == "" {
+			t.Errorf("Expected %s's decision to be signed", validator.ValidatorID)
+		}
+	}
+	if tally, _ := block.Data.(map[string]interface{})["consensus"].(map[string]interface{}); tally["approvals"] != 2.0 || tally["escalated"] != true {
+		t.Errorf("Expected the consensus tally in the block, got %+v", tally)
+	}
+	if score := block.Data.(map[string]interface{})["compliance_score"]; score != 85.0 {
+		t.Errorf("Expected the approving scores to be averaged, got %v", score)
+	}
+}
+
+func TestPanelRejectsWhenApprovalOutOfReach(t *testing.T) {
+	p, submitted := newPanelPlatform(t)
+	id := submitted["transaction_id"].(string)
+
+	for _, auditorID := range []string{"AUD001", "AUD002"} {
+		if _, err := p.ReviewExpenditure(auditorID, id, transactions.DecisionReject, "No quotation on file", nil); err != nil {
+			t.Fatalf("Failed to reject: %v", err)
+		}
+	}
+	if _, exists := p.PendingExpenditures[id]; exists || p.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength() != 1 {
+		t.Error("Expected two rejections of three to reject the expenditure")
+	}
+	if _, err := p.ReviewExpenditure("AUD003", id, transactions.DecisionApprove, "", nil); err == nil {
+		t.Error("Expected no further votes on a decided expenditure")
+	}
+
+	// Without enough eligible auditors a panel cannot be formed
+	p.Auditors["AUD003"].Verified = false
+	p.Auditors["AUD002"].Verified = false
+	if _, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 1500.0, "category": "education", "description": "Laptops"})); err == nil {
+		t.Error("Expected submission to fail with fewer eligible auditors than required approvals")
+	}
+}
@@ -0,0 +1,260 @@
+package platform
+
+import (
+	"fmt"
+	"time"
+
+	"ngo-transparency-platform/pkg/csr"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// MatchPaymentMethod is the payment method of donations a company makes to
+// match an employee's donation; they are settled with the company directly
+const MatchPaymentMethod = "corporate_match"
+
+// RegisterCorporate makes a donor a company that can match its employees'
+// donations and report its CSR spend
+func (p *NGOTransparencyPlatform) RegisterCorporate(donorID, companyName, cin string, rule entities.MatchingRule) (*entities.CorporateProfile, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	donor, exists := p.Donors[donorID]
+	if !exists {
+		return nil, ErrDonorNotFound
+	}
+	if donor.IsCorporate() {
+		return nil, fmt.Errorf("donor is already registered as a corporate")
+	}
+	if donor.EmployerID != "" {
+		return nil, fmt.Errorf("an employee cannot be registered as a corporate")
+	}
+
+	profile, err := entities.NewCorporateProfile(companyName, cin, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.saveCorporateProfile(donorID, profile); err != nil {
+		return nil, err
+	}
+
+	donor.Corporate = profile
+	return profile, nil
+}
+
+// UpdateMatchingRule replaces a company's rule for matching employee
+// donations. Donations already matched are not affected.
+func (p *NGOTransparencyPlatform) UpdateMatchingRule(corporateID string, rule entities.MatchingRule) (*entities.CorporateProfile, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	corporate, err := p.findCorporate(corporateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	updated := *corporate.Corporate
+	updated.MatchingRule = rule
+
+	if err := p.saveCorporateProfile(corporateID, &updated); err != nil {
+		return nil, err
+	}
+
+	*corporate.Corporate = updated
+	return corporate.Corporate, nil
+}
+
+// AddEmployee enrols a donor as an employee of a company, so the company
+// matches the donor's future donations
+func (p *NGOTransparencyPlatform) AddEmployee(corporateID, employeeID string) (*entities.CorporateProfile, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	corporate, err := p.findCorporate(corporateID)
+	if err != nil {
+		return nil, err
+	}
+	employee, exists := p.Donors[employeeID]
+	if !exists {
+		return nil, fmt.Errorf("employee %w", ErrDonorNotFound)
+	}
+	if employee.IsCorporate() {
+		return nil, fmt.Errorf("a corporate cannot be an employee")
+	}
+	if employee.EmployerID != "" {
+		return nil, fmt.Errorf("donor is already an employee of a corporate")
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		return tx.Corporates.AddEmployee(&database.CorporateEmployeeModel{
+			CorporateID: corporateID,
+			DonorID:     employeeID,
+			CreatedAt:   time.Now(),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist employee: %w", err)
+	}
+
+	employee.EmployerID = corporateID
+	corporate.Corporate.Employees = append(corporate.Corporate.Employees, employeeID)
+	return corporate.Corporate, nil
+}
+
+// RemoveEmployee stops a company matching a donor's donations
+func (p *NGOTransparencyPlatform) RemoveEmployee(corporateID, employeeID string) (*entities.CorporateProfile, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	corporate, err := p.findCorporate(corporateID)
+	if err != nil {
+		return nil, err
+	}
+	if !corporate.Corporate.HasEmployee(employeeID) {
+		return nil, ErrEmployeeNotFound
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		return tx.Corporates.RemoveEmployee(corporateID, employeeID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist employee removal: %w", err)
+	}
+
+	employees := make([]string, 0, len(corporate.Corporate.Employees)-1)
+	for _, id := range corporate.Corporate.Employees {
+		if id != employeeID {
+			employees = append(employees, id)
+		}
+	}
+	corporate.Corporate.Employees = employees
+	if employee, exists := p.Donors[employeeID]; exists {
+		employee.EmployerID = ""
+	}
+	return corporate.Corporate, nil
+}
+
+// GetCorporateProfile returns a company's profile
+func (p *NGOTransparencyPlatform) GetCorporateProfile(corporateID string) (*entities.CorporateProfile, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	corporate, err := p.findCorporate(corporateID)
+	if err != nil {
+		return nil, err
+	}
+	return corporate.Corporate, nil
+}
+
+// GetCSRReport returns a company's CSR spend for the financial year starting
+// in April of fyStartYear, grouped by the Schedule VII activity each NGO's
+// category maps to
+func (p *NGOTransparencyPlatform) GetCSRReport(corporateID string, fyStartYear int) (*csr.Report, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	corporate, err := p.findCorporate(corporateID)
+	if err != nil {
+		return nil, err
+	}
+
+	contributions := make([]csr.Contribution, 0, len(corporate.DonationHistory))
+	for _, record := range corporate.DonationHistory {
+		contribution := csr.Contribution{
+			TransactionID: record.TransactionID,
+			NGOID:         record.NGOID,
+			Amount:        record.GrossAmount,
+			Timestamp:     record.Timestamp,
+			MatchFor:      record.MatchFor,
+		}
+		if ngo, exists := p.NGOs[record.NGOID]; exists {
+			contribution.NGOName = ngo.Name
+			contribution.NGOCategory = ngo.Category
+		}
+		contributions = append(contributions, contribution)
+	}
+
+	profile := corporate.Corporate
+	return csr.NewReport(corporateID, profile.CompanyName, profile.CIN, fyStartYear, contributions), nil
+}
+
+// matchDonation has the donor's employer match a donation the donor just
+// made, if its matching rule covers the NGO. It returns nil when no match is
+// due. Matches are keyed by the employee donation, so a donation is matched
+// at most once.
+func (p *NGOTransparencyPlatform) matchDonation(donor *entities.Donor, ngo *entities.NGO, donation *transactions.DonationTransaction, grossAmount money.Money) (map[string]interface{}, error) {
+	if donor.EmployerID == "" || donation.MatchFor != "" {
+		return nil, nil
+	}
+	corporate, exists := p.Donors[donor.EmployerID]
+	if !exists || !corporate.IsCorporate() {
+		return nil, nil
+	}
+
+	rule := corporate.Corporate.MatchingRule
+	if !rule.Active || !rule.Eligible(ngo.Category) {
+		return nil, nil
+	}
+
+	transactionID := transactions.IdempotentTransactionID("match", corporate.DonorID, donation.TransactionID)
+	if findDonationBlock(ngo.DonationBlockchain, transactionID) != nil {
+		return nil, nil
+	}
+
+	amount, err := rule.MatchAmount(grossAmount, corporate.MatchedFor(donor, donation.Timestamp.Year()))
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+
+	if _, _, err := p.checkDonation(corporate.DonorID, ngo.NGOID, amount); err != nil {
+		return nil, err
+	}
+
+	platformFee, netAmount, err := p.splitDonation(amount)
+	if err != nil {
+		return nil, err
+	}
+	match := newDonation(transactionID, corporate.DonorID, ngo.NGOID, netAmount, MatchPaymentMethod, corporate.KYCData.DocumentHash)
+	match.MatchFor = donation.TransactionID
+	// Matches follow the employee's earmark while the campaign is still open
+	if checkCampaignOpen(ngo, donation.CampaignID) == nil {
+		match.CampaignID = donation.CampaignID
+	}
+
+	return p.recordDonation(corporate, ngo, match, amount, platformFee, nil)
+}
+
+// saveCorporateProfile stores a company's profile
+func (p *NGOTransparencyPlatform) saveCorporateProfile(corporateID string, profile *entities.CorporateProfile) error {
+	err := p.persist(func(tx *database.Repositories) error {
+		model, err := corporateProfileToModel(corporateID, profile)
+		if err != nil {
+			return err
+		}
+		return tx.Corporates.SaveProfile(model)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist corporate profile: %w", err)
+	}
+	return nil
+}
+
+func (p *NGOTransparencyPlatform) findCorporate(corporateID string) (*entities.Donor, error) {
+	corporate, exists := p.Donors[corporateID]
+	if !exists {
+		return nil, ErrDonorNotFound
+	}
+	if !corporate.IsCorporate() {
+		return nil, ErrCorporateProfileNotFound
+	}
+	return corporate, nil
+}
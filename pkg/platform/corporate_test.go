@@ -0,0 +1,114 @@
+package platform
+
+import (
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/csr"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/money"
+)
+
+// newCorporatePlatform adds a verified corporate donor CORP001 that employs DONOR001
+func newCorporatePlatform(t *testing.T, rule entities.MatchingRule) (*NGOTransparencyPlatform, *database.Repositories) {
+	t.Helper()
+
+	p, repos := newTestPlatform(t)
+	registerDonor(t, p, repos, "CORP001", "premium")
+
+	if _, err := p.RegisterCorporate("CORP001", "Acme Industries Ltd", "L12345MH2000PLC123456", rule); err != nil {
+		t.Fatalf("Failed to register corporate: %v", err)
+	}
+	if _, err := p.AddEmployee("CORP001", "DONOR001"); err != nil {
+		t.Fatalf("Failed to add employee: %v", err)
+	}
+	return p, repos
+}
+
+func TestCorporateMatchesEmployeeDonations(t *testing.T) {
+	p, repos := newCorporatePlatform(t, entities.MatchingRule{
+		Ratio:              1,
+		PerEmployeeCap:     money.INR(150000),
+		EligibleCategories: []string{"Education"},
+		Active:             true,
+	})
+
+	first := deposit(t, p, money.INR(100000))
+	if _, ok := first["matched_donation"].(map[string]interface{}); !ok {
+		t.Fatalf("Expected the donation to be matched, got %+v", first)
+	}
+
+	second := deposit(t, p, money.INR(100000))
+	matched, ok := second["matched_donation"].(map[string]interface{})
+	if !ok || !matched["gross_amount"].(money.Money).Equal(money.INR(50000)) {
+		t.Fatalf("Expected the second match capped at ₹500, got %+v", second)
+	}
+
+	third := deposit(t, p, money.INR(100000))
+	if _, ok := third["matched_donation"]; ok {
+		t.Errorf("Expected no match once the cap is reached, got %+v", third)
+	}
+
+	history := p.Donors["CORP001"].DonationHistory
+	if len(history) != 2 || history[0].MatchFor != first["transaction_id"] || history[1].MatchFor != second["transaction_id"] {
+		t.Fatalf("Expected two matched donations, got %+v", history)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	corporate := reloaded.Donors["CORP001"]
+	if !corporate.IsCorporate() || !corporate.Corporate.HasEmployee("DONOR001") || reloaded.Donors["DONOR001"].EmployerID != "CORP001" {
+		t.Fatalf("Expected the corporate profile and employee to survive a reload, got %+v", corporate.Corporate)
+	}
+	if rule := corporate.Corporate.MatchingRule; rule.Ratio != 1 || !rule.PerEmployeeCap.Equal(money.INR(150000)) || len(rule.EligibleCategories) != 1 {
+		t.Errorf("Expected the matching rule to survive a reload, got %+v", rule)
+	}
+	if len(corporate.DonationHistory) != 2 || corporate.DonationHistory[1].MatchFor != second["transaction_id"] {
+		t.Errorf("Expected matched donations to survive a reload, got %+v", corporate.DonationHistory)
+	}
+
+	report, err := reloaded.GetCSRReport("CORP001", csr.FinancialYear(time.Now()))
+	if err != nil {
+		t.Fatalf("Failed to get CSR report: %v", err)
+	}
+	if !report.TotalSpend.Equal(money.INR(150000)) || !report.MatchedSpend.Equal(money.INR(150000)) || !report.DirectSpend.IsZero() {
+		t.Errorf("Expected ₹1500 of matched spend, got %+v", report)
+	}
+	if len(report.Activities) != 1 || report.Activities[0].Item != "ii" || report.Activities[0].NGOs != 1 {
+		t.Errorf("Expected the spend under Schedule VII item (ii), got %+v", report.Activities)
+	}
+}
+
+func TestCorporateMatchingEligibility(t *testing.T) {
+	p, _ := newCorporatePlatform(t, entities.MatchingRule{
+		Ratio:              2,
+		EligibleCategories: []string{"healthcare"},
+		Active:             true,
+	})
+
+	if _, err := p.AddEmployee("CORP001", "CORP001"); err == nil {
+		t.Error("Expected a corporate to be rejected as an employee")
+	}
+
+	result := deposit(t, p, money.INR(100000))
+	if _, ok := result["matched_donation"]; ok {
+		t.Errorf("Expected no match for an ineligible NGO category, got %+v", result)
+	}
+
+	if _, err := p.UpdateMatchingRule("CORP001", entities.MatchingRule{Ratio: 2, Active: true}); err != nil {
+		t.Fatalf("Failed to update matching rule: %v", err)
+	}
+	result = deposit(t, p, money.INR(100000))
+	matched, ok := result["matched_donation"].(map[string]interface{})
+	if !ok || !matched["gross_amount"].(money.Money).Equal(money.INR(200000)) {
+		t.Fatalf("Expected a 2:1 match of ₹2000, got %+v", result)
+	}
+
+	if _, err := p.RemoveEmployee("CORP001", "DONOR001"); err != nil {
+		t.Fatalf("Failed to remove employee: %v", err)
+	}
+	result = deposit(t, p, money.INR(100000))
+	if _, ok := result["matched_donation"]; ok {
+		t.Errorf("Expected no match after the employee left, got %+v", result)
+	}
+}
@@ -0,0 +1,170 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/storage"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// InitializeDocuments sets the store uploaded documents are kept in
+func (p *NGOTransparencyPlatform) InitializeDocuments(store storage.Store) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.DocumentStore = store
+}
+
+// UploadDocument stores a file uploaded by an NGO, donor or auditor under the
+// SHA-256 of its contents, after checking its size and the MIME type detected
+// from its contents against the platform's DocumentPolicy. Uploading the same
+// contents again returns the earlier upload. The file is hashed and stored
+// without holding the platform lock, which is only taken to link it.
+func (p *NGOTransparencyPlatform) UploadDocument(ownerID, ownerType, filename string, data []byte) (*storage.Document, error) {
+	p.mutex.RLock()
+	store, policy := p.DocumentStore, p.DocumentPolicy
+	p.mutex.RUnlock()
+
+	if store == nil {
+		return nil, fmt.Errorf("document storage not configured")
+	}
+	filename = filepath.Base(strings.TrimSpace(filename))
+	if filename == "." || filename == string(filepath.Separator) {
+		return nil, fmt.Errorf("a filename is required")
+	}
+	mimeType, err := policy.Check(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files are stored by hash, so storing the same contents twice is harmless
+	hash := storage.Hash(data)
+	if err := store.Put(hash, data, mimeType); err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if document := p.ownedDocument(ownerID, hash); document != nil {
+		return document, nil
+	}
+
+	document := &storage.Document{
+		Hash:       hash,
+		Filename:   filename,
+		MIMEType:   mimeType,
+		Size:       int64(len(data)),
+		OwnerID:    ownerID,
+		OwnerType:  ownerType,
+		UploadedAt: time.Now(),
+	}
+	err = p.persist(func(tx *database.Repositories) error {
+		return tx.Documents.Create(documentToModel(document))
+	})
+	if err != nil {
+		// The stored file is left for a later upload of the same contents
+		return nil, fmt.Errorf("failed to persist document: %w", err)
+	}
+
+	p.documents[hash] = append(p.documents[hash], document)
+	return document, nil
+}
+
+// GetDocument returns an uploaded document and its contents, checked against
+// its hash. Auditors and admins may read any document; others only their own
+// uploads.
+func (p *NGOTransparencyPlatform) GetDocument(requesterID, requesterType, hash string) (*storage.Document, []byte, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.DocumentStore == nil {
+		return nil, nil, fmt.Errorf("document storage not configured")
+	}
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	uploads := p.documents[hash]
+	if len(uploads) == 0 {
+		return nil, nil, ErrDocumentNotFound
+	}
+
+	document := p.ownedDocument(requesterID, hash)
+	if document == nil {
+		if requesterType != "auditor" && requesterType != "admin" {
+			return nil, nil, ErrDocumentNotFound
+		}
+		document = uploads[0]
+	}
+
+	data, err := p.DocumentStore.Get(hash)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fmt.Errorf("document contents missing from storage")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, data, nil
+}
+
+// documentAttachments returns the attachments for documents an owner
+// uploaded, named by their hashes
+func (p *NGOTransparencyPlatform) documentAttachments(ownerID string, hashes []string) ([]transactions.Attachment, error) {
+	attachments := make([]transactions.Attachment, 0, len(hashes))
+	seen := make(map[string]bool)
+	for _, hash := range hashes {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if seen[hash] {
+			continue
+		}
+		document := p.ownedDocument(ownerID, hash)
+		if document == nil {
+			return nil, fmt.Errorf("attachment %s has not been uploaded", hash)
+		}
+		seen[hash] = true
+		attachments = append(attachments, transactions.Attachment{
+			Filename:   document.Filename,
+			Hash:       document.Hash,
+			Type:       document.MIMEType,
+			UploadedAt: document.UploadedAt,
+		})
+	}
+	return attachments, nil
+}
+
+// ownedDocument returns an owner's upload of a document, or nil
+func (p *NGOTransparencyPlatform) ownedDocument(ownerID, hash string) *storage.Document {
+	for _, document := range p.documents[hash] {
+		if document.OwnerID == ownerID {
+			return document
+		}
+	}
+	return nil
+}
+
+func documentToModel(document *storage.Document) *database.DocumentModel {
+	return &database.DocumentModel{
+		Hash:      document.Hash,
+		OwnerID:   document.OwnerID,
+		OwnerType: document.OwnerType,
+		Filename:  document.Filename,
+		MIMEType:  document.MIMEType,
+		Size:      document.Size,
+		CreatedAt: document.UploadedAt,
+	}
+}
+
+func documentFromModel(model *database.DocumentModel) *storage.Document {
+	return &storage.Document{
+		Hash:       model.Hash,
+		Filename:   model.Filename,
+		MIMEType:   model.MIMEType,
+		Size:       model.Size,
+		OwnerID:    model.OwnerID,
+		OwnerType:  model.OwnerType,
+		UploadedAt: model.CreatedAt,
+	}
+}
@@ -0,0 +1,99 @@
+package platform
+
+import (
+	"fmt"
+	"testing"
+
+	"ngo-transparency-platform/pkg/storage"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// uploadedPDF stores a small PDF uploaded by ownerID, setting up a local
+// document store on first use
+func uploadedPDF(t *testing.T, p *NGOTransparencyPlatform, ownerID, ownerType, name string) *storage.Document {
+	t.Helper()
+
+	if p.DocumentStore == nil {
+		store, err := storage.NewLocalStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("Failed to create document store: %v", err)
+		}
+		p.InitializeDocuments(store)
+	}
+	data := []byte(fmt.Sprintf("%%PDF-1.4\n%% %s\ntrailer << >>\n%%%%EOF\n", name))
+	document, err := p.UploadDocument(ownerID, ownerType, name, data)
+	if err != nil {
+		t.Fatalf("Failed to upload %s: %v", name, err)
+	}
+	return document
+}
+
+func TestUploadDocument(t *testing.T) {
+	p, repos := newAppealPlatform(t)
+	document := uploadedPDF(t, p, "NGO001", "ngo", "../invoice.pdf")
+
+	if document.Filename != "invoice.pdf" || document.MIMEType != "application/pdf" || !storage.ValidHash(document.Hash) {
+		t.Fatalf("Expected a stored PDF named by its hash, got %+v", document)
+	}
+	if again := uploadedPDF(t, p, "NGO001", "ngo", "../invoice.pdf"); again != document {
+		t.Errorf("Expected uploading the same contents again to return the earlier upload")
+	}
+	if _, err := p.UploadDocument("NGO001", "ngo", "notes.txt", []byte("plain text notes")); err == nil {
+		t.Errorf("Expected a disallowed file type to be refused")
+	}
+	p.DocumentPolicy.MaxSize = 16
+	if _, err := p.UploadDocument("NGO001", "ngo", "big.pdf", []byte("%PDF-1.4 larger than the limit")); err == nil {
+		t.Errorf("Expected a file over the size limit to be refused")
+	}
+
+	// Owners, auditors and admins may download the document; others may not
+	if _, data, err := p.GetDocument("NGO001", "ngo", document.Hash); err != nil || storage.Hash(data) != document.Hash {
+		t.Errorf("Expected the owner to download the document, got %v", err)
+	}
+	if _, _, err := p.GetDocument("AUD001", "auditor", document.Hash); err != nil {
+		t.Errorf("Expected an auditor to download the document, got %v", err)
+	}
+	if _, _, err := p.GetDocument("NGO002", "ngo", document.Hash); err == nil {
+		t.Errorf("Expected another NGO to be refused the document")
+	}
+
+	// Document metadata survives a restart
+	reloaded := newReloadedPlatform(t, repos)
+	reloaded.InitializeDocuments(p.DocumentStore)
+	if got, _, err := reloaded.GetDocument("NGO001", "ngo", document.Hash); err != nil || got.Filename != "invoice.pdf" {
+		t.Errorf("Expected the document to be reloaded, got %+v, %v", got, err)
+	}
+}
+
+func TestExpenditureAttachments(t *testing.T) {
+	p, _ := newAppealPlatform(t)
+	invoice := uploadedPDF(t, p, "NGO001", "ngo", "invoice.pdf")
+	foreign := uploadedPDF(t, p, "NGO002", "ngo", "other.pdf")
+
+	expenditure := invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books", "attachments": []string{foreign.Hash}})
+	if _, err := p.SubmitExpenditure("NGO001", expenditure); err == nil {
+		t.Errorf("Expected a document uploaded by another NGO not to be attached")
+	}
+
+	expenditure["attachments"] = []string{invoice.Hash, invoice.Hash}
+	submitted, err := p.SubmitExpenditure("NGO001", expenditure)
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+	id := submitted["transaction_id"].(string)
+	if _, err := p.ReviewExpenditure(submitted["assigned_auditor_id"].(string), id, transactions.DecisionApprove, "", nil); err != nil {
+		t.Fatalf("Failed to approve expenditure: %v", err)
+	}
+
+	// The block records the hash of the file auditors can download
+	var recorded struct {
+		Attachments []transactions.Attachment `json:"attachments"`
+	}
+	decodeBlockData(t, p.NGOs["NGO001"].ExpenditureBlockchain.GetLatestBlock(), &recorded)
+	if len(recorded.Attachments) != 1 || recorded.Attachments[0].Hash != invoice.Hash || recorded.Attachments[0].Filename != "invoice.pdf" {
+		t.Fatalf("Expected the invoice's hash in the block, got %+v", recorded.Attachments)
+	}
+	if _, data, err := p.GetDocument("AUD001", "auditor", recorded.Attachments[0].Hash); err != nil || storage.Hash(data) != invoice.Hash {
+		t.Errorf("Expected auditors to download the attached file, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"ngo-transparency-platform/pkg/einvoice"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// EInvoiceSubmission is a GST e-invoice given for an expenditure: the IRP's
+// response or the INV-01 invoice, and the signed QR code printed on it
+type EInvoiceSubmission struct {
+	Document     json.RawMessage `json:"document,omitempty"`
+	SignedQRCode string          `json:"signed_qr_code,omitempty"`
+}
+
+// InitializeEInvoices sets the verifier e-invoices are checked with
+func (p *NGOTransparencyPlatform) InitializeEInvoices(verifier *einvoice.Verifier) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.EInvoiceVerifier = verifier
+}
+
+// ImportEInvoice verifies an e-invoice and returns the invoice details an
+// expenditure paying it would record, without submitting anything
+func (p *NGOTransparencyPlatform) ImportEInvoice(submission EInvoiceSubmission) (transactions.InvoiceDetails, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.eInvoiceDetails(submission)
+}
+
+// eInvoiceDetails verifies an e-invoice and fills invoice details from it.
+// The QR code does not name the seller, so an invoice imported from it alone
+// takes the vendor's name from the registry.
+func (p *NGOTransparencyPlatform) eInvoiceDetails(submission EInvoiceSubmission) (transactions.InvoiceDetails, error) {
+	if p.EInvoiceVerifier == nil {
+		return transactions.InvoiceDetails{}, fmt.Errorf("e-invoice verification not configured")
+	}
+	record, err := p.EInvoiceVerifier.Import(submission.Document, submission.SignedQRCode)
+	if err != nil {
+		return transactions.InvoiceDetails{}, err
+	}
+
+	vendorName := record.SellerName
+	if vendorName == "" {
+		if vendor, exists := p.Vendors[record.SellerGSTIN]; exists {
+			vendorName = vendor.LegalName
+		}
+	}
+	return transactions.InvoiceDetails{
+		InvoiceNumber: record.InvoiceNumber,
+		GSTIN:         record.BuyerGSTIN,
+		VendorName:    vendorName,
+		VendorGSTIN:   record.SellerGSTIN,
+		InvoiceDate:   record.InvoiceDate,
+		Documents:     []string{"e-invoice " + record.IRN},
+		EInvoice:      record,
+	}, nil
+}
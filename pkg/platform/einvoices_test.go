@@ -0,0 +1,98 @@
+package platform
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"ngo-transparency-platform/pkg/einvoice"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// signedQRCode returns a QR code payload for an invoice of total signed
+// with key as the IRP signs it
+func signedQRCode(t *testing.T, key *rsa.PrivateKey, invoiceNumber string, total float64) string {
+	t.Helper()
+
+	data, err := json.Marshal(einvoice.QRCode{
+		SellerGstin: "27AAPFU0939F1ZV",
+		BuyerGstin:  "29AAGCB7383J1Z4",
+		DocNo:       invoiceNumber,
+		DocTyp:      "INV",
+		DocDt:       "15/03/2024",
+		TotInvVal:   money.FromMajor(total),
+		ItemCnt:     1,
+		MainHsnCode: "4820",
+		Irn:         "irn-" + invoiceNumber,
+		IrnDt:       "2024-03-15 11:42:00",
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode QR code: %v", err)
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"data": string(data)}).SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign QR code: %v", err)
+	}
+	return token
+}
+
+func TestExpenditureEInvoice(t *testing.T) {
+	p, _ := newAppealPlatform(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	qr := signedQRCode(t, key, "VND/118", 354)
+	expenditure := map[string]interface{}{"amount": 300.0, "category": "education", "description": "Notebooks", "einvoice": EInvoiceSubmission{SignedQRCode: qr}}
+
+	if _, err := p.SubmitExpenditure("NGO001", expenditure); err == nil {
+		t.Errorf("Expected e-invoices to be refused until a verifier is configured")
+	}
+	p.InitializeEInvoices(einvoice.NewVerifier(&key.PublicKey))
+	if _, err := p.RegisterVendor("27AAPFU0939F1ZV", "Pune Stationers Pvt Ltd", "NGO001"); err != nil {
+		t.Fatalf("Failed to register vendor: %v", err)
+	}
+
+	invoice, err := p.ImportEInvoice(EInvoiceSubmission{SignedQRCode: qr})
+	if err != nil {
+		t.Fatalf("Failed to import e-invoice: %v", err)
+	}
+	if invoice.InvoiceNumber != "VND/118" || invoice.VendorName != "Pune Stationers Pvt Ltd" || invoice.GSTIN != "29AAGCB7383J1Z4" || invoice.EInvoice == nil {
+		t.Fatalf("Expected invoice details from the QR code and vendor registry, got %+v", invoice)
+	}
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	if _, err := p.ImportEInvoice(EInvoiceSubmission{SignedQRCode: signedQRCode(t, other, "VND/118", 354)}); err == nil {
+		t.Errorf("Expected a QR code not signed by the IRP to be refused")
+	}
+	if _, err := p.SubmitExpenditure("NGO001", map[string]interface{}{"amount": 300.0, "category": "education", "einvoice": EInvoiceSubmission{SignedQRCode: signedQRCode(t, key, "VND/119", 250)}}); err == nil {
+		t.Errorf("Expected an expenditure above the e-invoice total to be refused")
+	}
+
+	submitted, err := p.SubmitExpenditure("NGO001", expenditure)
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+	id := submitted["transaction_id"].(string)
+	if _, err := p.ReviewExpenditure(submitted["assigned_auditor_id"].(string), id, transactions.DecisionApprove, "", nil); err != nil {
+		t.Fatalf("Failed to approve expenditure: %v", err)
+	}
+
+	// The block records the IRN and the signed QR code vouching for it
+	var recorded struct {
+		InvoiceDetails transactions.InvoiceDetails `json:"invoice_details"`
+	}
+	decodeBlockData(t, p.NGOs["NGO001"].ExpenditureBlockchain.GetLatestBlock(), &recorded)
+	if recorded.InvoiceDetails.EInvoice == nil || recorded.InvoiceDetails.EInvoice.IRN != "irn-VND/118" || recorded.InvoiceDetails.EInvoice.SignedQRCode != qr {
+		t.Fatalf("Expected the e-invoice registration in the block, got %+v", recorded.InvoiceDetails)
+	}
+	if _, err := p.SubmitExpenditure("NGO001", expenditure); err == nil {
+		t.Errorf("Expected an e-invoice already paid to be refused")
+	}
+}
@@ -0,0 +1,165 @@
+package platform
+
+import (
+	"testing"
+
+	"ngo-transparency-platform/pkg/config"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+)
+
+func newTestRepositories(t *testing.T) *database.Repositories {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Database.Driver = database.DriverSQLite
+	cfg.Database.Path = ":memory:"
+	config.AppConfig = cfg
+
+	db, err := database.OpenDatabase(cfg)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	database.DB = db
+	if err := database.MigrateDatabase(); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	return database.NewRepositories(db)
+}
+
+// createAccount stores a user with its entity row, as the registration handler does
+func createAccount(t *testing.T, repos *database.Repositories, userType string, entity interface{}) {
+	t.Helper()
+
+	var entityID string
+	switch model := entity.(type) {
+	case *database.NGOModel:
+		entityID = model.NGOID
+	case *database.DonorModel:
+		entityID = model.DonorID
+	case *database.AuditorModel:
+		entityID = model.AuditorID
+	}
+
+	user := &database.User{Email: entityID + "@example.org", Password: "hash", UserType: userType}
+	if err := repos.NGOs.Create(user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	switch model := entity.(type) {
+	case *database.NGOModel:
+		model.UserID = user.ID
+	case *database.DonorModel:
+		model.UserID = user.ID
+	case *database.AuditorModel:
+		model.UserID = user.ID
+	}
+	if err := repos.NGOs.Create(entity); err != nil {
+		t.Fatalf("Failed to create %s: %v", userType, err)
+	}
+}
+
+// newTestPlatform returns a platform on an in-memory database with a
+// verified NGO NGO001 and a verified donor DONOR001
+func newTestPlatform(t *testing.T) (*NGOTransparencyPlatform, *database.Repositories) {
+	t.Helper()
+
+	repos := newTestRepositories(t)
+	p := NewNGOTransparencyPlatform()
+	if err := p.AttachRepositories(repos); err != nil {
+		t.Fatalf("Failed to attach repositories: %v", err)
+	}
+
+	createAccount(t, repos, "ngo", &database.NGOModel{NGOID: "NGO001", Name: "Test NGO", RegistrationNumber: "REG001", Category: "education", PublicKey: "key"})
+	if _, err := p.RegisterNGO("NGO001", "Test NGO", "REG001", "education", map[string]interface{}{}, []string{"signer1"}); err != nil {
+		t.Fatalf("Failed to register NGO: %v", err)
+	}
+	if err := p.VerifyNGOKYC("NGO001", "AUTH001", []entities.Certificate{{Type: "80G", Number: "80G-001"}}); err != nil {
+		t.Fatalf("Failed to verify NGO: %v", err)
+	}
+	registerDonor(t, p, repos, "DONOR001", "basic")
+	return p, repos
+}
+
+// newReloadedPlatform loads a fresh platform from the repositories
+func newReloadedPlatform(t *testing.T, repos *database.Repositories) *NGOTransparencyPlatform {
+	t.Helper()
+
+	reloaded := NewNGOTransparencyPlatform()
+	if err := reloaded.AttachRepositories(repos); err != nil {
+		t.Fatalf("Failed to reload platform: %v", err)
+	}
+	return reloaded
+}
+
+// registerDonor registers a donor and verifies its KYC at the given level
+func registerDonor(t *testing.T, p *NGOTransparencyPlatform, repos *database.Repositories, donorID, kycLevel string) {
+	t.Helper()
+
+	createAccount(t, repos, "donor", &database.DonorModel{DonorID: donorID})
+	if _, err := p.RegisterDonor(donorID, map[string]interface{}{}); err != nil {
+		t.Fatalf("Failed to register donor: %v", err)
+	}
+	if err := p.VerifyDonorKYC(donorID, "AUTH001", kycLevel); err != nil {
+		t.Fatalf("Failed to verify donor: %v", err)
+	}
+}
+
+// registerAuditor registers and verifies an auditor with the given specializations
+func registerAuditor(t *testing.T, p *NGOTransparencyPlatform, repos *database.Repositories, auditorID string, specializations []string) {
+	t.Helper()
+
+	createAccount(t, repos, "auditor", &database.AuditorModel{AuditorID: auditorID, Name: auditorID, PublicKey: "key"})
+	if _, err := p.RegisterAuditor(auditorID, auditorID, map[string]interface{}{"license": "CA-" + auditorID}, specializations); err != nil {
+		t.Fatalf("Failed to register auditor: %v", err)
+	}
+	if err := p.VerifyAuditorCredentials(auditorID, "ICAI"); err != nil {
+		t.Fatalf("Failed to verify auditor: %v", err)
+	}
+}
+
+// verifiedAuditor registers and verifies auditor AUD001, a financial generalist
+func verifiedAuditor(t *testing.T, p *NGOTransparencyPlatform, repos *database.Repositories) {
+	t.Helper()
+
+	registerAuditor(t, p, repos, "AUD001", []string{"financial"})
+}
+
+// deposit records an unrestricted UPI donation of amount from DONOR001 to NGO001
+func deposit(t *testing.T, p *NGOTransparencyPlatform, amount money.Money) map[string]interface{} {
+	t.Helper()
+
+	result, err := p.ProcessDonation("DONOR001", "NGO001", amount, payments.MethodUPI)
+	if err != nil {
+		t.Fatalf("Failed to process donation: %v", err)
+	}
+	return result
+}
+
+// settledIntent settles a card payment of amount from DONOR001 to NGO001
+// through the fake gateway, earmarked to campaignID unless it is empty
+func settledIntent(t *testing.T, p *NGOTransparencyPlatform, gateway *payments.FakeGateway, campaignID string, amount money.Money) *PaymentIntent {
+	t.Helper()
+
+	intent, err := p.CreatePaymentIntent("DONOR001", "NGO001", campaignID, amount, payments.MethodCard, "")
+	if err != nil {
+		t.Fatalf("Failed to create payment intent: %v", err)
+	}
+	payload, signature, err := gateway.Capture(intent.GatewayOrderID)
+	if err != nil {
+		t.Fatalf("Failed to capture payment: %v", err)
+	}
+	if _, err := p.HandlePaymentWebhook(payload, signature); err != nil {
+		t.Fatalf("Failed to handle webhook: %v", err)
+	}
+	return intent
+}
@@ -0,0 +1,19 @@
+package platform
+
+import (
+	"ngo-transparency-platform/pkg/entities"
+)
+
+// TraceDonation returns every expenditure block a donation paid for, with
+// the amount it contributed to each
+func (p *NGOTransparencyPlatform) TraceDonation(transactionID string) (*entities.DonationTrace, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, ngo := range p.NGOs {
+		if findDonationBlock(ngo.DonationBlockchain, transactionID) != nil {
+			return ngo.TraceDonation(transactionID)
+		}
+	}
+	return nil, ErrDonationNotFound
+}
@@ -0,0 +1,65 @@
+package platform
+
+import (
+	"testing"
+
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+func TestExpendituresTraceToDonations(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+
+	first := deposit(t, p, money.INR(100000))
+	second := deposit(t, p, money.INR(50000))
+	firstID, secondID := first["transaction_id"].(string), second["transaction_id"].(string)
+
+	// FIFO drains the first donation before touching the second
+	if _, err := recordExpenditure(t, p, "NGO001", invoiced(map[string]interface{}{"amount": 1200.0, "category": "education", "description": "Books"})); err != nil {
+		t.Fatalf("Failed to process expenditure: %v", err)
+	}
+
+	overdrawn := invoiced(map[string]interface{}{
+		"amount": 200.0, "category": "education", "description": "Uniforms",
+		"funding": []transactions.FundAllocation{{DonationID: secondID, Amount: money.INR(50000)}},
+	})
+	if _, err := recordExpenditure(t, p, "NGO001", overdrawn); err == nil {
+		t.Error("Expected funding beyond the donation's unspent balance to be rejected")
+	}
+	if audits := p.Auditors["AUD001"].AuditHistory; len(audits) != 1 {
+		t.Errorf("Expected the rejected funding to be caught before audit, got %d audits", len(audits))
+	}
+
+	explicit := invoiced(map[string]interface{}{
+		"amount": 200.0, "category": "education", "description": "Uniforms",
+		"funding": []transactions.FundAllocation{{DonationID: secondID, Amount: money.INR(10000)}},
+	})
+	if _, err := recordExpenditure(t, p, "NGO001", explicit); err != nil {
+		t.Fatalf("Failed to process expenditure: %v", err)
+	}
+
+	trace, err := p.TraceDonation(firstID)
+	if err != nil {
+		t.Fatalf("Failed to trace donation: %v", err)
+	}
+	if len(trace.Expenditures) != 1 || !trace.Expenditures[0].Amount.Equal(money.INR(99000)) || !trace.Unspent.IsZero() {
+		t.Errorf("Expected the first donation to fund ₹990 of the books, got %+v", trace)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	trace, err = reloaded.TraceDonation(secondID)
+	if err != nil {
+		t.Fatalf("Failed to trace donation after reload: %v", err)
+	}
+	if len(trace.Expenditures) != 2 || !trace.Allocated.Equal(money.INR(41000)) || !trace.Unspent.Equal(money.INR(8500)) {
+		t.Fatalf("Expected the second donation to fund ₹410 of two expenditures with ₹85 unspent, got %+v", trace)
+	}
+	if !trace.Expenditures[0].Amount.Equal(money.INR(21000)) || trace.Expenditures[1].Description != "Uniforms" || !trace.Expenditures[1].Amount.Equal(money.INR(20000)) {
+		t.Errorf("Unexpected funded expenditures: %+v", trace.Expenditures)
+	}
+
+	if _, err := reloaded.TraceDonation("missing"); err == nil {
+		t.Error("Expected an unknown donation to be rejected")
+	}
+}
@@ -0,0 +1,187 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again
+// with different request parameters
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with different parameters")
+
+// CreatePaymentIntentIdempotent creates a payment intent like
+// CreatePaymentIntent, but retries with the same idempotency key return the
+// intent created by the first attempt instead of opening another order
+func (p *NGOTransparencyPlatform) CreatePaymentIntentIdempotent(idempotencyKey, donorID, ngoID, campaignID string, amount money.Money, paymentMethod, vpa string) (*PaymentIntent, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	transactionID := transactions.IdempotentTransactionID("donation", donorID, idempotencyKey)
+	for _, intent := range p.PaymentIntents {
+		if intent.TransactionID != transactionID {
+			continue
+		}
+		if intent.NGOID != ngoID || intent.CampaignID != campaignID || !intent.Amount.Equal(amount) || intent.PaymentMethod != paymentMethod {
+			return nil, ErrIdempotencyKeyReused
+		}
+		return intent, nil
+	}
+
+	return p.createPaymentIntent(transactionID, donorID, ngoID, campaignID, amount, paymentMethod, vpa)
+}
+
+// ProcessDonationIdempotent processes a donation like ProcessDonation, but
+// retries with the same idempotency key return the block already mined
+func (p *NGOTransparencyPlatform) ProcessDonationIdempotent(idempotencyKey, donorID, ngoID string, amount money.Money, paymentMethod string) (map[string]interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	transactionID := transactions.IdempotentTransactionID("donation", donorID, idempotencyKey)
+	if recorded, exists := p.donations[transactionID]; exists {
+		if recorded.ngoID != ngoID || !recorded.grossAmount.Equal(amount) {
+			return nil, ErrIdempotencyKeyReused
+		}
+		block := p.NGOs[ngoID].DonationBlockchain.GetBlockByIndex(recorded.blockIndex)
+		if block == nil {
+			return nil, fmt.Errorf("donation block not found")
+		}
+		data := block.Data.(map[string]interface{})
+		return map[string]interface{}{
+			"success":        true,
+			"block_hash":     block.Hash,
+			"transaction_id": transactionID,
+			"block_index":    block.Index,
+			"e_bill":         data["e_bill"],
+			"platform_fee":   recorded.platformFee,
+			"net_amount":     recorded.netAmount,
+			"gross_amount":   recorded.grossAmount,
+		}, nil
+	}
+
+	return p.processDonation(transactionID, donorID, ngoID, amount, paymentMethod)
+}
+
+// recordedDonation is where a donation was mined and how its gross amount
+// was split when it was recorded, before any reversal
+type recordedDonation struct {
+	ngoID       string
+	blockIndex  int
+	grossAmount money.Money
+	platformFee money.Money
+	netAmount   money.Money
+}
+
+// loadDonationIndex indexes the mined donations stored in repos by
+// transaction ID
+func loadDonationIndex(repos *database.Repositories, ngos map[string]*entities.NGO) (map[string]recordedDonation, error) {
+	var models []database.DonationModel
+	if err := repos.Donations.List(&models, 0, 0); err != nil {
+		return nil, fmt.Errorf("failed to load donations: %w", err)
+	}
+
+	index := make(map[string]recordedDonation, len(models))
+	for i := range models {
+		model := &models[i]
+		ngo, exists := ngos[model.NGOID]
+		if !exists || model.BlockHash == "" {
+			continue // Failed payments were never mined
+		}
+		block := ngo.DonationBlockchain.GetBlockByHash(model.BlockHash)
+		if block == nil {
+			return nil, fmt.Errorf("block of donation %s not found", model.TransactionID)
+		}
+		index[model.TransactionID] = recordedDonation{
+			ngoID:       model.NGOID,
+			blockIndex:  block.Index,
+			grossAmount: model.Amount,
+			platformFee: model.PlatformFee,
+			netAmount:   model.NetAmount,
+		}
+	}
+	return index, nil
+}
+
+// SubmitExpenditureIdempotent submits an expenditure like SubmitExpenditure,
+// but retries with the same idempotency key return the expenditure already
+// queued, the block mined once it was approved, or the auditor's rejection
+func (p *NGOTransparencyPlatform) SubmitExpenditureIdempotent(idempotencyKey, ngoID string, expenditureData map[string]interface{}) (map[string]interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+
+	transactionID := transactions.IdempotentTransactionID("expenditure", ngoID, idempotencyKey)
+	requested, _ := parseAmount(expenditureData["amount"])
+	requestedCampaign, _ := expenditureData["campaign_id"].(string)
+
+	if pending, exists := p.PendingExpenditures[transactionID]; exists {
+		if !pending.Amount.Equal(requested) || pending.Category != expenditureData["category"] || pending.CampaignID != requestedCampaign {
+			return nil, ErrIdempotencyKeyReused
+		}
+		return p.pendingExpenditureView(pending), nil
+	}
+
+	audit := p.findAudit(transactionID)
+
+	if block := findExpenditureBlock(ngo.ExpenditureBlockchain, transactionID); block != nil {
+		data := block.Data.(map[string]interface{})
+		recorded, ok := parseAmount(data["amount"])
+		recordedCampaign, _ := data["campaign_id"].(string)
+		if !ok || !recorded.Equal(requested) || data["category"] != expenditureData["category"] || recordedCampaign != requestedCampaign {
+			return nil, ErrIdempotencyKeyReused
+		}
+		return map[string]interface{}{
+			"success":        true,
+			"status":         "validated",
+			"block_hash":     block.Hash,
+			"transaction_id": transactionID,
+			"block_index":    block.Index,
+			"audit_result":   audit,
+		}, nil
+	}
+
+	if audit != nil {
+		return nil, fmt.Errorf("expenditure rejected by auditor: %s", audit.AuditNotes)
+	}
+
+	return p.submitExpenditure(transactionID, ngoID, expenditureData)
+}
+
+// findAudit returns the most recent audit of an expenditure
+func (p *NGOTransparencyPlatform) findAudit(expenditureID string) *entities.AuditResult {
+	for _, auditor := range p.Auditors {
+		for i := len(auditor.AuditHistory) - 1; i >= 0; i-- {
+			if auditor.AuditHistory[i].ExpenditureID == expenditureID {
+				audit := auditor.AuditHistory[i]
+				return &audit
+			}
+		}
+	}
+	return nil
+}
+
+// findExpenditureBlock returns the block an expenditure was mined in
+func findExpenditureBlock(chain *blockchain.Blockchain, transactionID string) *blockchain.Block {
+	return chain.FindBlock(func(block *blockchain.Block) bool {
+		data, ok := block.Data.(map[string]interface{})
+		return ok && data["type"] == "expenditure" && data["transaction_id"] == transactionID
+	})
+}
+
+// newDonation creates a donation under transactionID, or under a fresh ID
+// when transactionID is empty
+func newDonation(transactionID, donorID, ngoID string, netAmount money.Money, paymentMethod, donorKYCHash string) *transactions.DonationTransaction {
+	if transactionID == "" {
+		return transactions.NewDonationTransaction(donorID, ngoID, netAmount, paymentMethod, donorKYCHash)
+	}
+	return transactions.NewDonationTransactionWithID(transactionID, donorID, ngoID, netAmount, paymentMethod, donorKYCHash)
+}
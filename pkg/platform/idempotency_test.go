@@ -0,0 +1,142 @@
+package platform
+
+import (
+	"errors"
+	"testing"
+
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+func TestCreatePaymentIntentIdempotent(t *testing.T) {
+	p, repos, _ := newPaymentsPlatform(t)
+
+	first, err := p.CreatePaymentIntentIdempotent("key-1", "DONOR001", "NGO001", "", money.INR(50000), payments.MethodCard, "")
+	if err != nil {
+		t.Fatalf("Failed to create payment intent: %v", err)
+	}
+	retry, err := p.CreatePaymentIntentIdempotent("key-1", "DONOR001", "NGO001", "", money.INR(50000), payments.MethodCard, "")
+	if err != nil {
+		t.Fatalf("Failed to retry payment intent: %v", err)
+	}
+	if retry.IntentID != first.IntentID || len(p.PaymentIntents) != 1 {
+		t.Errorf("Expected the retry to return intent %s, got %s with %d intents", first.IntentID, retry.IntentID, len(p.PaymentIntents))
+	}
+
+	if _, err := p.CreatePaymentIntentIdempotent("key-1", "DONOR001", "NGO001", "", money.INR(60000), payments.MethodCard, ""); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected a different amount under the same key to be rejected, got %v", err)
+	}
+
+	// The key still holds after a restart
+	reloaded := newReloadedPlatform(t, repos)
+	reloaded.InitializePayments(p.PaymentGateway)
+	again, err := reloaded.CreatePaymentIntentIdempotent("key-1", "DONOR001", "NGO001", "", money.INR(50000), payments.MethodCard, "")
+	if err != nil || again.IntentID != first.IntentID {
+		t.Errorf("Expected intent %s after reload, got %+v (%v)", first.IntentID, again, err)
+	}
+}
+
+func TestProcessDonationIdempotent(t *testing.T) {
+	p, _ := newTestPlatform(t)
+
+	first, err := p.ProcessDonationIdempotent("key-1", "DONOR001", "NGO001", money.INR(100000), "upi")
+	if err != nil {
+		t.Fatalf("Failed to process donation: %v", err)
+	}
+	retry, err := p.ProcessDonationIdempotent("key-1", "DONOR001", "NGO001", money.INR(100000), "upi")
+	if err != nil {
+		t.Fatalf("Failed to retry donation: %v", err)
+	}
+	if retry["block_hash"] != first["block_hash"] || retry["transaction_id"] != first["transaction_id"] {
+		t.Errorf("Expected the retry to return block %v, got %v", first["block_hash"], retry["block_hash"])
+	}
+
+	ngo := p.NGOs["NGO001"]
+	if ngo.DonationBlockchain.GetChainLength() != 2 || !ngo.TotalDonationsReceived.Equal(money.INR(99000)) {
+		t.Errorf("Expected one donation of ₹990, got %d blocks and ₹%s",
+			ngo.DonationBlockchain.GetChainLength(), ngo.TotalDonationsReceived)
+	}
+
+	if _, err := p.ProcessDonationIdempotent("key-1", "DONOR001", "NGO001", money.INR(20000), "upi"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected a different amount under the same key to be rejected, got %v", err)
+	}
+	if _, err := p.ProcessDonationIdempotent("key-2", "DONOR001", "NGO001", money.INR(20000), "upi"); err != nil {
+		t.Errorf("Expected a new key to process a new donation, got %v", err)
+	}
+}
+
+func TestProcessDonationIdempotentReplaysRecordedSplit(t *testing.T) {
+	p, repos := newTestPlatform(t)
+
+	first, err := p.ProcessDonationIdempotent("key-1", "DONOR001", "NGO001", money.INR(100000), "upi")
+	if err != nil {
+		t.Fatalf("Failed to process donation: %v", err)
+	}
+
+	// A retry after the fee changes returns the split the donation was recorded with
+	p.SystemStats.PlatformFee = 0.02
+	retry, err := p.ProcessDonationIdempotent("key-1", "DONOR001", "NGO001", money.INR(100000), "upi")
+	if err != nil {
+		t.Fatalf("Failed to retry donation: %v", err)
+	}
+	if retry["block_hash"] != first["block_hash"] || !retry["platform_fee"].(money.Money).Equal(money.INR(1000)) || !retry["net_amount"].(money.Money).Equal(money.INR(99000)) {
+		t.Errorf("Expected the recorded fee of ₹10 and net of ₹990, got %+v", retry)
+	}
+
+	// The key still holds after a restart
+	reloaded := newReloadedPlatform(t, repos)
+	again, err := reloaded.ProcessDonationIdempotent("key-1", "DONOR001", "NGO001", money.INR(100000), "upi")
+	if err != nil || again["block_hash"] != first["block_hash"] || !again["gross_amount"].(money.Money).Equal(money.INR(100000)) {
+		t.Errorf("Expected block %v after reload, got %+v (%v)", first["block_hash"], again, err)
+	}
+	if _, err := reloaded.ProcessDonationIdempotent("key-1", "DONOR001", "NGO001", money.INR(99000), "upi"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected a different gross amount under the same key to be rejected, got %v", err)
+	}
+	if reloaded.NGOs["NGO001"].DonationBlockchain.GetChainLength() != 2 {
+		t.Errorf("Expected one donation block after reload, got %d", reloaded.NGOs["NGO001"].DonationBlockchain.GetChainLength())
+	}
+}
+
+func TestSubmitExpenditureIdempotent(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+	deposit(t, p, money.INR(100000))
+
+	data := invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books"})
+	first, err := p.SubmitExpenditureIdempotent("key-1", "NGO001", data)
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+
+	// A retry while the expenditure is under review returns the queued one
+	reloaded := newReloadedPlatform(t, repos)
+	retry, err := reloaded.SubmitExpenditureIdempotent("key-1", "NGO001", data)
+	if err != nil {
+		t.Fatalf("Failed to retry expenditure: %v", err)
+	}
+	if retry["transaction_id"] != first["transaction_id"] || len(reloaded.PendingExpenditures) != 1 {
+		t.Fatalf("Expected the retry to return queued expenditure %v, got %+v", first["transaction_id"], retry)
+	}
+
+	approved, err := reloaded.ReviewExpenditure("AUD001", first["transaction_id"].(string), transactions.DecisionApprove, "", nil)
+	if err != nil {
+		t.Fatalf("Failed to approve expenditure: %v", err)
+	}
+	retry, err = reloaded.SubmitExpenditureIdempotent("key-1", "NGO001", data)
+	if err != nil {
+		t.Fatalf("Failed to retry expenditure: %v", err)
+	}
+	if retry["block_hash"] != approved["block_hash"] || retry["audit_result"] == nil {
+		t.Errorf("Expected the retry to return block %v with its audit, got %+v", approved["block_hash"], retry)
+	}
+	if reloaded.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength() != 2 || !reloaded.SystemStats.TotalExpenditures.Equal(money.INR(30000)) {
+		t.Errorf("Expected one expenditure of ₹300, got %d blocks and ₹%s",
+			reloaded.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength(), reloaded.SystemStats.TotalExpenditures)
+	}
+
+	changed := invoiced(map[string]interface{}{"amount": 500.0, "category": "education", "description": "Books"})
+	if _, err := reloaded.SubmitExpenditureIdempotent("key-1", "NGO001", changed); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected a different amount under the same key to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package platform
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// MandateRetryPolicy controls how failed mandate installments are retried
+type MandateRetryPolicy struct {
+	MaxRetries int           // Retries of a failed installment before it is skipped
+	RetryDelay time.Duration // Wait before each retry
+}
+
+// DefaultMandateRetryPolicy retries a failed installment daily, three times
+func DefaultMandateRetryPolicy() MandateRetryPolicy {
+	return MandateRetryPolicy{MaxRetries: 3, RetryDelay: 24 * time.Hour}
+}
+
+// MandateRun is the outcome of one attempt at charging a mandate installment
+type MandateRun struct {
+	MandateID     string    `json:"mandate_id"`
+	DonorID       string    `json:"donor_id"`
+	Installment   int       `json:"installment"`
+	Success       bool      `json:"success"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	Status        string    `json:"status"` // Of the mandate after the attempt
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+// CreateMandate sets up a recurring donation from a donor to an NGO, or to
+// one of its campaigns
+func (p *NGOTransparencyPlatform) CreateMandate(donorID, ngoID, campaignID string, amount money.Money, paymentMethod, frequency string, startDate time.Time, endDate *time.Time) (*entities.RecurringMandate, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	donor, ngo, err := p.checkDonation(donorID, ngoID, amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCampaignOpen(ngo, campaignID); err != nil {
+		return nil, err
+	}
+	if !payments.ValidMethod(paymentMethod) {
+		return nil, fmt.Errorf("unsupported payment method: %s", paymentMethod)
+	}
+
+	mandate, err := entities.NewRecurringMandate(donorID, ngoID, campaignID, amount, paymentMethod, frequency, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		return tx.Mandates.Save(mandateToModel(mandate))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist mandate: %w", err)
+	}
+
+	if err := donor.AddMandate(mandate); err != nil {
+		return nil, err
+	}
+	return mandate, nil
+}
+
+// PauseMandate stops a donor's mandate from being charged until it is resumed
+func (p *NGOTransparencyPlatform) PauseMandate(donorID, mandateID string) (*entities.RecurringMandate, error) {
+	return p.updateMandate(donorID, mandateID, func(mandate *entities.RecurringMandate) error {
+		return mandate.Pause(time.Now())
+	})
+}
+
+// ResumeMandate reactivates a paused mandate from its next scheduled installment
+func (p *NGOTransparencyPlatform) ResumeMandate(donorID, mandateID string) (*entities.RecurringMandate, error) {
+	return p.updateMandate(donorID, mandateID, func(mandate *entities.RecurringMandate) error {
+		return mandate.Resume(time.Now())
+	})
+}
+
+// CancelMandate permanently stops a donor's mandate
+func (p *NGOTransparencyPlatform) CancelMandate(donorID, mandateID string) (*entities.RecurringMandate, error) {
+	return p.updateMandate(donorID, mandateID, func(mandate *entities.RecurringMandate) error {
+		return mandate.Cancel(time.Now())
+	})
+}
+
+// GetMandates returns a donor's mandates, oldest first
+func (p *NGOTransparencyPlatform) GetMandates(donorID string) ([]*entities.RecurringMandate, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	donor, exists := p.Donors[donorID]
+	if !exists {
+		return nil, ErrDonorNotFound
+	}
+	return donor.SortedMandates(), nil
+}
+
+// GetAnnualReceipts returns a donor's consolidated receipts for a calendar
+// year, one per NGO donated to
+func (p *NGOTransparencyPlatform) GetAnnualReceipts(donorID string, year int) ([]entities.AnnualReceipt, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	donor, exists := p.Donors[donorID]
+	if !exists {
+		return nil, ErrDonorNotFound
+	}
+
+	receipts := donor.AnnualReceipts(year)
+	for i := range receipts {
+		if ngo, exists := p.NGOs[receipts[i].NGOID]; exists {
+			receipts[i].NGOName = ngo.Name
+		}
+	}
+	return receipts, nil
+}
+
+// RunDueMandates charges every mandate installment due at the given time.
+// Each installment is processed like a one-off donation, subject to the
+// donor's annual limit. Failed installments are retried according to the
+// platform's MandateRetryPolicy.
+func (p *NGOTransparencyPlatform) RunDueMandates(now time.Time) []MandateRun {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	type dueMandate struct {
+		donorID, mandateID string
+		dueAt              time.Time
+	}
+	due := make([]dueMandate, 0)
+	for donorID, donor := range p.Donors {
+		for mandateID, mandate := range donor.Mandates {
+			if mandate.IsDue(now) {
+				due = append(due, dueMandate{donorID, mandateID, mandate.DueAt()})
+			}
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].dueAt.Equal(due[j].dueAt) {
+			return due[i].mandateID < due[j].mandateID
+		}
+		return due[i].dueAt.Before(due[j].dueAt)
+	})
+
+	runs := make([]MandateRun, 0, len(due))
+	for _, mandate := range due {
+		runs = append(runs, p.chargeMandate(mandate.donorID, mandate.mandateID, now))
+	}
+	return runs
+}
+
+// chargeMandate attempts a mandate's next installment. Mandates are looked up
+// afresh because a failed write reloads the donor.
+func (p *NGOTransparencyPlatform) chargeMandate(donorID, mandateID string, now time.Time) MandateRun {
+	mandate, exists := p.findMandate(donorID, mandateID)
+	if !exists {
+		return MandateRun{MandateID: mandateID, DonorID: donorID, Error: ErrMandateNotFound.Error()}
+	}
+
+	run := MandateRun{MandateID: mandateID, DonorID: donorID, Installment: mandate.Installment}
+	transactionID := transactions.IdempotentTransactionID("donation", donorID, fmt.Sprintf("%s/%d", mandateID, mandate.Installment))
+
+	charged := *mandate
+	charged.RecordCharge(now)
+	_, err := p.chargeInstallment(&charged, mandate.Amount, transactionID)
+	if err == nil {
+		*mandate = charged
+		run.Success = true
+		run.TransactionID = transactionID
+		run.Status = mandate.Status
+		run.NextAttemptAt = mandate.DueAt()
+		return run
+	}
+	run.Error = err.Error()
+
+	mandate, exists = p.findMandate(donorID, mandateID)
+	if !exists {
+		return run
+	}
+	failed := *mandate
+	failed.RecordFailure(run.Error, now, p.MandateRetryPolicy.MaxRetries, p.MandateRetryPolicy.RetryDelay)
+	err = p.persist(func(tx *database.Repositories) error {
+		return tx.Mandates.Save(mandateToModel(&failed))
+	})
+	if err == nil {
+		*mandate = failed
+	}
+	run.Status = mandate.Status
+	run.NextAttemptAt = mandate.DueAt()
+	return run
+}
+
+// chargeInstallment records a mandate installment as a donation and stores
+// the mandate's advanced schedule in the same database transaction
+func (p *NGOTransparencyPlatform) chargeInstallment(charged *entities.RecurringMandate, amount money.Money, transactionID string) (map[string]interface{}, error) {
+	donor, ngo, err := p.checkDonation(charged.DonorID, charged.NGOID, amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCampaignOpen(ngo, charged.CampaignID); err != nil {
+		return nil, err
+	}
+
+	platformFee, netAmount, err := p.splitDonation(amount)
+	if err != nil {
+		return nil, err
+	}
+	donation := newDonation(transactionID, donor.DonorID, ngo.NGOID, netAmount, charged.PaymentMethod, donor.KYCData.DocumentHash)
+	donation.CampaignID = charged.CampaignID
+	donation.MandateID = charged.MandateID
+
+	return p.recordDonation(donor, ngo, donation, amount, platformFee, func(tx *database.Repositories, _ string) error {
+		return tx.Mandates.Save(mandateToModel(charged))
+	})
+}
+
+// updateMandate applies a status change to a copy of a donor's mandate and
+// keeps it once stored
+func (p *NGOTransparencyPlatform) updateMandate(donorID, mandateID string, update func(mandate *entities.RecurringMandate) error) (*entities.RecurringMandate, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, exists := p.Donors[donorID]; !exists {
+		return nil, ErrDonorNotFound
+	}
+	mandate, exists := p.findMandate(donorID, mandateID)
+	if !exists {
+		return nil, ErrMandateNotFound
+	}
+
+	updated := *mandate
+	if err := update(&updated); err != nil {
+		return nil, err
+	}
+
+	err := p.persist(func(tx *database.Repositories) error {
+		return tx.Mandates.Save(mandateToModel(&updated))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist mandate: %w", err)
+	}
+
+	*mandate = updated
+	return mandate, nil
+}
+
+func (p *NGOTransparencyPlatform) findMandate(donorID, mandateID string) (*entities.RecurringMandate, bool) {
+	donor, exists := p.Donors[donorID]
+	if !exists {
+		return nil, false
+	}
+	return donor.GetMandate(mandateID)
+}
@@ -0,0 +1,137 @@
+package platform
+
+import (
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+)
+
+func TestRunDueMandates(t *testing.T) {
+	p, repos := newTestPlatform(t)
+
+	if _, err := p.CreateMandate("DONOR001", "NGO001", "", money.INR(50000), payments.MethodUPI, "weekly", time.Time{}, nil); err == nil {
+		t.Error("Expected an unsupported frequency to be rejected")
+	}
+	mandate, err := p.CreateMandate("DONOR001", "NGO001", "", money.INR(50000), payments.MethodUPI, entities.FrequencyMonthly, time.Time{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create mandate: %v", err)
+	}
+
+	now := time.Now()
+	runs := p.RunDueMandates(now)
+	if len(runs) != 1 || !runs[0].Success || runs[0].Installment != 1 {
+		t.Fatalf("Expected the first installment to be charged, got %+v", runs)
+	}
+	if runs := p.RunDueMandates(now); len(runs) != 0 {
+		t.Errorf("Expected nothing due until next month, got %+v", runs)
+	}
+
+	next := mandate.NextChargeDate
+	if days := next.Sub(mandate.StartDate).Hours() / 24; days < 28 || days > 31 {
+		t.Errorf("Expected the next installment a month after %v, got %v", mandate.StartDate, next)
+	}
+	if runs := p.RunDueMandates(next); len(runs) != 1 || !runs[0].Success {
+		t.Fatalf("Expected the second installment to be charged, got %+v", runs)
+	}
+
+	history := p.Donors["DONOR001"].DonationHistory
+	if len(history) != 2 || history[0].MandateID != mandate.MandateID || !history[1].Amount.Equal(money.INR(49500)) {
+		t.Errorf("Expected two ₹495 donations made by the mandate, got %+v", history)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	restored, exists := reloaded.Donors["DONOR001"].GetMandate(mandate.MandateID)
+	if !exists {
+		t.Fatal("Expected the mandate to survive a reload")
+	}
+	if restored.ChargedCount != 2 || restored.Installment != 3 || !restored.NextChargeDate.Equal(mandate.NextChargeDate) {
+		t.Errorf("Expected two charges and the third installment scheduled after reload, got %+v", restored)
+	}
+
+	receipts, err := reloaded.GetAnnualReceipts("DONOR001", now.Year())
+	if err != nil {
+		t.Fatalf("Failed to get annual receipts: %v", err)
+	}
+	if len(receipts) != 1 || receipts[0].NGOName != "Test NGO" || receipts[0].Signature == "" {
+		t.Fatalf("Expected one signed receipt for the NGO, got %+v", receipts)
+	}
+	if len(receipts[0].Donations) != 2 || !receipts[0].TotalDonated.Equal(money.INR(99000)) || receipts[0].Donations[0].MandateID != mandate.MandateID {
+		t.Errorf("Expected both installments on the receipt, got %+v", receipts[0])
+	}
+}
+
+func TestMandateRetriesRespectDonationLimit(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	p.MandateRetryPolicy = MandateRetryPolicy{MaxRetries: 1, RetryDelay: time.Hour}
+
+	p.Donors["DONOR001"].AnnualDonationLimit = money.INR(150000)
+
+	mandate, err := p.CreateMandate("DONOR001", "NGO001", "", money.INR(100000), payments.MethodCard, entities.FrequencyQuarterly, time.Time{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create mandate: %v", err)
+	}
+	if runs := p.RunDueMandates(time.Now()); len(runs) != 1 || !runs[0].Success {
+		t.Fatalf("Expected the first installment to be charged, got %+v", runs)
+	}
+
+	second := mandate.NextChargeDate
+	runs := p.RunDueMandates(second)
+	if len(runs) != 1 || runs[0].Success || runs[0].Error == "" {
+		t.Fatalf("Expected the second installment to exceed the annual limit, got %+v", runs)
+	}
+	if mandate.FailedAttempts != 1 || mandate.RetryAt == nil || !mandate.RetryAt.Equal(second.Add(time.Hour)) {
+		t.Errorf("Expected a retry an hour later, got %+v", mandate)
+	}
+
+	if runs := p.RunDueMandates(second.Add(time.Hour)); len(runs) != 1 || runs[0].Success {
+		t.Fatalf("Expected the retry to fail, got %+v", runs)
+	}
+	if mandate.MissedCount != 1 || mandate.Installment != 3 || mandate.RetryAt != nil || mandate.Status != entities.MandateActive {
+		t.Errorf("Expected the installment to be skipped after the last retry, got %+v", mandate)
+	}
+
+	restored, _ := newReloadedPlatform(t, repos).Donors["DONOR001"].GetMandate(mandate.MandateID)
+	if restored == nil || restored.MissedCount != 1 || restored.LastError == "" {
+		t.Errorf("Expected the missed installment to survive a reload, got %+v", restored)
+	}
+}
+
+func TestPauseResumeCancelMandate(t *testing.T) {
+	p, _ := newTestPlatform(t)
+
+	endDate := time.Now().AddDate(1, 0, 0)
+	mandate, err := p.CreateMandate("DONOR001", "NGO001", "", money.INR(50000), payments.MethodUPI, entities.FrequencyMonthly, time.Time{}, &endDate)
+	if err != nil {
+		t.Fatalf("Failed to create mandate: %v", err)
+	}
+
+	if _, err := p.PauseMandate("DONOR002", mandate.MandateID); err == nil {
+		t.Error("Expected another donor's pause to be rejected")
+	}
+	if _, err := p.PauseMandate("DONOR001", mandate.MandateID); err != nil {
+		t.Fatalf("Failed to pause mandate: %v", err)
+	}
+	if runs := p.RunDueMandates(time.Now()); len(runs) != 0 {
+		t.Errorf("Expected a paused mandate not to be charged, got %+v", runs)
+	}
+
+	if _, err := p.ResumeMandate("DONOR001", mandate.MandateID); err != nil {
+		t.Fatalf("Failed to resume mandate: %v", err)
+	}
+	if mandate.Installment != 2 || !mandate.NextChargeDate.After(time.Now()) {
+		t.Errorf("Expected the installment missed while paused to be skipped, got %+v", mandate)
+	}
+
+	if _, err := p.CancelMandate("DONOR001", mandate.MandateID); err != nil {
+		t.Fatalf("Failed to cancel mandate: %v", err)
+	}
+	if _, err := p.ResumeMandate("DONOR001", mandate.MandateID); err == nil {
+		t.Error("Expected a cancelled mandate not to resume")
+	}
+	if runs := p.RunDueMandates(endDate); len(runs) != 0 {
+		t.Errorf("Expected a cancelled mandate not to be charged, got %+v", runs)
+	}
+}
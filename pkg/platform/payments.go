@@ -0,0 +1,295 @@
+package platform
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// Payment intent statuses
+const (
+	PaymentIntentCreated   = "created"
+	PaymentIntentSucceeded = "succeeded"
+	PaymentIntentFailed    = "failed"
+)
+
+// ErrPaymentIntentNotFound is returned for an intent or gateway order the
+// platform does not know
+var ErrPaymentIntentNotFound = errors.New("payment intent not found")
+
+// PaymentIntent is a donation awaiting payment through the gateway. The
+// donation is only mined once the gateway confirms the payment.
+type PaymentIntent struct {
+	IntentID         string      `json:"intent_id"`
+	DonorID          string      `json:"donor_id"`
+	NGOID            string      `json:"ngo_id"`
+	CampaignID       string      `json:"campaign_id,omitempty"`
+	Amount           money.Money `json:"amount"` // Gross amount charged to the donor
+	PlatformFee      money.Money `json:"platform_fee"`
+	NetAmount        money.Money `json:"net_amount"`
+	PaymentMethod    string      `json:"payment_method"`
+	Status           string      `json:"status"`
+	Gateway          string      `json:"gateway"`
+	GatewayOrderID   string      `json:"gateway_order_id"`
+	GatewayPaymentID string      `json:"gateway_payment_id,omitempty"`
+	CheckoutURL      string      `json:"checkout_url,omitempty"`
+	CollectRequestID string      `json:"collect_request_id,omitempty"`
+	TransactionID    string      `json:"transaction_id"`
+	BlockHash        string      `json:"block_hash,omitempty"`
+	FailureReason    string      `json:"failure_reason,omitempty"`
+	RefundID         string      `json:"refund_id,omitempty"` // Set when a captured payment was refunded instead of mined
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+	donation         *transactions.DonationTransaction
+}
+
+// InitializePayments sets the gateway donations are collected through
+func (p *NGOTransparencyPlatform) InitializePayments(gateway payments.Gateway) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.PaymentGateway = gateway
+}
+
+// CreatePaymentIntent opens a gateway order for a donation, earmarked to one
+// of the NGO's campaigns unless campaignID is empty. The donor is sent to the
+// returned checkout URL, or approves a UPI collect request when a VPA is
+// given; the donation is recorded when the gateway's webhook arrives.
+func (p *NGOTransparencyPlatform) CreatePaymentIntent(donorID, ngoID, campaignID string, amount money.Money, paymentMethod, vpa string) (*PaymentIntent, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.createPaymentIntent("", donorID, ngoID, campaignID, amount, paymentMethod, vpa)
+}
+
+// createPaymentIntent opens a gateway order for a donation recorded under
+// transactionID, or under a fresh ID when transactionID is empty
+func (p *NGOTransparencyPlatform) createPaymentIntent(transactionID, donorID, ngoID, campaignID string, amount money.Money, paymentMethod, vpa string) (*PaymentIntent, error) {
+	if p.PaymentGateway == nil {
+		return nil, fmt.Errorf("payment gateway not configured")
+	}
+	if !payments.ValidMethod(paymentMethod) {
+		return nil, fmt.Errorf("unsupported payment method: %s", paymentMethod)
+	}
+
+	donor, ngo, err := p.checkDonation(donorID, ngoID, amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCampaignOpen(ngo, campaignID); err != nil {
+		return nil, err
+	}
+
+	platformFee, netAmount, err := p.splitDonation(amount)
+	if err != nil {
+		return nil, err
+	}
+	donation := newDonation(transactionID, donorID, ngoID, netAmount, paymentMethod, donor.KYCData.DocumentHash)
+	donation.CampaignID = campaignID
+
+	intentID := generatePaymentIntentID()
+	order, err := p.PaymentGateway.CreateOrder(payments.OrderRequest{
+		Reference: intentID,
+		Amount:    amount,
+		Method:    paymentMethod,
+		VPA:       vpa,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment order: %w", err)
+	}
+
+	intent := &PaymentIntent{
+		IntentID:         intentID,
+		DonorID:          donorID,
+		NGOID:            ngoID,
+		CampaignID:       campaignID,
+		Amount:           amount,
+		PlatformFee:      platformFee,
+		NetAmount:        netAmount,
+		PaymentMethod:    paymentMethod,
+		Status:           PaymentIntentCreated,
+		Gateway:          p.PaymentGateway.Name(),
+		GatewayOrderID:   order.OrderID,
+		CheckoutURL:      order.CheckoutURL,
+		CollectRequestID: order.CollectRequestID,
+		TransactionID:    donation.TransactionID,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+		donation:         donation,
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		model, err := paymentIntentToModel(intent)
+		if err != nil {
+			return err
+		}
+		return tx.Payments.Create(model)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist payment intent: %w", err)
+	}
+
+	p.PaymentIntents[intent.IntentID] = intent
+	p.paymentOrders[intent.GatewayOrderID] = intent.IntentID
+
+	return intent, nil
+}
+
+// GetPaymentIntent returns a payment intent by ID
+func (p *NGOTransparencyPlatform) GetPaymentIntent(intentID string) (*PaymentIntent, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	intent, exists := p.PaymentIntents[intentID]
+	if !exists {
+		return nil, ErrPaymentIntentNotFound
+	}
+	return intent, nil
+}
+
+// HandlePaymentWebhook verifies and applies a gateway webhook. A captured
+// payment mines the pending donation, or is refunded when it no longer
+// passes the donation checks; a failed one marks it failed; a
+// chargeback appends a reversal of the mined donation. Repeated
+// deliveries of an event for a settled intent are acknowledged unchanged.
+// An error wrapping payments.ErrInvalidSignature means the webhook is not
+// authentic; other errors are worth the gateway retrying.
+func (p *NGOTransparencyPlatform) HandlePaymentWebhook(payload []byte, signature string) (*PaymentIntent, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.PaymentGateway == nil {
+		return nil, fmt.Errorf("payment gateway not configured")
+	}
+
+	event, err := p.PaymentGateway.ParseWebhook(payload, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	intentID, exists := p.paymentOrders[event.OrderID]
+	if !exists {
+		return nil, fmt.Errorf("%w for order %s", ErrPaymentIntentNotFound, event.OrderID)
+	}
+	intent := p.PaymentIntents[intentID]
+	if event.Type == payments.EventPaymentChargeback {
+		return intent, p.chargebackPaymentIntent(intent, event)
+	}
+	if intent.Status != PaymentIntentCreated {
+		return intent, nil
+	}
+
+	switch event.Type {
+	case payments.EventPaymentCaptured:
+		if !event.Amount.Equal(intent.Amount) {
+			return intent, p.refundPaymentIntent(intent, event, fmt.Sprintf("captured amount ₹%s does not match intent amount ₹%s", event.Amount, intent.Amount))
+		}
+
+		// Limits and KYC are checked again as they may have changed since the intent was created
+		donor, ngo, err := p.checkDonation(intent.DonorID, intent.NGOID, intent.Amount)
+		if err != nil {
+			return intent, p.refundPaymentIntent(intent, event, err.Error())
+		}
+
+		// Mine a copy so a failed attempt leaves the pending donation untouched for a retry
+		donation := *intent.donation
+		settled := *intent
+		settled.Status = PaymentIntentSucceeded
+		settled.GatewayPaymentID = event.PaymentID
+		settled.UpdatedAt = time.Now()
+		settled.donation = &donation
+
+		_, err = p.recordDonation(donor, ngo, &donation, intent.Amount, intent.PlatformFee, func(tx *database.Repositories, blockHash string) error {
+			settled.BlockHash = blockHash
+			return savePaymentIntent(tx, &settled)
+		})
+		if err != nil {
+			return nil, err
+		}
+		*intent = settled
+
+	case payments.EventPaymentFailed:
+		reason := event.FailureReason
+		if reason == "" {
+			reason = "payment failed"
+		}
+		if err := p.failPaymentIntent(intent, event, reason); err != nil {
+			return nil, err
+		}
+	}
+
+	return intent, nil
+}
+
+// refundPaymentIntent returns a captured payment the platform cannot accept
+// to the donor and then marks the intent failed. Should the gateway refuse
+// the refund the intent is left pending, so the webhook is retried.
+func (p *NGOTransparencyPlatform) refundPaymentIntent(intent *PaymentIntent, event *payments.WebhookEvent, reason string) error {
+	// A refund issued on an earlier delivery is not repeated
+	if intent.RefundID == "" {
+		refund, err := p.PaymentGateway.Refund(payments.RefundRequest{
+			OrderID:   event.OrderID,
+			PaymentID: event.PaymentID,
+			Amount:    event.Amount,
+			Reason:    reason,
+		})
+		if err != nil {
+			return fmt.Errorf("gateway refund of rejected payment failed: %w", err)
+		}
+		intent.RefundID = refund.RefundID
+	}
+
+	if err := p.failPaymentIntent(intent, event, reason); err != nil {
+		return fmt.Errorf("gateway refund %s issued but not recorded: %w", intent.RefundID, err)
+	}
+	return nil
+}
+
+// failPaymentIntent marks the intent and its pending donation failed. The
+// failed donation is stored so the attempt remains on record.
+func (p *NGOTransparencyPlatform) failPaymentIntent(intent *PaymentIntent, event *payments.WebhookEvent, reason string) error {
+	donation := *intent.donation
+	donation.MarkFailed(reason)
+
+	failed := *intent
+	failed.Status = PaymentIntentFailed
+	failed.GatewayPaymentID = event.PaymentID
+	failed.FailureReason = reason
+	failed.UpdatedAt = time.Now()
+	failed.donation = &donation
+
+	err := p.persist(func(tx *database.Repositories) error {
+		donationModel, err := donationToModel(&donation, intent.Amount, intent.PlatformFee, "", "")
+		if err != nil {
+			return err
+		}
+		if err := tx.Donations.Create(donationModel); err != nil {
+			return err
+		}
+		return savePaymentIntent(tx, &failed)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist failed payment: %w", err)
+	}
+
+	*intent = failed
+	return nil
+}
+
+// IsInvalidWebhook reports whether err means a webhook failed verification
+func IsInvalidWebhook(err error) bool {
+	return errors.Is(err, payments.ErrInvalidSignature) || errors.Is(err, payments.ErrStaleWebhook)
+}
+
+func generatePaymentIntentID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "PI_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,210 @@
+package platform
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+)
+
+// newPaymentsPlatform returns the test platform collecting donations through a fake gateway
+func newPaymentsPlatform(t *testing.T) (*NGOTransparencyPlatform, *database.Repositories, *payments.FakeGateway) {
+	t.Helper()
+
+	p, repos := newTestPlatform(t)
+	gateway := payments.NewFakeGateway("whsec_test", "http://localhost/checkout")
+	p.InitializePayments(gateway)
+	return p, repos, gateway
+}
+
+func TestPaymentCapturedMinesDonation(t *testing.T) {
+	p, repos, gateway := newPaymentsPlatform(t)
+
+	intent, err := p.CreatePaymentIntent("DONOR001", "NGO001", "", money.INR(100000), payments.MethodUPI, "donor@upi")
+	if err != nil {
+		t.Fatalf("Failed to create payment intent: %v", err)
+	}
+	if intent.Status != PaymentIntentCreated || intent.CollectRequestID == "" || intent.NetAmount.Minor() != 99000 {
+		t.Fatalf("Unexpected intent: %+v", intent)
+	}
+	if p.NGOs["NGO001"].DonationBlockchain.GetChainLength() != 1 {
+		t.Fatal("Expected nothing to be mined before payment")
+	}
+
+	payload, signature, err := gateway.Capture(intent.GatewayOrderID)
+	if err != nil {
+		t.Fatalf("Failed to capture payment: %v", err)
+	}
+
+	if _, err := p.HandlePaymentWebhook(payload, "t=1,v1=forged"); !IsInvalidWebhook(err) {
+		t.Fatalf("Expected forged webhook to be rejected, got %v", err)
+	}
+
+	settled, err := p.HandlePaymentWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("Failed to handle webhook: %v", err)
+	}
+	if settled.Status != PaymentIntentSucceeded || settled.BlockHash == "" || settled.GatewayPaymentID == "" {
+		t.Fatalf("Expected succeeded intent with block, got %+v", settled)
+	}
+
+	// Gateways deliver at least once; a repeat must not mine twice
+	if _, err := p.HandlePaymentWebhook(payload, signature); err != nil {
+		t.Fatalf("Failed to handle repeated webhook: %v", err)
+	}
+	ngo := p.NGOs["NGO001"]
+	if ngo.DonationBlockchain.GetChainLength() != 2 || !ngo.TotalDonationsReceived.Equal(money.INR(99000)) {
+		t.Errorf("Expected one mined donation of ₹990, got %d blocks and ₹%s",
+			ngo.DonationBlockchain.GetChainLength(), ngo.TotalDonationsReceived)
+	}
+
+	donation, err := repos.Donations.GetByTransactionID(intent.TransactionID)
+	if err != nil || donation.Status != "completed" || donation.BlockHash != settled.BlockHash {
+		t.Errorf("Expected completed donation stored under the intent's transaction, got %+v", donation)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	stored, err := reloaded.GetPaymentIntent(intent.IntentID)
+	if err != nil || stored.Status != PaymentIntentSucceeded || stored.BlockHash != settled.BlockHash {
+		t.Errorf("Expected settled intent after reload, got %+v", stored)
+	}
+}
+
+func TestPaymentFailedMarksDonationFailed(t *testing.T) {
+	p, repos, gateway := newPaymentsPlatform(t)
+
+	intent, err := p.CreatePaymentIntent("DONOR001", "NGO001", "", money.INR(50000), payments.MethodCard, "")
+	if err != nil {
+		t.Fatalf("Failed to create payment intent: %v", err)
+	}
+	if intent.CheckoutURL == "" {
+		t.Fatalf("Expected a checkout redirect, got %+v", intent)
+	}
+
+	payload, signature, _ := gateway.Fail(intent.GatewayOrderID, "card declined")
+	failed, err := p.HandlePaymentWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("Failed to handle webhook: %v", err)
+	}
+	if failed.Status != PaymentIntentFailed || failed.FailureReason != "card declined" {
+		t.Errorf("Expected failed intent, got %+v", failed)
+	}
+	if p.NGOs["NGO001"].DonationBlockchain.GetChainLength() != 1 || p.SystemStats.TotalTransactions != 0 {
+		t.Error("Expected a failed payment not to be mined")
+	}
+
+	donation, err := repos.Donations.GetByTransactionID(intent.TransactionID)
+	if err != nil || donation.Status != "failed" {
+		t.Errorf("Expected failed donation on record, got %+v", donation)
+	}
+}
+
+func TestPendingPaymentSurvivesRestart(t *testing.T) {
+	p, repos, gateway := newPaymentsPlatform(t)
+
+	intent, err := p.CreatePaymentIntent("DONOR001", "NGO001", "", money.INR(25000), payments.MethodNetBanking, "")
+	if err != nil {
+		t.Fatalf("Failed to create payment intent: %v", err)
+	}
+
+	// The webhook arrives after a restart
+	reloaded := newReloadedPlatform(t, repos)
+	reloaded.InitializePayments(gateway)
+
+	payload, signature, _ := gateway.Capture(intent.GatewayOrderID)
+	settled, err := reloaded.HandlePaymentWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("Failed to handle webhook after restart: %v", err)
+	}
+	if settled.Status != PaymentIntentSucceeded || settled.TransactionID != intent.TransactionID {
+		t.Errorf("Expected pending donation to be mined after restart, got %+v", settled)
+	}
+	if reloaded.NGOs["NGO001"].DonationBlockchain.GetChainLength() != 2 {
+		t.Error("Expected donation block after restart")
+	}
+}
+
+func TestPaymentAmountMismatchIsNotMined(t *testing.T) {
+	p, repos, gateway := newPaymentsPlatform(t)
+
+	intent, err := p.CreatePaymentIntent("DONOR001", "NGO001", "", money.INR(100000), payments.MethodCard, "")
+	if err != nil {
+		t.Fatalf("Failed to create payment intent: %v", err)
+	}
+
+	payload, _, err := gateway.Capture(intent.GatewayOrderID)
+	if err != nil {
+		t.Fatalf("Failed to capture payment: %v", err)
+	}
+	var event payments.WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("Failed to decode webhook: %v", err)
+	}
+	event.Amount = money.INR(100)
+	underpaid, _ := json.Marshal(event)
+
+	settled, err := p.HandlePaymentWebhook(underpaid, gateway.Sign(underpaid))
+	if err != nil {
+		t.Fatalf("Failed to handle webhook: %v", err)
+	}
+	if settled.Status != PaymentIntentFailed || p.NGOs["NGO001"].DonationBlockchain.GetChainLength() != 1 {
+		t.Errorf("Expected underpaid intent to fail without mining, got %+v", settled)
+	}
+	if settled.RefundID == "" {
+		t.Error("Expected the captured amount to be refunded")
+	}
+	stored, err := repos.Payments.GetByIntentID(intent.IntentID)
+	if err != nil || stored.RefundID != settled.RefundID {
+		t.Errorf("Expected the refund to be stored with the intent, got %+v", stored)
+	}
+}
+
+func TestRejectedCaptureIsRefunded(t *testing.T) {
+	p, _, gateway := newPaymentsPlatform(t)
+
+	intent, err := p.CreatePaymentIntent("DONOR001", "NGO001", "", money.INR(100000), payments.MethodUPI, "")
+	if err != nil {
+		t.Fatalf("Failed to create payment intent: %v", err)
+	}
+
+	// The donor loses KYC while the payment is in flight
+	p.Donors["DONOR001"].KYCVerified = false
+
+	payload, signature, err := gateway.Capture(intent.GatewayOrderID)
+	if err != nil {
+		t.Fatalf("Failed to capture payment: %v", err)
+	}
+	settled, err := p.HandlePaymentWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("Failed to handle webhook: %v", err)
+	}
+	if settled.Status != PaymentIntentFailed || settled.RefundID == "" {
+		t.Fatalf("Expected a refunded, failed intent, got %+v", settled)
+	}
+	if p.NGOs["NGO001"].DonationBlockchain.GetChainLength() != 1 {
+		t.Error("Expected a rejected payment not to be mined")
+	}
+
+	// The whole payment went back to the donor, and a redelivery refunds nothing more
+	if _, err := gateway.Refund(payments.RefundRequest{OrderID: intent.GatewayOrderID, Amount: money.INR(1)}); err == nil {
+		t.Error("Expected the gateway to hold nothing left to refund")
+	}
+	if again, err := p.HandlePaymentWebhook(payload, signature); err != nil || again.RefundID != settled.RefundID {
+		t.Errorf("Expected a redelivered webhook to be acknowledged unchanged, got %+v, %v", again, err)
+	}
+}
+
+func TestWebhookForUnknownOrder(t *testing.T) {
+	p, _, gateway := newPaymentsPlatform(t)
+
+	event := []byte(`{"event_id":"evt_1","type":"payment.captured","order_id":"order_unknown","payment_id":"pay_1","amount":1000}`)
+	if _, err := p.HandlePaymentWebhook(event, gateway.Sign(event)); !errors.Is(err, ErrPaymentIntentNotFound) {
+		t.Errorf("Expected ErrPaymentIntentNotFound, got %v", err)
+	}
+	if _, err := p.GetPaymentIntent("pi_unknown"); !errors.Is(err, ErrPaymentIntentNotFound) {
+		t.Errorf("Expected ErrPaymentIntentNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,1278 @@
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/crypto"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/gst"
+	"ngo-transparency-platform/pkg/ledger"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/storage"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// chainDifficulty is the proof-of-work difficulty used for NGO chains
+const chainDifficulty = 2
+
+// AttachRepositories makes the given repositories the system of record for the
+// platform. All NGOs, donors and auditors are loaded into the in-memory maps,
+// which from then on act as a cache that is written through on every change.
+func (p *NGOTransparencyPlatform) AttachRepositories(repos *database.Repositories) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var ngoModels []database.NGOModel
+	if err := repos.NGOs.List(&ngoModels, 0, 0); err != nil {
+		return fmt.Errorf("failed to load NGOs: %w", err)
+	}
+	var donorModels []database.DonorModel
+	if err := repos.Donors.List(&donorModels, 0, 0); err != nil {
+		return fmt.Errorf("failed to load donors: %w", err)
+	}
+	var auditorModels []database.AuditorModel
+	if err := repos.Auditors.List(&auditorModels, 0, 0); err != nil {
+		return fmt.Errorf("failed to load auditors: %w", err)
+	}
+
+	ngos := make(map[string]*entities.NGO, len(ngoModels))
+	for i := range ngoModels {
+		ngo, err := loadNGO(repos, &ngoModels[i])
+		if err != nil {
+			return err
+		}
+		ngos[ngo.NGOID] = ngo
+	}
+
+	donors := make(map[string]*entities.Donor, len(donorModels))
+	for i := range donorModels {
+		donor, err := loadDonor(repos, &donorModels[i])
+		if err != nil {
+			return err
+		}
+		donors[donor.DonorID] = donor
+	}
+
+	auditors := make(map[string]*entities.Auditor, len(auditorModels))
+	for i := range auditorModels {
+		auditor, err := loadAuditor(repos, &auditorModels[i])
+		if err != nil {
+			return err
+		}
+		auditors[auditor.AuditorID] = auditor
+	}
+
+	books, err := loadLedger(repos, ngos)
+	if err != nil {
+		return err
+	}
+
+	var intentModels []database.PaymentIntentModel
+	if err := repos.Payments.List(&intentModels, 0, 0); err != nil {
+		return fmt.Errorf("failed to load payment intents: %w", err)
+	}
+	intents := make(map[string]*PaymentIntent, len(intentModels))
+	orders := make(map[string]string, len(intentModels))
+	for i := range intentModels {
+		intent, err := paymentIntentFromModel(&intentModels[i])
+		if err != nil {
+			return fmt.Errorf("failed to load payment intent %s: %w", intentModels[i].IntentID, err)
+		}
+		intents[intent.IntentID] = intent
+		orders[intent.GatewayOrderID] = intent.IntentID
+	}
+
+	donationIndex, err := loadDonationIndex(repos, ngos)
+	if err != nil {
+		return err
+	}
+
+	pendingModels, err := repos.Expenditures.GetAwaitingReview()
+	if err != nil {
+		return fmt.Errorf("failed to load expenditures under review: %w", err)
+	}
+	pending := make(map[string]*transactions.ExpenditureTransaction, len(pendingModels))
+	for i := range pendingModels {
+		expenditure, err := expenditureFromModel(&pendingModels[i])
+		if err != nil {
+			return fmt.Errorf("failed to load expenditure %s: %w", pendingModels[i].TransactionID, err)
+		}
+		pending[expenditure.TransactionID] = expenditure
+	}
+
+	rejectedModels, err := repos.Expenditures.GetRejected()
+	if err != nil {
+		return fmt.Errorf("failed to load rejected expenditures: %w", err)
+	}
+	rejected := make(map[string]*transactions.ExpenditureTransaction, len(rejectedModels))
+	for i := range rejectedModels {
+		expenditure, err := expenditureFromModel(&rejectedModels[i])
+		if err != nil {
+			return fmt.Errorf("failed to load expenditure %s: %w", rejectedModels[i].TransactionID, err)
+		}
+		rejected[expenditure.TransactionID] = expenditure
+	}
+
+	documentModels, err := repos.Documents.GetDocuments()
+	if err != nil {
+		return fmt.Errorf("failed to load documents: %w", err)
+	}
+	documents := make(map[string][]*storage.Document, len(documentModels))
+	for i := range documentModels {
+		document := documentFromModel(&documentModels[i])
+		documents[document.Hash] = append(documents[document.Hash], document)
+	}
+
+	assignmentModels, err := repos.Assignments.GetAssignments()
+	if err != nil {
+		return fmt.Errorf("failed to load auditor assignments: %w", err)
+	}
+	assignments := make([]*AuditorAssignment, 0, len(assignmentModels))
+	for i := range assignmentModels {
+		assignments = append(assignments, assignmentFromModel(&assignmentModels[i]))
+	}
+
+	snapshotModels, err := repos.Ratings.GetSnapshots()
+	if err != nil {
+		return fmt.Errorf("failed to load rating history: %w", err)
+	}
+	ratingHistory := make(map[string][]*RatingSnapshot)
+	for i := range snapshotModels {
+		snapshot, err := ratingSnapshotFromModel(&snapshotModels[i])
+		if err != nil {
+			return fmt.Errorf("failed to load rating of NGO %s: %w", snapshotModels[i].NGOID, err)
+		}
+		ratingHistory[snapshot.NGOID] = append(ratingHistory[snapshot.NGOID], snapshot)
+	}
+
+	var vendorModels []database.VendorModel
+	if err := repos.Vendors.List(&vendorModels, 0, 0); err != nil {
+		return fmt.Errorf("failed to load vendors: %w", err)
+	}
+	vendors := make(map[string]*entities.Vendor, len(vendorModels))
+	for i := range vendorModels {
+		vendor := vendorFromModel(&vendorModels[i])
+		vendors[vendor.GSTIN] = vendor
+	}
+
+	p.repos = repos
+	p.Ledger = books
+	p.NGOs = ngos
+	p.Donors = donors
+	p.Auditors = auditors
+	p.PaymentIntents = intents
+	p.paymentOrders = orders
+	p.donations = donationIndex
+	p.Vendors = vendors
+	p.PendingExpenditures = pending
+	p.RejectedExpenditures = rejected
+	p.documents = documents
+	p.Assignments = assignments
+	p.ratingHistory = ratingHistory
+	p.invoices = buildInvoiceIndex(ngos)
+	p.rebuildSystemStats()
+
+	return p.restoreAnchors()
+}
+
+// persist runs fn in a single database transaction when repositories are attached
+func (p *NGOTransparencyPlatform) persist(fn func(tx *database.Repositories) error) error {
+	if p.repos == nil {
+		return nil
+	}
+	return p.repos.Transaction(fn)
+}
+
+// rebuildSystemStats recomputes platform statistics from the cached entities
+func (p *NGOTransparencyPlatform) rebuildSystemStats() {
+	stats := SystemStats{
+		TotalDonations:    money.Zero(),
+		TotalExpenditures: money.Zero(),
+		TotalPlatformFees: money.Zero(),
+		PlatformFee:       p.SystemStats.PlatformFee,
+		CreatedAt:         time.Now(),
+	}
+
+	if p.repos != nil {
+		if fees, err := p.repos.Donations.SumPlatformFees(); err == nil {
+			stats.TotalPlatformFees = fees
+		}
+	}
+
+	for _, ngo := range p.NGOs {
+		stats.TotalDonations = stats.TotalDonations.Add(ngo.TotalDonationsReceived)
+		stats.TotalExpenditures = stats.TotalExpenditures.Add(ngo.TotalExpenditureReported)
+		stats.TotalTransactions += ngo.DonationBlockchain.GetChainLength() - 1
+		stats.TotalTransactions += ngo.ExpenditureBlockchain.GetChainLength() - 1
+		if ngo.CreatedAt.Before(stats.CreatedAt) {
+			stats.CreatedAt = ngo.CreatedAt
+		}
+		if ngo.KYCData.Verified && ngo.KYCData.VerificationAuthority != "" {
+			p.KYCAuthorities[ngo.KYCData.VerificationAuthority] = true
+		}
+	}
+
+	for _, donor := range p.Donors {
+		if donor.CreatedAt.Before(stats.CreatedAt) {
+			stats.CreatedAt = donor.CreatedAt
+		}
+		if donor.KYCVerified && donor.KYCData.VerificationAuthority != "" {
+			p.KYCAuthorities[donor.KYCData.VerificationAuthority] = true
+		}
+	}
+
+	p.SystemStats = stats
+}
+
+// reloadNGO replaces the cached NGO with its stored state after a failed write
+func (p *NGOTransparencyPlatform) reloadNGO(ngoID string) {
+	if p.repos == nil {
+		return
+	}
+
+	model, err := p.repos.NGOs.GetByNGOID(ngoID)
+	if err != nil {
+		delete(p.NGOs, ngoID)
+		return
+	}
+	if ngo, err := loadNGO(p.repos, model); err == nil {
+		p.NGOs[ngoID] = ngo
+	} else {
+		delete(p.NGOs, ngoID)
+	}
+}
+
+// reloadDonor replaces the cached donor with its stored state after a failed write
+func (p *NGOTransparencyPlatform) reloadDonor(donorID string) {
+	if p.repos == nil {
+		return
+	}
+
+	model, err := p.repos.Donors.GetByDonorID(donorID)
+	if err != nil {
+		delete(p.Donors, donorID)
+		return
+	}
+	if donor, err := loadDonor(p.repos, model); err == nil {
+		p.Donors[donorID] = donor
+	} else {
+		delete(p.Donors, donorID)
+	}
+}
+
+// reloadAuditor replaces the cached auditor with its stored state after a failed write
+func (p *NGOTransparencyPlatform) reloadAuditor(auditorID string) {
+	if p.repos == nil {
+		return
+	}
+
+	model, err := p.repos.Auditors.GetByAuditorID(auditorID)
+	if err != nil {
+		delete(p.Auditors, auditorID)
+		return
+	}
+	if auditor, err := loadAuditor(p.repos, model); err == nil {
+		p.Auditors[auditorID] = auditor
+	} else {
+		delete(p.Auditors, auditorID)
+	}
+}
+
+// Loading
+
+func loadNGO(repos *database.Repositories, model *database.NGOModel) (*entities.NGO, error) {
+	ngo := entities.NewNGO(model.NGOID, model.Name, model.RegistrationNumber, model.Category, nil, nil)
+	ngo.Rating = model.Rating
+	ngo.TotalDonationsReceived = model.TotalDonationsReceived
+	ngo.TotalExpenditureReported = model.TotalExpenditureReported
+	ngo.TransparencyScore = model.TransparencyScore
+	ngo.CreatedAt = model.CreatedAt
+
+	if err := unmarshalField(model.KYCData, &ngo.KYCData); err != nil {
+		return nil, fmt.Errorf("invalid KYC data for NGO %s: %w", model.NGOID, err)
+	}
+	ngo.KYCData.Verified = model.KYCVerified
+
+	if err := unmarshalField(model.Certificates, &ngo.Certificates); err != nil {
+		return nil, fmt.Errorf("invalid certificates for NGO %s: %w", model.NGOID, err)
+	}
+
+	var signers []string
+	if err := unmarshalField(model.MultiSigSigners, &signers); err != nil {
+		return nil, fmt.Errorf("invalid multi-sig signers for NGO %s: %w", model.NGOID, err)
+	}
+	for _, signer := range signers {
+		ngo.MultiSigWallet.AddSigner(signer)
+	}
+
+	for _, chainType := range []string{"donation", "expenditure"} {
+		chain, err := loadChain(repos, model.NGOID, chainType)
+		if err != nil {
+			return nil, err
+		}
+		if chain == nil {
+			// Rows created before blocks were persisted start from the fresh
+			// genesis block, which is stored so later blocks link to it
+			chain = ngo.DonationBlockchain
+			if chainType == "expenditure" {
+				chain = ngo.ExpenditureBlockchain
+			}
+			if err := saveBlock(repos, model.NGOID, chain.GetLatestBlock()); err != nil {
+				return nil, fmt.Errorf("failed to store genesis block of NGO %s: %w", model.NGOID, err)
+			}
+			continue
+		}
+		if chainType == "donation" {
+			ngo.DonationBlockchain = chain
+		} else {
+			ngo.ExpenditureBlockchain = chain
+		}
+	}
+
+	campaigns, err := repos.Campaigns.GetByNGOID(model.NGOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaigns of NGO %s: %w", model.NGOID, err)
+	}
+	for i := range campaigns {
+		campaign := campaignFromModel(&campaigns[i])
+		ngo.Campaigns[campaign.CampaignID] = campaign
+	}
+
+	return ngo, nil
+}
+
+func loadChain(repos *database.Repositories, ngoID, chainType string) (*blockchain.Blockchain, error) {
+	models, err := repos.Blocks.GetChain(ngoID, chainType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s chain of NGO %s: %w", chainType, ngoID, err)
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	blocks := make([]*blockchain.Block, len(models))
+	for i := range models {
+		block, err := blockFromModel(&models[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid block %s: %w", models[i].Hash, err)
+		}
+		blocks[i] = block
+	}
+
+	return blockchain.RestoreBlockchain(ngoID, chainType, chainDifficulty, blocks)
+}
+
+// loadLedger opens books for every NGO and replays the stored journal. NGOs
+// recorded before the ledger existed get their journal rebuilt from the
+// stored donations and expenditures.
+func loadLedger(repos *database.Repositories, ngos map[string]*entities.NGO) (*ledger.Ledger, error) {
+	books := ledger.NewLedger()
+
+	for ngoID := range ngos {
+		books.OpenBooks(ngoID)
+
+		models, err := repos.Journal.GetByNGOID(ngoID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load journal of NGO %s: %w", ngoID, err)
+		}
+
+		entries := make([]*ledger.JournalEntry, len(models))
+		for i := range models {
+			entries[i] = journalEntryFromModel(&models[i])
+		}
+
+		if len(entries) == 0 {
+			if entries, err = rebuildJournal(repos, ngoID); err != nil {
+				return nil, err
+			}
+			if len(entries) > 0 {
+				err := repos.Transaction(func(tx *database.Repositories) error {
+					return saveJournalEntries(tx, entries)
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to store rebuilt journal of NGO %s: %w", ngoID, err)
+				}
+			}
+		}
+
+		if err := books.Post(entries...); err != nil {
+			return nil, fmt.Errorf("invalid journal of NGO %s: %w", ngoID, err)
+		}
+	}
+
+	return books, nil
+}
+
+// rebuildJournal derives journal entries from an NGO's stored transactions
+func rebuildJournal(repos *database.Repositories, ngoID string) ([]*ledger.JournalEntry, error) {
+	donations, err := repos.Donations.GetByNGOID(ngoID, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load donations of NGO %s: %w", ngoID, err)
+	}
+	expenditures, err := repos.Expenditures.GetByNGOID(ngoID, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load expenditures of NGO %s: %w", ngoID, err)
+	}
+
+	var entries []*ledger.JournalEntry
+	for i := len(donations) - 1; i >= 0; i-- {
+		donation := donations[i]
+		if donation.Status != "completed" {
+			continue
+		}
+		entries = append(entries, ledger.DonationEntries(ngoID, donation.TransactionID, donation.Amount, donation.PlatformFee, donation.CreatedAt)...)
+	}
+	for i := len(expenditures) - 1; i >= 0; i-- {
+		expenditure := expenditures[i]
+		if expenditure.Status != "validated" {
+			continue
+		}
+		entries = append(entries, ledger.ExpenditureEntries(ngoID, expenditure.TransactionID, expenditure.Category, expenditure.Amount, expenditure.CreatedAt)...)
+	}
+
+	return entries, nil
+}
+
+func loadDonor(repos *database.Repositories, model *database.DonorModel) (*entities.Donor, error) {
+	donor := entities.NewDonor(model.DonorID, nil)
+	donor.KYCVerified = model.KYCVerified
+	donor.AnnualDonationLimit = model.AnnualDonationLimit
+	donor.CreatedAt = model.CreatedAt
+
+	if err := unmarshalField(model.KYCData, &donor.KYCData); err != nil {
+		return nil, fmt.Errorf("invalid KYC data for donor %s: %w", model.DonorID, err)
+	}
+	if err := unmarshalField(model.PreferredNGOs, &donor.PreferredNGOs); err != nil {
+		return nil, fmt.Errorf("invalid preferred NGOs for donor %s: %w", model.DonorID, err)
+	}
+
+	donations, err := repos.Donations.GetByDonorID(model.DonorID, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load donations of donor %s: %w", model.DonorID, err)
+	}
+
+	// Repository returns newest first; history is kept oldest first
+	for i := len(donations) - 1; i >= 0; i-- {
+		if donations[i].Status != "completed" && donations[i].Status != donationPartiallyReversed {
+			continue
+		}
+		record, err := donationRecordFromModel(&donations[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid donation %s: %w", donations[i].TransactionID, err)
+		}
+		donor.AddDonationRecord(record)
+	}
+
+	mandates, err := repos.Mandates.GetByDonorID(model.DonorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mandates of donor %s: %w", model.DonorID, err)
+	}
+	for i := range mandates {
+		mandate := mandateFromModel(&mandates[i])
+		donor.Mandates[mandate.MandateID] = mandate
+	}
+
+	if donor.Corporate, err = loadCorporateProfile(repos, model.DonorID); err != nil {
+		return nil, err
+	}
+	if donor.EmployerID, err = repos.Corporates.GetEmployer(model.DonorID); err != nil {
+		return nil, fmt.Errorf("failed to load employer of donor %s: %w", model.DonorID, err)
+	}
+
+	return donor, nil
+}
+
+// loadCorporateProfile loads a donor's corporate profile and employees, or
+// returns nil if the donor is not a company
+func loadCorporateProfile(repos *database.Repositories, donorID string) (*entities.CorporateProfile, error) {
+	model, err := repos.Corporates.GetProfile(donorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load corporate profile of donor %s: %w", donorID, err)
+	}
+	if model == nil {
+		return nil, nil
+	}
+
+	profile := &entities.CorporateProfile{
+		CompanyName: model.CompanyName,
+		CIN:         model.CIN,
+		MatchingRule: entities.MatchingRule{
+			Ratio:          model.MatchRatio,
+			PerEmployeeCap: model.PerEmployeeCap,
+			Active:         model.MatchingActive,
+		},
+		Employees: make([]string, 0),
+		CreatedAt: model.CreatedAt,
+	}
+	if err := unmarshalField(model.EligibleCategories, &profile.MatchingRule.EligibleCategories); err != nil {
+		return nil, fmt.Errorf("invalid eligible categories for corporate %s: %w", donorID, err)
+	}
+
+	employees, err := repos.Corporates.GetEmployees(donorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load employees of corporate %s: %w", donorID, err)
+	}
+	for _, employee := range employees {
+		profile.Employees = append(profile.Employees, employee.DonorID)
+	}
+	return profile, nil
+}
+
+func loadAuditor(repos *database.Repositories, model *database.AuditorModel) (*entities.Auditor, error) {
+	var specializations []string
+	if err := unmarshalField(model.Specializations, &specializations); err != nil {
+		return nil, fmt.Errorf("invalid specializations for auditor %s: %w", model.AuditorID, err)
+	}
+
+	var credentials interface{}
+	if err := unmarshalField(model.Credentials, &credentials); err != nil {
+		return nil, fmt.Errorf("invalid credentials for auditor %s: %w", model.AuditorID, err)
+	}
+
+	auditor := entities.NewAuditor(model.AuditorID, model.Name, credentials, specializations)
+	auditor.Verified = model.Verified
+	auditor.Rating = model.Rating
+	auditor.CreatedAt = model.CreatedAt
+	auditor.VerificationAuthority = model.VerificationAuthority
+	auditor.VerificationDate = model.VerificationDate
+
+	conflicts, err := repos.Assignments.GetConflicts(model.AuditorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conflicts of interest of auditor %s: %w", model.AuditorID, err)
+	}
+	for _, conflict := range conflicts {
+		auditor.Conflicts = append(auditor.Conflicts, entities.ConflictOfInterest{
+			NGOID:      conflict.NGOID,
+			Reason:     conflict.Reason,
+			DeclaredAt: conflict.CreatedAt,
+		})
+	}
+
+	audits, err := repos.Audits.GetByAuditorID(model.AuditorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audits of auditor %s: %w", model.AuditorID, err)
+	}
+	for i := range audits {
+		audit, err := auditFromModel(&audits[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit %s: %w", audits[i].AuditID, err)
+		}
+		auditor.AuditHistory = append(auditor.AuditHistory, audit)
+	}
+
+	return auditor, nil
+}
+
+// Conversion to database models
+
+func ngoToModel(ngo *entities.NGO) (*database.NGOModel, error) {
+	model := &database.NGOModel{
+		NGOID:                    ngo.NGOID,
+		Name:                     ngo.Name,
+		RegistrationNumber:       ngo.RegistrationNumber,
+		Category:                 ngo.Category,
+		Rating:                   ngo.Rating,
+		KYCVerified:              ngo.KYCData.Verified,
+		TotalDonationsReceived:   ngo.TotalDonationsReceived,
+		TotalExpenditureReported: ngo.TotalExpenditureReported,
+		TransparencyScore:        ngo.TransparencyScore,
+		PublicKey:                ngo.PublicKey,
+	}
+
+	var err error
+	if model.KYCData, err = marshalField(ngo.KYCData); err != nil {
+		return nil, err
+	}
+	if model.Certificates, err = marshalField(ngo.Certificates); err != nil {
+		return nil, err
+	}
+	if model.MultiSigSigners, err = marshalField(ngo.MultiSigWallet.GetSigners()); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func donorToModel(donor *entities.Donor) (*database.DonorModel, error) {
+	model := &database.DonorModel{
+		DonorID:             donor.DonorID,
+		KYCVerified:         donor.KYCVerified,
+		TotalDonated:        donor.TotalDonated,
+		AnnualDonationLimit: donor.AnnualDonationLimit,
+	}
+
+	var err error
+	if model.KYCData, err = marshalField(donor.KYCData); err != nil {
+		return nil, err
+	}
+	if model.PreferredNGOs, err = marshalField(donor.PreferredNGOs); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func auditorToModel(auditor *entities.Auditor) (*database.AuditorModel, error) {
+	model := &database.AuditorModel{
+		AuditorID:             auditor.AuditorID,
+		Name:                  auditor.Name,
+		Verified:              auditor.Verified,
+		Rating:                auditor.Rating,
+		PublicKey:             auditor.PublicKey,
+		VerificationAuthority: auditor.VerificationAuthority,
+		VerificationDate:      auditor.VerificationDate,
+	}
+
+	var err error
+	if model.Credentials, err = marshalField(auditor.Credentials); err != nil {
+		return nil, err
+	}
+	if model.Specializations, err = marshalField(auditor.Specializations); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func blockToModel(ngoID string, block *blockchain.Block) (*database.BlockchainBlockModel, error) {
+	model := &database.BlockchainBlockModel{
+		Index:         block.Index,
+		Hash:          block.Hash,
+		PreviousHash:  block.PreviousHash,
+		BlockType:     block.BlockType,
+		NGOID:         ngoID,
+		MerkleRoot:    block.MerkleRoot,
+		Nonce:         block.Nonce,
+		Validated:     block.Validated,
+		TimestampNano: block.Timestamp.UnixNano(),
+		CreatedAt:     block.Timestamp,
+	}
+
+	var err error
+	if model.Data, err = marshalField(block.Data); err != nil {
+		return nil, err
+	}
+	if model.Validators, err = marshalField(block.Validators); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func blockFromModel(model *database.BlockchainBlockModel) (*blockchain.Block, error) {
+	var data interface{}
+	if err := unmarshalField(model.Data, &data); err != nil {
+		return nil, err
+	}
+
+	var validators []blockchain.Validator
+	if err := unmarshalField(model.Validators, &validators); err != nil {
+		return nil, err
+	}
+
+	timestamp := model.CreatedAt
+	if model.TimestampNano != 0 {
+		timestamp = time.Unix(0, model.TimestampNano)
+	}
+
+	return blockchain.RestoreBlock(model.Index, timestamp, data, model.PreviousHash, model.BlockType,
+		model.Hash, model.MerkleRoot, model.Nonce, model.Validated, validators), nil
+}
+
+func donationToModel(donation *transactions.DonationTransaction, grossAmount, platformFee money.Money, blockHash, polygonTxHash string) (*database.DonationModel, error) {
+	model := &database.DonationModel{
+		TransactionID: donation.TransactionID,
+		DonorID:       donation.DonorID,
+		NGOID:         donation.NGOID,
+		Amount:        grossAmount,
+		PlatformFee:   platformFee,
+		NetAmount:     donation.Amount,
+		PaymentMethod: donation.PaymentMethod,
+		Status:        donation.Status,
+		BlockHash:     blockHash,
+		PolygonTxHash: polygonTxHash,
+		CreatedAt:     donation.Timestamp,
+		CompletedAt:   donation.CompletedAt,
+		CampaignID:    donation.CampaignID,
+		MandateID:     donation.MandateID,
+		MatchFor:      donation.MatchFor,
+	}
+
+	var err error
+	if model.ZKProofData, err = marshalField(donation.ZKProof); err != nil {
+		return nil, err
+	}
+	if model.EBillData, err = marshalField(donation.EBill); err != nil {
+		return nil, err
+	}
+	if donation.EBill != nil {
+		if model.TaxBenefit, err = marshalField(donation.EBill.TaxBenefit); err != nil {
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
+
+func donationRecordFromModel(model *database.DonationModel) (entities.DonationRecord, error) {
+	// Records hold what remains of the donation after refunds and chargebacks
+	reversedNet := model.ReversedAmount.Sub(model.ReversedFee)
+	record := entities.DonationRecord{
+		TransactionID: model.TransactionID,
+		NGOID:         model.NGOID,
+		Amount:        model.NetAmount.Sub(reversedNet),
+		GrossAmount:   model.Amount.Sub(model.ReversedAmount),
+		PlatformFee:   model.PlatformFee.Sub(model.ReversedFee),
+		Timestamp:     model.CreatedAt,
+		CampaignID:    model.CampaignID,
+		MandateID:     model.MandateID,
+		MatchFor:      model.MatchFor,
+	}
+
+	var eBill transactions.EBill
+	if model.EBillData != "" {
+		if err := json.Unmarshal([]byte(model.EBillData), &eBill); err != nil {
+			return record, err
+		}
+		record.EBill = &eBill
+	}
+
+	var zkProof crypto.ZKProof
+	if model.ZKProofData != "" {
+		if err := json.Unmarshal([]byte(model.ZKProofData), &zkProof); err != nil {
+			return record, err
+		}
+		record.ZKProof = &zkProof
+	}
+
+	if err := unmarshalField(model.TaxBenefit, &record.TaxBenefit); err != nil {
+		return record, err
+	}
+
+	return record, nil
+}
+
+func reversalToModel(reversal *transactions.DonationReversal) (*database.DonationReversalModel, error) {
+	model := &database.DonationReversalModel{
+		ReversalID:        reversal.ReversalID,
+		Kind:              reversal.Kind,
+		TransactionID:     reversal.TransactionID,
+		DonorID:           reversal.DonorID,
+		NGOID:             reversal.NGOID,
+		GrossAmount:       reversal.GrossAmount,
+		PlatformFee:       reversal.PlatformFee,
+		NetAmount:         reversal.Amount,
+		Reason:            reversal.Reason,
+		GatewayReference:  reversal.GatewayReference,
+		OriginalBlockHash: reversal.OriginalBlockHash,
+		BlockHash:         reversal.BlockHash,
+		VoidedBillID:      reversal.VoidedBillID,
+		CreatedAt:         reversal.Timestamp,
+	}
+
+	if reversal.ReissuedEBill != nil {
+		var err error
+		if model.ReissuedEBill, err = marshalField(reversal.ReissuedEBill); err != nil {
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
+
+func expenditureToModel(expenditure *transactions.ExpenditureTransaction, blockHash, polygonTxHash string) (*database.ExpenditureModel, error) {
+	model := &database.ExpenditureModel{
+		TransactionID:          expenditure.TransactionID,
+		NGOID:                  expenditure.NGOID,
+		Amount:                 expenditure.Amount,
+		Category:               expenditure.Category,
+		Description:            expenditure.Description,
+		Status:                 expenditure.Status,
+		ComplianceScore:        expenditure.ComplianceScore,
+		BlockHash:              blockHash,
+		PolygonTxHash:          polygonTxHash,
+		CreatedAt:              expenditure.Timestamp,
+		CampaignID:             expenditure.CampaignID,
+		AssignedAuditorID:      expenditure.AssignedAuditorID,
+		RequiredApprovals:      expenditure.RequiredApprovals,
+		ComplianceRulesVersion: expenditure.ComplianceRulesVersion,
+	}
+
+	var err error
+	if model.InvoiceDetails, err = marshalField(expenditure.InvoiceDetails); err != nil {
+		return nil, err
+	}
+	if model.Attachments, err = marshalField(expenditure.Attachments); err != nil {
+		return nil, err
+	}
+	if model.AuditorValidation, err = marshalField(expenditure.AuditorValidation); err != nil {
+		return nil, err
+	}
+	if model.Funding, err = marshalField(expenditure.Funding); err != nil {
+		return nil, err
+	}
+	if model.InvoiceFlags, err = marshalField(expenditure.InvoiceFlags); err != nil {
+		return nil, err
+	}
+	if model.Reviews, err = marshalField(expenditure.Reviews); err != nil {
+		return nil, err
+	}
+	if model.Panel, err = marshalField(expenditure.Panel); err != nil {
+		return nil, err
+	}
+	if model.Consensus, err = marshalField(expenditure.Consensus); err != nil {
+		return nil, err
+	}
+	if model.Appeal, err = marshalField(expenditure.Appeal); err != nil {
+		return nil, err
+	}
+	if model.Amendment, err = marshalField(expenditure.Amendment); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func expenditureFromModel(model *database.ExpenditureModel) (*transactions.ExpenditureTransaction, error) {
+	expenditure := &transactions.ExpenditureTransaction{
+		TransactionID:          model.TransactionID,
+		NGOID:                  model.NGOID,
+		Amount:                 model.Amount,
+		Category:               model.Category,
+		Description:            model.Description,
+		Timestamp:              model.CreatedAt,
+		Status:                 model.Status,
+		ComplianceScore:        model.ComplianceScore,
+		CampaignID:             model.CampaignID,
+		AssignedAuditorID:      model.AssignedAuditorID,
+		RequiredApprovals:      model.RequiredApprovals,
+		ComplianceRulesVersion: model.ComplianceRulesVersion,
+	}
+
+	if err := unmarshalField(model.InvoiceDetails, &expenditure.InvoiceDetails); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.Attachments, &expenditure.Attachments); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.AuditorValidation, &expenditure.AuditorValidation); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.Funding, &expenditure.Funding); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.InvoiceFlags, &expenditure.InvoiceFlags); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.Reviews, &expenditure.Reviews); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.Panel, &expenditure.Panel); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.Consensus, &expenditure.Consensus); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.Appeal, &expenditure.Appeal); err != nil {
+		return nil, err
+	}
+	if err := unmarshalField(model.Amendment, &expenditure.Amendment); err != nil {
+		return nil, err
+	}
+
+	return expenditure, nil
+}
+
+func paymentIntentToModel(intent *PaymentIntent) (*database.PaymentIntentModel, error) {
+	model := &database.PaymentIntentModel{
+		IntentID:         intent.IntentID,
+		DonorID:          intent.DonorID,
+		NGOID:            intent.NGOID,
+		Amount:           intent.Amount,
+		PlatformFee:      intent.PlatformFee,
+		PaymentMethod:    intent.PaymentMethod,
+		Status:           intent.Status,
+		Gateway:          intent.Gateway,
+		GatewayOrderID:   intent.GatewayOrderID,
+		GatewayPaymentID: intent.GatewayPaymentID,
+		CheckoutURL:      intent.CheckoutURL,
+		CollectRequestID: intent.CollectRequestID,
+		TransactionID:    intent.TransactionID,
+		BlockHash:        intent.BlockHash,
+		FailureReason:    intent.FailureReason,
+		RefundID:         intent.RefundID,
+		CreatedAt:        intent.CreatedAt,
+		UpdatedAt:        intent.UpdatedAt,
+	}
+
+	var err error
+	if model.DonationData, err = marshalField(intent.donation); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func paymentIntentFromModel(model *database.PaymentIntentModel) (*PaymentIntent, error) {
+	intent := &PaymentIntent{
+		IntentID:         model.IntentID,
+		DonorID:          model.DonorID,
+		NGOID:            model.NGOID,
+		Amount:           model.Amount,
+		PlatformFee:      model.PlatformFee,
+		NetAmount:        model.Amount.Sub(model.PlatformFee),
+		PaymentMethod:    model.PaymentMethod,
+		Status:           model.Status,
+		Gateway:          model.Gateway,
+		GatewayOrderID:   model.GatewayOrderID,
+		GatewayPaymentID: model.GatewayPaymentID,
+		CheckoutURL:      model.CheckoutURL,
+		CollectRequestID: model.CollectRequestID,
+		TransactionID:    model.TransactionID,
+		BlockHash:        model.BlockHash,
+		FailureReason:    model.FailureReason,
+		RefundID:         model.RefundID,
+		CreatedAt:        model.CreatedAt,
+		UpdatedAt:        model.UpdatedAt,
+		donation:         &transactions.DonationTransaction{},
+	}
+
+	// The pending donation keeps its original ID, proof and e-bill
+	if err := unmarshalField(model.DonationData, intent.donation); err != nil {
+		return nil, err
+	}
+	intent.CampaignID = intent.donation.CampaignID
+	return intent, nil
+}
+
+func journalEntryToModel(entry *ledger.JournalEntry) *database.JournalEntryModel {
+	model := &database.JournalEntryModel{
+		EntryID:     entry.EntryID,
+		NGOID:       entry.NGOID,
+		Reference:   entry.Reference,
+		Description: entry.Description,
+		PostedAt:    entry.PostedAt,
+	}
+	for i, line := range entry.Lines {
+		model.Lines = append(model.Lines, database.JournalLineModel{
+			EntryID:     entry.EntryID,
+			Position:    i,
+			AccountCode: line.Account,
+			Debit:       line.Debit,
+			Credit:      line.Credit,
+		})
+	}
+	return model
+}
+
+func journalEntryFromModel(model *database.JournalEntryModel) *ledger.JournalEntry {
+	entry := &ledger.JournalEntry{
+		EntryID:     model.EntryID,
+		NGOID:       model.NGOID,
+		Reference:   model.Reference,
+		Description: model.Description,
+		PostedAt:    model.PostedAt,
+	}
+	for _, line := range model.Lines {
+		entry.Lines = append(entry.Lines, ledger.Line{
+			Account: line.AccountCode,
+			Debit:   line.Debit,
+			Credit:  line.Credit,
+		})
+	}
+	return entry
+}
+
+func campaignToModel(campaign *entities.Campaign) *database.CampaignModel {
+	return &database.CampaignModel{
+		CampaignID:   campaign.CampaignID,
+		NGOID:        campaign.NGOID,
+		Name:         campaign.Name,
+		Description:  campaign.Description,
+		GoalAmount:   campaign.GoalAmount,
+		RaisedAmount: campaign.RaisedAmount,
+		SpentAmount:  campaign.SpentAmount,
+		Status:       campaign.Status,
+		StartDate:    campaign.StartDate,
+		EndDate:      campaign.EndDate,
+		ClosedAt:     campaign.ClosedAt,
+		CreatedAt:    campaign.CreatedAt,
+	}
+}
+
+func campaignFromModel(model *database.CampaignModel) *entities.Campaign {
+	return &entities.Campaign{
+		CampaignID:   model.CampaignID,
+		NGOID:        model.NGOID,
+		Name:         model.Name,
+		Description:  model.Description,
+		GoalAmount:   model.GoalAmount,
+		RaisedAmount: model.RaisedAmount,
+		SpentAmount:  model.SpentAmount,
+		Status:       model.Status,
+		StartDate:    model.StartDate,
+		EndDate:      model.EndDate,
+		ClosedAt:     model.ClosedAt,
+		CreatedAt:    model.CreatedAt,
+	}
+}
+
+func corporateProfileToModel(donorID string, profile *entities.CorporateProfile) (*database.CorporateProfileModel, error) {
+	model := &database.CorporateProfileModel{
+		DonorID:        donorID,
+		CompanyName:    profile.CompanyName,
+		CIN:            profile.CIN,
+		MatchRatio:     profile.MatchingRule.Ratio,
+		PerEmployeeCap: profile.MatchingRule.PerEmployeeCap,
+		MatchingActive: profile.MatchingRule.Active,
+		CreatedAt:      profile.CreatedAt,
+	}
+
+	var err error
+	if model.EligibleCategories, err = marshalField(profile.MatchingRule.EligibleCategories); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func vendorToModel(vendor *entities.Vendor) *database.VendorModel {
+	return &database.VendorModel{
+		GSTIN:        vendor.GSTIN,
+		LegalName:    vendor.LegalName,
+		StateCode:    vendor.StateCode,
+		PAN:          vendor.PAN,
+		RegisteredBy: vendor.RegisteredBy,
+		CreatedAt:    vendor.CreatedAt,
+	}
+}
+
+func vendorFromModel(model *database.VendorModel) *entities.Vendor {
+	return &entities.Vendor{
+		GSTIN:        model.GSTIN,
+		LegalName:    model.LegalName,
+		StateCode:    model.StateCode,
+		State:        gst.States[model.StateCode],
+		PAN:          model.PAN,
+		RegisteredBy: model.RegisteredBy,
+		CreatedAt:    model.CreatedAt,
+	}
+}
+
+func mandateToModel(mandate *entities.RecurringMandate) *database.RecurringMandateModel {
+	return &database.RecurringMandateModel{
+		MandateID:      mandate.MandateID,
+		DonorID:        mandate.DonorID,
+		NGOID:          mandate.NGOID,
+		CampaignID:     mandate.CampaignID,
+		Amount:         mandate.Amount,
+		PaymentMethod:  mandate.PaymentMethod,
+		Frequency:      mandate.Frequency,
+		Status:         mandate.Status,
+		StartDate:      mandate.StartDate,
+		EndDate:        mandate.EndDate,
+		Installment:    mandate.Installment,
+		NextChargeDate: mandate.NextChargeDate,
+		RetryAt:        mandate.RetryAt,
+		FailedAttempts: mandate.FailedAttempts,
+		LastError:      mandate.LastError,
+		ChargedCount:   mandate.ChargedCount,
+		MissedCount:    mandate.MissedCount,
+		LastChargedAt:  mandate.LastChargedAt,
+		PausedAt:       mandate.PausedAt,
+		CancelledAt:    mandate.CancelledAt,
+		CreatedAt:      mandate.CreatedAt,
+	}
+}
+
+func mandateFromModel(model *database.RecurringMandateModel) *entities.RecurringMandate {
+	return &entities.RecurringMandate{
+		MandateID:      model.MandateID,
+		DonorID:        model.DonorID,
+		NGOID:          model.NGOID,
+		CampaignID:     model.CampaignID,
+		Amount:         model.Amount,
+		PaymentMethod:  model.PaymentMethod,
+		Frequency:      model.Frequency,
+		Status:         model.Status,
+		StartDate:      model.StartDate,
+		EndDate:        model.EndDate,
+		Installment:    model.Installment,
+		NextChargeDate: model.NextChargeDate,
+		RetryAt:        model.RetryAt,
+		FailedAttempts: model.FailedAttempts,
+		LastError:      model.LastError,
+		ChargedCount:   model.ChargedCount,
+		MissedCount:    model.MissedCount,
+		LastChargedAt:  model.LastChargedAt,
+		PausedAt:       model.PausedAt,
+		CancelledAt:    model.CancelledAt,
+		CreatedAt:      model.CreatedAt,
+	}
+}
+
+func auditToModel(audit *entities.AuditResult) (*database.AuditModel, error) {
+	model := &database.AuditModel{
+		AuditID:         audit.AuditID,
+		ExpenditureID:   audit.ExpenditureID,
+		AuditorID:       audit.AuditorID,
+		ComplianceScore: audit.ComplianceScore,
+		Recommendation:  audit.Recommendation,
+		AuditNotes:      audit.AuditNotes,
+		Decision:        audit.Decision,
+		RulesVersion:    audit.RulesVersion,
+		Signature:       audit.Signature,
+		CreatedAt:       audit.Timestamp,
+	}
+
+	var err error
+	if model.Findings, err = marshalField(audit.Findings); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func auditFromModel(model *database.AuditModel) (entities.AuditResult, error) {
+	audit := entities.AuditResult{
+		AuditID:         model.AuditID,
+		ExpenditureID:   model.ExpenditureID,
+		AuditorID:       model.AuditorID,
+		Timestamp:       model.CreatedAt,
+		ComplianceScore: model.ComplianceScore,
+		Recommendation:  model.Recommendation,
+		AuditNotes:      model.AuditNotes,
+		Decision:        model.Decision,
+		RulesVersion:    model.RulesVersion,
+		Signature:       model.Signature,
+	}
+
+	err := unmarshalField(model.Findings, &audit.Findings)
+	return audit, err
+}
+
+// Write-through helpers used inside a transaction
+
+// saveNGO stores the NGO together with its campaigns, whose balances change
+// with the NGO's donations and expenditures
+func saveNGO(tx *database.Repositories, ngo *entities.NGO) error {
+	model, err := ngoToModel(ngo)
+	if err != nil {
+		return err
+	}
+	if err := tx.NGOs.Save(model); err != nil {
+		return err
+	}
+	for _, campaign := range ngo.Campaigns {
+		if err := tx.Campaigns.Save(campaignToModel(campaign)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveDonor(tx *database.Repositories, donor *entities.Donor) error {
+	model, err := donorToModel(donor)
+	if err != nil {
+		return err
+	}
+	return tx.Donors.Save(model)
+}
+
+func saveAuditor(tx *database.Repositories, auditor *entities.Auditor) error {
+	model, err := auditorToModel(auditor)
+	if err != nil {
+		return err
+	}
+	return tx.Auditors.Save(model)
+}
+
+func saveBlock(tx *database.Repositories, ngoID string, block *blockchain.Block) error {
+	model, err := blockToModel(ngoID, block)
+	if err != nil {
+		return err
+	}
+	return tx.Blocks.Create(model)
+}
+
+func savePaymentIntent(tx *database.Repositories, intent *PaymentIntent) error {
+	model, err := paymentIntentToModel(intent)
+	if err != nil {
+		return err
+	}
+	return tx.Payments.Save(model)
+}
+
+func saveJournalEntries(tx *database.Repositories, entries []*ledger.JournalEntry) error {
+	for _, entry := range entries {
+		if err := tx.Journal.Create(journalEntryToModel(entry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveReversal stores a reversal and applies it to the stored donation, whose
+// e-bill becomes the reissued one or, on a full reversal, the voided original
+func saveReversal(tx *database.Repositories, reversal *transactions.DonationReversal, status string, eBill *transactions.EBill) error {
+	eBillData, err := marshalField(eBill)
+	if err != nil {
+		return err
+	}
+	var taxBenefit string
+	if eBill != nil && !eBill.IsVoided() {
+		if taxBenefit, err = marshalField(eBill.TaxBenefit); err != nil {
+			return err
+		}
+	}
+	if err := tx.Donations.ApplyReversal(reversal.TransactionID, reversal.GrossAmount, reversal.PlatformFee, status, eBillData, taxBenefit); err != nil {
+		return err
+	}
+
+	model, err := reversalToModel(reversal)
+	if err != nil {
+		return err
+	}
+	return tx.Reversals.Create(model)
+}
+
+// saveExpenditureAudit stores an audited expenditure together with its audit
+// and the auditor's updated record
+func saveExpenditureAudit(tx *database.Repositories, expenditure *transactions.ExpenditureTransaction, audit *entities.AuditResult, auditor *entities.Auditor, blockHash, polygonTxHash string) error {
+	expenditureModel, err := expenditureToModel(expenditure, blockHash, polygonTxHash)
+	if err != nil {
+		return err
+	}
+	if err := tx.Expenditures.Save(expenditureModel); err != nil {
+		return err
+	}
+
+	auditModel, err := auditToModel(audit)
+	if err != nil {
+		return err
+	}
+	if err := tx.Audits.Create(auditModel); err != nil {
+		return err
+	}
+
+	return saveAuditor(tx, auditor)
+}
+
+// JSON column helpers
+
+func marshalField(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func unmarshalField(data string, target interface{}) error {
+	if data == "" || data == "null" {
+		return nil
+	}
+	return json.Unmarshal([]byte(data), target)
+}
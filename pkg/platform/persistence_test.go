@@ -0,0 +1,132 @@
+package platform
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/reconciliation"
+)
+
+func TestPlatformStateSurvivesReload(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+
+	deposit(t, p, money.INR(100000))
+	if _, err := recordExpenditure(t, p, "NGO001", invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books", "bank_transaction_id": "UTR9001"})); err != nil {
+		t.Fatalf("Failed to process expenditure: %v", err)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+
+	ngo := reloaded.NGOs["NGO001"]
+	if ngo == nil {
+		t.Fatal("Expected NGO to be reloaded")
+	}
+	if ngo.DonationBlockchain.GetChainLength() != 2 || ngo.ExpenditureBlockchain.GetChainLength() != 2 {
+		t.Errorf("Expected both chains to hold genesis and one block, got %d and %d",
+			ngo.DonationBlockchain.GetChainLength(), ngo.ExpenditureBlockchain.GetChainLength())
+	}
+	if !ngo.DonationBlockchain.IsChainValid() || !ngo.ExpenditureBlockchain.IsChainValid() {
+		t.Error("Expected reloaded chains to be valid")
+	}
+	if !ngo.KYCData.Verified || len(ngo.Certificates) != 1 {
+		t.Errorf("Expected verified NGO with one certificate, got %+v", ngo.KYCData)
+	}
+
+	donor := reloaded.Donors["DONOR001"]
+	if donor == nil || donor.TotalDonated != p.Donors["DONOR001"].TotalDonated || len(donor.DonationHistory) != 1 {
+		t.Errorf("Expected donor history to be reloaded, got %+v", donor)
+	}
+
+	auditor := reloaded.Auditors["AUD001"]
+	if auditor == nil || !auditor.Verified || len(auditor.AuditHistory) != 1 {
+		t.Errorf("Expected auditor with one audit, got %+v", auditor)
+	}
+
+	before, after := p.GetPlatformStats(), reloaded.GetPlatformStats()
+	if before.TotalTransactions != after.TotalTransactions || before.TotalDonations != after.TotalDonations ||
+		before.TotalExpenditures != after.TotalExpenditures || before.PlatformFeeCollected != after.PlatformFeeCollected {
+		t.Errorf("Expected matching stats after reload, got %+v and %+v", before, after)
+	}
+
+	journal, _ := reloaded.GetJournal("NGO001")
+	if len(journal) != 4 {
+		t.Errorf("Expected four journal entries after reload, got %d", len(journal))
+	}
+	sheet, err := reloaded.GetBalanceSheet("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to get balance sheet: %v", err)
+	}
+	// ₹1000 donated less ₹10 fee and ₹300 spent
+	if !sheet.Balanced || sheet.TotalAssets.Minor() != 69000 {
+		t.Errorf("Expected balanced sheet with ₹690 of assets, got %+v", sheet)
+	}
+
+	// The bank settles the net donation and pays the vendor by transfer
+	today := time.Now().Format("2006-01-02")
+	statement, err := reconciliation.Parse(reconciliation.FormatCSV, []byte(fmt.Sprintf(
+		"Date,Narration,Ref No,Debit,Credit\n%s,UPI SETTLEMENT,,,990.00\n%s,NEFT BOOKS,UTR9001,300.00,\n%s,SMS CHARGES,,15.00,\n", today, today, today)))
+	if err != nil {
+		t.Fatalf("Failed to parse statement: %v", err)
+	}
+	for _, platform := range []*NGOTransparencyPlatform{p, reloaded} {
+		report, err := platform.ReconcileStatement("NGO001", statement, 0)
+		if err != nil {
+			t.Fatalf("Failed to reconcile statement: %v", err)
+		}
+		if len(report.Matched) != 2 || len(report.UnmatchedItems) != 0 || len(report.UnmatchedLines) != 1 {
+			t.Errorf("Expected two matches and the bank charge unmatched, got %+v", report)
+		}
+		for _, match := range report.Matched {
+			if match.Item.Kind == reconciliation.ItemExpenditure && match.Method != reconciliation.MatchReference {
+				t.Errorf("Expected expenditure to match on its bank transaction ID, got %s", match.Method)
+			}
+		}
+	}
+}
+
+func TestLedgerRebuiltForExistingNGOs(t *testing.T) {
+	repos := newTestRepositories(t)
+
+	p := NewNGOTransparencyPlatform()
+	if err := p.AttachRepositories(repos); err != nil {
+		t.Fatalf("Failed to attach repositories: %v", err)
+	}
+	createAccount(t, repos, "ngo", &database.NGOModel{NGOID: "NGO001", Name: "Test NGO", RegistrationNumber: "REG001", Category: "education", PublicKey: "key"})
+	if _, err := p.RegisterNGO("NGO001", "Test NGO", "REG001", "education", map[string]interface{}{}, nil); err != nil {
+		t.Fatalf("Failed to register NGO: %v", err)
+	}
+
+	// A donation stored before the ledger existed
+	err := repos.Donations.Create(&database.DonationModel{
+		TransactionID: "DON_LEGACY",
+		DonorID:       "DONOR001",
+		NGOID:         "NGO001",
+		Amount:        money.INR(50000),
+		PlatformFee:   money.INR(500),
+		NetAmount:     money.INR(49500),
+		PaymentMethod: "upi",
+		Status:        "completed",
+	})
+	if err != nil {
+		t.Fatalf("Failed to store legacy donation: %v", err)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+
+	tb, err := reloaded.GetTrialBalance("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to get trial balance: %v", err)
+	}
+	if !tb.Balanced || tb.TotalDebits.Minor() != 50000 {
+		t.Errorf("Expected rebuilt trial balance of ₹500, got %+v", tb)
+	}
+
+	stored, _ := repos.Journal.GetByReference("DON_LEGACY")
+	if len(stored) != 2 || len(stored[1].Lines) != 3 {
+		t.Errorf("Expected rebuilt journal to be stored, got %+v", stored)
+	}
+}
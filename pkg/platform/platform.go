@@ -1,41 +1,76 @@
 package platform
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"ngo-transparency-platform/pkg/anomaly"
+	"ngo-transparency-platform/pkg/assignment"
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/compliance"
+	"ngo-transparency-platform/pkg/consensus"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/einvoice"
 	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/ledger"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
 	"ngo-transparency-platform/pkg/polygon"
+	"ngo-transparency-platform/pkg/rating"
+	"ngo-transparency-platform/pkg/reconciliation"
+	"ngo-transparency-platform/pkg/storage"
 	"ngo-transparency-platform/pkg/transactions"
 	"sort"
 	"sync"
 	"time"
 )
 
+// Errors returned for records the platform does not hold. Records another
+// NGO or donor holds are not found either.
+var (
+	ErrNGONotFound              = errors.New("NGO not found")
+	ErrDonorNotFound            = errors.New("donor not found")
+	ErrAuditorNotFound          = errors.New("auditor not found")
+	ErrExpenditureNotFound      = entities.ErrExpenditureNotFound
+	ErrCampaignNotFound         = entities.ErrCampaignNotFound
+	ErrDonationNotFound         = entities.ErrDonationNotFound
+	ErrMandateNotFound          = errors.New("mandate not found")
+	ErrDocumentNotFound         = errors.New("document not found")
+	ErrCorporateProfileNotFound = errors.New("corporate profile not found")
+	ErrEmployeeNotFound         = errors.New("employee not found")
+	ErrVendorNotFound           = errors.New("vendor not found")
+)
+
+// ErrNotAssigned is returned when an auditor reviews an expenditure assigned to others
+var ErrNotAssigned = errors.New("expenditure not assigned to this auditor")
+
 // SystemStats represents platform-wide statistics
 type SystemStats struct {
-	TotalTransactions int       `json:"total_transactions"`
-	TotalDonations    float64   `json:"total_donations"`
-	TotalExpenditures float64   `json:"total_expenditures"`
-	PlatformFee       float64   `json:"platform_fee"`
-	CreatedAt         time.Time `json:"created_at"`
+	TotalTransactions int         `json:"total_transactions"`
+	TotalDonations    money.Money `json:"total_donations"`
+	TotalExpenditures money.Money `json:"total_expenditures"`
+	TotalPlatformFees money.Money `json:"total_platform_fees"`
+	PlatformFee       float64     `json:"platform_fee"` // Fee rate, e.g. 0.01 for 1%
+	CreatedAt         time.Time   `json:"created_at"`
 }
 
 // PlatformStats represents comprehensive platform statistics
 type PlatformStats struct {
-	TotalNGOs            int      `json:"total_ngos"`
-	TotalDonors          int      `json:"total_donors"`
-	TotalAuditors        int      `json:"total_auditors"`
-	TotalTransactions    int      `json:"total_transactions"`
-	TotalDonations       float64  `json:"total_donations"`
-	TotalExpenditures    float64  `json:"total_expenditures"`
-	PlatformFeeCollected float64  `json:"platform_fee_collected"`
-	VerifiedNGOs         int      `json:"verified_ngos"`
-	VerifiedDonors       int      `json:"verified_donors"`
-	VerifiedAuditors     int      `json:"verified_auditors"`
-	KYCAuthorities       int      `json:"kyc_authorities"`
-	DaysActive           int      `json:"days_active"`
-	AverageNGORating     float64  `json:"average_ngo_rating"`
-	Categories           []string `json:"categories"`
+	TotalNGOs            int         `json:"total_ngos"`
+	TotalDonors          int         `json:"total_donors"`
+	TotalAuditors        int         `json:"total_auditors"`
+	TotalTransactions    int         `json:"total_transactions"`
+	TotalDonations       money.Money `json:"total_donations"`
+	TotalExpenditures    money.Money `json:"total_expenditures"`
+	PlatformFeeCollected money.Money `json:"platform_fee_collected"`
+	VerifiedNGOs         int         `json:"verified_ngos"`
+	VerifiedDonors       int         `json:"verified_donors"`
+	VerifiedAuditors     int         `json:"verified_auditors"`
+	KYCAuthorities       int         `json:"kyc_authorities"`
+	DaysActive           int         `json:"days_active"`
+	AverageNGORating     float64     `json:"average_ngo_rating"`
+	Categories           []string    `json:"categories"`
 }
 
 // NGOTransparencyPlatform is the main platform orchestrator
@@ -44,34 +79,82 @@ type NGOTransparencyPlatform struct {
 	Donors             map[string]*entities.Donor   `json:"donors"`
 	Auditors           map[string]*entities.Auditor `json:"auditors"`
 	PolygonIntegration *polygon.PolygonIntegration  `json:"polygon_integration"`
+	Ledger             *ledger.Ledger               `json:"-"`
+	PaymentGateway     payments.Gateway             `json:"-"`
+	PaymentIntents     map[string]*PaymentIntent    `json:"-"`
+	MandateRetryPolicy MandateRetryPolicy           `json:"-"`
 	SystemStats        SystemStats                  `json:"system_stats"`
 	KYCAuthorities     map[string]bool              `json:"kyc_authorities"`
-	mutex              sync.RWMutex
+	Vendors            map[string]*entities.Vendor  `json:"-"` // Vendor registry keyed by GSTIN
+	// Expenditures awaiting an auditor's decision or the NGO's reply, by transaction ID
+	PendingExpenditures  map[string]*transactions.ExpenditureTransaction `json:"-"`
+	RejectedExpenditures map[string]*transactions.ExpenditureTransaction `json:"-"` // Rejected expenditures, which the NGO may appeal
+	AssignmentPolicy     assignment.Policy                               `json:"-"`
+	ConsensusPolicy      consensus.Policy                                `json:"-"` // Panels required for high-value expenditures
+	AnomalyConfig        anomaly.Config                                  `json:"-"` // Sensitivity of expenditure anomaly detection
+	ComplianceRules      *compliance.RuleSet                             `json:"-"` // Rules expenditures are scored under
+	Assignments          []*AuditorAssignment                            `json:"-"` // Every auditor assignment, oldest first
+	DocumentStore        storage.Store                                   `json:"-"` // Uploaded documents by hash, none until initialized
+	DocumentPolicy       storage.Policy                                  `json:"-"` // Size and types of documents accepted
+	EInvoiceVerifier     *einvoice.Verifier                              `json:"-"` // Checks e-invoices against the IRP's key, none until initialized
+	RatingModel          rating.Model                                    `json:"-"` // How NGOs are rated
+	RatingPeers          rating.PeerPolicy                               `json:"-"` // How ratings are normalized within a category
+	paymentOrders        map[string]string                               // Gateway order ID to payment intent ID
+	donations            map[string]recordedDonation                     // Mined donations by transaction ID
+	documents            map[string][]*storage.Document                  // Uploads of each document by hash, oldest first
+	invoices             *transactions.InvoiceIndex                      // Invoices paid by recorded expenditures
+	ratingHistory        map[string][]*RatingSnapshot                    // Ratings recorded for each NGO, oldest first
+	pendingAnchors       map[string]*PendingAnchor                       // Blocks not yet anchored to Polygon by block hash
+	repos                *database.Repositories
+	mutex                sync.RWMutex
 }
 
 // NewNGOTransparencyPlatform creates a new platform instance
 func NewNGOTransparencyPlatform() *NGOTransparencyPlatform {
 	return &NGOTransparencyPlatform{
-		NGOs:           make(map[string]*entities.NGO),
-		Donors:         make(map[string]*entities.Donor),
-		Auditors:       make(map[string]*entities.Auditor),
-		KYCAuthorities: make(map[string]bool),
+		NGOs:                 make(map[string]*entities.NGO),
+		Donors:               make(map[string]*entities.Donor),
+		Auditors:             make(map[string]*entities.Auditor),
+		KYCAuthorities:       make(map[string]bool),
+		Ledger:               ledger.NewLedger(),
+		PaymentIntents:       make(map[string]*PaymentIntent),
+		paymentOrders:        make(map[string]string),
+		donations:            make(map[string]recordedDonation),
+		Vendors:              make(map[string]*entities.Vendor),
+		PendingExpenditures:  make(map[string]*transactions.ExpenditureTransaction),
+		RejectedExpenditures: make(map[string]*transactions.ExpenditureTransaction),
+		AssignmentPolicy:     assignment.DefaultPolicy(),
+		ConsensusPolicy:      consensus.DefaultPolicy(),
+		AnomalyConfig:        anomaly.DefaultConfig(),
+		ComplianceRules:      compliance.Default(),
+		Assignments:          make([]*AuditorAssignment, 0),
+		DocumentPolicy:       storage.DefaultPolicy(),
+		RatingModel:          rating.Baseline{},
+		RatingPeers:          rating.DefaultPeerPolicy(),
+		ratingHistory:        make(map[string][]*RatingSnapshot),
+		pendingAnchors:       make(map[string]*PendingAnchor),
+		documents:            make(map[string][]*storage.Document),
+		invoices:             transactions.NewInvoiceIndex(),
+		MandateRetryPolicy:   DefaultMandateRetryPolicy(),
 		SystemStats: SystemStats{
 			TotalTransactions: 0,
-			TotalDonations:    0,
-			TotalExpenditures: 0,
+			TotalDonations:    money.Zero(),
+			TotalExpenditures: money.Zero(),
+			TotalPlatformFees: money.Zero(),
 			PlatformFee:       0.01, // 1% platform fee
 			CreatedAt:         time.Now(),
 		},
 	}
 }
 
-// InitializePolygon initializes Polygon blockchain integration
-func (p *NGOTransparencyPlatform) InitializePolygon(providerURL, privateKey string, gasLimit int64, gasPrice *big.Int) {
+// InitializePolygon initializes Polygon blockchain integration with the
+// anchors already stored
+func (p *NGOTransparencyPlatform) InitializePolygon(providerURL, privateKey string, gasLimit int64, gasPrice *big.Int) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
 	p.PolygonIntegration = polygon.NewPolygonIntegration(providerURL, privateKey, gasLimit, gasPrice)
+	return p.restoreAnchors()
 }
 
 // RegisterNGO registers a new NGO on the platform
@@ -84,7 +167,22 @@ func (p *NGOTransparencyPlatform) RegisterNGO(ngoID, name, registrationNumber, c
 	}
 
 	ngo := entities.NewNGO(ngoID, name, registrationNumber, category, kycData, signers)
+
+	err := p.persist(func(tx *database.Repositories) error {
+		if err := saveNGO(tx, ngo); err != nil {
+			return err
+		}
+		if err := saveBlock(tx, ngoID, ngo.DonationBlockchain.GetLatestBlock()); err != nil {
+			return err
+		}
+		return saveBlock(tx, ngoID, ngo.ExpenditureBlockchain.GetLatestBlock())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist NGO: %w", err)
+	}
+
 	p.NGOs[ngoID] = ngo
+	p.Ledger.OpenBooks(ngoID)
 
 	return ngo, nil
 }
@@ -96,11 +194,20 @@ func (p *NGOTransparencyPlatform) VerifyNGOKYC(ngoID, authorityID string, certif
 
 	ngo, exists := p.NGOs[ngoID]
 	if !exists {
-		return fmt.Errorf("NGO not found")
+		return ErrNGONotFound
 	}
 
-	p.KYCAuthorities[authorityID] = true
 	ngo.VerifyKYC(authorityID, certificates)
+
+	err := p.persist(func(tx *database.Repositories) error {
+		return saveNGO(tx, ngo)
+	})
+	if err != nil {
+		p.reloadNGO(ngoID)
+		return fmt.Errorf("failed to persist NGO KYC: %w", err)
+	}
+
+	p.KYCAuthorities[authorityID] = true
 	return nil
 }
 
@@ -114,6 +221,14 @@ func (p *NGOTransparencyPlatform) RegisterDonor(donorID string, kycData map[stri
 	}
 
 	donor := entities.NewDonor(donorID, kycData)
+
+	err := p.persist(func(tx *database.Repositories) error {
+		return saveDonor(tx, donor)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist donor: %w", err)
+	}
+
 	p.Donors[donorID] = donor
 
 	return donor, nil
@@ -126,11 +241,20 @@ func (p *NGOTransparencyPlatform) VerifyDonorKYC(donorID, authorityID, verificat
 
 	donor, exists := p.Donors[donorID]
 	if !exists {
-		return fmt.Errorf("donor not found")
+		return ErrDonorNotFound
 	}
 
-	p.KYCAuthorities[authorityID] = true
 	donor.VerifyKYC(authorityID, verificationLevel)
+
+	err := p.persist(func(tx *database.Repositories) error {
+		return saveDonor(tx, donor)
+	})
+	if err != nil {
+		p.reloadDonor(donorID)
+		return fmt.Errorf("failed to persist donor KYC: %w", err)
+	}
+
+	p.KYCAuthorities[authorityID] = true
 	return nil
 }
 
@@ -144,6 +268,14 @@ func (p *NGOTransparencyPlatform) RegisterAuditor(auditorID, name string, creden
 	}
 
 	auditor := entities.NewAuditor(auditorID, name, credentials, specializations)
+
+	err := p.persist(func(tx *database.Repositories) error {
+		return saveAuditor(tx, auditor)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist auditor: %w", err)
+	}
+
 	p.Auditors[auditorID] = auditor
 
 	return auditor, nil
@@ -156,198 +288,216 @@ func (p *NGOTransparencyPlatform) VerifyAuditorCredentials(auditorID, verificati
 
 	auditor, exists := p.Auditors[auditorID]
 	if !exists {
-		return fmt.Errorf("auditor not found")
+		return ErrAuditorNotFound
 	}
 
 	auditor.VerifyCredentials(verificationAuthority)
+
+	err := p.persist(func(tx *database.Repositories) error {
+		return saveAuditor(tx, auditor)
+	})
+	if err != nil {
+		p.reloadAuditor(auditorID)
+		return fmt.Errorf("failed to persist auditor verification: %w", err)
+	}
+
 	return nil
 }
 
-// ProcessDonation processes a donation transaction
-func (p *NGOTransparencyPlatform) ProcessDonation(donorID, ngoID string, amount float64, paymentMethod string) (map[string]interface{}, error) {
+// ProcessDonation processes a donation whose payment has already been collected
+func (p *NGOTransparencyPlatform) ProcessDonation(donorID, ngoID string, amount money.Money, paymentMethod string) (map[string]interface{}, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
+	return p.processDonation("", donorID, ngoID, amount, paymentMethod)
+}
+
+// processDonation records a paid donation under transactionID, or under a
+// fresh ID when transactionID is empty
+func (p *NGOTransparencyPlatform) processDonation(transactionID, donorID, ngoID string, amount money.Money, paymentMethod string) (map[string]interface{}, error) {
+	donor, ngo, err := p.checkDonation(donorID, ngoID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	platformFee, netAmount, err := p.splitDonation(amount)
+	if err != nil {
+		return nil, err
+	}
+	donation := newDonation(transactionID, donorID, ngoID, netAmount, paymentMethod, donor.KYCData.DocumentHash)
+
+	return p.recordDonation(donor, ngo, donation, amount, platformFee, nil)
+}
+
+// checkDonation verifies that donor and NGO may transact the given amount
+func (p *NGOTransparencyPlatform) checkDonation(donorID, ngoID string, amount money.Money) (*entities.Donor, *entities.NGO, error) {
 	donor, donorExists := p.Donors[donorID]
 	ngo, ngoExists := p.NGOs[ngoID]
 
 	if !donorExists {
-		return nil, fmt.Errorf("donor not found")
+		return nil, nil, ErrDonorNotFound
 	}
 	if !ngoExists {
-		return nil, fmt.Errorf("NGO not found")
+		return nil, nil, ErrNGONotFound
 	}
 	if !donor.KYCVerified {
-		return nil, fmt.Errorf("donor KYC not verified")
+		return nil, nil, fmt.Errorf("donor KYC not verified")
 	}
 	if !ngo.KYCData.Verified {
-		return nil, fmt.Errorf("NGO KYC not verified")
+		return nil, nil, fmt.Errorf("NGO KYC not verified")
+	}
+
+	if !amount.IsPositive() {
+		return nil, nil, fmt.Errorf("donation amount must be positive")
 	}
 
 	// Check donation limit
 	limitCheck := donor.CheckDonationLimit(amount)
 	if !limitCheck.CanDonate {
-		return nil, fmt.Errorf("donation exceeds annual limit. Remaining: ₹%.2f", limitCheck.RemainingLimit)
+		return nil, nil, fmt.Errorf("donation exceeds annual limit. Remaining: ₹%s", limitCheck.RemainingLimit)
 	}
 
-	// Calculate platform fee
-	platformFee := amount * p.SystemStats.PlatformFee
-	netAmount := amount - platformFee
-
-	donation := transactions.NewDonationTransaction(donorID, ngoID, netAmount, paymentMethod, donor.KYCData.DocumentHash)
+	return donor, ngo, nil
+}
 
-	result, err := ngo.ProcessDonation(donation)
+// splitDonation calculates the platform fee, rounded half up, and the net
+// amount that is the exact remainder
+func (p *NGOTransparencyPlatform) splitDonation(amount money.Money) (platformFee, netAmount money.Money, err error) {
+	platformFee, err = amount.Percent(money.BasisPoints(p.SystemStats.PlatformFee), money.RoundHalfUp)
 	if err != nil {
-		return nil, err
-	}
-
-	donor.AddDonation(donation)
-
-	// Update system stats
-	p.SystemStats.TotalTransactions++
-	p.SystemStats.TotalDonations += netAmount
-
-	// Anchor to Polygon if available
-	if p.PolygonIntegration != nil {
-		additionalData := map[string]interface{}{
-			"amount":       netAmount,
-			"platform_fee": platformFee,
-		}
-
-		anchor, err := p.PolygonIntegration.AnchorBlockHash(result.BlockHash, ngoID, "donation", additionalData)
-		if err == nil {
-			result.EBill = map[string]interface{}{
-				"polygon_anchor": anchor,
-				"original_ebill": donation.EBill,
-			}
-		}
+		return money.Money{}, money.Money{}, err
 	}
-
-	return map[string]interface{}{
-		"success":        result.Success,
-		"block_hash":     result.BlockHash,
-		"transaction_id": result.TransactionID,
-		"block_index":    result.BlockIndex,
-		"e_bill":         result.EBill,
-		"platform_fee":   platformFee,
-		"net_amount":     netAmount,
-		"gross_amount":   amount,
-	}, nil
+	return platformFee, amount.Sub(platformFee), nil
 }
 
-// ProcessExpenditure processes an expenditure transaction
-func (p *NGOTransparencyPlatform) ProcessExpenditure(ngoID string, expenditureData map[string]interface{}, auditorID string) (map[string]interface{}, error) {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-
-	ngo, ngoExists := p.NGOs[ngoID]
-	auditor, auditorExists := p.Auditors[auditorID]
+// recordDonation mines a paid donation, posts it to the ledger and stores it.
+// onPersist, when given, runs in the same database transaction.
+func (p *NGOTransparencyPlatform) recordDonation(donor *entities.Donor, ngo *entities.NGO, donation *transactions.DonationTransaction, amount, platformFee money.Money, onPersist func(tx *database.Repositories, blockHash string) error) (map[string]interface{}, error) {
+	donorID, ngoID, netAmount := donor.DonorID, ngo.NGOID, donation.Amount
 
-	if !ngoExists {
-		return nil, fmt.Errorf("NGO not found")
-	}
-	if !auditorExists {
-		return nil, fmt.Errorf("auditor not found")
-	}
-	if !auditor.Verified {
-		return nil, fmt.Errorf("auditor not verified")
+	entries := ledger.DonationEntries(ngoID, donation.TransactionID, amount, platformFee, donation.Timestamp)
+	if err := p.Ledger.Validate(entries...); err != nil {
+		return nil, fmt.Errorf("failed to record donation in ledger: %w", err)
 	}
 
-	// Extract expenditure data
-	amount, ok := expenditureData["amount"].(float64)
-	if !ok {
-		return nil, fmt.Errorf("invalid amount")
+	result, err := ngo.ProcessDonation(donation)
+	if err != nil {
+		return nil, err
 	}
+	block := ngo.DonationBlockchain.GetLatestBlock()
 
-	category, _ := expenditureData["category"].(string)
-	description, _ := expenditureData["description"].(string)
-
-	// Create invoice details (simplified)
-	invoiceDetails := transactions.InvoiceDetails{
-		InvoiceNumber: fmt.Sprintf("INV-%d", time.Now().Unix()),
-		GSTIN:         "27ABCDE1234F1Z5", // Example GSTIN
-		VendorName:    "Test Vendor",
-		VendorGSTIN:   "29ABCDE1234F1Z3", // Example Vendor GSTIN
-		InvoiceDate:   time.Now(),
-		Documents:     []string{"invoice.pdf"},
-	}
+	donor.AddDonation(donation, amount, platformFee)
 
-	// Create expenditure transaction
-	expenditure := transactions.NewExpenditureTransaction(ngoID, amount, category, description, invoiceDetails, []transactions.Attachment{
-		{
-			Filename:   "invoice.pdf",
-			Hash:       "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
-			Type:       "application/pdf",
-			UploadedAt: time.Now(),
-		},
+	err = p.persist(func(tx *database.Repositories) error {
+		donationModel, err := donationToModel(donation, amount, platformFee, result.BlockHash, "")
+		if err != nil {
+			return err
+		}
+		if err := tx.Donations.Create(donationModel); err != nil {
+			return err
+		}
+		if err := saveBlock(tx, ngoID, block); err != nil {
+			return err
+		}
+		if err := saveNGO(tx, ngo); err != nil {
+			return err
+		}
+		if err := saveJournalEntries(tx, entries); err != nil {
+			return err
+		}
+		if onPersist != nil {
+			if err := onPersist(tx, result.BlockHash); err != nil {
+				return err
+			}
+		}
+		return saveDonor(tx, donor)
 	})
+	if err != nil {
+		p.reloadNGO(ngoID)
+		p.reloadDonor(donorID)
+		return nil, fmt.Errorf("failed to persist donation: %w", err)
+	}
 
-	// Auditor performs audit
-	auditResult := auditor.AuditExpenditure(expenditure, "")
-
-	// Auto-approve based on audit result
-	shouldApprove := auditResult.ComplianceScore >= 70.0 // Require at least 70% compliance
-	expenditure.ValidateByAuditor(auditorID, shouldApprove, auditResult.Recommendation, &auditResult.ComplianceScore)
-
-	if !shouldApprove {
-		return nil, fmt.Errorf("expenditure rejected by auditor (score: %.2f%%): %s",
-			auditResult.ComplianceScore, auditResult.Recommendation)
+	p.donations[donation.TransactionID] = recordedDonation{
+		ngoID:       ngoID,
+		blockIndex:  result.BlockIndex,
+		grossAmount: amount,
+		platformFee: platformFee,
+		netAmount:   netAmount,
 	}
 
-	result, err := ngo.ProcessExpenditure(expenditure)
-	if err != nil {
-		return nil, err
+	if err := p.Ledger.Post(entries...); err != nil {
+		return nil, fmt.Errorf("failed to post donation to ledger: %w", err)
 	}
 
 	// Update system stats
 	p.SystemStats.TotalTransactions++
-	p.SystemStats.TotalExpenditures += amount
+	p.SystemStats.TotalDonations = p.SystemStats.TotalDonations.Add(netAmount)
+	p.SystemStats.TotalPlatformFees = p.SystemStats.TotalPlatformFees.Add(platformFee)
 
-	// Anchor to Polygon if available
-	if p.PolygonIntegration != nil {
-		additionalData := map[string]interface{}{
-			"amount":   amount,
-			"category": category,
-		}
-
-		anchor, err := p.PolygonIntegration.AnchorBlockHash(result.BlockHash, ngoID, "expenditure", additionalData)
-		if err == nil {
-			result.EBill = map[string]interface{}{
-				"polygon_anchor": anchor,
-			}
+	// Anchor the stored block to Polygon if available
+	anchor, anchorErr := p.anchorBlock(result.BlockHash, ngoID, "donation", donation.TransactionID, map[string]interface{}{
+		"amount":       netAmount,
+		"platform_fee": platformFee,
+	})
+	if anchor != nil {
+		result.EBill = map[string]interface{}{
+			"polygon_anchor": anchor,
+			"original_ebill": donation.EBill,
 		}
 	}
 
-	return map[string]interface{}{
+	response := map[string]interface{}{
 		"success":        result.Success,
 		"block_hash":     result.BlockHash,
 		"transaction_id": result.TransactionID,
 		"block_index":    result.BlockIndex,
-		"audit_result":   auditResult,
-	}, nil
+		"e_bill":         result.EBill,
+		"platform_fee":   platformFee,
+		"net_amount":     netAmount,
+		"gross_amount":   amount,
+	}
+	// The donation stands when its block is not anchored; anchoring is retried
+	if anchorErr != nil {
+		response["anchor_error"] = anchorErr.Error()
+	}
+
+	// A failed employer match does not undo the employee's donation
+	if matched, err := p.matchDonation(donor, ngo, donation, amount); err != nil {
+		response["match_error"] = err.Error()
+	} else if matched != nil {
+		response["matched_donation"] = matched
+	}
+
+	return response, nil
 }
 
 // CalculateAllNGORatings calculates ratings for all NGOs
 func (p *NGOTransparencyPlatform) CalculateAllNGORatings(periodDays int) []map[string]interface{} {
-	p.mutex.RLock()
-	defer p.mutex.RUnlock()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 
 	var ratings []map[string]interface{}
 
-	for ngoID, ngo := range p.NGOs {
-		rating := ngo.CalculateRating(periodDays)
+	for ngoID, details := range p.rateNGOs(periodDays) {
+		ngo := p.NGOs[ngoID]
 		ratingInfo := map[string]interface{}{
 			"ngo_id":                ngoID,
 			"name":                  ngo.Name,
 			"category":              ngo.Category,
 			"kyc_verified":          ngo.KYCData.Verified,
-			"rating":                rating.Rating,
-			"transparency_score":    rating.TransparencyScore,
-			"utilization_rate":      rating.UtilizationRate,
-			"gap_percentage":        rating.GapPercentage,
-			"total_donations":       rating.TotalDonations,
-			"total_expenditures":    rating.TotalExpenditures,
-			"documentation_quality": rating.DocumentationQuality,
+			"rating":                details.Rating,
+			"transparency_score":    details.TransparencyScore,
+			"utilization_rate":      details.UtilizationRate,
+			"gap_percentage":        details.GapPercentage,
+			"total_donations":       details.TotalDonations,
+			"total_expenditures":    details.TotalExpenditures,
+			"documentation_quality": details.DocumentationQuality,
+			"model":                 details.Model,
+			"factors":               details.Factors,
+		}
+		if details.Peers != nil {
+			ratingInfo["peers"] = details.Peers
 		}
 		ratings = append(ratings, ratingInfo)
 	}
@@ -412,7 +562,7 @@ func (p *NGOTransparencyPlatform) GetPlatformStats() PlatformStats {
 		TotalTransactions:    p.SystemStats.TotalTransactions,
 		TotalDonations:       p.SystemStats.TotalDonations,
 		TotalExpenditures:    p.SystemStats.TotalExpenditures,
-		PlatformFeeCollected: p.SystemStats.TotalDonations * p.SystemStats.PlatformFee,
+		PlatformFeeCollected: p.SystemStats.TotalPlatformFees,
 		VerifiedNGOs:         verifiedNGOs,
 		VerifiedDonors:       verifiedDonors,
 		VerifiedAuditors:     verifiedAuditors,
@@ -425,22 +575,23 @@ func (p *NGOTransparencyPlatform) GetPlatformStats() PlatformStats {
 
 // GetNGODashboard returns NGO dashboard information
 func (p *NGOTransparencyPlatform) GetNGODashboard(ngoID string) (map[string]interface{}, error) {
-	p.mutex.RLock()
-	defer p.mutex.RUnlock()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 
 	ngo, exists := p.NGOs[ngoID]
 	if !exists {
-		return nil, fmt.Errorf("NGO not found")
+		return nil, ErrNGONotFound
 	}
 
 	stats := ngo.GetBlockchainStats()
 	financialSummary := ngo.GetFinancialSummary(12)
-	ratingDetails := ngo.CalculateRating(30)
+	ratingDetails := p.rateNGOs(30)[ngoID]
 
 	return map[string]interface{}{
 		"stats":             stats,
 		"financial_summary": financialSummary,
 		"rating_details":    ratingDetails,
+		"campaigns":         ngo.CampaignUtilizations(),
 		"multisig_status": map[string]interface{}{
 			"signers":              ngo.MultiSigWallet.GetSigners(),
 			"required_signatures":  2,
@@ -456,7 +607,7 @@ func (p *NGOTransparencyPlatform) GetDonorDashboard(donorID string) (map[string]
 
 	donor, exists := p.Donors[donorID]
 	if !exists {
-		return nil, fmt.Errorf("donor not found")
+		return nil, ErrDonorNotFound
 	}
 
 	stats := donor.GetDonorStats()
@@ -475,12 +626,21 @@ func (p *NGOTransparencyPlatform) GetDonorDashboard(donorID string) (map[string]
 		}
 	}
 
-	return map[string]interface{}{
+	dashboard := map[string]interface{}{
 		"stats":                     stats,
 		"recent_donations":          recentDonations,
 		"current_year_tax_benefits": currentYearTaxBenefits,
 		"preferred_ngos":            preferredNGOs,
-	}, nil
+		"campaigns":                 p.donorCampaigns(donor),
+		"mandates":                  donor.SortedMandates(),
+	}
+	if donor.IsCorporate() {
+		dashboard["corporate"] = donor.Corporate
+	}
+	if donor.EmployerID != "" {
+		dashboard["employer_id"] = donor.EmployerID
+	}
+	return dashboard, nil
 }
 
 // GetAuditorDashboard returns auditor dashboard information
@@ -490,7 +650,7 @@ func (p *NGOTransparencyPlatform) GetAuditorDashboard(auditorID string) (map[str
 
 	auditor, exists := p.Auditors[auditorID]
 	if !exists {
-		return nil, fmt.Errorf("auditor not found")
+		return nil, ErrAuditorNotFound
 	}
 
 	stats := auditor.GetAuditorStats()
@@ -501,3 +661,141 @@ func (p *NGOTransparencyPlatform) GetAuditorDashboard(auditorID string) (map[str
 		"recent_audits": recentAudits,
 	}, nil
 }
+
+// GetTrialBalance returns the NGO's trial balance
+func (p *NGOTransparencyPlatform) GetTrialBalance(ngoID string) (*ledger.TrialBalance, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if _, exists := p.NGOs[ngoID]; !exists {
+		return nil, ErrNGONotFound
+	}
+	return p.Ledger.TrialBalance(ngoID)
+}
+
+// GetBalanceSheet returns the NGO's balance sheet
+func (p *NGOTransparencyPlatform) GetBalanceSheet(ngoID string) (*ledger.BalanceSheet, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if _, exists := p.NGOs[ngoID]; !exists {
+		return nil, ErrNGONotFound
+	}
+	return p.Ledger.BalanceSheet(ngoID)
+}
+
+// GetJournal returns the NGO's journal entries in posting order
+func (p *NGOTransparencyPlatform) GetJournal(ngoID string) ([]ledger.JournalEntry, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if _, exists := p.NGOs[ngoID]; !exists {
+		return nil, ErrNGONotFound
+	}
+	return p.Ledger.Entries(ngoID), nil
+}
+
+// ReconcileStatement matches a bank statement against the donations and
+// expenditures on the NGO's chains. Only transactions dated within the
+// statement period, widened by the date window, are expected on it.
+func (p *NGOTransparencyPlatform) ReconcileStatement(ngoID string, statement *reconciliation.Statement, dateWindow time.Duration) (*reconciliation.Report, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+
+	reconciler := reconciliation.NewReconciler(dateWindow)
+	from, to := statement.Period()
+	from, to = from.Add(-reconciler.DateWindow), to.Add(reconciler.DateWindow)
+
+	var items []reconciliation.Item
+	chains := []struct {
+		chain *blockchain.Blockchain
+		kinds []reconciliation.ItemKind
+	}{
+		{ngo.DonationBlockchain, []reconciliation.ItemKind{reconciliation.ItemDonation, reconciliation.ItemDonationReversal}},
+		{ngo.ExpenditureBlockchain, []reconciliation.ItemKind{reconciliation.ItemExpenditure}},
+	}
+	for _, c := range chains {
+		for _, block := range c.chain.GetBlocksByDateRange(from, to) {
+			for _, kind := range c.kinds {
+				if item, ok := reconciliationItem(kind, block); ok {
+					items = append(items, item)
+				}
+			}
+		}
+	}
+
+	return reconciler.Reconcile(statement.Lines, items), nil
+}
+
+// reconciliationItem reads a donation, expenditure or donation reversal from its block
+func reconciliationItem(kind reconciliation.ItemKind, block *blockchain.Block) (reconciliation.Item, bool) {
+	data, ok := block.Data.(map[string]interface{})
+	if !ok || data["type"] != string(kind) {
+		return reconciliation.Item{}, false
+	}
+	amount, ok := parseAmount(data["amount"])
+	if !ok {
+		return reconciliation.Item{}, false
+	}
+
+	transactionID, _ := data["transaction_id"].(string)
+	item := reconciliation.Item{
+		Kind:          kind,
+		TransactionID: transactionID,
+		BlockHash:     block.Hash,
+		Amount:        amount,
+		Date:          block.Timestamp,
+		References:    []string{transactionID},
+	}
+
+	nested := "e_bill"
+	fields := []string{"bill_id", "receipt_number"}
+	switch kind {
+	case reconciliation.ItemExpenditure:
+		nested = "invoice_details"
+		fields = []string{"bank_transaction_id", "cheque_number", "invoice_number"}
+		item.Description, _ = data["description"].(string)
+	case reconciliation.ItemDonationReversal:
+		for _, field := range []string{"reversal_id", "gateway_reference"} {
+			if reference, ok := data[field].(string); ok && reference != "" {
+				item.References = append(item.References, reference)
+			}
+		}
+		item.Description, _ = data["reason"].(string)
+		return item, true
+	}
+	// Blocks hold the typed e-bill or invoice until they are reloaded from JSON
+	var details map[string]interface{}
+	if encoded, err := json.Marshal(data[nested]); err == nil && json.Unmarshal(encoded, &details) == nil {
+		for _, field := range fields {
+			if reference, ok := details[field].(string); ok && reference != "" {
+				item.References = append(item.References, reference)
+			}
+		}
+	}
+
+	return item, true
+}
+
+// parseAmount reads an amount from loosely typed request data: a Money value,
+// a number of rupees or a decimal string
+func parseAmount(value interface{}) (money.Money, bool) {
+	switch v := value.(type) {
+	case money.Money:
+		return v, true
+	case float64:
+		return money.FromMajor(v), true
+	case int:
+		return money.INR(int64(v) * 100), true
+	case string:
+		amount, err := money.Parse(v)
+		return amount, err == nil
+	default:
+		return money.Money{}, false
+	}
+}
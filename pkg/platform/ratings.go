@@ -0,0 +1,153 @@
+package platform
+
+import (
+	"math"
+	"time"
+
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/rating"
+)
+
+// RatingSnapshot is an NGO's rating as it stood after moving
+type RatingSnapshot struct {
+	NGOID             string          `json:"ngo_id"`
+	Model             string          `json:"model"`
+	Rating            float64         `json:"rating"`
+	TransparencyScore int             `json:"transparency_score"`
+	PeriodDays        int             `json:"period_days"`
+	Factors           []rating.Factor `json:"factors"`
+	RecordedAt        time.Time       `json:"recorded_at"`
+}
+
+// RatingHistoryEntry is a recorded rating and what moved it from the one before
+type RatingHistoryEntry struct {
+	RatingSnapshot
+	Change  float64               `json:"change"`
+	Changes []rating.FactorChange `json:"changes"` // Factors that moved, those that moved most first
+}
+
+// GetNGORatingHistory returns every rating recorded for an NGO, oldest first,
+// each with the factors that moved it
+func (p *NGOTransparencyPlatform) GetNGORatingHistory(ngoID string) ([]RatingHistoryEntry, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if _, exists := p.NGOs[ngoID]; !exists {
+		return nil, ErrNGONotFound
+	}
+
+	snapshots := p.ratingHistory[ngoID]
+	history := make([]RatingHistoryEntry, 0, len(snapshots))
+	var previous *RatingSnapshot
+	for _, snapshot := range snapshots {
+		entry := RatingHistoryEntry{RatingSnapshot: *snapshot, Changes: rating.Compare(nil, snapshot.Factors)}
+		if previous != nil {
+			entry.Change = math.Round((snapshot.Rating-previous.Rating)*100) / 100
+			entry.Changes = rating.Compare(previous.Factors, snapshot.Factors)
+		}
+		history = append(history, entry)
+		previous = snapshot
+	}
+	return history, nil
+}
+
+// rateNGOs rates every NGO on its activity in the past periodDays under the
+// platform's model, normalizes the ratings within each category and records
+// those that moved. The caller must hold the write lock.
+func (p *NGOTransparencyPlatform) rateNGOs(periodDays int) map[string]*entities.RatingDetails {
+	now := time.Now()
+	model := p.RatingModel
+	if model == nil {
+		model = rating.Baseline{}
+	}
+
+	inputs := make(map[string]rating.Inputs, len(p.NGOs))
+	results := make(map[string]*rating.Result, len(p.NGOs))
+	peers := make([]rating.Peer, 0, len(p.NGOs))
+	for ngoID, ngo := range p.NGOs {
+		inputs[ngoID] = ngo.RatingInputs(periodDays, now)
+		result := model.Rate(inputs[ngoID], now)
+		results[ngoID] = &result
+		peers = append(peers, rating.Peer{ID: ngoID, Category: ngo.Category, Result: &result})
+	}
+	rating.NormalizePeers(p.RatingPeers, peers)
+
+	details := make(map[string]*entities.RatingDetails, len(p.NGOs))
+	for ngoID, ngo := range p.NGOs {
+		ratingDetails := ngo.ApplyRating(*results[ngoID], inputs[ngoID], periodDays)
+		details[ngoID] = &ratingDetails
+		p.recordRating(ngo, &ratingDetails, now)
+	}
+	return details
+}
+
+// recordRating adds an NGO's rating to its history if it moved since the
+// last one recorded. A rating that fails to save is recorded the next time
+// the NGO is rated.
+func (p *NGOTransparencyPlatform) recordRating(ngo *entities.NGO, details *entities.RatingDetails, now time.Time) {
+	history := p.ratingHistory[ngo.NGOID]
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		if last.Model == details.Model && last.Rating == details.Rating && last.PeriodDays == details.PeriodDays &&
+			len(rating.Compare(last.Factors, details.Factors)) == 0 {
+			return
+		}
+	}
+
+	snapshot := &RatingSnapshot{
+		NGOID:             ngo.NGOID,
+		Model:             details.Model,
+		Rating:            details.Rating,
+		TransparencyScore: details.TransparencyScore,
+		PeriodDays:        details.PeriodDays,
+		Factors:           details.Factors,
+		RecordedAt:        now,
+	}
+	err := p.persist(func(tx *database.Repositories) error {
+		if err := tx.NGOs.UpdateRating(ngo.NGOID, snapshot.Rating, snapshot.TransparencyScore); err != nil {
+			return err
+		}
+		model, err := ratingSnapshotToModel(snapshot)
+		if err != nil {
+			return err
+		}
+		return tx.Ratings.Create(model)
+	})
+	if err != nil {
+		return
+	}
+	p.ratingHistory[ngo.NGOID] = append(history, snapshot)
+}
+
+func ratingSnapshotToModel(snapshot *RatingSnapshot) (*database.RatingSnapshotModel, error) {
+	factors, err := marshalField(snapshot.Factors)
+	if err != nil {
+		return nil, err
+	}
+	return &database.RatingSnapshotModel{
+		NGOID:             snapshot.NGOID,
+		Model:             snapshot.Model,
+		Rating:            snapshot.Rating,
+		TransparencyScore: snapshot.TransparencyScore,
+		PeriodDays:        snapshot.PeriodDays,
+		Factors:           factors,
+		CreatedAt:         snapshot.RecordedAt,
+	}, nil
+}
+
+func ratingSnapshotFromModel(model *database.RatingSnapshotModel) (*RatingSnapshot, error) {
+	snapshot := &RatingSnapshot{
+		NGOID:             model.NGOID,
+		Model:             model.Model,
+		Rating:            model.Rating,
+		TransparencyScore: model.TransparencyScore,
+		PeriodDays:        model.PeriodDays,
+		Factors:           make([]rating.Factor, 0),
+		RecordedAt:        model.CreatedAt,
+	}
+	if err := unmarshalField(model.Factors, &snapshot.Factors); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
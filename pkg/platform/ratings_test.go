@@ -0,0 +1,74 @@
+package platform
+
+import (
+	"testing"
+
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/rating"
+)
+
+func TestRatingHistoryExplainsMoves(t *testing.T) {
+	p, repos := newAppealPlatform(t)
+
+	ratings := p.CalculateAllNGORatings(30)
+	if len(ratings) != 1 || ratings[0]["model"] != rating.BaselineName {
+		t.Fatalf("Expected the NGO rated by the baseline model, got %+v", ratings)
+	}
+	// Nothing spent yet: 5 - 2 for the gap - 0.5 for spending + 0.5 for documentation + 0.2 for KYC + 0.3 for the certificate
+	if ratings[0]["rating"] != 3.5 || len(ratings[0]["factors"].([]rating.Factor)) != 5 {
+		t.Fatalf("Expected a rating of 3.5 made of five factors, got %+v", ratings[0])
+	}
+
+	// Rating again without any activity records nothing new
+	p.CalculateAllNGORatings(30)
+	history, err := p.GetNGORatingHistory("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to get rating history: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("Expected one recorded rating, got %+v", history)
+	}
+	if _, err := p.GetNGORatingHistory("NGO404"); err == nil {
+		t.Errorf("Expected an unknown NGO's history to be refused")
+	}
+
+	if _, err := recordExpenditure(t, p, "NGO001", invoiced(map[string]interface{}{"amount": 700.0, "category": "education", "description": "Books"})); err != nil {
+		t.Fatalf("Failed to record expenditure: %v", err)
+	}
+	dashboard, err := p.GetNGODashboard("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to get dashboard: %v", err)
+	}
+
+	history, _ = p.GetNGORatingHistory("NGO001")
+	if len(history) != 2 || history[1].Change <= 0 {
+		t.Fatalf("Expected the spending to raise the rating, got %+v", history)
+	}
+	if details := dashboard["rating_details"].(*entities.RatingDetails); details.Rating != history[1].Rating {
+		t.Errorf("Expected the dashboard to show the recorded rating, got %+v", details)
+	}
+	// Spending 70% of the donation closes the gap and reaches ideal utilization
+	moved := history[1].Changes
+	if len(moved) < 2 || moved[0].Name != rating.FactorGap || moved[0].Change != 1.5 || moved[1].Name != rating.FactorUtilization || moved[1].Change != 1 {
+		t.Errorf("Expected the gap and utilization to explain the move, got %+v", moved)
+	}
+	total := 0.0
+	for _, change := range moved {
+		total += change.Change
+	}
+	if diff := total - history[1].Change; diff > 0.001 || diff < -0.001 {
+		t.Errorf("Expected the factors to add up to the change of %v, got %v", history[1].Change, total)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	restored, err := reloaded.GetNGORatingHistory("NGO001")
+	if err != nil {
+		t.Fatalf("Failed to get reloaded rating history: %v", err)
+	}
+	if len(restored) != 2 || len(restored[1].Factors) != len(history[1].Factors) || restored[1].Rating != history[1].Rating {
+		t.Fatalf("Expected the rating history to survive a restart, got %+v", restored)
+	}
+	if reloaded.NGOs["NGO001"].Rating != history[1].Rating {
+		t.Errorf("Expected the latest rating %v to be stored, got %v", history[1].Rating, reloaded.NGOs["NGO001"].Rating)
+	}
+}
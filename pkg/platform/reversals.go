@@ -0,0 +1,235 @@
+package platform
+
+import (
+	"fmt"
+
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/ledger"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// Statuses of donations that have been refunded or charged back
+const (
+	donationPartiallyReversed = "partially_reversed"
+	donationReversed          = "reversed"
+)
+
+// RefundDonation returns grossAmount of one of the NGO's donations to the
+// donor, or all that remains of it when grossAmount is zero. Donations paid
+// through the gateway are refunded there before the reversal is recorded.
+func (p *NGOTransparencyPlatform) RefundDonation(ngoID, transactionID string, grossAmount money.Money, reason string) (*transactions.DonationReversal, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+	donor, record := p.findDonation(transactionID)
+	if record == nil || record.NGOID != ngoID {
+		return nil, ErrDonationNotFound
+	}
+	if grossAmount.IsZero() {
+		grossAmount = record.GrossAmount
+	}
+	if grossAmount.Cmp(record.GrossAmount) > 0 {
+		return nil, fmt.Errorf("refund exceeds remaining donation of ₹%s", record.GrossAmount)
+	}
+
+	// Earmarked money the campaign has already spent cannot be refunded
+	if campaign, exists := ngo.GetCampaign(record.CampaignID); exists {
+		platformFee, err := reversalFee(record, grossAmount)
+		if err != nil {
+			return nil, err
+		}
+		ngoShare := grossAmount.Sub(platformFee)
+		if ngoShare.Cmp(campaign.RestrictedBalance()) > 0 {
+			return nil, fmt.Errorf("refund exceeds campaign's restricted balance of ₹%s", campaign.RestrictedBalance())
+		}
+	}
+
+	gatewayReference := ""
+	if intent := p.paymentIntentFor(transactionID); intent != nil {
+		if p.PaymentGateway == nil || p.PaymentGateway.Name() != intent.Gateway {
+			return nil, fmt.Errorf("payment gateway %s not configured", intent.Gateway)
+		}
+		refund, err := p.PaymentGateway.Refund(payments.RefundRequest{
+			OrderID:   intent.GatewayOrderID,
+			PaymentID: intent.GatewayPaymentID,
+			Amount:    grossAmount,
+			Reason:    reason,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gateway refund failed: %w", err)
+		}
+		gatewayReference = refund.RefundID
+	}
+
+	reversal, err := p.reverseDonation(transactions.ReversalRefund, donor, record, ngo, grossAmount, reason, gatewayReference)
+	if err != nil && gatewayReference != "" {
+		return nil, fmt.Errorf("gateway refund %s issued but not recorded: %w", gatewayReference, err)
+	}
+	return reversal, err
+}
+
+// chargebackPaymentIntent records a chargeback reported by the gateway against
+// a settled payment. Redelivered chargebacks are recognised by their dispute ID.
+func (p *NGOTransparencyPlatform) chargebackPaymentIntent(intent *PaymentIntent, event *payments.WebhookEvent) error {
+	if intent.Status != PaymentIntentSucceeded {
+		return fmt.Errorf("chargeback for payment intent %s that has not succeeded", intent.IntentID)
+	}
+
+	ngo, exists := p.NGOs[intent.NGOID]
+	if !exists {
+		return ErrNGONotFound
+	}
+	if event.DisputeID != "" && findReversalBlock(ngo.DonationBlockchain, event.DisputeID) != nil {
+		return nil
+	}
+
+	donor, record := p.findDonation(intent.TransactionID)
+	if record == nil {
+		return fmt.Errorf("donation %s has already been fully reversed", intent.TransactionID)
+	}
+
+	reason := event.DisputeReason
+	if reason == "" {
+		reason = "chargeback"
+	}
+	_, err := p.reverseDonation(transactions.ReversalChargeback, donor, record, ngo, event.Amount, reason, event.DisputeID)
+	return err
+}
+
+// reverseDonation appends a reversal block for grossAmount of the donation and
+// brings the donor's history, e-bill, the ledger and stored records in line
+func (p *NGOTransparencyPlatform) reverseDonation(kind string, donor *entities.Donor, record *entities.DonationRecord, ngo *entities.NGO, grossAmount money.Money, reason, gatewayReference string) (*transactions.DonationReversal, error) {
+	if grossAmount.Cmp(record.GrossAmount) > 0 {
+		return nil, fmt.Errorf("reversal exceeds remaining donation of ₹%s", record.GrossAmount)
+	}
+
+	platformFee, err := reversalFee(record, grossAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	original := findDonationBlock(ngo.DonationBlockchain, record.TransactionID)
+	if original == nil {
+		return nil, fmt.Errorf("donation block not found")
+	}
+
+	reversal, err := transactions.NewDonationReversal(kind, record.TransactionID, original.Hash, donor.DonorID, ngo.NGOID, grossAmount, platformFee, reason)
+	if err != nil {
+		return nil, err
+	}
+	reversal.GatewayReference = gatewayReference
+	reversal.ReplaceEBill(record.EBill, record.Amount.Sub(reversal.Amount))
+
+	// The stored donation keeps its current e-bill: the reissued one, or the
+	// original marked void once nothing of the donation remains
+	status := donationPartiallyReversed
+	eBill := reversal.ReissuedEBill
+	if reversal.GrossAmount.Equal(record.GrossAmount) {
+		status = donationReversed
+		if record.EBill != nil {
+			voided := *record.EBill
+			voided.Void(reason)
+			eBill = &voided
+		}
+	}
+
+	entries := ledger.DonationReversalEntries(ngo.NGOID, reversal.ReversalID, reversal.TransactionID, grossAmount, platformFee, reversal.Timestamp)
+	if err := p.Ledger.Validate(entries...); err != nil {
+		return nil, fmt.Errorf("failed to record reversal in ledger: %w", err)
+	}
+
+	if _, err := ngo.ReverseDonation(reversal); err != nil {
+		return nil, err
+	}
+	block := ngo.DonationBlockchain.GetLatestBlock()
+	if err := donor.ApplyReversal(reversal); err != nil {
+		p.reloadNGO(ngo.NGOID)
+		return nil, err
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		if err := saveReversal(tx, reversal, status, eBill); err != nil {
+			return err
+		}
+		if err := saveBlock(tx, ngo.NGOID, block); err != nil {
+			return err
+		}
+		if err := saveNGO(tx, ngo); err != nil {
+			return err
+		}
+		if err := saveJournalEntries(tx, entries); err != nil {
+			return err
+		}
+		return saveDonor(tx, donor)
+	})
+	if err != nil {
+		p.reloadNGO(ngo.NGOID)
+		p.reloadDonor(donor.DonorID)
+		return nil, fmt.Errorf("failed to persist reversal: %w", err)
+	}
+
+	if err := p.Ledger.Post(entries...); err != nil {
+		return nil, fmt.Errorf("failed to post reversal to ledger: %w", err)
+	}
+
+	p.SystemStats.TotalTransactions++
+	p.SystemStats.TotalDonations = p.SystemStats.TotalDonations.Sub(reversal.Amount)
+	p.SystemStats.TotalPlatformFees = p.SystemStats.TotalPlatformFees.Sub(platformFee)
+
+	return reversal, nil
+}
+
+// reversalFee returns the platform's share of reversing grossAmount of a
+// donation. The platform returns its fee pro rata; the last reversal takes
+// whatever is left so rounding never leaves a stray paisa behind.
+func reversalFee(record *entities.DonationRecord, grossAmount money.Money) (money.Money, error) {
+	if grossAmount.Equal(record.GrossAmount) {
+		return record.PlatformFee, nil
+	}
+	return record.PlatformFee.MulRatio(grossAmount.Minor(), record.GrossAmount.Minor(), money.RoundHalfUp)
+}
+
+// findDonation returns the donor and record of a donation that has not been
+// fully reversed
+func (p *NGOTransparencyPlatform) findDonation(transactionID string) (*entities.Donor, *entities.DonationRecord) {
+	for _, donor := range p.Donors {
+		if record, exists := donor.GetDonation(transactionID); exists {
+			return donor, record
+		}
+	}
+	return nil, nil
+}
+
+// paymentIntentFor returns the settled payment intent a donation was paid through
+func (p *NGOTransparencyPlatform) paymentIntentFor(transactionID string) *PaymentIntent {
+	for _, intent := range p.PaymentIntents {
+		if intent.TransactionID == transactionID && intent.Status == PaymentIntentSucceeded {
+			return intent
+		}
+	}
+	return nil
+}
+
+// findDonationBlock returns the block a donation was mined in
+func findDonationBlock(chain *blockchain.Blockchain, transactionID string) *blockchain.Block {
+	return chain.FindBlock(func(block *blockchain.Block) bool {
+		data, ok := block.Data.(map[string]interface{})
+		return ok && data["type"] == "donation" && data["transaction_id"] == transactionID
+	})
+}
+
+// findReversalBlock returns the reversal block recorded under a gateway refund or dispute ID
+func findReversalBlock(chain *blockchain.Blockchain, gatewayReference string) *blockchain.Block {
+	return chain.FindBlock(func(block *blockchain.Block) bool {
+		data, ok := block.Data.(map[string]interface{})
+		return ok && data["type"] == "donation_reversal" && data["gateway_reference"] == gatewayReference
+	})
+}
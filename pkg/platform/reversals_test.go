@@ -0,0 +1,137 @@
+package platform
+
+import (
+	"testing"
+
+	"ngo-transparency-platform/pkg/ledger"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+func TestRefundDonation(t *testing.T) {
+	p, repos, gateway := newPaymentsPlatform(t)
+	intent := settledIntent(t, p, gateway, "", money.INR(100000))
+	donor, ngo := p.Donors["DONOR001"], p.NGOs["NGO001"]
+	originalBill := donor.DonationHistory[0].EBill
+
+	if _, err := p.RefundDonation("NGO002", intent.TransactionID, money.INR(40000), "duplicate payment"); err == nil {
+		t.Error("Expected another NGO's refund to be rejected")
+	}
+
+	partial, err := p.RefundDonation("NGO001", intent.TransactionID, money.INR(40000), "duplicate payment")
+	if err != nil {
+		t.Fatalf("Failed to refund donation: %v", err)
+	}
+	if partial.PlatformFee.Minor() != 400 || partial.Amount.Minor() != 39600 || partial.GatewayReference == "" {
+		t.Errorf("Expected ₹396 borne by the NGO and ₹4 fee returned, got %+v", partial)
+	}
+	if partial.OriginalBlockHash != intent.BlockHash || partial.VoidedBillID != originalBill.BillID {
+		t.Errorf("Expected reversal to reference the donation block and void its bill, got %+v", partial)
+	}
+
+	record := donor.DonationHistory[0]
+	if !record.Amount.Equal(money.INR(59400)) || !donor.TotalDonated.Equal(money.INR(59400)) {
+		t.Errorf("Expected ₹594 left of the donation, got record ₹%s and total ₹%s", record.Amount, donor.TotalDonated)
+	}
+	if record.EBill.Replaces != originalBill.BillID || !record.EBill.Amount.Equal(money.INR(59400)) {
+		t.Errorf("Expected reissued e-bill for ₹594, got %+v", record.EBill)
+	}
+	if limit := donor.CheckDonationLimit(money.Zero()); !limit.CurrentYearTotal.Equal(money.INR(59400)) {
+		t.Errorf("Expected annual limit usage of ₹594, got ₹%s", limit.CurrentYearTotal)
+	}
+	if benefit := donor.GetAnnualTaxBenefits(0); !benefit.TotalDeductible.Equal(money.INR(59400)) {
+		t.Errorf("Expected ₹594 deductible, got ₹%s", benefit.TotalDeductible)
+	}
+	if ngo.DonationBlockchain.GetChainLength() != 3 || !ngo.TotalDonationsReceived.Equal(money.INR(59400)) {
+		t.Errorf("Expected a reversal block and ₹594 received, got %d blocks and ₹%s",
+			ngo.DonationBlockchain.GetChainLength(), ngo.TotalDonationsReceived)
+	}
+
+	// Refunding the rest returns the remaining fee exactly and voids the bill
+	full, err := p.RefundDonation("NGO001", intent.TransactionID, money.Zero(), "donor request")
+	if err != nil {
+		t.Fatalf("Failed to refund remaining donation: %v", err)
+	}
+	if !full.GrossAmount.Equal(money.INR(60000)) || full.PlatformFee.Minor() != 600 || full.ReissuedEBill != nil {
+		t.Errorf("Unexpected final refund: %+v", full)
+	}
+	if len(donor.DonationHistory) != 0 || !donor.TotalDonated.IsZero() || len(donor.TaxBenefits) != 0 {
+		t.Errorf("Expected fully refunded donation to leave the donor's history, got %+v", donor)
+	}
+	if _, err := p.RefundDonation("NGO001", intent.TransactionID, money.INR(100), "again"); err == nil {
+		t.Error("Expected refund of a fully refunded donation to fail")
+	}
+
+	bank, _ := p.Ledger.Balance("NGO001", ledger.AccountBank)
+	if !bank.IsZero() || !p.SystemStats.TotalPlatformFees.IsZero() || !ngo.GetFinancialSummary(1).TotalDonations.IsZero() {
+		t.Errorf("Expected nothing left after a full refund, got bank ₹%s and fees ₹%s", bank, p.SystemStats.TotalPlatformFees)
+	}
+
+	stored, err := repos.Donations.GetByTransactionID(intent.TransactionID)
+	if err != nil || stored.Status != donationReversed || !stored.ReversedAmount.Equal(money.INR(100000)) || !stored.ReversedFee.Equal(money.INR(1000)) {
+		t.Errorf("Expected stored donation to be reversed, got %+v", stored)
+	}
+	reversals, _ := repos.Reversals.GetByTransactionID(intent.TransactionID)
+	if len(reversals) != 2 {
+		t.Errorf("Expected two stored reversals, got %d", len(reversals))
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	if !reloaded.NGOs["NGO001"].TotalDonationsReceived.IsZero() || len(reloaded.Donors["DONOR001"].DonationHistory) != 0 {
+		t.Error("Expected reversals to survive a reload")
+	}
+	if !reloaded.SystemStats.TotalPlatformFees.IsZero() {
+		t.Errorf("Expected no platform fees after reload, got ₹%s", reloaded.SystemStats.TotalPlatformFees)
+	}
+}
+
+func TestPartialRefundSurvivesReload(t *testing.T) {
+	p, repos, gateway := newPaymentsPlatform(t)
+	intent := settledIntent(t, p, gateway, "", money.INR(100000))
+
+	if _, err := p.RefundDonation("NGO001", intent.TransactionID, money.INR(25000), "partial refund"); err != nil {
+		t.Fatalf("Failed to refund donation: %v", err)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	record, exists := reloaded.Donors["DONOR001"].GetDonation(intent.TransactionID)
+	if !exists || !record.Amount.Equal(money.INR(74250)) || !record.GrossAmount.Equal(money.INR(75000)) || !record.PlatformFee.Equal(money.INR(750)) {
+		t.Fatalf("Expected ₹750 of the donation after reload, got %+v", record)
+	}
+	if record.EBill == nil || record.EBill.Replaces == "" {
+		t.Errorf("Expected reissued e-bill after reload, got %+v", record.EBill)
+	}
+	if !reloaded.SystemStats.TotalPlatformFees.Equal(money.INR(750)) {
+		t.Errorf("Expected ₹7.50 platform fees after reload, got ₹%s", reloaded.SystemStats.TotalPlatformFees)
+	}
+}
+
+func TestChargebackWebhookReversesDonation(t *testing.T) {
+	p, repos, gateway := newPaymentsPlatform(t)
+	intent := settledIntent(t, p, gateway, "", money.INR(50000))
+
+	payload, signature, err := gateway.Chargeback(intent.GatewayOrderID, money.INR(50000), "fraudulent transaction")
+	if err != nil {
+		t.Fatalf("Failed to raise chargeback: %v", err)
+	}
+	if _, err := p.HandlePaymentWebhook(payload, signature); err != nil {
+		t.Fatalf("Failed to handle chargeback: %v", err)
+	}
+	// A redelivered chargeback must not reverse twice
+	if _, err := p.HandlePaymentWebhook(payload, signature); err != nil {
+		t.Fatalf("Failed to handle repeated chargeback: %v", err)
+	}
+
+	ngo := p.NGOs["NGO001"]
+	if ngo.DonationBlockchain.GetChainLength() != 3 || !ngo.TotalDonationsReceived.IsZero() {
+		t.Errorf("Expected one reversal block, got %d blocks and ₹%s received",
+			ngo.DonationBlockchain.GetChainLength(), ngo.TotalDonationsReceived)
+	}
+	reversals, _ := repos.Reversals.GetByTransactionID(intent.TransactionID)
+	if len(reversals) != 1 || reversals[0].Kind != transactions.ReversalChargeback || reversals[0].Reason != "fraudulent transaction" {
+		t.Errorf("Expected one stored chargeback, got %+v", reversals)
+	}
+	if _, err := p.RefundDonation("NGO001", intent.TransactionID, money.Zero(), "too late"); err == nil {
+		t.Error("Expected a charged back donation not to be refunded")
+	}
+}
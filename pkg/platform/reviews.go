@@ -0,0 +1,609 @@
+package platform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/anomaly"
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/consensus"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/ledger"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// ExpenditureInformation is an NGO's reply to an auditor's request for
+// information. Payment references and documents given replace those submitted.
+type ExpenditureInformation struct {
+	Notes             string   `json:"notes"`
+	BankTransactionID string   `json:"bank_transaction_id,omitempty"`
+	ChequeNumber      string   `json:"cheque_number,omitempty"`
+	Documents         []string `json:"documents,omitempty"`
+}
+
+// SubmitExpenditure queues an expenditure for review by auditors chosen
+// under the platform's AssignmentPolicy: one auditor, or a panel when the
+// amount reaches a threshold of the ConsensusPolicy. Nothing is written to
+// the expenditure blockchain until the expenditure is approved.
+func (p *NGOTransparencyPlatform) SubmitExpenditure(ngoID string, expenditureData map[string]interface{}) (map[string]interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.submitExpenditure("", ngoID, expenditureData)
+}
+
+// submitExpenditure queues an expenditure under transactionID, or under a
+// fresh ID when transactionID is empty
+func (p *NGOTransparencyPlatform) submitExpenditure(transactionID, ngoID string, expenditureData map[string]interface{}) (map[string]interface{}, error) {
+	ngo, ngoExists := p.NGOs[ngoID]
+	if !ngoExists {
+		return nil, ErrNGONotFound
+	}
+
+	// Extract expenditure data
+	amount, ok := parseAmount(expenditureData["amount"])
+	if !ok || !amount.IsPositive() {
+		return nil, fmt.Errorf("invalid amount")
+	}
+
+	category, _ := expenditureData["category"].(string)
+	description, _ := expenditureData["description"].(string)
+	campaignID, _ := expenditureData["campaign_id"].(string)
+	funding, _ := expenditureData["funding"].([]transactions.FundAllocation)
+
+	// Every expenditure must be backed by the vendor's invoice or e-invoice
+	invoiceDetails, ok := expenditureData["invoice"].(transactions.InvoiceDetails)
+	if submission, given := expenditureData["einvoice"].(EInvoiceSubmission); given {
+		if ok {
+			return nil, fmt.Errorf("give either an invoice or an e-invoice, not both")
+		}
+		var err error
+		if invoiceDetails, err = p.eInvoiceDetails(submission); err != nil {
+			return nil, err
+		}
+		if amount.Cmp(invoiceDetails.EInvoice.Total) > 0 {
+			return nil, fmt.Errorf("expenditure exceeds the e-invoice total of ₹%s", invoiceDetails.EInvoice.Total)
+		}
+		ok = true
+	}
+	if !ok {
+		return nil, fmt.Errorf("an invoice or e-invoice is required")
+	}
+	invoiceDetails.BankTransactionID, _ = expenditureData["bank_transaction_id"].(string)
+	invoiceDetails.ChequeNumber, _ = expenditureData["cheque_number"].(string)
+
+	// Attach the documents the NGO uploaded, named by their hashes
+	hashes, _ := expenditureData["attachments"].([]string)
+	attachments, err := p.documentAttachments(ngoID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create expenditure transaction
+	var expenditure *transactions.ExpenditureTransaction
+	if transactionID == "" {
+		expenditure = transactions.NewExpenditureTransaction(ngoID, amount, category, description, invoiceDetails, attachments, p.ComplianceRules)
+	} else {
+		expenditure = transactions.NewExpenditureTransactionWithID(transactionID, ngoID, amount, category, description, invoiceDetails, attachments, p.ComplianceRules)
+	}
+	expenditure.CampaignID = campaignID
+	if err := p.reserveExpenditure(ngo, expenditure, funding); err != nil {
+		return nil, err
+	}
+
+	threshold := p.ConsensusPolicy.For(amount)
+	records, err := p.assignAuditors(expenditure, threshold.Panel, threshold.Required, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		expenditure.Panel = append(expenditure.Panel, record.AuditorID)
+	}
+	expenditure.AssignedAuditorID = expenditure.Panel[0]
+	expenditure.RequiredApprovals = threshold.Required
+
+	err = p.persist(func(tx *database.Repositories) error {
+		model, err := expenditureToModel(expenditure, "", "")
+		if err != nil {
+			return err
+		}
+		if err := tx.Expenditures.Save(model); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := tx.Assignments.Create(assignmentToModel(record)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist expenditure: %w", err)
+	}
+
+	p.PendingExpenditures[expenditure.TransactionID] = expenditure
+	p.Assignments = append(p.Assignments, records...)
+
+	view := p.pendingExpenditureView(expenditure)
+	view["assignments"] = records
+	return view, nil
+}
+
+// reserveExpenditure checks an expenditure about to be queued for review and
+// allocates the donations that pay for it. The funds, campaign balance and
+// invoices held by the other expenditures under review count as taken, so
+// that approving any of them later cannot fail.
+func (p *NGOTransparencyPlatform) reserveExpenditure(ngo *entities.NGO, expenditure *transactions.ExpenditureTransaction, requested []transactions.FundAllocation) error {
+	if !expenditure.VerifyGSTIN(expenditure.InvoiceDetails.GSTIN) {
+		return fmt.Errorf("invalid GSTIN")
+	}
+
+	// Amendments under review change expenditures already recorded
+	reserved := make([]transactions.FundAllocation, 0)
+	reservedAmount := money.Zero()
+	pendingInvoices := transactions.NewInvoiceIndex()
+	for _, pending := range p.PendingExpenditures {
+		if pending.TransactionID == expenditure.TransactionID || isAmendmentUnderReview(pending) {
+			continue
+		}
+		if record, ok := transactions.NewInvoiceRecord(pending); ok {
+			pendingInvoices.Add(record)
+		}
+		if pending.NGOID != ngo.NGOID {
+			continue
+		}
+		reserved = append(reserved, pending.Funding...)
+		if expenditure.CampaignID != "" && pending.CampaignID == expenditure.CampaignID {
+			reservedAmount = reservedAmount.Add(pending.Amount)
+		}
+	}
+
+	if expenditure.CampaignID != "" {
+		campaign, exists := ngo.GetCampaign(expenditure.CampaignID)
+		if !exists {
+			return ErrCampaignNotFound
+		}
+		available := money.Max(money.Zero(), campaign.RestrictedBalance().Sub(reservedAmount))
+		if expenditure.Amount.Cmp(available) > 0 {
+			return fmt.Errorf("expenditure exceeds campaign's restricted balance of ₹%s", available)
+		}
+	}
+
+	funding, err := ngo.AllocateFunding(expenditure.CampaignID, expenditure.Amount, requested, reserved)
+	if err != nil {
+		return err
+	}
+	expenditure.Funding = funding
+
+	// Reject invoices already paid or awaiting approval, and flag likely
+	// duplicates for the reviewer
+	record, ok, err := p.checkInvoice(expenditure)
+	if err != nil {
+		return err
+	}
+	if ok {
+		for _, flag := range pendingInvoices.Check(record) {
+			if flag.Kind == transactions.DuplicateInvoiceNumber {
+				return fmt.Errorf("duplicate invoice: %s", flag.Description())
+			}
+		}
+	}
+	return nil
+}
+
+// GetPendingExpenditures returns the expenditures assigned to an auditor that
+// await the auditor's review, oldest first, with the results of the
+// automated checks and the anomaly alerts concerning each
+func (p *NGOTransparencyPlatform) GetPendingExpenditures(auditorID string) []map[string]interface{} {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	views := p.pendingExpenditures(func(expenditure *transactions.ExpenditureTransaction) bool {
+		return expenditure.IsAssignedTo(auditorID) && !expenditure.HasVoted(auditorID)
+	})
+	for _, view := range views {
+		alerts := p.pendingAnomalies(p.PendingExpenditures[view["transaction_id"].(string)])
+		view["anomaly_alerts"] = alerts
+		view["risk_score"] = anomaly.RiskScore(alerts)
+	}
+	return views
+}
+
+// GetEscalatedExpenditures returns the pending expenditures whose panel
+// disagrees, oldest first
+func (p *NGOTransparencyPlatform) GetEscalatedExpenditures() []map[string]interface{} {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.pendingExpenditures(func(expenditure *transactions.ExpenditureTransaction) bool {
+		return expenditure.Consensus != nil && expenditure.Consensus.Escalated
+	})
+}
+
+// GetNGOPendingExpenditures returns an NGO's expenditures still under review,
+// oldest first, including any the auditor has asked about
+func (p *NGOTransparencyPlatform) GetNGOPendingExpenditures(ngoID string) []map[string]interface{} {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.pendingExpenditures(func(expenditure *transactions.ExpenditureTransaction) bool {
+		return expenditure.NGOID == ngoID
+	})
+}
+
+func (p *NGOTransparencyPlatform) pendingExpenditures(match func(*transactions.ExpenditureTransaction) bool) []map[string]interface{} {
+	var pending []*transactions.ExpenditureTransaction
+	for _, expenditure := range p.PendingExpenditures {
+		if match(expenditure) {
+			pending = append(pending, expenditure)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Timestamp.Before(pending[j].Timestamp)
+	})
+
+	views := make([]map[string]interface{}, 0, len(pending))
+	for _, expenditure := range pending {
+		views = append(views, p.pendingExpenditureView(expenditure))
+	}
+	return views
+}
+
+// ReviewExpenditure records an assigned auditor's decision on a queued
+// expenditure, with the auditor's compliance score when given. Once enough
+// of the panel approves, the expenditure is mined into the NGO's expenditure
+// blockchain and posted to the ledger; once enough rejects, the rejection is
+// recorded with the auditors' reasons. A request for information returns the
+// expenditure to the NGO. Notes are required unless the auditor approves.
+func (p *NGOTransparencyPlatform) ReviewExpenditure(auditorID, expenditureID, decision, notes string, score *float64) (map[string]interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	pending, exists := p.PendingExpenditures[expenditureID]
+	if !exists {
+		return nil, ErrExpenditureNotFound
+	}
+	if !pending.IsAssignedTo(auditorID) {
+		return nil, ErrNotAssigned
+	}
+	if pending.HasVoted(auditorID) {
+		return nil, fmt.Errorf("auditor has already reviewed this expenditure")
+	}
+	auditor, exists := p.Auditors[auditorID]
+	if !exists {
+		return nil, ErrAuditorNotFound
+	}
+	if !auditor.Verified {
+		return nil, fmt.Errorf("auditor not verified")
+	}
+	if pending.IsInformationRequested() {
+		return nil, fmt.Errorf("expenditure is awaiting information from the NGO")
+	}
+
+	notes = strings.TrimSpace(notes)
+	if notes == "" && decision != transactions.DecisionApprove {
+		return nil, fmt.Errorf("notes are required to reject an expenditure or request information")
+	}
+	if score != nil && (*score < 0 || *score > 100) {
+		return nil, fmt.Errorf("score must be between 0 and 100")
+	}
+
+	expenditure := copyExpenditure(pending)
+	switch decision {
+	case transactions.DecisionApprove, transactions.DecisionReject:
+		approve := decision == transactions.DecisionApprove
+		if score == nil {
+			automated := expenditure.ComplianceScore
+			score = &automated
+		}
+		vote := consensus.Vote{AuditorID: auditorID, Approve: approve, Score: *score}
+		outcome := p.tally(expenditure, &vote)
+		switch {
+		case !outcome.Decided:
+			return p.recordVote(auditor, expenditure, approve, notes, *score, outcome)
+		case outcome.Approved:
+			return p.approveExpenditure(auditor, expenditure, notes, *score, outcome)
+		default:
+			return p.rejectExpenditure(auditor, expenditure, notes, *score, outcome)
+		}
+	case transactions.DecisionRequestInfo:
+		expenditure.RequestInformation(auditorID, notes)
+		if err := p.saveReviewedExpenditure(expenditure); err != nil {
+			return nil, err
+		}
+		return p.pendingExpenditureView(expenditure), nil
+	default:
+		return nil, fmt.Errorf("invalid decision %q", decision)
+	}
+}
+
+// ProvideExpenditureInformation records an NGO's reply to an auditor's
+// request for information and returns the expenditure to the auditor's queue
+func (p *NGOTransparencyPlatform) ProvideExpenditureInformation(ngoID, expenditureID string, info ExpenditureInformation) (map[string]interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	pending, exists := p.PendingExpenditures[expenditureID]
+	if !exists || pending.NGOID != ngoID {
+		return nil, ErrExpenditureNotFound
+	}
+	if !pending.IsInformationRequested() {
+		return nil, fmt.Errorf("no information was requested for this expenditure")
+	}
+	notes := strings.TrimSpace(info.Notes)
+	if notes == "" {
+		return nil, fmt.Errorf("notes are required")
+	}
+
+	expenditure := copyExpenditure(pending)
+	if info.BankTransactionID != "" {
+		expenditure.InvoiceDetails.BankTransactionID = info.BankTransactionID
+	}
+	if info.ChequeNumber != "" {
+		expenditure.InvoiceDetails.ChequeNumber = info.ChequeNumber
+	}
+	if len(info.Documents) > 0 {
+		expenditure.InvoiceDetails.Documents = info.Documents
+	}
+	expenditure.ProvideInformation(notes, p.ComplianceRules)
+
+	if err := p.saveReviewedExpenditure(expenditure); err != nil {
+		return nil, err
+	}
+	return p.pendingExpenditureView(expenditure), nil
+}
+
+// tally counts the panel's votes on an expenditure, including vote when given
+func (p *NGOTransparencyPlatform) tally(expenditure *transactions.ExpenditureTransaction, vote *consensus.Vote) consensus.Outcome {
+	votes := expenditure.ConsensusVotes()
+	if vote != nil {
+		votes = append(votes, *vote)
+	}
+	return p.ConsensusPolicy.Tally(expenditure.Approvals(), len(expenditure.Reviewers()), votes)
+}
+
+// castVote records an auditor's signed vote and, for panels, the resulting tally
+func (p *NGOTransparencyPlatform) castVote(auditor *entities.Auditor, expenditure *transactions.ExpenditureTransaction, approve bool, notes string, score float64, outcome consensus.Outcome) *entities.AuditResult {
+	auditResult := auditor.ReviewExpenditure(expenditure, approve, notes, &score, p.ComplianceRules)
+	expenditure.Vote(auditor.AuditorID, approve, notes, score, auditResult.Signature)
+	if len(expenditure.Reviewers()) > 1 {
+		expenditure.Consensus = &outcome
+	}
+	return auditResult
+}
+
+// recordVote stores a panel member's vote on an expenditure the panel has
+// not yet decided
+func (p *NGOTransparencyPlatform) recordVote(auditor *entities.Auditor, expenditure *transactions.ExpenditureTransaction, approve bool, notes string, score float64, outcome consensus.Outcome) (map[string]interface{}, error) {
+	auditResult := p.castVote(auditor, expenditure, approve, notes, score, outcome)
+
+	err := p.persist(func(tx *database.Repositories) error {
+		return saveExpenditureAudit(tx, expenditure, auditResult, auditor, "", "")
+	})
+	if err != nil {
+		p.reloadAuditor(auditor.AuditorID)
+		return nil, fmt.Errorf("failed to persist vote: %w", err)
+	}
+
+	p.PendingExpenditures[expenditure.TransactionID] = expenditure
+	view := p.pendingExpenditureView(expenditure)
+	view["audit_result"] = auditResult
+	return view, nil
+}
+
+// approveExpenditure records an approved expenditure in the NGO's chain and ledger
+func (p *NGOTransparencyPlatform) approveExpenditure(auditor *entities.Auditor, expenditure *transactions.ExpenditureTransaction, notes string, score float64, outcome consensus.Outcome) (map[string]interface{}, error) {
+	if isAmendmentUnderReview(expenditure) {
+		return p.approveAmendment(auditor, expenditure, notes, score, outcome)
+	}
+
+	ngoID := expenditure.NGOID
+	ngo, exists := p.NGOs[ngoID]
+	if !exists {
+		return nil, ErrNGONotFound
+	}
+
+	// The funds and invoice were reserved when the expenditure was submitted
+	invoice, indexInvoice := transactions.NewInvoiceRecord(expenditure)
+
+	auditResult := p.castVote(auditor, expenditure, true, notes, score, outcome)
+	expenditure.ValidateByAuditor(auditor.AuditorID, true, notes, &outcome.Score)
+
+	entries := ledger.ExpenditureEntries(ngoID, expenditure.TransactionID, expenditure.Category, expenditure.Amount, expenditure.Timestamp)
+	if err := p.Ledger.Validate(entries...); err != nil {
+		p.reloadAuditor(auditor.AuditorID)
+		return nil, fmt.Errorf("failed to record expenditure in ledger: %w", err)
+	}
+
+	result, err := ngo.ProcessExpenditure(expenditure)
+	if err != nil {
+		// Drop the unrecorded audit from the cached auditor history
+		p.reloadAuditor(auditor.AuditorID)
+		return nil, err
+	}
+	block := ngo.ExpenditureBlockchain.GetLatestBlock()
+
+	// Suppliers first paid through an invoice join the vendor registry
+	var vendor *entities.Vendor
+	if indexInvoice {
+		vendor = p.invoiceVendor(ngoID, expenditure.InvoiceDetails)
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		if err := saveExpenditureAudit(tx, expenditure, auditResult, auditor, result.BlockHash, ""); err != nil {
+			return err
+		}
+		if err := saveBlock(tx, ngoID, block); err != nil {
+			return err
+		}
+		if err := saveJournalEntries(tx, entries); err != nil {
+			return err
+		}
+		if vendor != nil {
+			if err := tx.Vendors.Save(vendorToModel(vendor)); err != nil {
+				return err
+			}
+		}
+		return saveNGO(tx, ngo)
+	})
+	if err != nil {
+		p.reloadNGO(ngoID)
+		p.reloadAuditor(auditor.AuditorID)
+		return nil, fmt.Errorf("failed to persist expenditure: %w", err)
+	}
+
+	delete(p.PendingExpenditures, expenditure.TransactionID)
+	if indexInvoice {
+		p.invoices.Add(invoice)
+	}
+	if vendor != nil {
+		p.Vendors[vendor.GSTIN] = vendor
+	}
+
+	if err := p.Ledger.Post(entries...); err != nil {
+		return nil, fmt.Errorf("failed to post expenditure to ledger: %w", err)
+	}
+
+	// Update system stats
+	p.SystemStats.TotalTransactions++
+	p.SystemStats.TotalExpenditures = p.SystemStats.TotalExpenditures.Add(expenditure.Amount)
+
+	// Anchor the stored block to Polygon if available
+	_, anchorErr := p.anchorBlock(result.BlockHash, ngoID, "expenditure", expenditure.TransactionID, map[string]interface{}{
+		"amount":   expenditure.Amount,
+		"category": expenditure.Category,
+	})
+
+	response := map[string]interface{}{
+		"success":        result.Success,
+		"status":         expenditure.Status,
+		"block_hash":     result.BlockHash,
+		"transaction_id": result.TransactionID,
+		"block_index":    result.BlockIndex,
+		"audit_result":   auditResult,
+		"invoice_flags":  expenditure.InvoiceFlags,
+		"consensus":      expenditure.Consensus,
+		"appeal":         expenditure.Appeal,
+	}
+	if anchorErr != nil {
+		response["anchor_error"] = anchorErr.Error()
+	}
+	return response, nil
+}
+
+// rejectExpenditure records the panel's rejection. Rejected expenditures
+// and their audits stay part of the record, where the NGO may appeal them,
+// but never reach the chain unless a rejection is upheld on appeal.
+func (p *NGOTransparencyPlatform) rejectExpenditure(auditor *entities.Auditor, expenditure *transactions.ExpenditureTransaction, notes string, score float64, outcome consensus.Outcome) (map[string]interface{}, error) {
+	if isAmendmentUnderReview(expenditure) {
+		return p.rejectAmendment(auditor, expenditure, notes, score, outcome)
+	}
+
+	auditResult := p.castVote(auditor, expenditure, false, notes, score, outcome)
+	expenditure.ValidateByAuditor(auditor.AuditorID, false, notes, &outcome.Score)
+
+	// A denied appeal is final and recorded in the NGO's chain
+	var block *blockchain.Block
+	if expenditure.Appeal != nil {
+		ngo, exists := p.NGOs[expenditure.NGOID]
+		if !exists {
+			p.reloadAuditor(auditor.AuditorID)
+			return nil, ErrNGONotFound
+		}
+		if _, err := ngo.RecordDeniedAppeal(expenditure); err != nil {
+			p.reloadAuditor(auditor.AuditorID)
+			return nil, err
+		}
+		block = ngo.ExpenditureBlockchain.GetLatestBlock()
+	}
+
+	err := p.persist(func(tx *database.Repositories) error {
+		if block == nil {
+			return saveExpenditureAudit(tx, expenditure, auditResult, auditor, "", "")
+		}
+		if err := saveExpenditureAudit(tx, expenditure, auditResult, auditor, block.Hash, ""); err != nil {
+			return err
+		}
+		return saveBlock(tx, expenditure.NGOID, block)
+	})
+	if err != nil {
+		if block != nil {
+			p.reloadNGO(expenditure.NGOID)
+		}
+		p.reloadAuditor(auditor.AuditorID)
+		return nil, fmt.Errorf("failed to persist rejected expenditure: %w", err)
+	}
+
+	delete(p.PendingExpenditures, expenditure.TransactionID)
+	p.RejectedExpenditures[expenditure.TransactionID] = expenditure
+
+	result := map[string]interface{}{
+		"success":        true,
+		"status":         expenditure.Status,
+		"transaction_id": expenditure.TransactionID,
+		"audit_result":   auditResult,
+		"consensus":      expenditure.Consensus,
+	}
+	if block != nil {
+		p.SystemStats.TotalTransactions++
+		result["appeal"] = expenditure.Appeal
+		result["block_hash"] = block.Hash
+		result["block_index"] = block.Index
+	}
+	return result, nil
+}
+
+// saveReviewedExpenditure stores an expenditure that remains under review
+func (p *NGOTransparencyPlatform) saveReviewedExpenditure(expenditure *transactions.ExpenditureTransaction) error {
+	err := p.persist(func(tx *database.Repositories) error {
+		model, err := expenditureToModel(expenditure, "", "")
+		if err != nil {
+			return err
+		}
+		return tx.Expenditures.Save(model)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist expenditure: %w", err)
+	}
+
+	p.PendingExpenditures[expenditure.TransactionID] = expenditure
+	return nil
+}
+
+// copyExpenditure copies a queued, rejected or recorded expenditure so a
+// review, appeal or amendment can be applied without touching the cached one until it is persisted
+func copyExpenditure(expenditure *transactions.ExpenditureTransaction) *transactions.ExpenditureTransaction {
+	updated := *expenditure
+	updated.Reviews = append([]transactions.ExpenditureReview(nil), expenditure.Reviews...)
+	updated.Panel = append([]string(nil), expenditure.Panel...)
+	updated.InvoiceDetails.Documents = append([]string(nil), expenditure.InvoiceDetails.Documents...)
+	updated.Attachments = append([]transactions.Attachment(nil), expenditure.Attachments...)
+	if expenditure.Appeal != nil {
+		appeal := *expenditure.Appeal
+		updated.Appeal = &appeal
+	}
+	if expenditure.Amendment != nil {
+		amendment := *expenditure.Amendment
+		updated.Amendment = &amendment
+	}
+	return &updated
+}
+
+// pendingExpenditureView describes a queued expenditure for its reviewer and NGO
+func (p *NGOTransparencyPlatform) pendingExpenditureView(expenditure *transactions.ExpenditureTransaction) map[string]interface{} {
+	view := expenditure.GetTransactionSummary()
+	view["invoice"] = expenditure.GetInvoiceInfo()
+	view["compliance_breakdown"] = expenditure.GetComplianceBreakdown(p.ComplianceRules)
+	view["compliance_issues"] = expenditure.GetComplianceIssues(p.ComplianceRules)
+	view["suggested_recommendation"] = expenditure.GetValidationRecommendation(p.ComplianceRules)
+	view["invoice_flags"] = expenditure.InvoiceFlags
+	view["funding"] = expenditure.Funding
+	if expenditure.CampaignID != "" {
+		view["campaign_id"] = expenditure.CampaignID
+	}
+	return view
+}
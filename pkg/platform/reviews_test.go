@@ -0,0 +1,178 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+var invoiceSequence int
+
+// invoiced adds a vendor invoice to expenditure data, numbered apart from
+// every other invoice the tests submit
+func invoiced(expenditureData map[string]interface{}) map[string]interface{} {
+	invoiceSequence++
+	expenditureData["invoice"] = transactions.InvoiceDetails{
+		InvoiceNumber: fmt.Sprintf("TEST/%d", invoiceSequence),
+		GSTIN:         "27AAPFU0939F1ZV",
+		VendorName:    "Bharat Books",
+		VendorGSTIN:   "29AAGCB7383J1Z4",
+		InvoiceDate:   time.Now(),
+		Documents:     []string{"invoice.pdf"},
+	}
+	return expenditureData
+}
+
+// recordExpenditure submits an expenditure and has the assigned auditor approve it
+func recordExpenditure(t *testing.T, p *NGOTransparencyPlatform, ngoID string, expenditureData map[string]interface{}) (map[string]interface{}, error) {
+	t.Helper()
+
+	submitted, err := p.SubmitExpenditure(ngoID, expenditureData)
+	if err != nil {
+		return nil, err
+	}
+	return p.ReviewExpenditure(submitted["assigned_auditor_id"].(string), submitted["transaction_id"].(string), transactions.DecisionApprove, "", nil)
+}
+
+func TestExpenditureReviewWorkflow(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+
+	deposit(t, p, money.INR(100000))
+
+	if _, err := p.SubmitExpenditure("NGO001", map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books"}); err == nil {
+		t.Error("Expected an expenditure without an invoice to be refused")
+	}
+	submitted, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books"}))
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+	id := submitted["transaction_id"].(string)
+	if submitted["status"] != "pending_validation" || p.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength() != 1 {
+		t.Fatalf("Expected the expenditure to wait for review off chain, got %+v", submitted)
+	}
+	if queue := p.GetPendingExpenditures("AUD001"); len(queue) != 1 || queue[0]["transaction_id"] != id {
+		t.Fatalf("Expected the expenditure in the auditor's queue, got %+v", queue)
+	}
+
+	if _, err := p.ReviewExpenditure("AUD002", id, transactions.DecisionApprove, "", nil); !errors.Is(err, ErrNotAssigned) {
+		t.Errorf("Expected an auditor not assigned to the expenditure to be refused, got %v", err)
+	}
+	if _, err := p.ReviewExpenditure("AUD001", "EXP-MISSING", transactions.DecisionApprove, "", nil); !errors.Is(err, ErrExpenditureNotFound) {
+		t.Errorf("Expected an unknown expenditure not to be found, got %v", err)
+	}
+	if _, err := p.ReviewExpenditure("AUD001", id, transactions.DecisionRequestInfo, " ", nil); err == nil {
+		t.Error("Expected a request for information without notes to be refused")
+	}
+	if _, err := p.ReviewExpenditure("AUD001", id, transactions.DecisionRequestInfo, "Attach proof of payment", nil); err != nil {
+		t.Fatalf("Failed to request information: %v", err)
+	}
+	if _, err := p.ReviewExpenditure("AUD001", id, transactions.DecisionApprove, "", nil); err == nil {
+		t.Error("Expected approval to wait for the NGO's reply")
+	}
+	if _, err := p.ProvideExpenditureInformation("NGO001", id, ExpenditureInformation{Notes: "Paid by NEFT", BankTransactionID: "UTR9001"}); err != nil {
+		t.Fatalf("Failed to provide information: %v", err)
+	}
+
+	// The queue and its review history survive a restart
+	reloaded := newReloadedPlatform(t, repos)
+	pending := reloaded.PendingExpenditures[id]
+	if pending == nil || !pending.IsPendingValidation() || len(pending.Reviews) != 2 || pending.InvoiceDetails.BankTransactionID != "UTR9001" {
+		t.Fatalf("Expected the answered expenditure back in the queue after reload, got %+v", pending)
+	}
+
+	result, err := reloaded.ReviewExpenditure("AUD001", id, transactions.DecisionApprove, "Payment proof checked", nil)
+	if err != nil {
+		t.Fatalf("Failed to approve expenditure: %v", err)
+	}
+	if result["status"] != "validated" || len(reloaded.PendingExpenditures) != 0 {
+		t.Errorf("Expected the approved expenditure to leave the queue, got %+v", result)
+	}
+
+	block := reloaded.NGOs["NGO001"].ExpenditureBlockchain.GetLatestBlock()
+	reviews, _ := block.Data.(map[string]interface{})["reviews"].([]interface{})
+	if block.Hash != result["block_hash"] || len(reviews) != 3 || reviews[2].(map[string]interface{})["decision"] != transactions.DecisionApprove {
+		t.Errorf("Expected the block to record all three review steps, got %+v", reviews)
+	}
+	audits := reloaded.Auditors["AUD001"].AuditHistory
+	if len(audits) != 1 || audits[0].Decision != transactions.DecisionApprove || audits[0].AuditNotes != "Payment proof checked" {
+		t.Errorf("Expected one approving audit, got %+v", audits)
+	}
+}
+
+func TestRejectedExpenditureNeverReachesChain(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+
+	deposit(t, p, money.INR(100000))
+
+	// A high automated score does not approve anything by itself
+	submitted, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 300.0, "category": "education", "description": "Books", "bank_transaction_id": "UTR9001"}))
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+	id := submitted["transaction_id"].(string)
+
+	if _, err := p.ReviewExpenditure("AUD001", id, transactions.DecisionReject, "", nil); err == nil {
+		t.Error("Expected a rejection without notes to be refused")
+	}
+	result, err := p.ReviewExpenditure("AUD001", id, transactions.DecisionReject, "Books were bought for a staff member", nil)
+	if err != nil {
+		t.Fatalf("Failed to reject expenditure: %v", err)
+	}
+	if result["status"] != "rejected" || p.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength() != 1 {
+		t.Errorf("Expected the rejected expenditure to stay off chain, got %+v", result)
+	}
+
+	if model, err := repos.Expenditures.GetByTransactionID(id); err != nil || model.Status != "rejected" {
+		t.Errorf("Expected the rejection to be stored, got %+v (%v)", model, err)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	if len(reloaded.PendingExpenditures) != 0 {
+		t.Errorf("Expected no expenditures under review after reload, got %d", len(reloaded.PendingExpenditures))
+	}
+	if stats := reloaded.Auditors["AUD001"].GetAuditorStats(); stats.TotalAudits != 1 || stats.ApprovedAudits != 0 {
+		t.Errorf("Expected one rejecting audit, got %+v", stats)
+	}
+}
+
+func TestPendingExpendituresReserveFundsAndInvoices(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+
+	donation := deposit(t, p, money.INR(100000))
+	funding := []transactions.FundAllocation{{DonationID: donation["transaction_id"].(string), Amount: money.INR(60000)}}
+
+	first := invoiced(map[string]interface{}{"amount": 600.0, "category": "education", "description": "Books", "funding": funding})
+	submitted, err := p.SubmitExpenditure("NGO001", first)
+	if err != nil {
+		t.Fatalf("Failed to submit expenditure: %v", err)
+	}
+
+	// Funds and invoices held by an expenditure under review are not available to others
+	if _, err := p.SubmitExpenditure("NGO001", invoiced(map[string]interface{}{"amount": 600.0, "category": "education", "description": "More books", "funding": funding})); err == nil {
+		t.Error("Expected funds reserved by a pending expenditure to be refused")
+	}
+	duplicate := map[string]interface{}{"amount": 100.0, "category": "education", "description": "Books again", "invoice": first["invoice"]}
+	if _, err := p.SubmitExpenditure("NGO001", duplicate); err == nil {
+		t.Error("Expected an invoice awaiting approval to be refused")
+	}
+
+	// The auditor's approval and score stand, whatever the automated score
+	score := 40.0
+	result, err := p.ReviewExpenditure(submitted["assigned_auditor_id"].(string), submitted["transaction_id"].(string), transactions.DecisionApprove, "Checked against the bank statement", &score)
+	if err != nil {
+		t.Fatalf("Expected the approval to take effect, got %v", err)
+	}
+	if result["status"] != "validated" || p.NGOs["NGO001"].ExpenditureBlockchain.GetChainLength() != 2 {
+		t.Errorf("Expected the expenditure on chain, got %+v", result)
+	}
+	if _, pending := p.PendingExpenditures[submitted["transaction_id"].(string)]; pending {
+		t.Error("Expected the approved expenditure to leave the queue")
+	}
+}
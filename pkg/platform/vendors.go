@@ -0,0 +1,147 @@
+package platform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/blockchain"
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// GSTINs of the placeholder invoice once attached to expenditures submitted
+// without one. No supplier holds them, so invoices naming them are refused.
+var placeholderGSTINs = map[string]bool{
+	"27ABCDE1234F1Z0": true,
+	"29ABCDE1234F1ZW": true,
+	"29ABCDE1234F1Z3": true,
+}
+
+// RegisterVendor adds a supplier to the platform-wide vendor registry
+func (p *NGOTransparencyPlatform) RegisterVendor(gstin, legalName, registeredBy string) (*entities.Vendor, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	vendor, err := entities.NewVendor(gstin, legalName, registeredBy)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := p.Vendors[vendor.GSTIN]; exists {
+		return nil, fmt.Errorf("vendor already registered")
+	}
+
+	err = p.persist(func(tx *database.Repositories) error {
+		return tx.Vendors.Save(vendorToModel(vendor))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist vendor: %w", err)
+	}
+
+	p.Vendors[vendor.GSTIN] = vendor
+	return vendor, nil
+}
+
+// GetVendor returns a registered vendor by GSTIN
+func (p *NGOTransparencyPlatform) GetVendor(gstin string) (*entities.Vendor, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	vendor, exists := p.Vendors[strings.ToUpper(strings.TrimSpace(gstin))]
+	if !exists {
+		return nil, ErrVendorNotFound
+	}
+	return vendor, nil
+}
+
+// GetFlaggedExpenditures returns the recorded expenditures whose invoices may
+// duplicate earlier ones, across all NGOs, newest first
+func (p *NGOTransparencyPlatform) GetFlaggedExpenditures() []map[string]interface{} {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	flagged := make([]map[string]interface{}, 0)
+	for ngoID, ngo := range p.NGOs {
+		blocks := ngo.ExpenditureBlockchain.FindBlocks(func(block *blockchain.Block) bool {
+			data, ok := block.Data.(map[string]interface{})
+			return ok && data["type"] == "expenditure" && data["invoice_flags"] != nil
+		})
+		for _, block := range blocks {
+			data := block.Data.(map[string]interface{})
+			amount, _ := parseAmount(data["amount"])
+			flagged = append(flagged, map[string]interface{}{
+				"ngo_id":          ngoID,
+				"transaction_id":  data["transaction_id"],
+				"amount":          amount,
+				"invoice_details": data["invoice_details"],
+				"invoice_flags":   data["invoice_flags"],
+				"block_hash":      block.Hash,
+				"timestamp":       block.Timestamp,
+			})
+		}
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		return flagged[i]["timestamp"].(time.Time).After(flagged[j]["timestamp"].(time.Time))
+	})
+	return flagged
+}
+
+// checkInvoice rejects an expenditure paying a placeholder invoice or a
+// vendor invoice number that was already paid, and flags invoices from the
+// same vendor for the same amount on the same date. It returns the invoice to
+// index once the expenditure is recorded, or false if it cannot be indexed.
+func (p *NGOTransparencyPlatform) checkInvoice(expenditure *transactions.ExpenditureTransaction) (transactions.InvoiceRecord, bool, error) {
+	invoice := expenditure.InvoiceDetails
+	for _, gstin := range []string{invoice.GSTIN, invoice.VendorGSTIN} {
+		if placeholderGSTINs[strings.ToUpper(strings.TrimSpace(gstin))] {
+			return transactions.InvoiceRecord{}, false, fmt.Errorf("invoice names placeholder GSTIN %s", gstin)
+		}
+	}
+	record, ok := transactions.NewInvoiceRecord(expenditure)
+	if !ok {
+		return record, false, nil
+	}
+
+	flags := p.invoices.Check(record)
+	for _, flag := range flags {
+		if flag.Kind == transactions.DuplicateInvoiceNumber {
+			return record, false, fmt.Errorf("duplicate invoice: %s", flag.Description())
+		}
+	}
+	expenditure.InvoiceFlags = flags
+	return record, true, nil
+}
+
+// invoiceVendor returns the vendor to add to the registry for an invoice
+// from a supplier not yet registered, or nil
+func (p *NGOTransparencyPlatform) invoiceVendor(ngoID string, invoice transactions.InvoiceDetails) *entities.Vendor {
+	vendor, err := entities.NewVendor(invoice.VendorGSTIN, invoice.VendorName, ngoID)
+	if err != nil {
+		return nil
+	}
+	if _, exists := p.Vendors[vendor.GSTIN]; exists {
+		return nil
+	}
+	return vendor
+}
+
+// buildInvoiceIndex indexes the invoices paid by every recorded expenditure,
+// as last amended
+func buildInvoiceIndex(ngos map[string]*entities.NGO) *transactions.InvoiceIndex {
+	index := transactions.NewInvoiceIndex()
+	for _, ngo := range ngos {
+		expenditures, err := ngo.RecordedExpenditures()
+		if err != nil {
+			continue
+		}
+		for _, expenditure := range expenditures {
+			if record, ok := transactions.NewInvoiceRecord(expenditure); ok {
+				index.Add(record)
+			}
+		}
+	}
+	return index
+}
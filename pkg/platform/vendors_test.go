@@ -0,0 +1,93 @@
+package platform
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+func vendorExpenditure(invoiceNumber string, amount float64, invoiceDate time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"amount": amount, "category": "education", "description": "Textbooks",
+		"invoice": transactions.InvoiceDetails{
+			InvoiceNumber: invoiceNumber,
+			GSTIN:         "27AAPFU0939F1ZV",
+			VendorName:    "Bharat Books",
+			VendorGSTIN:   "29AAGCB7383J1Z4",
+			InvoiceDate:   invoiceDate,
+			Documents:     []string{"invoice.pdf"},
+		},
+	}
+}
+
+func TestRegisterVendorValidatesGSTIN(t *testing.T) {
+	p, _ := newTestPlatform(t)
+
+	if _, err := p.RegisterVendor("29AAGCB7383J1Z5", "Bharat Books", "NGO001"); err == nil {
+		t.Error("Expected a GSTIN with the wrong check character to be rejected")
+	}
+	vendor, err := p.RegisterVendor("29aagcb7383j1z4", "Bharat Books", "NGO001")
+	if err != nil {
+		t.Fatalf("Failed to register vendor: %v", err)
+	}
+	if vendor.State != "Karnataka" || vendor.PAN != "AAGCB7383J" {
+		t.Errorf("Unexpected vendor %+v", vendor)
+	}
+	if _, err := p.RegisterVendor("29AAGCB7383J1Z4", "Bharat Books", "NGO001"); err == nil {
+		t.Error("Expected a vendor to be registered only once")
+	}
+}
+
+func TestDuplicateInvoices(t *testing.T) {
+	p, repos := newTestPlatform(t)
+	verifiedAuditor(t, p, repos)
+
+	deposit(t, p, money.INR(500000))
+
+	invoiceDate := time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := recordExpenditure(t, p, "NGO001", vendorExpenditure("INV/2026/001", 1500.0, invoiceDate)); err != nil {
+		t.Fatalf("Failed to process expenditure: %v", err)
+	}
+	if vendor, err := p.GetVendor("29AAGCB7383J1Z4"); err != nil || vendor.RegisteredBy != "NGO001" {
+		t.Errorf("Expected the paid vendor to join the registry, got %+v (%v)", vendor, err)
+	}
+
+	placeholder := vendorExpenditure("INV/2026/009", 900.0, invoiceDate)
+	invoice := placeholder["invoice"].(transactions.InvoiceDetails)
+	invoice.VendorGSTIN = "29abcde1234f1zw"
+	placeholder["invoice"] = invoice
+	if _, err := recordExpenditure(t, p, "NGO001", placeholder); err == nil {
+		t.Error("Expected an invoice naming a placeholder vendor to be rejected")
+	}
+
+	_, err := recordExpenditure(t, p, "NGO001", vendorExpenditure("inv-2026-001", 900.0, invoiceDate))
+	if err == nil || !strings.Contains(err.Error(), "duplicate invoice") {
+		t.Errorf("Expected the same invoice number to be rejected, got %v", err)
+	}
+
+	result, err := recordExpenditure(t, p, "NGO001", vendorExpenditure("INV/2026/002", 1500.0, invoiceDate))
+	if err != nil {
+		t.Fatalf("Failed to process expenditure: %v", err)
+	}
+	flags, _ := result["invoice_flags"].([]transactions.InvoiceFlag)
+	if len(flags) != 1 || flags[0].Kind != transactions.DuplicateAmountDate || flags[0].InvoiceNumber != "INV/2026/001" {
+		t.Errorf("Expected the same amount and date to be flagged, got %+v", result["invoice_flags"])
+	}
+	if flagged := p.GetFlaggedExpenditures(); len(flagged) != 1 || flagged[0]["transaction_id"] != result["transaction_id"] {
+		t.Errorf("Expected one flagged expenditure, got %+v", flagged)
+	}
+
+	reloaded := newReloadedPlatform(t, repos)
+	if _, err := reloaded.GetVendor("29AAGCB7383J1Z4"); err != nil {
+		t.Errorf("Expected the vendor to survive a reload: %v", err)
+	}
+	if _, err := recordExpenditure(t, reloaded, "NGO001", vendorExpenditure("INV 2026 002", 100.0, invoiceDate)); err == nil {
+		t.Error("Expected a paid invoice to stay rejected after a reload")
+	}
+	if flagged := reloaded.GetFlaggedExpenditures(); len(flagged) != 1 {
+		t.Errorf("Expected the flagged expenditure to survive a reload, got %d", len(flagged))
+	}
+}
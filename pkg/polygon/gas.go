@@ -0,0 +1,474 @@
+package polygon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDailySpendCapExceeded is returned when an anchor would push the day's spend over the cap
+var ErrDailySpendCapExceeded = errors.New("daily anchoring spend cap exceeded")
+
+var weiPerGwei = big.NewInt(1e9)
+var weiPerMATIC = new(big.Float).SetFloat64(1e18)
+
+// GasPolicy describes how EIP-1559 fees are chosen for anchoring transactions
+type GasPolicy struct {
+	FeeHistoryBlocks      int      `json:"fee_history_blocks"`
+	PriorityFeePercentile float64  `json:"priority_fee_percentile"`
+	BaseFeeMultiplier     float64  `json:"base_fee_multiplier"`
+	MinPriorityFee        *big.Int `json:"min_priority_fee"`
+	MaxFeeCap             *big.Int `json:"max_fee_cap,omitempty"`
+	DailySpendCap         *big.Int `json:"daily_spend_cap,omitempty"`
+	MaticINRRate          float64  `json:"matic_inr_rate"`
+}
+
+// DefaultGasPolicy returns the policy used when none is configured
+func DefaultGasPolicy() GasPolicy {
+	return GasPolicy{
+		FeeHistoryBlocks:      20,
+		PriorityFeePercentile: 50,
+		BaseFeeMultiplier:     2.0,
+		MinPriorityFee:        new(big.Int).Mul(big.NewInt(30), weiPerGwei), // Polygon PoS enforces a 30 gwei tip floor
+		MaticINRRate:          45.0,
+	}
+}
+
+// FeeHistory is a summary of recent fee market data
+type FeeHistory struct {
+	BaseFee     *big.Int  `json:"base_fee"`
+	PriorityFee *big.Int  `json:"priority_fee"`
+	OldestBlock int64     `json:"oldest_block"`
+	LatestBlock int64     `json:"latest_block"`
+	Source      string    `json:"source"` // "rpc" or "static"
+	FetchedAt   time.Time `json:"fetched_at"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// FeeQuote represents the fees to be offered for a single transaction
+type FeeQuote struct {
+	BaseFee     *big.Int `json:"base_fee"`
+	PriorityFee *big.Int `json:"priority_fee"`
+	MaxFee      *big.Int `json:"max_fee"`
+	GasLimit    int64    `json:"gas_limit"`
+	MaxCost     *big.Int `json:"max_cost"`
+	Source      string   `json:"source"`
+}
+
+// AnchorCost represents the actual cost paid for an anchoring transaction
+type AnchorCost struct {
+	GasUsed           int64    `json:"gas_used"`
+	EffectiveGasPrice *big.Int `json:"effective_gas_price"`
+	CostWei           *big.Int `json:"cost_wei"`
+	CostMATIC         float64  `json:"cost_matic"`
+	CostINR           float64  `json:"cost_inr"`
+}
+
+// SpendReservation holds a quoted worst-case cost against the daily spend cap
+// while its transaction is in flight
+type SpendReservation struct {
+	Quote *FeeQuote
+	day   string
+}
+
+// GasStrategy reads fee history from the RPC endpoint and prices anchoring transactions
+type GasStrategy struct {
+	RPCURL           string
+	Policy           GasPolicy
+	FallbackGasPrice *big.Int
+	CacheTTL         time.Duration
+	client           *http.Client
+	cached           *FeeHistory
+	dailySpend       map[string]*big.Int
+	mutex            sync.Mutex
+}
+
+// NewGasStrategy creates a gas strategy for the given RPC endpoint
+func NewGasStrategy(rpcURL string, fallbackGasPrice *big.Int, policy GasPolicy) *GasStrategy {
+	defaults := DefaultGasPolicy()
+	if policy.FeeHistoryBlocks <= 0 {
+		policy.FeeHistoryBlocks = defaults.FeeHistoryBlocks
+	}
+	if policy.PriorityFeePercentile <= 0 || policy.PriorityFeePercentile > 100 {
+		policy.PriorityFeePercentile = defaults.PriorityFeePercentile
+	}
+	if policy.BaseFeeMultiplier < 1 {
+		policy.BaseFeeMultiplier = defaults.BaseFeeMultiplier
+	}
+	if policy.MinPriorityFee == nil {
+		policy.MinPriorityFee = defaults.MinPriorityFee
+	}
+	if policy.MaticINRRate <= 0 {
+		policy.MaticINRRate = defaults.MaticINRRate
+	}
+	if fallbackGasPrice == nil || fallbackGasPrice.Sign() <= 0 {
+		fallbackGasPrice = new(big.Int).Mul(big.NewInt(30), weiPerGwei)
+	}
+
+	return &GasStrategy{
+		RPCURL:           rpcURL,
+		Policy:           policy,
+		FallbackGasPrice: fallbackGasPrice,
+		CacheTTL:         30 * time.Second,
+		client:           &http.Client{Timeout: 3 * time.Second},
+		dailySpend:       make(map[string]*big.Int),
+	}
+}
+
+// FeeHistory returns recent fee data, refreshing from the RPC endpoint when the cache is stale
+func (gs *GasStrategy) FeeHistory() *FeeHistory {
+	gs.mutex.Lock()
+	if gs.cached != nil && time.Since(gs.cached.FetchedAt) < gs.CacheTTL {
+		history := gs.cached
+		gs.mutex.Unlock()
+		return history
+	}
+	gs.mutex.Unlock()
+
+	history, err := gs.fetchFeeHistory()
+	if err != nil {
+		// Fall back to the configured gas price so anchoring keeps working
+		// offline. The legacy price is the whole fee, the tip included.
+		baseFee := new(big.Int).Sub(gs.FallbackGasPrice, gs.Policy.MinPriorityFee)
+		if baseFee.Sign() < 0 {
+			baseFee.SetInt64(0)
+		}
+		history = &FeeHistory{
+			BaseFee:     baseFee,
+			PriorityFee: new(big.Int).Set(gs.Policy.MinPriorityFee),
+			Source:      "static",
+			FetchedAt:   time.Now(),
+			Error:       err.Error(),
+		}
+	}
+
+	gs.mutex.Lock()
+	gs.cached = history
+	gs.mutex.Unlock()
+
+	return history
+}
+
+// Quote prices a transaction with the given gas limit according to the policy
+func (gs *GasStrategy) Quote(gasLimit int64) *FeeQuote {
+	history := gs.FeeHistory()
+
+	priorityFee := new(big.Int).Set(history.PriorityFee)
+	if priorityFee.Cmp(gs.Policy.MinPriorityFee) < 0 {
+		priorityFee.Set(gs.Policy.MinPriorityFee)
+	}
+
+	// maxFee = baseFee * multiplier + priorityFee leaves headroom for base fee growth
+	scaledBase, _ := new(big.Float).Mul(
+		new(big.Float).SetInt(history.BaseFee),
+		big.NewFloat(gs.Policy.BaseFeeMultiplier),
+	).Int(nil)
+	maxFee := new(big.Int).Add(scaledBase, priorityFee)
+
+	if gs.Policy.MaxFeeCap != nil && gs.Policy.MaxFeeCap.Sign() > 0 && maxFee.Cmp(gs.Policy.MaxFeeCap) > 0 {
+		maxFee.Set(gs.Policy.MaxFeeCap)
+		if priorityFee.Cmp(maxFee) > 0 {
+			priorityFee.Set(maxFee)
+		}
+	}
+
+	return &FeeQuote{
+		BaseFee:     new(big.Int).Set(history.BaseFee),
+		PriorityFee: priorityFee,
+		MaxFee:      maxFee,
+		GasLimit:    gasLimit,
+		MaxCost:     new(big.Int).Mul(maxFee, big.NewInt(gasLimit)),
+		Source:      history.Source,
+	}
+}
+
+// CheckSpendCap verifies that the quoted worst-case cost fits within today's spend cap
+func (gs *GasStrategy) CheckSpendCap(quote *FeeQuote, at time.Time) error {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	return gs.checkSpendCap(quote, at)
+}
+
+// ReserveSpend checks the quoted worst-case cost against today's spend cap
+// and holds it there until the transaction is settled or released, so
+// concurrent anchors cannot together overspend
+func (gs *GasStrategy) ReserveSpend(quote *FeeQuote, at time.Time) (*SpendReservation, error) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if err := gs.checkSpendCap(quote, at); err != nil {
+		return nil, err
+	}
+
+	reservation := &SpendReservation{Quote: quote, day: spendDay(at)}
+	gs.dailySpend[reservation.day] = new(big.Int).Add(gs.spentOn(at), quote.MaxCost)
+	return reservation, nil
+}
+
+// EstimateCost computes what a transaction using gasUsed would cost at the quoted fees
+func (gs *GasStrategy) EstimateCost(quote *FeeQuote, gasUsed int64) AnchorCost {
+	// Effective price under EIP-1559: baseFee + min(priorityFee, maxFee - baseFee)
+	effective := new(big.Int).Sub(quote.MaxFee, quote.BaseFee)
+	if effective.Sign() < 0 {
+		effective.SetInt64(0)
+	}
+	if quote.PriorityFee.Cmp(effective) < 0 {
+		effective.Set(quote.PriorityFee)
+	}
+	effective.Add(effective, quote.BaseFee)
+	if effective.Cmp(quote.MaxFee) > 0 {
+		effective.Set(quote.MaxFee)
+	}
+
+	costWei := new(big.Int).Mul(effective, big.NewInt(gasUsed))
+	costMATIC, costINR := gs.ConvertWei(costWei)
+
+	return AnchorCost{
+		GasUsed:           gasUsed,
+		EffectiveGasPrice: effective,
+		CostWei:           costWei,
+		CostMATIC:         costMATIC,
+		CostINR:           costINR,
+	}
+}
+
+// SettleSpend replaces a reservation with the actual cost of the mined transaction
+func (gs *GasStrategy) SettleSpend(reservation *SpendReservation, gasUsed int64) AnchorCost {
+	cost := gs.EstimateCost(reservation.Quote, gasUsed)
+
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	gs.adjustSpend(reservation.day, new(big.Int).Sub(cost.CostWei, reservation.Quote.MaxCost))
+	return cost
+}
+
+// ReleaseSpend returns a reservation to the day's budget when its transaction was not sent
+func (gs *GasStrategy) ReleaseSpend(reservation *SpendReservation) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	gs.adjustSpend(reservation.day, new(big.Int).Neg(reservation.Quote.MaxCost))
+}
+
+// AddSpend counts a cost paid earlier, such as that of an anchor sent before
+// a restart, towards the spend of the UTC day containing at
+func (gs *GasStrategy) AddSpend(costWei *big.Int, at time.Time) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	gs.adjustSpend(spendDay(at), costWei)
+}
+
+// SpentOn returns the total anchoring spend for the UTC day containing t
+func (gs *GasStrategy) SpentOn(t time.Time) *big.Int {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	return new(big.Int).Set(gs.spentOn(t))
+}
+
+// ConvertWei converts a wei amount into MATIC and INR at the configured rate
+func (gs *GasStrategy) ConvertWei(wei *big.Int) (float64, float64) {
+	matic := weiToMATIC(wei)
+	return matic, matic * gs.Policy.MaticINRRate
+}
+
+// spentOn returns the spend for the day (assumes lock is held)
+func (gs *GasStrategy) spentOn(t time.Time) *big.Int {
+	if spent, ok := gs.dailySpend[spendDay(t)]; ok {
+		return spent
+	}
+	return big.NewInt(0)
+}
+
+// checkSpendCap verifies a quote against the day's cap (assumes lock is held)
+func (gs *GasStrategy) checkSpendCap(quote *FeeQuote, at time.Time) error {
+	if gs.Policy.DailySpendCap == nil || gs.Policy.DailySpendCap.Sign() <= 0 {
+		return nil
+	}
+
+	spent := gs.spentOn(at)
+	projected := new(big.Int).Add(spent, quote.MaxCost)
+	if projected.Cmp(gs.Policy.DailySpendCap) > 0 {
+		return fmt.Errorf("%w: spent %s MATIC of %s MATIC cap",
+			ErrDailySpendCapExceeded, formatMATIC(spent), formatMATIC(gs.Policy.DailySpendCap))
+	}
+	return nil
+}
+
+// adjustSpend adds delta to a day's spend (assumes lock is held)
+func (gs *GasStrategy) adjustSpend(day string, delta *big.Int) {
+	spent := new(big.Int).Set(delta)
+	if current, ok := gs.dailySpend[day]; ok {
+		spent.Add(spent, current)
+	}
+	if spent.Sign() < 0 {
+		spent.SetInt64(0)
+	}
+	gs.dailySpend[day] = spent
+}
+
+// carrySpend copies the spend recorded by another strategy, so replacing a
+// strategy does not reset the spend cap (assumes neither lock is held)
+func (gs *GasStrategy) carrySpend(from *GasStrategy) {
+	from.mutex.Lock()
+	spend := make(map[string]*big.Int, len(from.dailySpend))
+	for day, spent := range from.dailySpend {
+		spend[day] = new(big.Int).Set(spent)
+	}
+	from.mutex.Unlock()
+
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	for day, spent := range spend {
+		gs.adjustSpend(day, spent)
+	}
+}
+
+func spendDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type feeHistoryResponse struct {
+	Result *struct {
+		OldestBlock   string     `json:"oldestBlock"`
+		BaseFeePerGas []string   `json:"baseFeePerGas"`
+		Reward        [][]string `json:"reward"`
+	} `json:"result"`
+	Error *rpcError `json:"error"`
+}
+
+// fetchFeeHistory calls eth_feeHistory on the RPC endpoint
+func (gs *GasStrategy) fetchFeeHistory() (*FeeHistory, error) {
+	if gs.RPCURL == "" {
+		return nil, fmt.Errorf("no RPC endpoint configured")
+	}
+
+	request := rpcRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "eth_feeHistory",
+		Params: []interface{}{
+			fmt.Sprintf("0x%x", gs.Policy.FeeHistoryBlocks),
+			"latest",
+			[]float64{gs.Policy.PriorityFeePercentile},
+		},
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := gs.client.Post(gs.RPCURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("fee history request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fee history request returned status %d", resp.StatusCode)
+	}
+
+	var decoded feeHistoryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("invalid fee history response: %w", err)
+	}
+	if decoded.Error != nil {
+		return nil, fmt.Errorf("fee history RPC error %d: %s", decoded.Error.Code, decoded.Error.Message)
+	}
+	if decoded.Result == nil || len(decoded.Result.BaseFeePerGas) == 0 {
+		return nil, fmt.Errorf("empty fee history response")
+	}
+
+	// The last base fee entry is the base fee of the next (pending) block
+	baseFee, err := parseHexBig(decoded.Result.BaseFeePerGas[len(decoded.Result.BaseFeePerGas)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	rewards := make([]*big.Int, 0, len(decoded.Result.Reward))
+	for _, blockRewards := range decoded.Result.Reward {
+		if len(blockRewards) == 0 {
+			continue
+		}
+		reward, err := parseHexBig(blockRewards[0])
+		if err != nil {
+			return nil, err
+		}
+		rewards = append(rewards, reward)
+	}
+
+	priorityFee := new(big.Int).Set(gs.Policy.MinPriorityFee)
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		priorityFee = rewards[len(rewards)/2]
+	}
+
+	oldestBlock, err := parseHexBig(decoded.Result.OldestBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FeeHistory{
+		BaseFee:     baseFee,
+		PriorityFee: priorityFee,
+		OldestBlock: oldestBlock.Int64(),
+		LatestBlock: oldestBlock.Int64() + int64(len(decoded.Result.BaseFeePerGas)) - 2,
+		Source:      "rpc",
+		FetchedAt:   time.Now(),
+	}, nil
+}
+
+func parseHexBig(value string) (*big.Int, error) {
+	parsed, ok := new(big.Int).SetString(strings.TrimPrefix(value, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex quantity %q", value)
+	}
+	return parsed, nil
+}
+
+func weiToMATIC(wei *big.Int) float64 {
+	matic, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), weiPerMATIC).Float64()
+	return matic
+}
+
+func weiToGwei(wei *big.Int) float64 {
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), new(big.Float).SetInt(weiPerGwei)).Float64()
+	return gwei
+}
+
+func formatMATIC(wei *big.Int) string {
+	return fmt.Sprintf("%.6f", weiToMATIC(wei))
+}
+
+// MATICToWei converts a MATIC amount to wei
+func MATICToWei(matic float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(matic), weiPerMATIC).Int(nil)
+	return wei
+}
+
+// GweiToWei converts a gwei amount to wei
+func GweiToWei(gwei int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(gwei), weiPerGwei)
+}
@@ -0,0 +1,193 @@
+package polygon
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newFeeHistoryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Base fees of 40, 50 gwei and tips of 25, 35, 45 gwei
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{
+			"oldestBlock":"0x64",
+			"baseFeePerGas":["0x9502f9000","0xba43b7400"],
+			"reward":[["0x5d21dba00"],["0x826299e00"],["0xa7a358200"]]
+		}}`))
+	}))
+}
+
+func TestGasStrategyReadsFeeHistory(t *testing.T) {
+	server := newFeeHistoryServer(t)
+	defer server.Close()
+
+	strategy := NewGasStrategy(server.URL, GweiToWei(30), GasPolicy{MinPriorityFee: GweiToWei(1)})
+	history := strategy.FeeHistory()
+
+	if history.Source != "rpc" {
+		t.Fatalf("Expected rpc fee source, got %s (%s)", history.Source, history.Error)
+	}
+	if history.BaseFee.Cmp(GweiToWei(50)) != 0 {
+		t.Errorf("Expected base fee of 50 gwei, got %s", history.BaseFee)
+	}
+	if history.PriorityFee.Cmp(GweiToWei(35)) != 0 {
+		t.Errorf("Expected median priority fee of 35 gwei, got %s", history.PriorityFee)
+	}
+
+	quote := strategy.Quote(100000)
+	expectedMaxFee := GweiToWei(135) // 50 * 2 + 35
+	if quote.MaxFee.Cmp(expectedMaxFee) != 0 {
+		t.Errorf("Expected max fee %s, got %s", expectedMaxFee, quote.MaxFee)
+	}
+}
+
+func TestGasStrategyFallsBackWhenRPCUnavailable(t *testing.T) {
+	strategy := NewGasStrategy("", GweiToWei(40), DefaultGasPolicy())
+	history := strategy.FeeHistory()
+
+	if history.Source != "static" {
+		t.Fatalf("Expected static fee source, got %s", history.Source)
+	}
+	// The 40 gwei legacy price is the whole fee: a 10 gwei base fee and the 30 gwei tip
+	if history.BaseFee.Cmp(GweiToWei(10)) != 0 || history.PriorityFee.Cmp(GweiToWei(30)) != 0 {
+		t.Errorf("Expected a 10 gwei base fee and 30 gwei tip, got %s and %s", history.BaseFee, history.PriorityFee)
+	}
+	quote := strategy.Quote(100000)
+	if cost := strategy.EstimateCost(quote, 100000); cost.EffectiveGasPrice.Cmp(GweiToWei(40)) != 0 {
+		t.Errorf("Expected to pay the 40 gwei legacy price, got %s", cost.EffectiveGasPrice)
+	}
+}
+
+func TestGasStrategyMaxFeeCap(t *testing.T) {
+	policy := DefaultGasPolicy()
+	policy.MaxFeeCap = GweiToWei(80)
+	strategy := NewGasStrategy("", GweiToWei(100), policy)
+
+	quote := strategy.Quote(50000)
+	if quote.MaxFee.Cmp(GweiToWei(80)) != 0 {
+		t.Errorf("Expected max fee capped at 80 gwei, got %s", quote.MaxFee)
+	}
+
+	cost := strategy.EstimateCost(quote, 50000)
+	if cost.EffectiveGasPrice.Cmp(quote.MaxFee) > 0 {
+		t.Errorf("Effective gas price %s exceeds max fee %s", cost.EffectiveGasPrice, quote.MaxFee)
+	}
+}
+
+func TestGasStrategyDailySpendCap(t *testing.T) {
+	policy := DefaultGasPolicy()
+	policy.DailySpendCap = MATICToWei(0.005)
+	strategy := NewGasStrategy("", GweiToWei(30), policy)
+
+	now := time.Now()
+	quote := strategy.Quote(100000) // worst case 0.003 MATIC
+	reservation, err := strategy.ReserveSpend(quote, now)
+	if err != nil {
+		t.Fatalf("Expected first anchor to fit within cap: %v", err)
+	}
+
+	// The reservation holds the worst case until it is settled
+	if _, err := strategy.ReserveSpend(quote, now); !errors.Is(err, ErrDailySpendCapExceeded) {
+		t.Errorf("Expected a concurrent anchor to exceed the cap, got %v", err)
+	}
+
+	cost := strategy.SettleSpend(reservation, 50000)
+	expectedWei := new(big.Int).Mul(GweiToWei(30), big.NewInt(50000))
+	if cost.CostWei.Cmp(expectedWei) != 0 || strategy.SpentOn(now).Cmp(expectedWei) != 0 {
+		t.Errorf("Expected cost and spend of %s wei, got %s and %s", expectedWei, cost.CostWei, strategy.SpentOn(now))
+	}
+
+	second, err := strategy.ReserveSpend(quote, now)
+	if err != nil {
+		t.Fatalf("Expected the settled spend to leave room for another anchor: %v", err)
+	}
+	if err := strategy.CheckSpendCap(quote, now); !errors.Is(err, ErrDailySpendCapExceeded) {
+		t.Errorf("Expected daily spend cap error, got %v", err)
+	}
+	strategy.ReleaseSpend(second)
+	if strategy.SpentOn(now).Cmp(expectedWei) != 0 {
+		t.Errorf("Expected a released reservation to be returned, spent %s", strategy.SpentOn(now))
+	}
+
+	tomorrow := now.Add(24 * time.Hour)
+	if err := strategy.CheckSpendCap(quote, tomorrow); err != nil {
+		t.Errorf("Expected cap to reset the next day: %v", err)
+	}
+}
+
+func TestRestoredAnchorsCountTowardsSpendCap(t *testing.T) {
+	policy := DefaultGasPolicy()
+	policy.DailySpendCap = MATICToWei(0.005)
+	pi := NewPolygonIntegration("", "key", 100000, GweiToWei(30))
+	pi.SetGasStrategy(NewGasStrategy("", GweiToWei(30), policy))
+
+	anchor, err := pi.AnchorBlockHash("hash-1", "NGO001", "donation", nil)
+	if err != nil {
+		t.Fatalf("Failed to anchor: %v", err)
+	}
+
+	restarted := NewPolygonIntegration("", "key", 100000, GweiToWei(30))
+	restarted.RestoreAnchors(map[string]AnchorResult{"hash-1": *anchor})
+	restarted.SetGasStrategy(NewGasStrategy("", GweiToWei(30), policy))
+
+	if !restarted.VerifyAnchoredHash("hash-1").Exists {
+		t.Error("Expected the restored anchor to verify")
+	}
+	if spent := restarted.GasStrategy.SpentOn(anchor.Timestamp); spent.String() != anchor.CostWei {
+		t.Errorf("Expected the restored spend of %s wei, got %s", anchor.CostWei, spent)
+	}
+}
+
+func TestAnchorStatisticsReportSpendPerNGO(t *testing.T) {
+	pi := NewPolygonIntegration("", "key", 100000, GweiToWei(30))
+
+	if _, err := pi.AnchorBlockHash("hash-1", "NGO001", "donation", nil); err != nil {
+		t.Fatalf("Failed to anchor: %v", err)
+	}
+	if _, err := pi.AnchorBlockHash("hash-2", "NGO002", "expenditure", nil); err != nil {
+		t.Fatalf("Failed to anchor: %v", err)
+	}
+
+	stats := pi.GetAnchorStatistics()
+	byNGO, ok := stats["spend_by_ngo"].(map[string]NGOAnchorSpend)
+	if !ok {
+		t.Fatalf("Expected spend_by_ngo breakdown, got %T", stats["spend_by_ngo"])
+	}
+
+	if byNGO["NGO001"].DonationAnchors != 1 || byNGO["NGO002"].ExpenditureAnchors != 1 {
+		t.Errorf("Unexpected per-NGO anchor counts: %+v", byNGO)
+	}
+	if byNGO["NGO001"].TotalCostMATIC <= 0 || byNGO["NGO001"].TotalCostINR <= 0 {
+		t.Errorf("Expected non-zero spend for NGO001, got %+v", byNGO["NGO001"])
+	}
+	if stats["total_cost_matic"].(float64) < byNGO["NGO001"].TotalCostMATIC {
+		t.Error("Total spend should include per-NGO spend")
+	}
+}
+
+func TestAnchorStatisticsUseRecordedCost(t *testing.T) {
+	pi := NewPolygonIntegration("", "key", 100000, GweiToWei(30))
+
+	anchor, err := pi.AnchorBlockHash("hash-1", "NGO001", "donation", nil)
+	if err != nil {
+		t.Fatalf("Failed to anchor: %v", err)
+	}
+
+	// A later rate change does not reprice anchors already sent
+	policy := DefaultGasPolicy()
+	policy.MaticINRRate = anchor.CostINR / anchor.CostMATIC * 2
+	pi.SetGasStrategy(NewGasStrategy("", GweiToWei(30), policy))
+
+	stats := pi.GetAnchorStatistics()
+	if got := stats["total_cost_inr"].(float64); got != anchor.CostINR {
+		t.Errorf("Expected total cost of %.4f INR as recorded, got %.4f", anchor.CostINR, got)
+	}
+	if got := stats["spend_by_ngo"].(map[string]NGOAnchorSpend)["NGO001"].TotalCostINR; got != anchor.CostINR {
+		t.Errorf("Expected NGO001 cost of %.4f INR as recorded, got %.4f", anchor.CostINR, got)
+	}
+}
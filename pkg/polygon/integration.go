@@ -19,6 +19,13 @@ type AnchorResult struct {
 	BlockNumber   int64     `json:"block_number"`
 	GasUsed       int64     `json:"gas_used"`
 	Confirmations int       `json:"confirmations"`
+	NGOID         string    `json:"ngo_id"`
+	ChainType     string    `json:"chain_type"`
+	MaxFeeGwei    float64   `json:"max_fee_gwei"`
+	GasPriceGwei  float64   `json:"gas_price_gwei"` // effective gas price paid
+	CostWei       string    `json:"cost_wei"`
+	CostMATIC     float64   `json:"cost_matic"`
+	CostINR       float64   `json:"cost_inr"`
 }
 
 // VerificationResult represents the result of verifying anchored data
@@ -38,6 +45,9 @@ type NetworkStats struct {
 	Network         string `json:"network"`
 	ChainID         int64  `json:"chain_id"`
 	GasPrice        string `json:"gas_price"`
+	BaseFee         string `json:"base_fee"`
+	PriorityFee     string `json:"priority_fee"`
+	FeeSource       string `json:"fee_source"`
 	CurrentBlock    int64  `json:"current_block"`
 	WalletAddress   string `json:"wallet_address"`
 	ContractAddress string `json:"contract_address"`
@@ -46,13 +56,14 @@ type NetworkStats struct {
 
 // PolygonIntegration handles integration with Polygon blockchain
 type PolygonIntegration struct {
-	ProviderURL     string                   `json:"provider_url"`
-	PrivateKey      string                   `json:"private_key"`
-	ContractAddress string                   `json:"contract_address"`
-	GasLimit        int64                    `json:"gas_limit"`
-	GasPrice        *big.Int                 `json:"gas_price"`
-	Anchors         map[string]AnchorResult  `json:"anchors"`
-	WalletAddress   string                   `json:"wallet_address"`
+	ProviderURL     string                  `json:"provider_url"`
+	PrivateKey      string                  `json:"private_key"`
+	ContractAddress string                  `json:"contract_address"`
+	GasLimit        int64                   `json:"gas_limit"`
+	GasPrice        *big.Int                `json:"gas_price"`
+	Anchors         map[string]AnchorResult `json:"anchors"`
+	WalletAddress   string                  `json:"wallet_address"`
+	GasStrategy     *GasStrategy            `json:"-"`
 	mutex           sync.RWMutex
 }
 
@@ -72,9 +83,31 @@ func NewPolygonIntegration(providerURL, privateKey string, gasLimit int64, gasPr
 		GasPrice:      gasPrice,
 		Anchors:       make(map[string]AnchorResult),
 		WalletAddress: walletAddress,
+		GasStrategy:   NewGasStrategy(providerURL, gasPrice, DefaultGasPolicy()),
 	}
 }
 
+// SetGasStrategy replaces the gas strategy used to price anchoring transactions
+func (pi *PolygonIntegration) SetGasStrategy(strategy *GasStrategy) {
+	if strategy == nil {
+		return
+	}
+
+	pi.mutex.Lock()
+	defer pi.mutex.Unlock()
+	if pi.GasStrategy != nil {
+		strategy.carrySpend(pi.GasStrategy)
+	}
+	pi.GasStrategy = strategy
+}
+
+// gasStrategy returns the current gas strategy, which SetGasStrategy may replace
+func (pi *PolygonIntegration) gasStrategy() *GasStrategy {
+	pi.mutex.RLock()
+	defer pi.mutex.RUnlock()
+	return pi.GasStrategy
+}
+
 // DeployContract simulates deploying a smart contract to Polygon
 func (pi *PolygonIntegration) DeployContract(contractABI, contractBytecode string, constructorArgs []interface{}) map[string]interface{} {
 	pi.mutex.Lock()
@@ -101,6 +134,14 @@ func (pi *PolygonIntegration) DeployContract(contractABI, contractBytecode strin
 
 // AnchorBlockHash anchors a block hash to the Polygon blockchain
 func (pi *PolygonIntegration) AnchorBlockHash(blockHash, ngoID, chainType string, additionalData map[string]interface{}) (*AnchorResult, error) {
+	// Price the transaction before taking the lock; this may hit the RPC endpoint
+	strategy := pi.gasStrategy()
+	quote := strategy.Quote(pi.GasLimit)
+	reservation, err := strategy.ReserveSpend(quote, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	pi.mutex.Lock()
 	defer pi.mutex.Unlock()
 
@@ -120,21 +161,29 @@ func (pi *PolygonIntegration) AnchorBlockHash(blockHash, ngoID, chainType string
 	dataHashBytes := sha256.Sum256([]byte(fmt.Sprintf("%v", anchorData)))
 	dataHash := hex.EncodeToString(dataHashBytes[:])
 
-	// Simulate transaction to Polygon
-	time.Sleep(200 * time.Millisecond) // Simulate network delay
+	txHash, blockNumber, gasUsed, err := pi.sendAnchor(dataHash)
+	if err != nil {
+		strategy.ReleaseSpend(reservation)
+		return nil, fmt.Errorf("failed to send anchor transaction: %w", err)
+	}
 
-	// Generate simulated transaction hash
-	simulatedTxHash := generateTransactionHash()
-	blockNumber := generateBlockNumber()
-	gasUsed := int64(21000 + mathrand.Intn(50000))
+	timestamp := time.Now()
+	cost := strategy.SettleSpend(reservation, gasUsed)
 
 	anchorResult := AnchorResult{
-		PolygonTxHash: simulatedTxHash,
+		PolygonTxHash: txHash,
 		DataHash:      dataHash,
-		Timestamp:     time.Now(),
+		Timestamp:     timestamp,
 		BlockNumber:   blockNumber,
 		GasUsed:       gasUsed,
 		Confirmations: 12,
+		NGOID:         ngoID,
+		ChainType:     chainType,
+		MaxFeeGwei:    weiToGwei(quote.MaxFee),
+		GasPriceGwei:  weiToGwei(cost.EffectiveGasPrice),
+		CostWei:       cost.CostWei.String(),
+		CostMATIC:     cost.CostMATIC,
+		CostINR:       cost.CostINR,
 	}
 
 	// Store anchor for verification
@@ -143,6 +192,37 @@ func (pi *PolygonIntegration) AnchorBlockHash(blockHash, ngoID, chainType string
 	return &anchorResult, nil
 }
 
+// sendAnchor sends a transaction recording dataHash and waits for it to be
+// mined, returning its hash, block number and gas used
+func (pi *PolygonIntegration) sendAnchor(dataHash string) (string, int64, int64, error) {
+	// Simulate transaction to Polygon
+	time.Sleep(200 * time.Millisecond) // Simulate network delay
+
+	// Generate simulated gas usage and transaction hash
+	gasUsed := int64(21000 + mathrand.Intn(50000))
+	if gasUsed > pi.GasLimit {
+		gasUsed = pi.GasLimit
+	}
+	return generateTransactionHash(), generateBlockNumber(), gasUsed, nil
+}
+
+// RestoreAnchors reloads anchors sent before a restart, keyed by block hash,
+// and counts their cost towards the daily spend cap again
+func (pi *PolygonIntegration) RestoreAnchors(anchors map[string]AnchorResult) {
+	pi.mutex.Lock()
+	defer pi.mutex.Unlock()
+
+	for blockHash, anchor := range anchors {
+		if _, exists := pi.Anchors[blockHash]; exists {
+			continue
+		}
+		pi.Anchors[blockHash] = anchor
+		if cost, ok := new(big.Int).SetString(anchor.CostWei, 10); ok {
+			pi.GasStrategy.AddSpend(cost, anchor.Timestamp)
+		}
+	}
+}
+
 // VerifyAnchoredHash verifies if a block hash has been anchored
 func (pi *PolygonIntegration) VerifyAnchoredHash(blockHash string) *VerificationResult {
 	pi.mutex.RLock()
@@ -177,6 +257,9 @@ func (pi *PolygonIntegration) GetAnchorHistory(ngoID string) []map[string]interf
 	var history []map[string]interface{}
 
 	for blockHash, anchor := range pi.Anchors {
+		if ngoID != "" && anchor.NGOID != ngoID {
+			continue
+		}
 		historyEntry := map[string]interface{}{
 			"block_hash":      blockHash,
 			"polygon_tx_hash": anchor.PolygonTxHash,
@@ -185,6 +268,9 @@ func (pi *PolygonIntegration) GetAnchorHistory(ngoID string) []map[string]interf
 			"block_number":    anchor.BlockNumber,
 			"gas_used":        anchor.GasUsed,
 			"confirmations":   anchor.Confirmations,
+			"chain_type":      anchor.ChainType,
+			"cost_matic":      anchor.CostMATIC,
+			"cost_inr":        anchor.CostINR,
 		}
 		history = append(history, historyEntry)
 	}
@@ -196,24 +282,29 @@ func (pi *PolygonIntegration) GetAnchorHistory(ngoID string) []map[string]interf
 
 // GetNetworkStats returns Polygon network statistics
 func (pi *PolygonIntegration) GetNetworkStats() *NetworkStats {
+	history := pi.gasStrategy().FeeHistory()
+
 	pi.mutex.RLock()
 	defer pi.mutex.RUnlock()
 
-	// Simulate fetching network stats
-	time.Sleep(50 * time.Millisecond)
-
-	gasPrice := "30 gwei"
-	if pi.GasPrice != nil {
-		gasPrice = fmt.Sprintf("%s gwei", pi.GasPrice.String())
+	currentBlock := history.LatestBlock
+	if currentBlock == 0 {
+		currentBlock = generateBlockNumber()
 	}
 
+	gasPrice := new(big.Int).Add(history.BaseFee, history.PriorityFee)
+
 	return &NetworkStats{
 		Network:         "Polygon Mumbai Testnet",
 		ChainID:         80001,
-		GasPrice:        gasPrice,
-		CurrentBlock:    generateBlockNumber(),
+		GasPrice:        fmt.Sprintf("%.2f gwei", weiToGwei(gasPrice)),
+		BaseFee:         fmt.Sprintf("%.2f gwei", weiToGwei(history.BaseFee)),
+		PriorityFee:     fmt.Sprintf("%.2f gwei", weiToGwei(history.PriorityFee)),
+		FeeSource:       history.Source,
+		CurrentBlock:    currentBlock,
 		WalletAddress:   pi.WalletAddress,
 		ContractAddress: pi.ContractAddress,
+		Error:           history.Error,
 	}
 }
 
@@ -252,21 +343,38 @@ func (pi *PolygonIntegration) EstimateGasCost() map[string]interface{} {
 	dataGas := int64(10000)
 	totalGas := baseGas + dataGas
 
-	gasPrice := int64(30) // 30 gwei
-	if pi.GasPrice != nil {
-		gasPrice = pi.GasPrice.Int64()
+	strategy := pi.gasStrategy()
+	quote := strategy.Quote(totalGas)
+	expected := strategy.EstimateCost(quote, totalGas)
+	maxMATIC, maxINR := strategy.ConvertWei(quote.MaxCost)
+	spentToday := strategy.SpentOn(time.Now())
+	spentMATIC, spentINR := strategy.ConvertWei(spentToday)
+
+	estimate := map[string]interface{}{
+		"estimated_gas":        totalGas,
+		"base_fee_gwei":        weiToGwei(quote.BaseFee),
+		"priority_fee_gwei":    weiToGwei(quote.PriorityFee),
+		"max_fee_gwei":         weiToGwei(quote.MaxFee),
+		"gas_price_gwei":       weiToGwei(expected.EffectiveGasPrice),
+		"estimated_cost_wei":   expected.CostWei.String(),
+		"estimated_cost_matic": fmt.Sprintf("%.8f", expected.CostMATIC),
+		"estimated_cost_inr":   fmt.Sprintf("%.4f", expected.CostINR),
+		"max_cost_matic":       fmt.Sprintf("%.8f", maxMATIC),
+		"max_cost_inr":         fmt.Sprintf("%.4f", maxINR),
+		"fee_source":           quote.Source,
+		"spent_today_matic":    fmt.Sprintf("%.8f", spentMATIC),
+		"spent_today_inr":      fmt.Sprintf("%.4f", spentINR),
+		"matic_inr_rate":       strategy.Policy.MaticINRRate,
 	}
 
-	costInWei := totalGas * gasPrice
-	costInEth := float64(costInWei) / 1e18
-
-	return map[string]interface{}{
-		"estimated_gas":      totalGas,
-		"gas_price_gwei":     gasPrice,
-		"estimated_cost_wei": costInWei,
-		"estimated_cost_eth": fmt.Sprintf("%.8f", costInEth),
-		"estimated_cost_usd": fmt.Sprintf("%.4f", costInEth*2000), // Assuming $2000 per ETH
+	if capWei := strategy.Policy.DailySpendCap; capWei != nil && capWei.Sign() > 0 {
+		capMATIC, capINR := strategy.ConvertWei(capWei)
+		estimate["daily_spend_cap_matic"] = fmt.Sprintf("%.8f", capMATIC)
+		estimate["daily_spend_cap_inr"] = fmt.Sprintf("%.4f", capINR)
+		estimate["within_daily_cap"] = strategy.CheckSpendCap(quote, time.Now()) == nil
 	}
+
+	return estimate
 }
 
 // Helper functions
@@ -296,6 +404,18 @@ func generateBlockNumber() int64 {
 	return baseBlock + randomOffset
 }
 
+// NGOAnchorSpend represents the anchoring spend attributed to a single NGO
+type NGOAnchorSpend struct {
+	NGOID              string  `json:"ngo_id"`
+	TotalAnchors       int     `json:"total_anchors"`
+	DonationAnchors    int     `json:"donation_anchors"`
+	ExpenditureAnchors int     `json:"expenditure_anchors"`
+	TotalGasUsed       int64   `json:"total_gas_used"`
+	TotalCostWei       string  `json:"total_cost_wei"`
+	TotalCostMATIC     float64 `json:"total_cost_matic"`
+	TotalCostINR       float64 `json:"total_cost_inr"`
+}
+
 // GetAnchorStatistics returns statistics about anchored data
 func (pi *PolygonIntegration) GetAnchorStatistics() map[string]interface{} {
 	pi.mutex.RLock()
@@ -303,21 +423,55 @@ func (pi *PolygonIntegration) GetAnchorStatistics() map[string]interface{} {
 
 	if len(pi.Anchors) == 0 {
 		return map[string]interface{}{
-			"total_anchors":     0,
-			"total_gas_used":    0,
-			"average_gas_used":  0,
-			"earliest_anchor":   nil,
-			"latest_anchor":     nil,
+			"total_anchors":    0,
+			"total_gas_used":   0,
+			"average_gas_used": 0,
+			"total_cost_matic": 0.0,
+			"total_cost_inr":   0.0,
+			"spend_by_ngo":     map[string]NGOAnchorSpend{},
+			"earliest_anchor":  nil,
+			"latest_anchor":    nil,
 		}
 	}
 
 	totalGasUsed := int64(0)
+	totalCostWei := big.NewInt(0)
+	totalCostMATIC, totalCostINR := 0.0, 0.0
+	spendByNGO := make(map[string]*big.Int)
+	byNGO := make(map[string]NGOAnchorSpend)
 	var earliestTime, latestTime time.Time
 	firstIteration := true
 
 	for _, anchor := range pi.Anchors {
 		totalGasUsed += anchor.GasUsed
-		
+
+		costWei, ok := new(big.Int).SetString(anchor.CostWei, 10)
+		if !ok {
+			costWei = big.NewInt(0)
+		}
+		totalCostWei.Add(totalCostWei, costWei)
+		// Each anchor is costed at the rate recorded when it was sent
+		totalCostMATIC += anchor.CostMATIC
+		totalCostINR += anchor.CostINR
+
+		ngoSpend := byNGO[anchor.NGOID]
+		ngoSpend.NGOID = anchor.NGOID
+		ngoSpend.TotalAnchors++
+		switch anchor.ChainType {
+		case "donation":
+			ngoSpend.DonationAnchors++
+		case "expenditure":
+			ngoSpend.ExpenditureAnchors++
+		}
+		ngoSpend.TotalGasUsed += anchor.GasUsed
+		ngoSpend.TotalCostMATIC += anchor.CostMATIC
+		ngoSpend.TotalCostINR += anchor.CostINR
+		if spendByNGO[anchor.NGOID] == nil {
+			spendByNGO[anchor.NGOID] = big.NewInt(0)
+		}
+		spendByNGO[anchor.NGOID].Add(spendByNGO[anchor.NGOID], costWei)
+		byNGO[anchor.NGOID] = ngoSpend
+
 		if firstIteration {
 			earliestTime = anchor.Timestamp
 			latestTime = anchor.Timestamp
@@ -332,12 +486,21 @@ func (pi *PolygonIntegration) GetAnchorStatistics() map[string]interface{} {
 		}
 	}
 
+	for ngoID, ngoSpend := range byNGO {
+		ngoSpend.TotalCostWei = spendByNGO[ngoID].String()
+		byNGO[ngoID] = ngoSpend
+	}
+
 	averageGasUsed := totalGasUsed / int64(len(pi.Anchors))
 
 	return map[string]interface{}{
 		"total_anchors":    len(pi.Anchors),
 		"total_gas_used":   totalGasUsed,
 		"average_gas_used": averageGasUsed,
+		"total_cost_wei":   totalCostWei.String(),
+		"total_cost_matic": totalCostMATIC,
+		"total_cost_inr":   totalCostINR,
+		"spend_by_ngo":     byNGO,
 		"earliest_anchor":  earliestTime,
 		"latest_anchor":    latestTime,
 	}
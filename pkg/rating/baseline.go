@@ -0,0 +1,116 @@
+package rating
+
+import (
+	"fmt"
+	"time"
+)
+
+// BaselineName is the name of the baseline model
+const BaselineName = "baseline"
+
+// Baseline is the original rating: from 5, points are taken away for a large
+// gap between donations and spending and for spending too little or too
+// much of them, and added for spending 60-85% of them, for documentation,
+// for verified KYC and for certificates
+type Baseline struct {
+	// HalfLife weights donations, spending and documentation by age, so
+	// recent activity counts most. Zero weights all activity equally.
+	HalfLife time.Duration
+}
+
+// Name identifies the model
+func (Baseline) Name() string {
+	return BaselineName
+}
+
+// Rate rates an NGO on its inputs
+func (b Baseline) Rate(inputs Inputs, now time.Time) Result {
+	donations := weightedTotal(inputs.Donations, b.HalfLife, now)
+	expenditures := weightedTotal(inputs.Expenditures, b.HalfLife, now)
+
+	utilizationRate, gapPercentage := 0.0, 0.0
+	if donations != 0 {
+		utilizationRate = expenditures / donations
+		gap := donations - expenditures
+		if gap < 0 {
+			gap = -gap
+		}
+		gapPercentage = gap / donations * 100
+	}
+	documentationQuality := b.documentationQuality(inputs.Documentation, now)
+
+	result := Result{
+		Model:                BaselineName,
+		Base:                 MaxRating,
+		UtilizationRate:      utilizationRate,
+		GapPercentage:        gapPercentage,
+		DocumentationQuality: documentationQuality,
+	}
+
+	// Penalize large gaps
+	gapPenalty := 0.0
+	if gapPercentage > 50 {
+		gapPenalty = -2.0
+	} else if gapPercentage > 30 {
+		gapPenalty = -1.0
+	} else if gapPercentage > 15 {
+		gapPenalty = -0.5
+	}
+	result.add(Factor{
+		Name:         FactorGap,
+		Value:        gapPercentage,
+		Contribution: gapPenalty,
+		Explanation:  fmt.Sprintf("Spending differs from donations by %.2f%%, more than 15%% costs points", gapPercentage),
+	})
+
+	// Reward optimal utilization (60-85% is ideal)
+	utilization := 0.0
+	if utilizationRate >= 0.6 && utilizationRate <= 0.85 {
+		utilization = 0.5
+	} else if utilizationRate < 0.3 || utilizationRate > 0.95 {
+		utilization = -0.5
+	}
+	result.add(Factor{
+		Name:         FactorUtilization,
+		Value:        utilizationRate,
+		Contribution: utilization,
+		Explanation:  fmt.Sprintf("%.2f%% of donations spent, 60-85%% is ideal and below 30%% or above 95%% costs points", utilizationRate*100),
+	})
+
+	result.add(Factor{
+		Name:         FactorDocumentation,
+		Value:        documentationQuality,
+		Contribution: documentationQuality * 0.5,
+		Explanation:  fmt.Sprintf("Recent expenditures are %.1f%% documented", documentationQuality*100),
+	})
+
+	if inputs.KYCVerified {
+		result.add(Factor{Name: FactorKYC, Value: 1, Contribution: 0.2, Explanation: "KYC verified"})
+	}
+	if inputs.Certificates > 0 {
+		result.add(Factor{
+			Name:         FactorCertificates,
+			Value:        float64(inputs.Certificates),
+			Contribution: 0.3,
+			Explanation:  fmt.Sprintf("%d certificates held", inputs.Certificates),
+		})
+	}
+
+	result.settle()
+	return result
+}
+
+// documentationQuality averages the documentation of recent expenditures,
+// weighted by age. An NGO that has not spent yet is fully documented.
+func (b Baseline) documentationQuality(scores []Score, now time.Time) float64 {
+	total, weights := 0.0, 0.0
+	for _, score := range scores {
+		weight := Decay(now.Sub(score.Timestamp), b.HalfLife)
+		total += score.Value * weight
+		weights += weight
+	}
+	if weights == 0 {
+		return 1.0
+	}
+	return total / weights
+}
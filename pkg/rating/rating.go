@@ -0,0 +1,293 @@
+// Package rating scores how well NGOs account for the donations they receive,
+// on a scale of 1 to 5. A model starts from a base rating and adds or takes
+// away points for each factor it weighs, so every rating can be explained as
+// the sum of its factors' contributions. Ratings may then be normalized
+// against NGOs in the same category, whose circumstances are most alike.
+package rating
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Factors a rating is made of
+const (
+	FactorGap           = "gap"
+	FactorUtilization   = "utilization"
+	FactorDocumentation = "documentation"
+	FactorKYC           = "kyc"
+	FactorCertificates  = "certificates"
+	FactorPeers         = "peer_normalization"
+	FactorBounds        = "bounds" // Brings the rating back within 1 to 5
+)
+
+// Bounds of a rating
+const (
+	MinRating = 1.0
+	MaxRating = 5.0
+)
+
+// Flow is a donation received or an expenditure made
+type Flow struct {
+	Amount    money.Money // Negative for reversals and reductions
+	Timestamp time.Time
+}
+
+// Score is how well one expenditure was documented, from 0 to 1
+type Score struct {
+	Value     float64
+	Timestamp time.Time
+}
+
+// Inputs is what an NGO is rated on
+type Inputs struct {
+	Donations     []Flow  // Received in the rating period
+	Expenditures  []Flow  // Recorded in the rating period
+	Documentation []Score // Recent expenditures, none if the NGO has not spent yet
+	KYCVerified   bool
+	Certificates  int
+}
+
+// Factor is one part of a rating and why it counts
+type Factor struct {
+	Name         string  `json:"name"`
+	Value        float64 `json:"value"`        // What was measured
+	Contribution float64 `json:"contribution"` // Points added to the rating, negative if taken away
+	Explanation  string  `json:"explanation"`
+}
+
+// PeerComparison places a rating among the NGOs of the same category
+type PeerComparison struct {
+	Category        string  `json:"category"`
+	Peers           int     `json:"peers"` // NGOs in the category, this one included
+	CategoryAverage float64 `json:"category_average"`
+	PlatformAverage float64 `json:"platform_average"`
+	Percentile      float64 `json:"percentile"` // Share of the category rated lower before normalization, equal ratings counting half
+}
+
+// Result is a rating and the factors it was made of. The rating is the base
+// plus the contributions of every factor.
+type Result struct {
+	Model                string          `json:"model"`
+	Rating               float64         `json:"rating"`
+	Base                 float64         `json:"base"`
+	Factors              []Factor        `json:"factors"`
+	UtilizationRate      float64         `json:"utilization_rate"` // Share of donations spent, weighted for recency
+	GapPercentage        float64         `json:"gap_percentage"`
+	DocumentationQuality float64         `json:"documentation_quality"` // 0 to 1
+	Peers                *PeerComparison `json:"peers,omitempty"`
+}
+
+// Factor returns the named factor, if the rating has one
+func (r *Result) Factor(name string) (Factor, bool) {
+	for _, factor := range r.Factors {
+		if factor.Name == name {
+			return factor, true
+		}
+	}
+	return Factor{}, false
+}
+
+// add appends a factor, leaving out those that change nothing
+func (r *Result) add(factor Factor) {
+	factor.Contribution = round(factor.Contribution)
+	if factor.Contribution != 0 {
+		r.Factors = append(r.Factors, factor)
+	}
+}
+
+// settle sums the factors into the rating, adding a bounds factor when the
+// sum falls outside 1 to 5
+func (r *Result) settle() {
+	factors := r.Factors[:0]
+	total := r.Base
+	for _, factor := range r.Factors {
+		if factor.Name != FactorBounds {
+			factors = append(factors, factor)
+			total += factor.Contribution
+		}
+	}
+	r.Factors = factors
+
+	total = round(total)
+	r.Rating = math.Max(MinRating, math.Min(MaxRating, total))
+	if r.Rating != total {
+		r.add(Factor{
+			Name:         FactorBounds,
+			Value:        total,
+			Contribution: r.Rating - total,
+			Explanation:  fmt.Sprintf("Ratings are kept between %.0f and %.0f", MinRating, MaxRating),
+		})
+	}
+}
+
+// Model rates an NGO
+type Model interface {
+	Name() string
+	Rate(inputs Inputs, now time.Time) Result
+}
+
+// NewModel returns the named model. A positive half-life weights activity
+// by its age, halving the weight of activity that much older.
+func NewModel(name string, halfLife time.Duration) (Model, error) {
+	switch name {
+	case "", BaselineName:
+		return Baseline{HalfLife: halfLife}, nil
+	default:
+		return nil, fmt.Errorf("unknown rating model: %s", name)
+	}
+}
+
+// Decay is the weight of activity of a given age under a half-life, 1 when
+// there is no half-life
+func Decay(age, halfLife time.Duration) float64 {
+	if halfLife <= 0 || age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// Total adds up flows
+func Total(flows []Flow) money.Money {
+	total := money.Zero()
+	for _, flow := range flows {
+		total = total.Add(flow.Amount)
+	}
+	return total
+}
+
+// weightedTotal adds up flows weighted by their age, in rupees
+func weightedTotal(flows []Flow, halfLife time.Duration, now time.Time) float64 {
+	total := 0.0
+	for _, flow := range flows {
+		total += flow.Amount.Float64() * Decay(now.Sub(flow.Timestamp), halfLife)
+	}
+	return total
+}
+
+// round keeps two decimal places
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// Peer is an NGO rated alongside others
+type Peer struct {
+	ID       string
+	Category string
+	Result   *Result
+}
+
+// PeerPolicy holds how ratings are normalized against peers
+type PeerPolicy struct {
+	// Weight is the share of the difference between a category's average
+	// and the platform's added to the category's ratings, 0 to disable
+	Weight float64
+	// MinPeers is how many NGOs a category needs before it is normalized
+	MinPeers int
+}
+
+// DefaultPeerPolicy closes half of the gap between a category of at least
+// three NGOs and the platform
+func DefaultPeerPolicy() PeerPolicy {
+	return PeerPolicy{Weight: 0.5, MinPeers: 3}
+}
+
+// NormalizePeers compares each rating with those of its category and moves
+// the ratings of categories with enough NGOs toward the platform's average,
+// so NGOs working in harder conditions are not marked down for them
+func NormalizePeers(policy PeerPolicy, peers []Peer) {
+	if len(peers) == 0 {
+		return
+	}
+
+	platformTotal := 0.0
+	byCategory := make(map[string][]float64)
+	for _, peer := range peers {
+		platformTotal += peer.Result.Rating
+		byCategory[peer.Category] = append(byCategory[peer.Category], peer.Result.Rating)
+	}
+	platformAverage := round(platformTotal / float64(len(peers)))
+
+	for _, peer := range peers {
+		ratings := byCategory[peer.Category]
+		categoryTotal, lower := 0.0, 0.0
+		for _, rating := range ratings {
+			categoryTotal += rating
+			if rating < peer.Result.Rating {
+				lower++
+			} else if rating == peer.Result.Rating {
+				lower += 0.5
+			}
+		}
+		categoryAverage := round(categoryTotal / float64(len(ratings)))
+		percentile := math.Round(lower / float64(len(ratings)) * 100)
+
+		peer.Result.Peers = &PeerComparison{
+			Category:        peer.Category,
+			Peers:           len(ratings),
+			CategoryAverage: categoryAverage,
+			PlatformAverage: platformAverage,
+			Percentile:      percentile,
+		}
+
+		if policy.Weight <= 0 || len(ratings) < policy.MinPeers || len(ratings) == len(peers) {
+			continue
+		}
+		peer.Result.add(Factor{
+			Name:         FactorPeers,
+			Value:        categoryAverage,
+			Contribution: policy.Weight * (platformAverage - categoryAverage),
+			Explanation: fmt.Sprintf("%d %s NGOs average %.2f against %.2f across the platform",
+				len(ratings), peer.Category, categoryAverage, platformAverage),
+		})
+		peer.Result.settle()
+	}
+}
+
+// FactorChange is how much a factor's contribution moved between two ratings
+type FactorChange struct {
+	Name     string  `json:"name"`
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"`
+}
+
+// Compare lists the factors whose contributions moved from one rating to the
+// next, those that moved most first
+func Compare(previous, current []Factor) []FactorChange {
+	contributions := func(factors []Factor) map[string]float64 {
+		byName := make(map[string]float64, len(factors))
+		for _, factor := range factors {
+			byName[factor.Name] = factor.Contribution
+		}
+		return byName
+	}
+	before, after := contributions(previous), contributions(current)
+
+	names := make(map[string]bool, len(before)+len(after))
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	changes := make([]FactorChange, 0)
+	for name := range names {
+		change := round(after[name] - before[name])
+		if change != 0 {
+			changes = append(changes, FactorChange{Name: name, Previous: before[name], Current: after[name], Change: change})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if math.Abs(changes[i].Change) != math.Abs(changes[j].Change) {
+			return math.Abs(changes[i].Change) > math.Abs(changes[j].Change)
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
@@ -0,0 +1,142 @@
+package rating
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+var now = time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+func flow(rupees float64, age time.Duration) Flow {
+	return Flow{Amount: money.FromMajor(rupees), Timestamp: now.Add(-age)}
+}
+
+// sumOfFactors is what a result's factors add up to
+func sumOfFactors(result Result) float64 {
+	total := result.Base
+	for _, factor := range result.Factors {
+		total += factor.Contribution
+	}
+	return math.Round(total*100) / 100
+}
+
+func TestBaselineExplainsRating(t *testing.T) {
+	inputs := Inputs{
+		Donations:     []Flow{flow(1000, 48*time.Hour)},
+		Expenditures:  []Flow{flow(200, 24*time.Hour)},
+		Documentation: []Score{{Value: 0.6, Timestamp: now.Add(-24 * time.Hour)}},
+		KYCVerified:   true,
+	}
+	result := Baseline{}.Rate(inputs, now)
+
+	// 5 - 2 for an 80% gap - 0.5 for spending 20% + 0.3 for documentation + 0.2 for KYC
+	if result.Rating != 3.0 || result.Model != BaselineName {
+		t.Fatalf("Expected a baseline rating of 3, got %+v", result)
+	}
+	if gap, ok := result.Factor(FactorGap); !ok || gap.Contribution != -2 || gap.Value != 80 {
+		t.Errorf("Expected the gap to cost 2 points, got %+v", gap)
+	}
+	if _, ok := result.Factor(FactorCertificates); ok {
+		t.Errorf("Expected no certificates factor without certificates")
+	}
+	if sum := sumOfFactors(result); sum != result.Rating {
+		t.Errorf("Expected the factors to add up to %v, got %v", result.Rating, sum)
+	}
+
+	// Ideal spending and a certificate push the sum above 5
+	inputs.Expenditures = []Flow{flow(700, 24*time.Hour)}
+	inputs.Documentation = nil
+	inputs.Certificates = 1
+	result = Baseline{}.Rate(inputs, now)
+	bounds, ok := result.Factor(FactorBounds)
+	if result.Rating != MaxRating || !ok || bounds.Contribution != -1 {
+		t.Fatalf("Expected the rating capped at 5 by a bounds factor, got %+v", result)
+	}
+	if sum := sumOfFactors(result); sum != result.Rating {
+		t.Errorf("Expected the factors to add up to %v, got %v", result.Rating, sum)
+	}
+}
+
+func TestDecayWeightsRecentActivity(t *testing.T) {
+	inputs := Inputs{
+		Donations:    []Flow{flow(1000, 28*24*time.Hour)},
+		Expenditures: []Flow{flow(700, 24*time.Hour)},
+	}
+
+	equal := Baseline{}.Rate(inputs, now)
+	if utilization, _ := equal.Factor(FactorUtilization); utilization.Contribution != 0.5 {
+		t.Errorf("Expected 70%% spent to be ideal without decay, got %+v", utilization)
+	}
+
+	// A month-old donation counts for a sixteenth of yesterday's spending
+	decayed := Baseline{HalfLife: 7 * 24 * time.Hour}.Rate(inputs, now)
+	if utilization, _ := decayed.Factor(FactorUtilization); utilization.Contribution != -0.5 || utilization.Value < 10 {
+		t.Errorf("Expected recent spending to outweigh the old donation, got %+v", utilization)
+	}
+	if decayed.Rating >= equal.Rating {
+		t.Errorf("Expected the decayed rating below %v, got %v", equal.Rating, decayed.Rating)
+	}
+
+	if weight := Decay(7*24*time.Hour, 7*24*time.Hour); weight != 0.5 {
+		t.Errorf("Expected activity one half-life old to count half, got %v", weight)
+	}
+	if _, err := NewModel("unknown", 0); err == nil {
+		t.Errorf("Expected an unknown model to be refused")
+	}
+}
+
+func TestNormalizePeers(t *testing.T) {
+	rated := func(id, category string, base float64) Peer {
+		result := &Result{Base: base}
+		result.settle()
+		return Peer{ID: id, Category: category, Result: result}
+	}
+	peers := []Peer{
+		rated("E1", "education", 3.0),
+		rated("E2", "education", 3.5),
+		rated("E3", "education", 4.0),
+		rated("H1", "health", 4.5),
+		rated("H2", "health", 4.5),
+	}
+	NormalizePeers(DefaultPeerPolicy(), peers)
+
+	// Education averages 3.5 against 3.9 across the platform
+	for i, expected := range []float64{3.2, 3.7, 4.2} {
+		result := peers[i].Result
+		factor, ok := result.Factor(FactorPeers)
+		if result.Rating != expected || !ok || factor.Contribution != 0.2 {
+			t.Errorf("Expected %s to be normalized to %v, got %+v", peers[i].ID, expected, result)
+		}
+	}
+	if comparison := peers[2].Result.Peers; comparison == nil || comparison.Peers != 3 || comparison.CategoryAverage != 3.5 || comparison.Percentile != 83 {
+		t.Errorf("Expected E3 compared with its category, got %+v", comparison)
+	}
+
+	// Too few health NGOs to normalize, but each is still compared
+	health := peers[3].Result
+	if _, ok := health.Factor(FactorPeers); ok || health.Rating != 4.5 {
+		t.Errorf("Expected health ratings to stand, got %+v", health)
+	}
+	if health.Peers == nil || health.Peers.Percentile != 50 {
+		t.Errorf("Expected equal health ratings at the 50th percentile, got %+v", health.Peers)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	previous := []Factor{{Name: FactorGap, Contribution: -2}, {Name: FactorKYC, Contribution: 0.2}, {Name: FactorDocumentation, Contribution: 0.5}}
+	current := []Factor{{Name: FactorGap, Contribution: -0.5}, {Name: FactorKYC, Contribution: 0.2}, {Name: FactorUtilization, Contribution: 0.5}, {Name: FactorDocumentation, Contribution: 0.3}}
+
+	changes := Compare(previous, current)
+	if len(changes) != 3 {
+		t.Fatalf("Expected three factors to move, got %+v", changes)
+	}
+	if changes[0].Name != FactorGap || changes[0].Change != 1.5 || changes[1].Name != FactorUtilization || changes[2].Change != -0.2 {
+		t.Errorf("Expected the largest moves first, got %+v", changes)
+	}
+	if len(Compare(current, current)) != 0 {
+		t.Errorf("Expected no change between equal ratings")
+	}
+}
@@ -0,0 +1,197 @@
+package reconciliation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// camtDocument is the subset of an ISO 20022 camt.053 document that is read.
+// Element names are matched without namespace, so camt.053.001.02 through .08
+// are all accepted.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	IBAN     string        `xml:"Acct>Id>IBAN"`
+	Other    string        `xml:"Acct>Id>Othr>Id"`
+	Currency string        `xml:"Acct>Ccy"`
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount `xml:"Amt"`
+	Indicator string     `xml:"CdtDbtInd"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtEntry struct {
+	Reference      string          `xml:"NtryRef"`
+	Amount         camtAmount      `xml:"Amt"`
+	Indicator      string          `xml:"CdtDbtInd"`
+	BookingDate    camtDate        `xml:"BookgDt"`
+	ValueDate      camtDate        `xml:"ValDt"`
+	ServicerRef    string          `xml:"AcctSvcrRef"`
+	Details        []camtTxDetails `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo string          `xml:"AddtlNtryInf"`
+}
+
+type camtTxDetails struct {
+	EndToEndID      string   `xml:"Refs>EndToEndId"`
+	TransactionID   string   `xml:"Refs>TxId"`
+	ChequeNumber    string   `xml:"Refs>ChqNb"`
+	ServicerRef     string   `xml:"Refs>AcctSvcrRef"`
+	Unstructured    []string `xml:"RmtInf>Ustrd"`
+	CreditorName    string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorPtyName string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+	DebtorName      string   `xml:"RltdPties>Dbtr>Nm"`
+	DebtorPtyName   string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+}
+
+// ParseCAMT053 parses an ISO 20022 camt.053 bank-to-customer statement
+func ParseCAMT053(r io.Reader) (*Statement, error) {
+	var document camtDocument
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid camt.053 document: %w", err)
+	}
+	if len(document.Statements) == 0 {
+		return nil, fmt.Errorf("camt.053 document contains no statements")
+	}
+
+	statement := &Statement{Format: FormatCAMT053, Lines: make([]StatementLine, 0)}
+	for i, stmt := range document.Statements {
+		if i == 0 {
+			statement.AccountID = firstNonEmpty(stmt.IBAN, stmt.Other)
+			statement.Currency = stmt.Currency
+		}
+
+		for _, balance := range stmt.Balances {
+			amount, err := parseCAMTAmount(balance.Amount.Value, balance.Indicator)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s balance: %w", balance.Code, err)
+			}
+			switch balance.Code {
+			case "OPBD", "PRCD":
+				if statement.OpeningBalance == nil {
+					statement.OpeningBalance = &amount
+				}
+			case "CLBD":
+				statement.ClosingBalance = &amount
+			}
+		}
+
+		for _, entry := range stmt.Entries {
+			line, err := camtLine(entry)
+			if err != nil {
+				return nil, err
+			}
+			if statement.Currency == "" {
+				statement.Currency = entry.Amount.Currency
+			}
+			statement.Lines = append(statement.Lines, *line)
+		}
+	}
+
+	if statement.Currency == "" {
+		statement.Currency = money.DefaultCurrency
+	}
+	return statement, nil
+}
+
+func camtLine(entry camtEntry) (*StatementLine, error) {
+	booking, err := parseCAMTDate(entry.BookingDate)
+	if err != nil {
+		return nil, fmt.Errorf("entry %s: invalid booking date: %w", entry.Reference, err)
+	}
+
+	// For reversals the indicator already gives the direction of the reversing entry
+	amount, err := parseCAMTAmount(entry.Amount.Value, entry.Indicator)
+	if err != nil {
+		return nil, fmt.Errorf("entry %s: %w", entry.Reference, err)
+	}
+
+	line := &StatementLine{BookingDate: booking}
+	signedLine(line, amount)
+	if valueDate, err := parseCAMTDate(entry.ValueDate); err == nil {
+		line.ValueDate = &valueDate
+	}
+
+	var remittance []string
+	for _, details := range entry.Details {
+		addReference(line, details.EndToEndID)
+		addReference(line, details.TransactionID)
+		addReference(line, details.ChequeNumber)
+		addReference(line, details.ServicerRef)
+		remittance = append(remittance, details.Unstructured...)
+
+		// The counterparty is the creditor of outgoing and the debtor of incoming payments
+		counterparty := firstNonEmpty(details.DebtorName, details.DebtorPtyName)
+		if line.Direction == Debit {
+			counterparty = firstNonEmpty(details.CreditorName, details.CreditorPtyName)
+		}
+		if line.Counterparty == "" {
+			line.Counterparty = cleanText(counterparty)
+		}
+	}
+	addReference(line, entry.ServicerRef)
+	addReference(line, entry.Reference)
+
+	if len(remittance) == 0 && entry.AdditionalInfo != "" {
+		remittance = append(remittance, entry.AdditionalInfo)
+	}
+	line.Description = cleanText(strings.Join(remittance, " "))
+
+	return line, nil
+}
+
+// parseCAMTAmount returns an amount that is negative for debits
+func parseCAMTAmount(value, indicator string) (money.Money, error) {
+	amount, err := money.Parse(value)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if indicator == "DBIT" {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+func parseCAMTDate(d camtDate) (time.Time, error) {
+	if d.Date != "" {
+		return time.Parse("2006-01-02", strings.TrimSpace(d.Date))
+	}
+	if d.DateTime != "" {
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(d.DateTime))
+		if err != nil {
+			// ISO date-times without a zone are common
+			t, err = time.Parse("2006-01-02T15:04:05", strings.TrimSpace(d.DateTime))
+		}
+		return t, err
+	}
+	return time.Time{}, fmt.Errorf("missing date")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,210 @@
+package reconciliation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// csvColumns maps normalized header names to statement fields
+var csvColumns = map[string]string{
+	"date":              "date",
+	"txndate":           "date",
+	"transactiondate":   "date",
+	"trandate":          "date",
+	"bookingdate":       "date",
+	"postingdate":       "date",
+	"valuedate":         "value_date",
+	"valuedt":           "value_date",
+	"description":       "description",
+	"narration":         "description",
+	"particulars":       "description",
+	"remarks":           "description",
+	"details":           "description",
+	"reference":         "reference",
+	"ref":               "reference",
+	"refno":             "reference",
+	"referenceno":       "reference",
+	"chqrefno":          "reference",
+	"utr":               "reference",
+	"utrno":             "reference",
+	"transactionid":     "reference",
+	"chequeno":          "cheque",
+	"chqno":             "cheque",
+	"chequenumber":      "cheque",
+	"debit":             "debit",
+	"dr":                "debit",
+	"withdrawal":        "debit",
+	"withdrawalamt":     "debit",
+	"withdrawalamount":  "debit",
+	"debitamount":       "debit",
+	"credit":            "credit",
+	"cr":                "credit",
+	"deposit":           "credit",
+	"depositamt":        "credit",
+	"depositamount":     "credit",
+	"creditamount":      "credit",
+	"amount":            "amount",
+	"transactionamount": "amount",
+	"type":              "type",
+	"drcr":              "type",
+	"crdr":              "type",
+	"counterparty":      "counterparty",
+	"beneficiary":       "counterparty",
+	"payee":             "counterparty",
+}
+
+// ParseCSV parses a bank statement exported as CSV. The header row is
+// required; common Indian bank column names such as "Narration",
+// "Chq./Ref.No.", "Withdrawal Amt." and "Deposit Amt." are recognised, as is a
+// single signed "Amount" column with an optional "Dr/Cr" column.
+func ParseCSV(r io.Reader) (*Statement, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		if field, ok := csvColumns[normalizeHeader(name)]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+
+	if _, ok := columns["date"]; !ok {
+		return nil, fmt.Errorf("CSV statement has no date column")
+	}
+	_, hasAmount := columns["amount"]
+	_, hasDebit := columns["debit"]
+	_, hasCredit := columns["credit"]
+	if !hasAmount && !hasDebit && !hasCredit {
+		return nil, fmt.Errorf("CSV statement has no amount, debit or credit column")
+	}
+
+	statement := &Statement{Format: FormatCSV, Currency: "INR", Lines: make([]StatementLine, 0)}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+
+		cell := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		// Blank rows and rows without a date (summaries, wrapped narration) carry no transaction
+		if isBlank(record) || cell("date") == "" {
+			continue
+		}
+
+		date, err := parseDate(cell("date"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		line := StatementLine{
+			BookingDate:  date,
+			Description:  cleanText(cell("description")),
+			Counterparty: cleanText(cell("counterparty")),
+		}
+		if valueDate, err := parseDate(cell("value_date")); err == nil {
+			line.ValueDate = &valueDate
+		}
+		addReference(&line, cell("reference"))
+		addReference(&line, cell("cheque"))
+
+		if err := csvAmount(&line, cell("amount"), cell("debit"), cell("credit"), cell("type")); err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		if line.Amount.IsZero() {
+			continue
+		}
+
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	return statement, nil
+}
+
+// csvAmount sets the line amount from either separate debit/credit columns or
+// a signed amount with an optional Dr/Cr indicator
+func csvAmount(line *StatementLine, amount, debit, credit, indicator string) error {
+	if debit != "" || credit != "" {
+		if debit != "" {
+			value, err := parseAmount(debit)
+			if err != nil {
+				return err
+			}
+			if !value.IsZero() {
+				signedLine(line, value.Abs().Neg())
+				return nil
+			}
+		}
+		if credit != "" {
+			value, err := parseAmount(credit)
+			if err != nil {
+				return err
+			}
+			signedLine(line, value.Abs())
+		}
+		return nil
+	}
+
+	if amount == "" {
+		return nil
+	}
+
+	// Amounts such as "1,200.00 Dr" carry the indicator inline
+	upper := strings.ToUpper(amount)
+	for _, suffix := range []string{"DR", "CR"} {
+		if strings.HasSuffix(upper, suffix) {
+			indicator = suffix
+			amount = strings.TrimSpace(amount[:len(amount)-len(suffix)])
+		}
+	}
+
+	value, err := parseAmount(amount)
+	if err != nil {
+		return err
+	}
+	switch strings.ToUpper(strings.TrimSpace(indicator)) {
+	case "DR", "D", "DEBIT":
+		value = value.Abs().Neg()
+	case "CR", "C", "CREDIT":
+		value = value.Abs()
+	}
+	signedLine(line, value)
+	return nil
+}
+
+func normalizeHeader(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
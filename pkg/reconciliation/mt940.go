@@ -0,0 +1,178 @@
+package reconciliation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+var (
+	mt940Tag = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+	// :61: value date, optional entry date, D/C mark, optional funds code,
+	// amount, transaction type, customer reference, optional //bank reference
+	mt940Line = regexp.MustCompile(`^(\d{6})(\d{4})?(R?[CD])([A-Z])?(\d+,\d{0,2})([A-Z][A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+	// :60F:/:62F: D/C mark, date, currency, amount
+	mt940Balance = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})(\d+,\d{0,2})$`)
+	// Structured :86: subfields such as ?20 or ?32
+	mt940Subfield = regexp.MustCompile(`\?\d{2}`)
+)
+
+// ParseMT940 parses a SWIFT MT940 customer statement. Several statements in
+// one file are merged; the account of the first one is reported.
+func ParseMT940(r io.Reader) (*Statement, error) {
+	statement := &Statement{Format: FormatMT940, Lines: make([]StatementLine, 0)}
+
+	type field struct{ tag, value string }
+	var fields []field
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if match := mt940Tag.FindStringSubmatch(text); match != nil {
+			fields = append(fields, field{tag: match[1], value: match[2]})
+			continue
+		}
+		// Continuation lines belong to the previous field; "-" or "-}" ends a
+		// message and "{" opens the SWIFT header blocks
+		if len(fields) > 0 && text != "-" && !strings.HasPrefix(text, "-}") && !strings.HasPrefix(text, "{") {
+			fields[len(fields)-1].value += "\n" + text
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read MT940 statement: %w", err)
+	}
+
+	var current *StatementLine
+	flush := func() {
+		if current != nil {
+			statement.Lines = append(statement.Lines, *current)
+			current = nil
+		}
+	}
+
+	for _, f := range fields {
+		switch f.tag {
+		case "25":
+			if statement.AccountID == "" {
+				statement.AccountID = strings.TrimSpace(f.value)
+			}
+		case "60F", "60M":
+			balance, currency, err := parseMT940Balance(f.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid opening balance: %w", err)
+			}
+			if statement.OpeningBalance == nil {
+				statement.OpeningBalance = &balance
+				statement.Currency = currency
+			}
+		case "62F", "62M":
+			balance, _, err := parseMT940Balance(f.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid closing balance: %w", err)
+			}
+			statement.ClosingBalance = &balance
+		case "61":
+			flush()
+			line, err := parseMT940Line(f.value)
+			if err != nil {
+				return nil, err
+			}
+			current = line
+		case "86":
+			if current != nil {
+				info := mt940Subfield.ReplaceAllString(strings.ReplaceAll(f.value, "\n", ""), " ")
+				current.Description = cleanText(info)
+			}
+		}
+	}
+	flush()
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no MT940 fields found")
+	}
+	if statement.Currency == "" {
+		statement.Currency = money.DefaultCurrency
+	}
+
+	return statement, nil
+}
+
+func parseMT940Line(value string) (*StatementLine, error) {
+	lines := strings.SplitN(value, "\n", 2)
+	match := mt940Line.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if match == nil {
+		return nil, fmt.Errorf("invalid MT940 statement line: %q", lines[0])
+	}
+
+	date, err := time.Parse("060102", match[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid MT940 value date %q: %w", match[1], err)
+	}
+
+	amount, err := parseMT940Amount(match[5])
+	if err != nil {
+		return nil, err
+	}
+
+	line := &StatementLine{BookingDate: date, Amount: amount}
+	valueDate := date
+	line.ValueDate = &valueDate
+
+	if match[2] != "" {
+		// Entry date is MMDD in the value date's year, or the adjacent year
+		// around new year
+		if entry, err := time.Parse("0102", match[2]); err == nil {
+			booking := time.Date(date.Year(), entry.Month(), entry.Day(), 0, 0, 0, 0, time.UTC)
+			if booking.Sub(date) > 180*24*time.Hour {
+				booking = booking.AddDate(-1, 0, 0)
+			} else if date.Sub(booking) > 180*24*time.Hour {
+				booking = booking.AddDate(1, 0, 0)
+			}
+			line.BookingDate = booking
+		}
+	}
+
+	// RC and RD reverse an earlier credit or debit
+	switch match[3] {
+	case "C", "RD":
+		line.Direction = Credit
+	default:
+		line.Direction = Debit
+	}
+
+	addReference(line, match[7])
+	addReference(line, match[8])
+	if len(lines) > 1 {
+		// Supplementary details
+		line.Counterparty = cleanText(lines[1])
+	}
+
+	return line, nil
+}
+
+func parseMT940Balance(value string) (money.Money, string, error) {
+	match := mt940Balance.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return money.Money{}, "", fmt.Errorf("unrecognised balance %q", value)
+	}
+
+	amount, err := parseMT940Amount(match[4])
+	if err != nil {
+		return money.Money{}, "", err
+	}
+	if match[1] == "D" {
+		amount = amount.Neg()
+	}
+	return amount, match[3], nil
+}
+
+// parseMT940Amount parses an MT940 amount, whose decimal comma may have no
+// decimals after it, e.g. "500,"
+func parseMT940Amount(value string) (money.Money, error) {
+	return money.Parse(strings.TrimSuffix(strings.Replace(value, ",", ".", 1), "."))
+}
@@ -0,0 +1,248 @@
+package reconciliation
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// DefaultDateWindow is how far apart a statement line and a recorded
+// transaction may be dated and still match on amount alone
+const DefaultDateWindow = 3 * 24 * time.Hour
+
+// minDescriptionReference is the shortest reference searched for inside
+// statement narration, so short cheque numbers do not match by accident
+const minDescriptionReference = 6
+
+// ItemKind is the type of recorded transaction
+type ItemKind string
+
+const (
+	ItemDonation         ItemKind = "donation"
+	ItemExpenditure      ItemKind = "expenditure"
+	ItemDonationReversal ItemKind = "donation_reversal" // Refund or chargeback paid back out of the bank
+)
+
+// Item is a donation, expenditure or donation reversal recorded on an NGO's chain
+type Item struct {
+	Kind          ItemKind    `json:"kind"`
+	TransactionID string      `json:"transaction_id"`
+	BlockHash     string      `json:"block_hash,omitempty"`
+	Amount        money.Money `json:"amount"` // Amount expected to move through the bank
+	Date          time.Time   `json:"date"`
+	References    []string    `json:"references"` // Transaction ID, bank transaction ID, cheque and invoice numbers
+	Description   string      `json:"description,omitempty"`
+}
+
+// direction is the statement direction the item should appear with
+func (i Item) direction() Direction {
+	if i.Kind == ItemDonation {
+		return Credit
+	}
+	return Debit
+}
+
+// MatchMethod describes how a statement line was matched
+type MatchMethod string
+
+const (
+	MatchReference  MatchMethod = "reference"
+	MatchAmountDate MatchMethod = "amount_date"
+)
+
+// Match pairs a statement line with a recorded transaction
+type Match struct {
+	Line      StatementLine `json:"line"`
+	Item      Item          `json:"item"`
+	Method    MatchMethod   `json:"method"`
+	DaysApart int           `json:"days_apart"`
+}
+
+// Discrepancy is a statement line whose reference identifies a recorded
+// transaction that it disagrees with
+type Discrepancy struct {
+	Line   StatementLine `json:"line"`
+	Item   Item          `json:"item"`
+	Reason string        `json:"reason"`
+}
+
+// Report is the outcome of reconciling a statement
+type Report struct {
+	Matched          []Match         `json:"matched"`
+	Discrepancies    []Discrepancy   `json:"discrepancies"`
+	UnmatchedLines   []StatementLine `json:"unmatched_lines"`   // In the bank but not on chain
+	UnmatchedItems   []Item          `json:"unmatched_items"`   // On chain but not in the bank
+	StatementCredits money.Money     `json:"statement_credits"` // Total of all credit lines
+	StatementDebits  money.Money     `json:"statement_debits"`  // Total of all debit lines
+	MatchedCredits   money.Money     `json:"matched_credits"`
+	MatchedDebits    money.Money     `json:"matched_debits"`
+	Reconciled       bool            `json:"reconciled"`
+}
+
+// Reconciler matches statement lines to recorded transactions
+type Reconciler struct {
+	DateWindow time.Duration
+}
+
+// NewReconciler creates a reconciler; a non-positive window uses DefaultDateWindow
+func NewReconciler(dateWindow time.Duration) *Reconciler {
+	if dateWindow <= 0 {
+		dateWindow = DefaultDateWindow
+	}
+	return &Reconciler{DateWindow: dateWindow}
+}
+
+// Reconcile matches lines to items in two passes. Lines are first matched by
+// a shared reference, which must also agree on amount and direction or the
+// pair is reported as a discrepancy. Remaining lines are then matched on
+// exact amount and direction within the date window, closest date first.
+func (r *Reconciler) Reconcile(lines []StatementLine, items []Item) *Report {
+	report := &Report{
+		Matched:          make([]Match, 0),
+		Discrepancies:    make([]Discrepancy, 0),
+		UnmatchedLines:   make([]StatementLine, 0),
+		UnmatchedItems:   make([]Item, 0),
+		StatementCredits: money.Zero(),
+		StatementDebits:  money.Zero(),
+		MatchedCredits:   money.Zero(),
+		MatchedDebits:    money.Zero(),
+	}
+
+	for _, line := range lines {
+		if line.Direction == Credit {
+			report.StatementCredits = report.StatementCredits.Add(line.Amount)
+		} else {
+			report.StatementDebits = report.StatementDebits.Add(line.Amount)
+		}
+	}
+
+	lineUsed := make([]bool, len(lines))
+	itemUsed := make([]bool, len(items))
+
+	match := func(li, ii int, method MatchMethod) {
+		lineUsed[li], itemUsed[ii] = true, true
+		line, item := lines[li], items[ii]
+		report.Matched = append(report.Matched, Match{
+			Line:      line,
+			Item:      item,
+			Method:    method,
+			DaysApart: daysApart(lineDate(line), item.Date),
+		})
+		if line.Direction == Credit {
+			report.MatchedCredits = report.MatchedCredits.Add(line.Amount)
+		} else {
+			report.MatchedDebits = report.MatchedDebits.Add(line.Amount)
+		}
+	}
+
+	// Pass 1: shared references
+	for ii, item := range items {
+		for li, line := range lines {
+			if lineUsed[li] || !sharesReference(line, item) {
+				continue
+			}
+
+			switch {
+			case line.Direction != item.direction():
+				report.Discrepancies = append(report.Discrepancies, Discrepancy{
+					Line: line, Item: item, Reason: "statement line is a " + string(line.Direction) + " but the " + string(item.Kind) + " expects a " + string(item.direction()),
+				})
+				lineUsed[li], itemUsed[ii] = true, true
+			case !line.Amount.Equal(item.Amount):
+				report.Discrepancies = append(report.Discrepancies, Discrepancy{
+					Line: line, Item: item, Reason: "amount differs: statement " + line.Amount.String() + ", recorded " + item.Amount.String(),
+				})
+				lineUsed[li], itemUsed[ii] = true, true
+			default:
+				match(li, ii, MatchReference)
+			}
+			break
+		}
+	}
+
+	// Pass 2: amount and date window, oldest items first
+	order := make([]int, 0, len(items))
+	for ii := range items {
+		if !itemUsed[ii] {
+			order = append(order, ii)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return items[order[a]].Date.Before(items[order[b]].Date) })
+
+	for _, ii := range order {
+		item := items[ii]
+		best, bestGap := -1, time.Duration(0)
+		for li, line := range lines {
+			if lineUsed[li] || line.Direction != item.direction() || !line.Amount.Equal(item.Amount) {
+				continue
+			}
+			gap := absDuration(lineDate(line).Sub(item.Date))
+			if gap > r.DateWindow {
+				continue
+			}
+			if best == -1 || gap < bestGap {
+				best, bestGap = li, gap
+			}
+		}
+		if best >= 0 {
+			match(best, ii, MatchAmountDate)
+		}
+	}
+
+	for li, line := range lines {
+		if !lineUsed[li] {
+			report.UnmatchedLines = append(report.UnmatchedLines, line)
+		}
+	}
+	for ii, item := range items {
+		if !itemUsed[ii] {
+			report.UnmatchedItems = append(report.UnmatchedItems, item)
+		}
+	}
+
+	report.Reconciled = len(report.Discrepancies) == 0 && len(report.UnmatchedLines) == 0 && len(report.UnmatchedItems) == 0
+	return report
+}
+
+// sharesReference reports whether the line carries one of the item's
+// references, either as a reference field or within its narration
+func sharesReference(line StatementLine, item Item) bool {
+	description := strings.ToUpper(line.Description)
+	for _, reference := range item.References {
+		reference = strings.TrimSpace(reference)
+		if reference == "" {
+			continue
+		}
+		for _, lineReference := range line.References {
+			if strings.EqualFold(lineReference, reference) {
+				return true
+			}
+		}
+		if len(reference) >= minDescriptionReference && strings.Contains(description, strings.ToUpper(reference)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lineDate is the date a line is compared on; banks book up to a few days
+// after the value date, which is closer to when the money actually moved
+func lineDate(line StatementLine) time.Time {
+	if line.ValueDate != nil {
+		return *line.ValueDate
+	}
+	return line.BookingDate
+}
+
+func daysApart(a, b time.Time) int {
+	return int(absDuration(a.Sub(b)).Hours() / 24)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
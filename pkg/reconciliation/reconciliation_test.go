@@ -0,0 +1,220 @@
+package reconciliation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+const sampleCSV = `Date,Narration,Chq./Ref.No.,Value Dt,Withdrawal Amt.,Deposit Amt.,Closing Balance
+01/04/2024,UPI-DONATION-DON_1711962000,UTR4001,01/04/2024,,"49,500.00","1,49,500.00"
+03/04/2024,NEFT-TEST VENDOR-INV-2024-17,000123,04/04/2024,"12,000.50",,"1,37,499.50"
+
+05/04/2024,BANK CHARGES,,05/04/2024,59.00,,"1,37,440.50"
+`
+
+const sampleMT940 = `{1:F01BANKINBBAXXX0000000000}{4:
+:20:STMT240405
+:25:50100012345678
+:28C:00001/001
+:60F:C240331INR100000,
+:61:2404010401C49500,00NTRFUTR4001//BNK998
+:86:?20DONATION DON_1711962000?32RAVI KUMAR
+:61:2404040403D12000,50NCHK000123
+:86:TEST VENDOR INV-2024-17
+:62F:C240405INR137499,50
+-}`
+
+const sampleCAMT = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Acct><Id><Othr><Id>50100012345678</Id></Othr></Id><Ccy>INR</Ccy></Acct>
+      <Bal><Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp><Amt Ccy="INR">100000.00</Amt><CdtDbtInd>CRDT</CdtDbtInd></Bal>
+      <Bal><Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp><Amt Ccy="INR">137499.50</Amt><CdtDbtInd>CRDT</CdtDbtInd></Bal>
+      <Ntry>
+        <Amt Ccy="INR">49500.00</Amt><CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2024-04-01</Dt></BookgDt><ValDt><Dt>2024-04-01</Dt></ValDt>
+        <AcctSvcrRef>BNK998</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>UTR4001</EndToEndId></Refs>
+          <RltdPties><Dbtr><Nm>Ravi Kumar</Nm></Dbtr></RltdPties>
+          <RmtInf><Ustrd>Donation DON_1711962000</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="INR">12000.50</Amt><CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2024-04-03</Dt></BookgDt><ValDt><Dt>2024-04-04</Dt></ValDt>
+        <NtryDtls><TxDtls>
+          <Refs><ChqNb>000123</ChqNb></Refs>
+          <RltdPties><Cdtr><Nm>Test Vendor</Nm></Cdtr></RltdPties>
+        </TxDtls></NtryDtls>
+        <AddtlNtryInf>INV-2024-17</AddtlNtryInf>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+func date(day int) time.Time {
+	return time.Date(2024, time.April, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseFormats(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+		want string
+	}{
+		{"csv", sampleCSV, FormatCSV},
+		{"mt940", sampleMT940, FormatMT940},
+		{"camt053", sampleCAMT, FormatCAMT053},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tc.data)); got != tc.want {
+				t.Fatalf("Expected format %s, got %s", tc.want, got)
+			}
+
+			statement, err := Parse("", []byte(tc.data))
+			if err != nil {
+				t.Fatalf("Failed to parse statement: %v", err)
+			}
+			if len(statement.Lines) < 2 {
+				t.Fatalf("Expected at least 2 lines, got %d", len(statement.Lines))
+			}
+
+			donation, payment := statement.Lines[0], statement.Lines[1]
+			if donation.Direction != Credit || donation.Amount.Minor() != 4950000 || !donation.BookingDate.Equal(date(1)) {
+				t.Errorf("Unexpected donation line: %+v", donation)
+			}
+			if !containsFold(donation.References, "UTR4001") {
+				t.Errorf("Expected donation line to carry UTR4001, got %v", donation.References)
+			}
+			if payment.Direction != Debit || payment.Amount.Minor() != 1200050 {
+				t.Errorf("Unexpected payment line: %+v", payment)
+			}
+			if !containsFold(payment.References, "000123") {
+				t.Errorf("Expected payment line to carry cheque 000123, got %v", payment.References)
+			}
+			if payment.ValueDate == nil || !payment.ValueDate.Equal(date(4)) {
+				t.Errorf("Expected payment value date 2024-04-04, got %v", payment.ValueDate)
+			}
+			if !strings.Contains(payment.Description, "INV-2024-17") {
+				t.Errorf("Expected invoice number in payment narration, got %q", payment.Description)
+			}
+		})
+	}
+}
+
+func TestParseBalances(t *testing.T) {
+	for _, data := range []string{sampleMT940, sampleCAMT} {
+		statement, err := Parse("", []byte(data))
+		if err != nil {
+			t.Fatalf("Failed to parse statement: %v", err)
+		}
+		if statement.AccountID != "50100012345678" || statement.Currency != "INR" {
+			t.Errorf("Unexpected account %q in %s", statement.AccountID, statement.Currency)
+		}
+		if statement.OpeningBalance == nil || statement.OpeningBalance.Minor() != 10000000 {
+			t.Errorf("Unexpected opening balance %v", statement.OpeningBalance)
+		}
+		if statement.ClosingBalance == nil || statement.ClosingBalance.Minor() != 13749950 {
+			t.Errorf("Unexpected closing balance %v", statement.ClosingBalance)
+		}
+	}
+}
+
+func TestParseCSVSignedAmount(t *testing.T) {
+	data := "Txn Date,Description,Amount,Dr/Cr\n2024-04-02,Refund,\"1,000.00\",DR\n2024-04-02,Grant,(250.00),\n"
+	statement, err := ParseCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(statement.Lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(statement.Lines))
+	}
+	if statement.Lines[0].Direction != Debit || statement.Lines[0].Amount.Minor() != 100000 {
+		t.Errorf("Unexpected Dr line: %+v", statement.Lines[0])
+	}
+	if statement.Lines[1].Direction != Debit || statement.Lines[1].Amount.Minor() != 25000 {
+		t.Errorf("Unexpected parenthesised line: %+v", statement.Lines[1])
+	}
+
+	if _, err := ParseCSV(strings.NewReader("Narration,Amount\nx,1\n")); err == nil {
+		t.Error("Expected error for CSV without a date column")
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	lines := []StatementLine{
+		{BookingDate: date(1), Amount: money.INR(4950000), Direction: Credit, References: []string{"UTR4001"}, Description: "DONATION DON_1711962000"},
+		{BookingDate: date(5), Amount: money.INR(1200050), Direction: Debit, References: []string{"000123"}},
+		{BookingDate: date(6), Amount: money.INR(500000), Direction: Credit},
+		{BookingDate: date(7), Amount: money.INR(300000), Direction: Debit, References: []string{"UTR7777"}},
+		{BookingDate: date(9), Amount: money.INR(5900), Direction: Debit, Description: "BANK CHARGES"},
+	}
+	items := []Item{
+		{Kind: ItemDonation, TransactionID: "DON_1711962000", Amount: money.INR(4950000), Date: date(1), References: []string{"DON_1711962000"}},
+		{Kind: ItemExpenditure, TransactionID: "EXP_1", Amount: money.INR(1200050), Date: date(3), References: []string{"EXP_1", "000123"}},
+		{Kind: ItemDonation, TransactionID: "DON_2", Amount: money.INR(500000), Date: date(4), References: []string{"DON_2"}},
+		{Kind: ItemExpenditure, TransactionID: "EXP_2", Amount: money.INR(350000), Date: date(7), References: []string{"EXP_2", "UTR7777"}},
+		{Kind: ItemExpenditure, TransactionID: "EXP_3", Amount: money.INR(80000), Date: date(8), References: []string{"EXP_3"}},
+	}
+
+	report := NewReconciler(0).Reconcile(lines, items)
+
+	methods := map[string]MatchMethod{}
+	for _, m := range report.Matched {
+		methods[m.Item.TransactionID] = m.Method
+	}
+	if methods["DON_1711962000"] != MatchReference || methods["EXP_1"] != MatchReference {
+		t.Errorf("Expected reference matches for DON_1711962000 and EXP_1, got %v", methods)
+	}
+	if methods["DON_2"] != MatchAmountDate {
+		t.Errorf("Expected amount/date match for DON_2, got %v", methods)
+	}
+
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].Item.TransactionID != "EXP_2" {
+		t.Errorf("Expected one discrepancy for EXP_2, got %+v", report.Discrepancies)
+	}
+	if len(report.UnmatchedLines) != 1 || report.UnmatchedLines[0].Description != "BANK CHARGES" {
+		t.Errorf("Expected bank charges to be unmatched, got %+v", report.UnmatchedLines)
+	}
+	if len(report.UnmatchedItems) != 1 || report.UnmatchedItems[0].TransactionID != "EXP_3" {
+		t.Errorf("Expected EXP_3 to be unmatched, got %+v", report.UnmatchedItems)
+	}
+	if report.Reconciled {
+		t.Error("Expected report not to be reconciled")
+	}
+	if report.MatchedCredits.Minor() != 5450000 || report.StatementDebits.Minor() != 1505950 {
+		t.Errorf("Unexpected totals: matched credits %s, statement debits %s", report.MatchedCredits, report.StatementDebits)
+	}
+}
+
+func TestReconcileDateWindow(t *testing.T) {
+	lines := []StatementLine{
+		{BookingDate: date(10), Amount: money.INR(100000), Direction: Credit},
+		{BookingDate: date(2), Amount: money.INR(100000), Direction: Credit},
+	}
+	items := []Item{{Kind: ItemDonation, TransactionID: "DON_1", Amount: money.INR(100000), Date: date(1)}}
+
+	report := NewReconciler(0).Reconcile(lines, items)
+	if len(report.Matched) != 1 || !report.Matched[0].Line.BookingDate.Equal(date(2)) || report.Matched[0].DaysApart != 1 {
+		t.Fatalf("Expected match with the line one day later, got %+v", report.Matched)
+	}
+
+	report = NewReconciler(12*time.Hour).Reconcile(lines[1:], items)
+	if len(report.Matched) != 0 || len(report.UnmatchedItems) != 1 {
+		t.Errorf("Expected no match outside a 12h window, got %+v", report.Matched)
+	}
+}
+
+func containsFold(values []string, want string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, want) {
+			return true
+		}
+	}
+	return false
+}
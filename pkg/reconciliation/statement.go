@@ -0,0 +1,170 @@
+// Package reconciliation imports bank statements and matches their lines
+// against the donations and expenditures recorded on an NGO's chains.
+package reconciliation
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Supported statement formats
+const (
+	FormatCSV     = "csv"
+	FormatMT940   = "mt940"
+	FormatCAMT053 = "camt053"
+)
+
+// Direction of a statement line from the account holder's point of view
+type Direction string
+
+const (
+	Credit Direction = "credit" // Money into the account
+	Debit  Direction = "debit"  // Money out of the account
+)
+
+// StatementLine is a single booked bank transaction
+type StatementLine struct {
+	BookingDate  time.Time   `json:"booking_date"`
+	ValueDate    *time.Time  `json:"value_date,omitempty"`
+	Amount       money.Money `json:"amount"` // Always positive; see Direction
+	Direction    Direction   `json:"direction"`
+	References   []string    `json:"references"` // UTR, end-to-end ID, cheque number, bank reference
+	Description  string      `json:"description"`
+	Counterparty string      `json:"counterparty,omitempty"`
+}
+
+// Statement is a parsed bank statement
+type Statement struct {
+	Format         string          `json:"format"`
+	AccountID      string          `json:"account_id"`
+	Currency       string          `json:"currency"`
+	OpeningBalance *money.Money    `json:"opening_balance,omitempty"`
+	ClosingBalance *money.Money    `json:"closing_balance,omitempty"`
+	Lines          []StatementLine `json:"lines"`
+}
+
+// Period returns the first and last booking dates of the statement
+func (s *Statement) Period() (from, to time.Time) {
+	for i, line := range s.Lines {
+		if i == 0 || line.BookingDate.Before(from) {
+			from = line.BookingDate
+		}
+		if i == 0 || line.BookingDate.After(to) {
+			to = line.BookingDate
+		}
+	}
+	return from, to
+}
+
+// DetectFormat guesses the statement format from its content
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")) && bytes.Contains(trimmed, []byte("BkToCstmrStmt")):
+		return FormatCAMT053
+	case bytes.Contains(trimmed, []byte(":20:")) && bytes.Contains(trimmed, []byte(":61:")):
+		return FormatMT940
+	default:
+		return FormatCSV
+	}
+}
+
+// Parse parses a statement in the given format, detecting it when format is empty
+func Parse(format string, data []byte) (*Statement, error) {
+	if format == "" {
+		format = DetectFormat(data)
+	}
+
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return ParseCSV(bytes.NewReader(data))
+	case FormatMT940:
+		return ParseMT940(bytes.NewReader(data))
+	case FormatCAMT053, "camt.053":
+		return ParseCAMT053(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported statement format: %s", format)
+	}
+}
+
+// dateLayouts are the date formats accepted in CSV statements
+var dateLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"02-01-2006",
+	"02.01.2006",
+	"02/01/06",
+	"02-01-06",
+	"02 Jan 2006",
+	"02-Jan-2006",
+	"02-Jan-06",
+	"2006/01/02",
+	time.RFC3339,
+}
+
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %q", value)
+}
+
+var amountNoise = strings.NewReplacer(",", "", "₹", "", "INR", "", "Rs.", "", "Rs", "", " ", "")
+
+// parseAmount parses a statement amount. Thousands separators and currency
+// markers are ignored; parentheses or a leading minus make it negative.
+func parseAmount(value string) (money.Money, error) {
+	value = strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	amount, err := money.Parse(amountNoise.Replace(value))
+	if err != nil {
+		return money.Money{}, err
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+// signedLine sets the line's amount and direction from a signed amount
+func signedLine(line *StatementLine, amount money.Money) {
+	line.Direction = Credit
+	if amount.IsNegative() {
+		line.Direction = Debit
+	}
+	line.Amount = amount.Abs()
+}
+
+// addReference appends a non-empty reference that is not a placeholder
+func addReference(line *StatementLine, reference string) {
+	reference = strings.TrimSpace(reference)
+	switch strings.ToUpper(reference) {
+	case "", "NONREF", "NOTPROVIDED", "NA", "N/A", "-":
+		return
+	}
+	for _, existing := range line.References {
+		if strings.EqualFold(existing, reference) {
+			return
+		}
+	}
+	line.References = append(line.References, reference)
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+func cleanText(value string) string {
+	return strings.TrimSpace(spaces.ReplaceAllString(value, " "))
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/platform"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// AmendExpenditureRequest represents an NGO's correction of a recorded
+// expenditure. Only the fields given are changed.
+type AmendExpenditureRequest struct {
+	Reason            string       `json:"reason" binding:"required"`
+	Amount            *money.Money `json:"amount,omitempty"` // Rupees
+	Category          *string      `json:"category,omitempty"`
+	Description       *string      `json:"description,omitempty"`
+	InvoiceNumber     *string      `json:"invoice_number,omitempty"`
+	InvoiceDate       *time.Time   `json:"invoice_date,omitempty"`
+	GSTIN             *string      `json:"gstin,omitempty"` // NGO's GSTIN as billed
+	VendorName        *string      `json:"vendor_name,omitempty"`
+	VendorGSTIN       *string      `json:"vendor_gstin,omitempty"`
+	BankTransactionID *string      `json:"bank_transaction_id,omitempty"`
+	ChequeNumber      *string      `json:"cheque_number,omitempty"`
+	Documents         []string     `json:"documents,omitempty"`                                     // Replaces the invoice's supporting documents
+	Attachments       []string     `json:"attachments,omitempty" binding:"dive,len=64,hexadecimal"` // Hashes of uploaded documents
+}
+
+// UpdateExpenditureHandler amends a recorded expenditure
+// @Summary Amend a recorded expenditure
+// @Description Correct an expenditure of the authenticated NGO already recorded in the expenditure blockchain, giving the reason and only the fields to change. The original block is never changed: the amendment is appended to the chain referencing the original block and the version it amends, with the old and new value of every field changed. Changes to the amount, vendor_name or vendor_gstin are re-audited by a new panel and recorded only once approved; a change in amount is then posted to the ledger and the donations funding the expenditure are adjusted. Other corrections are recorded at once. An expenditure may have one amendment awaiting re-audit at a time.
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Expenditure transaction ID"
+// @Param request body AmendExpenditureRequest true "Amendment"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/expenditures/{id} [put]
+func (s *Server) UpdateExpenditureHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req AmendExpenditureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	submission := platform.AmendmentSubmission{
+		Reason: req.Reason,
+		ExpenditureChanges: transactions.ExpenditureChanges{
+			Amount:            req.Amount,
+			Category:          req.Category,
+			Description:       req.Description,
+			InvoiceNumber:     req.InvoiceNumber,
+			InvoiceDate:       req.InvoiceDate,
+			GSTIN:             req.GSTIN,
+			VendorName:        req.VendorName,
+			VendorGSTIN:       req.VendorGSTIN,
+			BankTransactionID: req.BankTransactionID,
+			ChequeNumber:      req.ChequeNumber,
+			Documents:         req.Documents,
+		},
+		Attachments: req.Attachments,
+	}
+
+	result, err := s.Platform.AmendExpenditure(entityID, c.Param("id"), submission)
+	if err != nil {
+		if errors.Is(err, platform.ErrNGONotFound) || errors.Is(err, platform.ErrExpenditureNotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "expenditure_not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "amendment_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, result, "Amendment submitted successfully")
+}
+
+// GetNGOExpenditureHistoryHandler returns the version history of one of the NGO's expenditures
+// @Summary Get expenditure version history
+// @Description Get every version of an expenditure of the authenticated NGO, oldest first: the original as recorded, then each amendment with its reason, the fields it changed, its block and whether it was recorded, rejected on re-audit or awaits re-audit.
+// @Tags NGO
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Expenditure transaction ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/expenditures/{id}/history [get]
+func (s *Server) GetNGOExpenditureHistoryHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	s.expenditureHistory(c, entityID)
+}
+
+// GetExpenditureHistoryHandler returns the version history of any recorded expenditure
+// @Summary Get expenditure version history
+// @Description Get every version of a recorded expenditure of any NGO, oldest first: the original as recorded, then each amendment with its reason, the fields it changed, its block and whether it was recorded, rejected on re-audit or awaits re-audit.
+// @Tags Transactions
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Expenditure transaction ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/transactions/expenditures/{id}/history [get]
+func (s *Server) GetExpenditureHistoryHandler(c *gin.Context) {
+	s.expenditureHistory(c, "")
+}
+
+func (s *Server) expenditureHistory(c *gin.Context, ngoID string) {
+	history, err := s.Platform.GetExpenditureHistory(ngoID, c.Param("id"))
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "expenditure_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, history, "Expenditure history retrieved successfully")
+}
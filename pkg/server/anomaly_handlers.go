@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/middleware"
+)
+
+// GetAnomalyReportHandler lists the NGOs whose expenditures show anomalies
+// @Summary List expenditure anomalies
+// @Description Scan every NGO's expenditure blockchain for anomalies: first digits departing from Benford's law, invoices from one vendor split under an approval threshold, clusters of round amounts, spikes in a category's spending and spending concentrated on one vendor. NGOs with alerts are listed riskiest first, each alert with a risk score from 0 to 100.
+// @Tags Auditor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auditors/anomalies [get]
+func (s *Server) GetAnomalyReportHandler(c *gin.Context) {
+	middleware.StandardResponse(c, s.Platform.GetAnomalyReport(), "Anomalies retrieved successfully")
+}
+
+// GetNGOAnomaliesHandler lists the anomalies in one NGO's expenditures
+// @Summary Get NGO expenditure anomalies
+// @Description Scan an NGO's expenditure blockchain for anomalies and list the alerts riskiest first.
+// @Tags Auditor
+// @Security Bearer
+// @Produce json
+// @Param ngo_id path string true "NGO ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/auditors/anomalies/{ngo_id} [get]
+func (s *Server) GetNGOAnomaliesHandler(c *gin.Context) {
+	anomalies, err := s.Platform.GetAnomalies(c.Param("ngo_id"))
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "ngo_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, anomalies, "Anomalies retrieved successfully")
+}
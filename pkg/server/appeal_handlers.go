@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// AppealExpenditureRequest represents an NGO's appeal against a rejected expenditure
+type AppealExpenditureRequest struct {
+	Reason            string   `json:"reason" binding:"required"`
+	BankTransactionID string   `json:"bank_transaction_id,omitempty"`
+	ChequeNumber      string   `json:"cheque_number,omitempty"`
+	Documents         []string `json:"documents,omitempty"`                                     // Replaces the invoice's supporting documents
+	Attachments       []string `json:"attachments,omitempty" binding:"dive,len=64,hexadecimal"` // Hashes of uploaded documents
+}
+
+// GetNGORejectedExpendituresHandler lists the NGO's rejected expenditures
+// @Summary List rejected expenditures
+// @Description List the authenticated NGO's rejected expenditures, oldest first, with every review decision and note. Those with appealable true may be appealed once; the others were upheld on appeal.
+// @Tags NGO
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/expenditures/rejected [get]
+func (s *Server) GetNGORejectedExpendituresHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	middleware.StandardResponse(c, s.Platform.GetNGORejectedExpenditures(entityID), "Rejected expenditures retrieved successfully")
+}
+
+// AppealExpenditureHandler contests the rejection of an expenditure
+// @Summary Appeal a rejected expenditure
+// @Description Contest the rejection of an expenditure of the authenticated NGO, optionally adding payment proof, supporting documents or attachments. The expenditure is rescored and re-reviewed by auditors who were not on the panel that rejected it. Their decision is final and is recorded in the expenditure blockchain with a reference to the original rejection.
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Expenditure transaction ID"
+// @Param request body AppealExpenditureRequest true "Appeal"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/expenditures/{id}/appeal [post]
+func (s *Server) AppealExpenditureHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req AppealExpenditureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	submission := platform.AppealSubmission{
+		Reason:            req.Reason,
+		BankTransactionID: req.BankTransactionID,
+		ChequeNumber:      req.ChequeNumber,
+		Documents:         req.Documents,
+		Attachments:       req.Attachments,
+	}
+
+	result, err := s.Platform.AppealExpenditure(entityID, c.Param("id"), submission)
+	if err != nil {
+		if errors.Is(err, platform.ErrExpenditureNotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "expenditure_not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "appeal_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, result, "Appeal submitted successfully")
+}
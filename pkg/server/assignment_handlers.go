@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// DeclareConflictRequest represents an auditor's conflict of interest with an NGO
+type DeclareConflictRequest struct {
+	NGOID  string `json:"ngo_id" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// DeclareConflictHandler records a conflict of interest for the authenticated auditor
+// @Summary Declare conflict of interest
+// @Description Declare a conflict of interest with an NGO. The auditor is never assigned the NGO's expenditures again, and those already awaiting the auditor's review are reassigned. The declaration is refused if a pending expenditure has no other eligible auditor.
+// @Tags Auditor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body DeclareConflictRequest true "NGO and reason"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/auditors/conflicts [post]
+func (s *Server) DeclareConflictHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req DeclareConflictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	conflict, err := s.Platform.DeclareConflict(entityID, req.NGOID, req.Reason)
+	if err != nil {
+		if errors.Is(err, platform.ErrAuditorNotFound) || errors.Is(err, platform.ErrNGONotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "conflict_declaration_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, conflict, "Conflict of interest declared successfully")
+}
+
+// GetAssignmentsHandler lists the expenditures the authenticated auditor has been assigned
+// @Summary List auditor assignments
+// @Description List every expenditure assigned to the authenticated auditor, newest first, with the reason the auditor was chosen.
+// @Tags Auditor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auditors/assignments [get]
+func (s *Server) GetAssignmentsHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	middleware.StandardResponse(c, s.Platform.GetAssignments(entityID), "Assignments retrieved successfully")
+}
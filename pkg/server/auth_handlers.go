@@ -10,6 +10,7 @@ import (
 	"ngo-transparency-platform/pkg/auth"
 	"ngo-transparency-platform/pkg/database"
 	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/money"
 )
 
 // RegisterRequest represents the registration request
@@ -260,7 +261,7 @@ func (s *Server) createDonorEntity(userID uint, req RegisterRequest) (string, er
 	donorModel := database.DonorModel{
 		UserID:              userID,
 		DonorID:             donorID,
-		AnnualDonationLimit: 1000000, // Default 10 lakh
+		AnnualDonationLimit: money.INR(1000000 * 100), // Default 10 lakh
 	}
 
 	if err := database.DB.Create(&donorModel).Error; err != nil {
@@ -269,7 +270,7 @@ func (s *Server) createDonorEntity(userID uint, req RegisterRequest) (string, er
 
 	// Register in platform
 	kycData := map[string]interface{}{
-		"annual_limit": 1000000,
+		"annual_limit": money.INR(1000000 * 100),
 	}
 	
 	_, err := s.Platform.RegisterDonor(donorID, kycData)
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// CreateCampaignRequest represents an NGO's request to start a campaign
+type CreateCampaignRequest struct {
+	Name        string      `json:"name" binding:"required"`
+	Description string      `json:"description"`
+	GoalAmount  money.Money `json:"goal_amount"`          // Rupees
+	StartDate   *time.Time  `json:"start_date,omitempty"` // Defaults to now
+	EndDate     *time.Time  `json:"end_date,omitempty"`   // Open-ended when omitted
+}
+
+// CreateCampaignHandler starts a campaign for the authenticated NGO
+// @Summary Create campaign
+// @Description Start a campaign with a fundraising goal. Donations earmarked to the campaign are restricted funds that only its expenditures may draw on.
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body CreateCampaignRequest true "Campaign details"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/campaigns [post]
+func (s *Server) CreateCampaignHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var startDate time.Time
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+
+	campaign, err := s.Platform.CreateCampaign(entityID, req.Name, req.Description, req.GoalAmount, startDate, req.EndDate)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "campaign_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, campaign, "Campaign created successfully")
+}
+
+// CloseCampaignHandler stops one of the NGO's campaigns from accepting donations
+// @Summary Close campaign
+// @Description Stop a campaign of the authenticated NGO from accepting donations. Its restricted balance can still be spent.
+// @Tags NGO
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/campaigns/{id}/close [post]
+func (s *Server) CloseCampaignHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	campaign, err := s.Platform.CloseCampaign(entityID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, platform.ErrNGONotFound) || errors.Is(err, platform.ErrCampaignNotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "campaign_not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "campaign_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, campaign, "Campaign closed successfully")
+}
+
+// GetNGOCampaignsHandler lists an NGO's campaigns
+// @Summary List NGO campaigns
+// @Description List an NGO's campaigns with their goal progress, restricted balance and utilization
+// @Tags Public
+// @Produce json
+// @Param id path string true "NGO ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/{id}/campaigns [get]
+func (s *Server) GetNGOCampaignsHandler(c *gin.Context) {
+	campaigns, err := s.Platform.GetCampaigns(c.Param("id"))
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "ngo_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, campaigns, "Campaigns retrieved successfully")
+}
+
+// GetCampaignHandler reports where a campaign's funds went
+// @Summary Get campaign
+// @Description Get a campaign's goal progress and utilization, and the expenditures its restricted funds were spent on
+// @Tags Public
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/campaigns/{id} [get]
+func (s *Server) GetCampaignHandler(c *gin.Context) {
+	report, err := s.Platform.GetCampaignReport(c.Param("id"))
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "campaign_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, report, "Campaign retrieved successfully")
+}
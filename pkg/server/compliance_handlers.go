@@ -0,0 +1,17 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/middleware"
+)
+
+// GetComplianceRulesHandler returns the rules expenditures are scored under
+// @Summary Get compliance rules
+// @Description Get the active compliance scoring rules: the weighted documentation checks, per-category overrides and recommendation bands. Every expenditure and audit records the version of the rules its score was calculated under.
+// @Tags Public
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Router /api/v1/compliance/rules [get]
+func (s *Server) GetComplianceRulesHandler(c *gin.Context) {
+	middleware.StandardResponse(c, s.Platform.ComplianceRules, "Compliance rules retrieved successfully")
+}
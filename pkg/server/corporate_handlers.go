@@ -0,0 +1,222 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/csr"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// RegisterCorporateRequest represents a donor's request to register as a company
+type RegisterCorporateRequest struct {
+	CompanyName  string                `json:"company_name" binding:"required"`
+	CIN          string                `json:"cin,omitempty"`
+	MatchingRule entities.MatchingRule `json:"matching_rule"`
+}
+
+// AddEmployeeRequest represents a company's request to match a donor's donations
+type AddEmployeeRequest struct {
+	DonorID string `json:"donor_id" binding:"required"`
+}
+
+// RegisterCorporateHandler registers the authenticated donor as a company
+// @Summary Register corporate donor
+// @Description Register the authenticated donor as a company that matches its employees' donations and reports its CSR spend
+// @Tags Donor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body RegisterCorporateRequest true "Company and matching rule"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/corporate [post]
+func (s *Server) RegisterCorporateHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req RegisterCorporateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	profile, err := s.Platform.RegisterCorporate(entityID, req.CompanyName, req.CIN, req.MatchingRule)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "corporate_registration_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, profile, "Corporate registered successfully")
+}
+
+// GetCorporateProfileHandler returns the authenticated company's profile
+// @Summary Get corporate profile
+// @Description Get the authenticated company's matching rule and employees
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/corporate [get]
+func (s *Server) GetCorporateProfileHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	profile, err := s.Platform.GetCorporateProfile(entityID)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "corporate_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, profile, "Corporate profile retrieved successfully")
+}
+
+// UpdateMatchingRuleHandler replaces the authenticated company's matching rule
+// @Summary Update matching rule
+// @Description Set the ratio, per-employee annual cap and eligible NGO categories for matching employee donations. Donations already matched are not affected.
+// @Tags Donor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body entities.MatchingRule true "Matching rule"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/corporate/matching-rule [put]
+func (s *Server) UpdateMatchingRuleHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var rule entities.MatchingRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	profile, err := s.Platform.UpdateMatchingRule(entityID, rule)
+	s.corporateResponse(c, profile, err, "Matching rule updated successfully")
+}
+
+// AddEmployeeHandler enrols a donor as an employee of the authenticated company
+// @Summary Add employee
+// @Description Enrol a donor as an employee so the company matches their future donations
+// @Tags Donor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body AddEmployeeRequest true "Employee donor"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/corporate/employees [post]
+func (s *Server) AddEmployeeHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req AddEmployeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	profile, err := s.Platform.AddEmployee(entityID, req.DonorID)
+	s.corporateResponse(c, profile, err, "Employee added successfully")
+}
+
+// RemoveEmployeeHandler stops the authenticated company matching a donor's donations
+// @Summary Remove employee
+// @Description Stop matching an employee's donations. Donations already matched are not affected.
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Param donor_id path string true "Employee donor ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/corporate/employees/{donor_id} [delete]
+func (s *Server) RemoveEmployeeHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	profile, err := s.Platform.RemoveEmployee(entityID, c.Param("donor_id"))
+	s.corporateResponse(c, profile, err, "Employee removed successfully")
+}
+
+// GetCSRReportHandler returns the authenticated company's CSR spend for a financial year
+// @Summary Get CSR report
+// @Description Summarise the company's direct and matched donations in an April-March financial year by the Schedule VII activity each NGO's category maps to
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Param fy query int false "Year the financial year starts in, e.g. 2025 for 2025-26 (defaults to the current financial year)"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/corporate/csr-report [get]
+func (s *Server) GetCSRReportHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	fy := csr.FinancialYear(time.Now())
+	if value := c.Query("fy"); value != "" {
+		if fy, err = strconv.Atoi(value); err != nil {
+			middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid financial year", nil)
+			return
+		}
+	}
+
+	report, err := s.Platform.GetCSRReport(entityID, fy)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "corporate_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, report, "CSR report generated successfully")
+}
+
+// corporateResponse writes the result of a change to a company's profile
+func (s *Server) corporateResponse(c *gin.Context, profile *entities.CorporateProfile, err error, message string) {
+	if err != nil {
+		if errors.Is(err, platform.ErrDonorNotFound) || errors.Is(err, platform.ErrCorporateProfileNotFound) || errors.Is(err, platform.ErrEmployeeNotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "corporate_not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "corporate_update_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, profile, message)
+}
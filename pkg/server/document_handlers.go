@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// UploadDocumentHandler stores an uploaded invoice, receipt or KYC document
+// @Summary Upload document
+// @Description Upload a file as multipart form field file. The platform computes the SHA-256 of the contents, detects the MIME type from them and checks both type and size against the storage limits. Files are stored under their hash, so uploading the same contents again returns the earlier upload. Give the hash in an expenditure's or appeal's attachments to link the document to it.
+// @Tags Documents
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Document"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 413 {object} middleware.ErrorResponse
+// @Router /api/v1/documents [post]
+func (s *Server) UploadDocumentHandler(c *gin.Context) {
+	_, userType, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	// Leave room for the multipart headers around the file
+	maxSize := s.Platform.DocumentPolicy.MaxSize
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if header.Size > maxSize {
+		middleware.ErrorResponseWithDetails(c, http.StatusRequestEntityTooLarge, "document_too_large", fmt.Sprintf("document exceeds the %d byte limit", maxSize), nil)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "upload_failed", err.Error(), nil)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "upload_failed", err.Error(), nil)
+		return
+	}
+
+	document, err := s.Platform.UploadDocument(entityID, userType, header.Filename, data)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "upload_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, document, "Document uploaded successfully")
+}
+
+// GetDocumentHandler downloads an uploaded document
+// @Summary Download document
+// @Description Download the document with the given SHA-256, as recorded in an expenditure's attachments on chain. The contents are checked against the hash before they are sent, and the hash is returned in the X-Content-SHA256 header. Auditors and admins may download any document; others only their own uploads.
+// @Tags Documents
+// @Security Bearer
+// @Produce application/octet-stream
+// @Param hash path string true "SHA-256 of the document"
+// @Success 200 {file} file
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/documents/{hash} [get]
+func (s *Server) GetDocumentHandler(c *gin.Context) {
+	_, userType, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	document, data, err := s.Platform.GetDocument(entityID, userType, c.Param("hash"))
+	if err != nil {
+		if errors.Is(err, platform.ErrDocumentNotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "document_not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusInternalServerError, "download_failed", err.Error(), nil)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.Filename))
+	c.Header("X-Content-SHA256", document.Hash)
+	c.Data(http.StatusOK, document.MIMEType, data)
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// ImportEInvoiceHandler verifies a GST e-invoice without recording anything
+// @Summary Import e-invoice
+// @Description Verify a GST e-invoice and return the invoice details an expenditure paying it would record. Give the IRP's response to the supplier, or the INV-01 invoice with the signed QR code printed on it, or the signed QR code alone. Signatures are checked against the Invoice Registration Portal's public key, and the invoice and QR code must describe the same invoice. The details include the verified IRN and signed QR code, so anyone can check the invoice's registration again.
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body platform.EInvoiceSubmission true "E-invoice"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/einvoices/import [post]
+func (s *Server) ImportEInvoiceHandler(c *gin.Context) {
+	var req platform.EInvoiceSubmission
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	invoice, err := s.Platform.ImportEInvoice(req)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "einvoice_invalid", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, invoice, "E-invoice verified successfully")
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/platform"
+	"ngo-transparency-platform/pkg/transactions"
+)
+
+// CreateExpenditureRequest represents an NGO's request to record an expenditure
+type CreateExpenditureRequest struct {
+	Amount            money.Money `json:"amount"`                      // Rupees
+	Category          string      `json:"category" binding:"required"` // Auditors specialised in the category are preferred
+	Description       string      `json:"description"`
+	BankTransactionID string      `json:"bank_transaction_id,omitempty"` // Bank reference used for reconciliation
+	ChequeNumber      string      `json:"cheque_number,omitempty"`
+	// Draws on the campaign's restricted funds, which must cover the amount
+	CampaignID string `json:"campaign_id,omitempty"`
+	// Donations to draw on first; the rest is funded from the oldest unspent donations
+	Funding []transactions.FundAllocation `json:"funding,omitempty"`
+	// Vendor invoice the expenditure pays. One whose vendor and invoice number were
+	// already paid, by any NGO, is rejected; one matching an earlier invoice's
+	// vendor, amount and date is recorded with invoice_flags for auditors
+	Invoice *ExpenditureInvoice `json:"invoice,omitempty"`
+	// GST e-invoice the expenditure pays, instead of invoice. Its signatures are
+	// verified against the IRP key, and the amount may not exceed its total
+	EInvoice *platform.EInvoiceSubmission `json:"einvoice,omitempty"`
+	// Hashes of documents uploaded to /api/v1/documents, e.g. the invoice and receipt
+	Attachments []string `json:"attachments,omitempty" binding:"dive,len=64,hexadecimal"`
+}
+
+// ExpenditureInvoice represents the vendor invoice an expenditure pays
+type ExpenditureInvoice struct {
+	InvoiceNumber string    `json:"invoice_number" binding:"required"`
+	GSTIN         string    `json:"gstin" binding:"required"` // NGO's GSTIN as billed
+	VendorName    string    `json:"vendor_name" binding:"required"`
+	VendorGSTIN   string    `json:"vendor_gstin" binding:"required"`
+	InvoiceDate   time.Time `json:"invoice_date" binding:"required"`
+	Documents     []string  `json:"documents,omitempty"`
+}
+
+// CreateExpenditureHandler submits an expenditure for review
+// @Summary Create expenditure
+// @Description Submit an expenditure of the authenticated NGO for auditor review
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Client key identifying this expenditure across retries"
+// @Param request body CreateExpenditureRequest true "Expenditure details"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 422 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/expenditures [post]
+func (s *Server) CreateExpenditureHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req CreateExpenditureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	expenditureData := map[string]interface{}{
+		"amount":              req.Amount,
+		"category":            req.Category,
+		"description":         req.Description,
+		"bank_transaction_id": req.BankTransactionID,
+		"cheque_number":       req.ChequeNumber,
+		"campaign_id":         req.CampaignID,
+		"funding":             req.Funding,
+		"attachments":         req.Attachments,
+	}
+	if req.Invoice != nil {
+		expenditureData["invoice"] = transactions.InvoiceDetails{
+			InvoiceNumber: req.Invoice.InvoiceNumber,
+			GSTIN:         strings.ToUpper(strings.TrimSpace(req.Invoice.GSTIN)),
+			VendorName:    req.Invoice.VendorName,
+			VendorGSTIN:   strings.ToUpper(strings.TrimSpace(req.Invoice.VendorGSTIN)),
+			InvoiceDate:   req.Invoice.InvoiceDate,
+			Documents:     req.Invoice.Documents,
+		}
+	}
+	if req.EInvoice != nil {
+		expenditureData["einvoice"] = *req.EInvoice
+	}
+
+	var result map[string]interface{}
+	if key := c.GetHeader(middleware.IdempotencyKeyHeader); key != "" {
+		result, err = s.Platform.SubmitExpenditureIdempotent(key, entityID, expenditureData)
+	} else {
+		result, err = s.Platform.SubmitExpenditure(entityID, expenditureData)
+	}
+	if err != nil {
+		respondIdempotentError(c, err, "expenditure_failed")
+		return
+	}
+
+	middleware.StandardResponse(c, result, "Expenditure submitted for review successfully")
+}
+
+// respondIdempotentError reports a reused idempotency key as 422 and any
+// other error as a bad request
+func respondIdempotentError(c *gin.Context, err error, errorCode string) {
+	if errors.Is(err, platform.ErrIdempotencyKeyReused) {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnprocessableEntity, "idempotency_key_reused", err.Error(), nil)
+		return
+	}
+	middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, errorCode, err.Error(), nil)
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/middleware"
+)
+
+// TraceDonationHandler follows a donation to the expenditures it paid for
+// @Summary Trace donation
+// @Description List every expenditure block a donation funded, with the amount it contributed to each and how much of it is still unspent
+// @Tags Public
+// @Produce json
+// @Param id path string true "Donation transaction ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donations/{id}/trace [get]
+func (s *Server) TraceDonationHandler(c *gin.Context) {
+	trace, err := s.Platform.TraceDonation(c.Param("id"))
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "donation_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, trace, "Donation traced successfully")
+}
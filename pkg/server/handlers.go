@@ -122,6 +122,28 @@ func (s *Server) GetNGORatingHandler(c *gin.Context) {
 	middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "rating_not_found", "Rating data not found", nil)
 }
 
+// GetNGORatingHistoryHandler returns every rating recorded for an NGO
+// @Summary Get NGO rating history
+// @Description Get each recorded NGO rating, oldest first, with the factors it was made of and those that moved it
+// @Tags Public
+// @Produce json
+// @Param id path string true "NGO ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/{id}/rating/history [get]
+func (s *Server) GetNGORatingHistoryHandler(c *gin.Context) {
+	history, err := s.Platform.GetNGORatingHistory(c.Param("id"))
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "ngo_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, gin.H{
+		"ngo_id":  c.Param("id"),
+		"history": history,
+	}, "NGO rating history retrieved successfully")
+}
+
 // GetSystemStatusHandler returns system health status
 // @Summary Get system status
 // @Description Get system health and status information
@@ -206,6 +228,31 @@ func (s *Server) VerifyBlockchainDataHandler(c *gin.Context) {
 	middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "hash_not_found", "Hash not found in blockchain records", nil)
 }
 
+// GetPolygonStatsHandler returns Polygon network fees and anchoring spend
+// @Summary Get Polygon anchoring statistics
+// @Description Get current fee market data, anchoring cost estimate and spend per NGO
+// @Tags Blockchain
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 503 {object} middleware.ErrorResponse
+// @Router /api/v1/blockchain/polygon/stats [get]
+func (s *Server) GetPolygonStatsHandler(c *gin.Context) {
+	if s.Platform.PolygonIntegration == nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusServiceUnavailable, "polygon_unavailable", "Polygon integration is not configured", nil)
+		return
+	}
+
+	response := gin.H{
+		"network":           s.Platform.PolygonIntegration.GetNetworkStats(),
+		"cost_estimate":     s.Platform.PolygonIntegration.EstimateGasCost(),
+		"anchor_statistics": s.Platform.PolygonIntegration.GetAnchorStatistics(),
+		"pending_anchors":   s.Platform.GetPendingAnchors(""),
+	}
+
+	middleware.StandardResponse(c, response, "Polygon statistics retrieved successfully")
+}
+
 // Protected endpoint handlers (require authentication)
 
 // GetNGOProfileHandler returns NGO profile information
@@ -388,15 +435,12 @@ func (s *Server) UpdateDonorProfileHandler(c *gin.Context)      { c.JSON(501, gi
 func (s *Server) UpdateAuditorProfileHandler(c *gin.Context)    { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetNGODonationsHandler(c *gin.Context)         { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetNGOExpendituresHandler(c *gin.Context)      { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
-func (s *Server) CreateExpenditureHandler(c *gin.Context)       { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetExpenditureHandler(c *gin.Context)          { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
-func (s *Server) UpdateExpenditureHandler(c *gin.Context)       { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetNGODonationBlocksHandler(c *gin.Context)    { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetNGOExpenditureBlocksHandler(c *gin.Context) { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) SubmitNGOKYCHandler(c *gin.Context)            { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetNGOFinancialSummaryHandler(c *gin.Context)  { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetDonorDonationsHandler(c *gin.Context)       { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
-func (s *Server) CreateDonationHandler(c *gin.Context)          { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetDonationHandler(c *gin.Context)             { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetTaxBenefitsHandler(c *gin.Context)          { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetPreferredNGOsHandler(c *gin.Context)        { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
@@ -405,8 +449,6 @@ func (s *Server) RemovePreferredNGOHandler(c *gin.Context)      { c.JSON(501, gi
 func (s *Server) SubmitDonorKYCHandler(c *gin.Context)          { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) CheckDonationLimitHandler(c *gin.Context)      { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetAuditorAuditsHandler(c *gin.Context)        { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
-func (s *Server) GetPendingExpendituresHandler(c *gin.Context)  { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
-func (s *Server) AuditExpenditureHandler(c *gin.Context)        { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetAuditHandler(c *gin.Context)                { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) SubmitAuditorKYCHandler(c *gin.Context)        { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetDonationTransactionHandler(c *gin.Context)  { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
@@ -416,5 +458,4 @@ func (s *Server) GetComplianceReportHandler(c *gin.Context)     { c.JSON(501, gi
 func (s *Server) GetBlockHandler(c *gin.Context)                { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) VerifyBlockHandler(c *gin.Context)             { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) GetPolygonAnchorsHandler(c *gin.Context)       { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
-func (s *Server) GetPolygonStatsHandler(c *gin.Context)         { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
 func (s *Server) AnchorBlockToPolygonHandler(c *gin.Context)    { c.JSON(501, gin.H{"error": "Not implemented yet"}) }
\ No newline at end of file
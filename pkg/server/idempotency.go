@@ -0,0 +1,48 @@
+package server
+
+import (
+	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/middleware"
+)
+
+// idempotencyKeyStore keeps idempotency keys in the database so retries are
+// recognised across restarts and server instances
+type idempotencyKeyStore struct {
+	keys *database.IdempotencyKeyRepository
+}
+
+// newIdempotencyStore returns the database-backed store, or an in-memory one
+// when no database is configured
+func newIdempotencyStore() middleware.IdempotencyStore {
+	if database.DB == nil {
+		return middleware.NewMemoryIdempotencyStore()
+	}
+	return &idempotencyKeyStore{keys: database.NewRepositories(database.DB).Idempotency}
+}
+
+func (s *idempotencyKeyStore) Reserve(record *middleware.IdempotencyRecord) (*middleware.IdempotencyRecord, error) {
+	existing, err := s.keys.Reserve(&database.IdempotencyKeyModel{
+		Key:         record.Key,
+		RequestHash: record.RequestHash,
+		ExpiresAt:   record.ExpiresAt,
+	})
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	return &middleware.IdempotencyRecord{
+		Key:         existing.Key,
+		RequestHash: existing.RequestHash,
+		StatusCode:  existing.StatusCode,
+		Body:        []byte(existing.ResponseBody),
+		Completed:   existing.Completed,
+		ExpiresAt:   existing.ExpiresAt,
+	}, nil
+}
+
+func (s *idempotencyKeyStore) Complete(key string, statusCode int, body []byte) error {
+	return s.keys.Complete(key, statusCode, string(body))
+}
+
+func (s *idempotencyKeyStore) Release(key string) error {
+	return s.keys.Release(key)
+}
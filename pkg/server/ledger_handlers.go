@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+)
+
+// GetNGOJournalHandler returns the NGO's ledger journal entries
+// @Summary Get NGO journal
+// @Description Get the double-entry journal of the authenticated NGO in posting order
+// @Tags NGO
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/ledger/journal [get]
+func (s *Server) GetNGOJournalHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	journal, err := s.Platform.GetJournal(entityID)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "ngo_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, journal, "Journal retrieved successfully")
+}
+
+// GetNGOTrialBalanceHandler returns the NGO's trial balance
+// @Summary Get NGO trial balance
+// @Description Get the trial balance of the authenticated NGO's ledger
+// @Tags NGO
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/ledger/trial-balance [get]
+func (s *Server) GetNGOTrialBalanceHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	trialBalance, err := s.Platform.GetTrialBalance(entityID)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "ngo_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, trialBalance, "Trial balance retrieved successfully")
+}
+
+// GetNGOBalanceSheetHandler returns the NGO's balance sheet
+// @Summary Get NGO balance sheet
+// @Description Get assets, liabilities and net assets from the authenticated NGO's ledger
+// @Tags NGO
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/ledger/balance-sheet [get]
+func (s *Server) GetNGOBalanceSheetHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	balanceSheet, err := s.Platform.GetBalanceSheet(entityID)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "ngo_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, balanceSheet, "Balance sheet retrieved successfully")
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/entities"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// CreateMandateRequest represents a donor's request to donate on a schedule
+type CreateMandateRequest struct {
+	NGOID         string      `json:"ngo_id" binding:"required"`
+	CampaignID    string      `json:"campaign_id,omitempty"` // Earmarks every installment to the campaign
+	Amount        money.Money `json:"amount"`                // Rupees per installment
+	PaymentMethod string      `json:"payment_method" binding:"required"`
+	Frequency     string      `json:"frequency" binding:"required"` // monthly, quarterly or annual
+	StartDate     *time.Time  `json:"start_date,omitempty"`         // Defaults to now
+	EndDate       *time.Time  `json:"end_date,omitempty"`           // Runs until cancelled when omitted
+}
+
+// CreateMandateHandler sets up a recurring donation for the authenticated donor
+// @Summary Create recurring donation
+// @Description Set up a mandate that donates the same amount to an NGO every month, quarter or year. Installments are charged by the scheduler, count towards the annual donation limit and are retried if they fail.
+// @Tags Donor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body CreateMandateRequest true "Mandate details"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/mandates [post]
+func (s *Server) CreateMandateHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req CreateMandateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var startDate time.Time
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+
+	mandate, err := s.Platform.CreateMandate(entityID, req.NGOID, req.CampaignID, req.Amount, req.PaymentMethod, req.Frequency, startDate, req.EndDate)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "mandate_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, mandate, "Recurring donation created successfully")
+}
+
+// GetMandatesHandler lists the authenticated donor's recurring donations
+// @Summary List recurring donations
+// @Description List the authenticated donor's mandates with their schedule and any failed installments
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/mandates [get]
+func (s *Server) GetMandatesHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	mandates, err := s.Platform.GetMandates(entityID)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "donor_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, mandates, "Recurring donations retrieved successfully")
+}
+
+// PauseMandateHandler pauses one of the donor's recurring donations
+// @Summary Pause recurring donation
+// @Description Stop charging a mandate until it is resumed
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Mandate ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/mandates/{id}/pause [post]
+func (s *Server) PauseMandateHandler(c *gin.Context) {
+	s.updateMandate(c, s.Platform.PauseMandate, "Recurring donation paused successfully")
+}
+
+// ResumeMandateHandler resumes one of the donor's paused recurring donations
+// @Summary Resume recurring donation
+// @Description Resume a paused mandate from its next scheduled installment. Installments scheduled while it was paused are not charged.
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Mandate ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/mandates/{id}/resume [post]
+func (s *Server) ResumeMandateHandler(c *gin.Context) {
+	s.updateMandate(c, s.Platform.ResumeMandate, "Recurring donation resumed successfully")
+}
+
+// CancelMandateHandler cancels one of the donor's recurring donations
+// @Summary Cancel recurring donation
+// @Description Permanently stop a mandate
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Mandate ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/mandates/{id}/cancel [post]
+func (s *Server) CancelMandateHandler(c *gin.Context) {
+	s.updateMandate(c, s.Platform.CancelMandate, "Recurring donation cancelled successfully")
+}
+
+// GetAnnualReceiptsHandler returns the donor's consolidated receipts for a year
+// @Summary Get annual receipts
+// @Description Consolidate the authenticated donor's donations in a calendar year into one receipt per NGO, with the total deductible amount
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Param year query int false "Calendar year (defaults to the current year)"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/receipts/annual [get]
+func (s *Server) GetAnnualReceiptsHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	year := time.Now().Year()
+	if value := c.Query("year"); value != "" {
+		if year, err = strconv.Atoi(value); err != nil {
+			middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid year", nil)
+			return
+		}
+	}
+
+	receipts, err := s.Platform.GetAnnualReceipts(entityID, year)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "donor_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, receipts, "Annual receipts generated successfully")
+}
+
+// updateMandate applies a status change to one of the authenticated donor's mandates
+func (s *Server) updateMandate(c *gin.Context, update func(donorID, mandateID string) (*entities.RecurringMandate, error), message string) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	mandate, err := update(entityID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, platform.ErrDonorNotFound) || errors.Is(err, platform.ErrMandateNotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "mandate_not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "mandate_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, mandate, message)
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/payments"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// CreateDonationRequest represents a donor's request to pay a donation
+type CreateDonationRequest struct {
+	NGOID         string      `json:"ngo_id" binding:"required"`
+	CampaignID    string      `json:"campaign_id,omitempty"` // Earmarks the donation to one of the NGO's campaigns
+	Amount        money.Money `json:"amount"`                // Rupees
+	PaymentMethod string      `json:"payment_method" binding:"required,oneof=upi card netbanking"`
+	VPA           string      `json:"vpa,omitempty"` // UPI address to send a collect request to
+}
+
+// FakeCheckoutRequest represents the donor's action on the fake gateway's checkout page
+type FakeCheckoutRequest struct {
+	Outcome string `json:"outcome" binding:"required,oneof=success failure"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// FakeChargebackRequest represents a dispute raised by the donor's bank on the fake gateway
+type FakeChargebackRequest struct {
+	Amount money.Money `json:"amount"` // Rupees charged back
+	Reason string      `json:"reason,omitempty"`
+}
+
+// CreateDonationHandler creates a payment intent for a donation
+// @Summary Create donation
+// @Description Create a payment intent for a donation. The donor completes payment at the checkout URL or approves the UPI collect request; the donation is recorded once the gateway confirms payment. A campaign_id earmarks the donation to one of the NGO's active campaigns. Send an Idempotency-Key header to make retries safe.
+// @Tags Donor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Client key identifying this donation across retries"
+// @Param request body CreateDonationRequest true "Donation details"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 422 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/donations [post]
+func (s *Server) CreateDonationHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req CreateDonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var intent *platform.PaymentIntent
+	if key := c.GetHeader(middleware.IdempotencyKeyHeader); key != "" {
+		intent, err = s.Platform.CreatePaymentIntentIdempotent(key, entityID, req.NGOID, req.CampaignID, req.Amount, req.PaymentMethod, req.VPA)
+	} else {
+		intent, err = s.Platform.CreatePaymentIntent(entityID, req.NGOID, req.CampaignID, req.Amount, req.PaymentMethod, req.VPA)
+	}
+	if err != nil {
+		respondIdempotentError(c, err, "donation_failed")
+		return
+	}
+
+	middleware.StandardResponse(c, intent, "Payment intent created successfully")
+}
+
+// GetPaymentIntentHandler returns the status of one of the donor's payment intents
+// @Summary Get payment intent
+// @Description Get the status of a donation payment intent of the authenticated donor
+// @Tags Donor
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Payment intent ID"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/donors/payments/{id} [get]
+func (s *Server) GetPaymentIntentHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	intent, err := s.Platform.GetPaymentIntent(c.Param("id"))
+	if err != nil || intent.DonorID != entityID {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "payment_intent_not_found", "Payment intent not found", nil)
+		return
+	}
+
+	middleware.StandardResponse(c, intent, "Payment intent retrieved successfully")
+}
+
+// PaymentWebhookHandler receives payment outcomes from the gateway
+// @Summary Payment gateway webhook
+// @Description Receive a signed payment.captured, payment.failed or payment.chargeback event from the payment gateway
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Signature header string true "t=<unix>,v1=<hex HMAC-SHA256>"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /api/v1/payments/webhook [post]
+func (s *Server) PaymentWebhookHandler(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "invalid_payload", "Failed to read webhook payload", nil)
+		return
+	}
+
+	intent, err := s.Platform.HandlePaymentWebhook(payload, c.GetHeader(payments.SignatureHeader))
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	middleware.StandardResponse(c, intent, "Webhook processed successfully")
+}
+
+// FakeCheckoutHandler completes or fails a payment on the fake gateway
+// @Summary Fake gateway checkout
+// @Description Simulate the donor paying or abandoning a fake gateway order. The resulting signed webhook is processed immediately. Only available with PAYMENT_GATEWAY=fake in development.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param order_id path string true "Gateway order ID"
+// @Param request body FakeCheckoutRequest true "Checkout outcome"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/payments/fake/checkout/{order_id} [post]
+func (s *Server) FakeCheckoutHandler(c *gin.Context) {
+	gateway, ok := s.Platform.PaymentGateway.(*payments.FakeGateway)
+	if !ok {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "not_found", "Fake gateway not enabled", nil)
+		return
+	}
+
+	var req FakeCheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var payload []byte
+	var signature string
+	var err error
+	if req.Outcome == "success" {
+		payload, signature, err = gateway.Capture(c.Param("order_id"))
+	} else {
+		payload, signature, err = gateway.Fail(c.Param("order_id"), req.Reason)
+	}
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "order_not_found", err.Error(), nil)
+		return
+	}
+
+	intent, err := s.Platform.HandlePaymentWebhook(payload, signature)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	middleware.StandardResponse(c, intent, "Checkout completed")
+}
+
+// FakeChargebackHandler charges back a captured payment on the fake gateway
+// @Summary Fake gateway chargeback
+// @Description Simulate the donor's bank charging back a captured fake gateway payment. The resulting signed payment.chargeback webhook is processed immediately. Only available with PAYMENT_GATEWAY=fake in development.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param order_id path string true "Gateway order ID"
+// @Param request body FakeChargebackRequest true "Chargeback details"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/payments/fake/chargeback/{order_id} [post]
+func (s *Server) FakeChargebackHandler(c *gin.Context) {
+	gateway, ok := s.Platform.PaymentGateway.(*payments.FakeGateway)
+	if !ok {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "not_found", "Fake gateway not enabled", nil)
+		return
+	}
+
+	var req FakeChargebackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	payload, signature, err := gateway.Chargeback(c.Param("order_id"), req.Amount, req.Reason)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "chargeback_failed", err.Error(), nil)
+		return
+	}
+
+	intent, err := s.Platform.HandlePaymentWebhook(payload, signature)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	middleware.StandardResponse(c, intent, "Chargeback processed")
+}
+
+// respondWebhookError maps webhook processing errors to status codes; gateways
+// retry deliveries that are not acknowledged with a 2xx
+func respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case platform.IsInvalidWebhook(err):
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "invalid_signature", err.Error(), nil)
+	case errors.Is(err, platform.ErrPaymentIntentNotFound):
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "payment_intent_not_found", err.Error(), nil)
+	default:
+		middleware.ErrorResponseWithDetails(c, http.StatusInternalServerError, "webhook_processing_failed", err.Error(), nil)
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/reconciliation"
+)
+
+// ReconcileStatementRequest represents a bank statement upload
+type ReconcileStatementRequest struct {
+	Format     string `json:"format,omitempty" binding:"omitempty,oneof=csv mt940 camt053"` // Detected from content when empty
+	Content    string `json:"content" binding:"required"`                                   // Raw statement file contents
+	WindowDays int    `json:"window_days,omitempty" binding:"omitempty,min=0,max=31"`       // Date window for amount matches; default 3
+}
+
+// ReconcileStatementHandler reconciles a bank statement against the NGO's chains
+// @Summary Reconcile bank statement
+// @Description Import a CSV, MT940 or camt.053 bank statement and match its lines to the authenticated NGO's donations and expenditures by reference, amount and date
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body ReconcileStatementRequest true "Bank statement"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/reconciliation [post]
+func (s *Server) ReconcileStatementHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req ReconcileStatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	statement, err := reconciliation.Parse(req.Format, []byte(req.Content))
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "invalid_statement", "Failed to parse bank statement", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	report, err := s.Platform.ReconcileStatement(entityID, statement, time.Duration(req.WindowDays)*24*time.Hour)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "ngo_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, gin.H{
+		"statement": gin.H{
+			"format":          statement.Format,
+			"account_id":      statement.AccountID,
+			"currency":        statement.Currency,
+			"opening_balance": statement.OpeningBalance,
+			"closing_balance": statement.ClosingBalance,
+			"lines":           len(statement.Lines),
+		},
+		"report": report,
+	}, "Statement reconciled successfully")
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/money"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// RefundDonationRequest represents an NGO's refund of a donation
+type RefundDonationRequest struct {
+	Amount money.Money `json:"amount,omitempty"` // Rupees returned to the donor; the full remaining amount when omitted
+	Reason string      `json:"reason" binding:"required"`
+}
+
+// RefundDonationHandler refunds all or part of a donation to the donor
+// @Summary Refund donation
+// @Description Refund all or part of a donation received by the authenticated NGO. Gateway payments are refunded through the gateway. A reversal block referencing the donation block is appended, and the donor's e-bill is voided or reissued for the remaining amount.
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Donation transaction ID"
+// @Param request body RefundDonationRequest true "Refund details"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/donations/{id}/refund [post]
+func (s *Server) RefundDonationHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req RefundDonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if req.Amount.IsNegative() {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Refund amount must be positive", nil)
+		return
+	}
+
+	reversal, err := s.Platform.RefundDonation(entityID, c.Param("id"), req.Amount, req.Reason)
+	if err != nil {
+		if errors.Is(err, platform.ErrNGONotFound) || errors.Is(err, platform.ErrDonationNotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "donation_not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "refund_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, reversal, "Donation refunded successfully")
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/platform"
+)
+
+// ReviewExpenditureRequest represents an auditor's decision on a submitted expenditure
+type ReviewExpenditureRequest struct {
+	Decision string   `json:"decision" binding:"required,oneof=approve reject request_info"`
+	Notes    string   `json:"notes"`                                             // Required to reject or request information
+	Score    *float64 `json:"score,omitempty" binding:"omitempty,min=0,max=100"` // Auditor's compliance score, the automated score when omitted
+}
+
+// ProvideExpenditureInformationRequest represents an NGO's reply to an auditor's request for information
+type ProvideExpenditureInformationRequest struct {
+	Notes             string   `json:"notes" binding:"required"`
+	BankTransactionID string   `json:"bank_transaction_id,omitempty"`
+	ChequeNumber      string   `json:"cheque_number,omitempty"`
+	Documents         []string `json:"documents,omitempty"` // Replaces the invoice's supporting documents
+}
+
+// GetPendingExpendituresHandler lists the expenditures awaiting the auditor's review
+// @Summary List pending expenditures
+// @Description List the expenditures assigned to the authenticated auditor that are under review, oldest first, with the automated compliance checks, suggested recommendation, any duplicate-invoice flags and the anomaly_alerts concerning each with their highest risk_score. Expenditures waiting on the NGO have status information_requested.
+// @Tags Auditor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auditors/pending-expenditures [get]
+func (s *Server) GetPendingExpendituresHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	middleware.StandardResponse(c, s.Platform.GetPendingExpenditures(entityID), "Pending expenditures retrieved successfully")
+}
+
+// AuditExpenditureHandler records the auditor's decision on an expenditure
+// @Summary Review expenditure
+// @Description Approve, reject or request information on an expenditure assigned to the authenticated auditor, optionally with the auditor's own compliance score. High-value expenditures are reviewed by a panel and decided once the required number of its auditors approve, or once so many reject that approval is out of reach; until then each decision is recorded as a vote and the consensus tally returned. Approval mines the expenditure into the NGO's expenditure blockchain with the signature of every auditor who voted and posts it to the ledger; it fails if the funds or invoice it relies on were used by another expenditure since submission. Rejection and requests for information require notes; a request for information returns the expenditure to the NGO.
+// @Tags Auditor
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param expenditure_id path string true "Expenditure transaction ID"
+// @Param request body ReviewExpenditureRequest true "Decision and notes"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/auditors/audit/{expenditure_id} [post]
+func (s *Server) AuditExpenditureHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req ReviewExpenditureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := s.Platform.ReviewExpenditure(entityID, c.Param("expenditure_id"), req.Decision, req.Notes, req.Score)
+	if err != nil {
+		switch {
+		case errors.Is(err, platform.ErrExpenditureNotFound) || errors.Is(err, platform.ErrAuditorNotFound):
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "expenditure_not_found", err.Error(), nil)
+		case errors.Is(err, platform.ErrNotAssigned):
+			middleware.ErrorResponseWithDetails(c, http.StatusForbidden, "expenditure_not_assigned", err.Error(), nil)
+		default:
+			middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "review_failed", err.Error(), nil)
+		}
+		return
+	}
+
+	middleware.StandardResponse(c, result, "Expenditure reviewed successfully")
+}
+
+// GetEscalatedExpendituresHandler lists the expenditures whose review panel disagrees
+// @Summary List escalated expenditures
+// @Description List the expenditures under review whose panel of auditors is split between approval and rejection, or whose compliance scores differ by more than the allowed spread, oldest first, with the consensus tally.
+// @Tags Auditor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auditors/escalations [get]
+func (s *Server) GetEscalatedExpendituresHandler(c *gin.Context) {
+	middleware.StandardResponse(c, s.Platform.GetEscalatedExpenditures(), "Escalated expenditures retrieved successfully")
+}
+
+// GetNGOPendingExpendituresHandler lists the NGO's expenditures under review
+// @Summary List expenditures under review
+// @Description List the authenticated NGO's expenditures not yet approved or rejected, oldest first, with every review decision and note. Those with status information_requested await the NGO's reply; those with status under_appeal are being re-reviewed after a rejection.
+// @Tags NGO
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/expenditures/pending [get]
+func (s *Server) GetNGOPendingExpendituresHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	middleware.StandardResponse(c, s.Platform.GetNGOPendingExpenditures(entityID), "Expenditures under review retrieved successfully")
+}
+
+// ProvideExpenditureInformationHandler answers an auditor's request for information
+// @Summary Reply to request for information
+// @Description Answer the auditor's questions on an expenditure of the authenticated NGO, optionally adding payment proof or supporting documents. The expenditure is rescored and returned to the auditor's queue.
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Expenditure transaction ID"
+// @Param request body ProvideExpenditureInformationRequest true "Reply"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/expenditures/{id}/information [post]
+func (s *Server) ProvideExpenditureInformationHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req ProvideExpenditureInformationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := s.Platform.ProvideExpenditureInformation(entityID, c.Param("id"), platform.ExpenditureInformation{
+		Notes:             req.Notes,
+		BankTransactionID: req.BankTransactionID,
+		ChequeNumber:      req.ChequeNumber,
+		Documents:         req.Documents,
+	})
+	if err != nil {
+		if errors.Is(err, platform.ErrExpenditureNotFound) {
+			middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "expenditure_not_found", err.Error(), nil)
+			return
+		}
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "information_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, result, "Information provided successfully")
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"ngo-transparency-platform/pkg/middleware"
+)
+
+// startMandateScheduler charges due recurring donations every interval until
+// the server shuts down
+func (s *Server) startMandateScheduler(interval time.Duration) {
+	s.stopScheduler = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				s.runDueMandates(now)
+			case <-s.stopScheduler:
+				return
+			}
+		}
+	}()
+}
+
+// stopMandateScheduler stops the scheduler if it was started
+func (s *Server) stopMandateScheduler() {
+	if s.stopScheduler != nil {
+		close(s.stopScheduler)
+		s.stopScheduler = nil
+	}
+}
+
+// startAnchorRetries retries the blocks pending a Polygon anchor every
+// interval until the server shuts down
+func (s *Server) startAnchorRetries(interval time.Duration) {
+	s.stopAnchorRetry = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				s.retryPendingAnchors(now)
+			case <-s.stopAnchorRetry:
+				return
+			}
+		}
+	}()
+}
+
+// stopAnchorRetries stops retrying pending anchors if it was started
+func (s *Server) stopAnchorRetries() {
+	if s.stopAnchorRetry != nil {
+		close(s.stopAnchorRetry)
+		s.stopAnchorRetry = nil
+	}
+}
+
+func (s *Server) runDueMandates(now time.Time) {
+	for _, run := range s.Platform.RunDueMandates(now) {
+		fields := logrus.Fields{
+			"mandate_id":      run.MandateID,
+			"donor_id":        run.DonorID,
+			"installment":     run.Installment,
+			"status":          run.Status,
+			"next_attempt_at": run.NextAttemptAt,
+		}
+		if run.Success {
+			fields["transaction_id"] = run.TransactionID
+			middleware.Logger.WithFields(fields).Info("Recurring donation charged")
+		} else {
+			fields["error"] = run.Error
+			middleware.Logger.WithFields(fields).Warn("Recurring donation failed")
+		}
+	}
+}
+
+func (s *Server) retryPendingAnchors(now time.Time) {
+	for _, retry := range s.Platform.RetryPendingAnchors(now) {
+		fields := logrus.Fields{
+			"block_hash": retry.BlockHash,
+			"ngo_id":     retry.NGOID,
+		}
+		if retry.Success {
+			fields["polygon_tx_hash"] = retry.PolygonTxHash
+			middleware.Logger.WithFields(fields).Info("Pending block anchored to Polygon")
+		} else {
+			fields["error"] = retry.Error
+			middleware.Logger.WithFields(fields).Warn("Pending block still not anchored to Polygon")
+		}
+	}
+}
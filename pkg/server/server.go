@@ -12,17 +12,32 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/compliance"
 	"ngo-transparency-platform/pkg/config"
+	"ngo-transparency-platform/pkg/consensus"
 	"ngo-transparency-platform/pkg/database"
+	"ngo-transparency-platform/pkg/einvoice"
 	"ngo-transparency-platform/pkg/middleware"
+	"ngo-transparency-platform/pkg/payments"
 	"ngo-transparency-platform/pkg/platform"
+	"ngo-transparency-platform/pkg/polygon"
+	"ngo-transparency-platform/pkg/rating"
+	"ngo-transparency-platform/pkg/storage"
 )
 
+// placeholderWebhookSecret is the example webhook secret published in the
+// docs, which a payment gateway refuses to start with
+const placeholderWebhookSecret = "change-this-webhook-secret"
+
 // Server represents the HTTP server
 type Server struct {
 	Config   *config.Config
 	Router   *gin.Engine
 	Platform *platform.NGOTransparencyPlatform
+
+	idempotency     gin.HandlerFunc // Replays retried Idempotency-Key requests
+	stopScheduler   chan struct{}   // Closed to stop the recurring donation scheduler
+	stopAnchorRetry chan struct{}   // Closed to stop retrying pending Polygon anchors
 }
 
 // NewServer creates a new server instance
@@ -41,21 +56,126 @@ func (s *Server) InitializePlatform() error {
 	// Initialize the transparency platform
 	s.Platform = platform.NewNGOTransparencyPlatform()
 
+	// Load platform state from the database and write through to it from now on
+	if database.DB != nil {
+		if err := s.Platform.AttachRepositories(database.NewRepositories(database.DB)); err != nil {
+			return fmt.Errorf("failed to load platform state: %w", err)
+		}
+	}
+
 	// Initialize Polygon integration if configured
 	if s.Config.Blockchain.PolygonRPC != "" {
 		gasPrice := big.NewInt(s.Config.Blockchain.GasPriceGwei)
 		gasPrice.Mul(gasPrice, big.NewInt(1e9)) // Convert Gwei to Wei
 
-		s.Platform.InitializePolygon(
+		err := s.Platform.InitializePolygon(
 			s.Config.Blockchain.PolygonRPC,
 			s.Config.Blockchain.PrivateKey,
 			s.Config.Blockchain.GasLimit,
 			gasPrice,
 		)
+		if err != nil {
+			return err
+		}
+
+		policy := polygon.DefaultGasPolicy()
+		policy.MinPriorityFee = polygon.GweiToWei(s.Config.Blockchain.MinPriorityFeeGwei)
+		policy.MaticINRRate = s.Config.Blockchain.MaticINRRate
+		if s.Config.Blockchain.MaxFeeGwei > 0 {
+			policy.MaxFeeCap = polygon.GweiToWei(s.Config.Blockchain.MaxFeeGwei)
+		}
+		if s.Config.Blockchain.DailySpendCapMATIC > 0 {
+			policy.DailySpendCap = polygon.MATICToWei(s.Config.Blockchain.DailySpendCapMATIC)
+		}
+		s.Platform.PolygonIntegration.SetGasStrategy(
+			polygon.NewGasStrategy(s.Config.Blockchain.PolygonRPC, gasPrice, policy),
+		)
 
 		log.Println("Polygon integration initialized")
 	}
 
+	// Failed recurring donation installments are retried before being skipped
+	s.Platform.MandateRetryPolicy = platform.MandateRetryPolicy{
+		MaxRetries: s.Config.Recurring.MaxRetries,
+		RetryDelay: time.Duration(s.Config.Recurring.RetryDelayHours) * time.Hour,
+	}
+
+	// Expenditures are scored under the configured compliance rules
+	if s.Config.Compliance.RulesFile != "" {
+		rules, err := compliance.Load(s.Config.Compliance.RulesFile)
+		if err != nil {
+			return err
+		}
+		s.Platform.ComplianceRules = rules
+		log.Printf("Compliance rules version %s loaded", rules.Version)
+	}
+
+	// NGOs are rated under the configured model, weighting recent activity most
+	ratingModel, err := rating.NewModel(s.Config.Rating.Model, time.Duration(s.Config.Rating.HalfLifeDays*24*float64(time.Hour)))
+	if err != nil {
+		return fmt.Errorf("failed to load rating model: %w", err)
+	}
+	s.Platform.RatingModel = ratingModel
+	s.Platform.RatingPeers = rating.PeerPolicy{
+		Weight:   s.Config.Rating.PeerWeight,
+		MinPeers: s.Config.Rating.MinPeers,
+	}
+
+	// High-value expenditures are decided by a panel of auditors
+	thresholds, err := consensus.ParseThresholds(s.Config.Consensus.Thresholds)
+	if err != nil {
+		return fmt.Errorf("failed to load consensus thresholds: %w", err)
+	}
+	s.Platform.ConsensusPolicy = consensus.Policy{
+		Thresholds:     thresholds,
+		MaxScoreSpread: s.Config.Consensus.MaxScoreSpread,
+	}
+
+	// E-invoices are verified against the IRP's public key
+	if s.Config.EInvoice.IRPPublicKeyFile != "" {
+		verifier, err := einvoice.LoadVerifier(s.Config.EInvoice.IRPPublicKeyFile)
+		if err != nil {
+			return err
+		}
+		s.Platform.InitializeEInvoices(verifier)
+		log.Println("E-invoice verification initialized")
+	}
+
+	// Uploaded documents are stored by the SHA-256 of their contents
+	store, err := storage.NewLocalStore(s.Config.Storage.Path)
+	if err != nil {
+		return fmt.Errorf("failed to initialize document storage: %w", err)
+	}
+	policy := storage.DefaultPolicy()
+	switch {
+	case s.Config.Storage.MaxUploadMB < 0:
+		return fmt.Errorf("STORAGE_MAX_UPLOAD_MB cannot be negative")
+	case s.Config.Storage.MaxUploadMB > 0:
+		policy.MaxSize = int64(s.Config.Storage.MaxUploadMB) << 20
+	}
+	if types := storage.ParseTypes(s.Config.Storage.AllowedTypes); len(types) > 0 {
+		policy.AllowedTypes = types
+	}
+	s.Platform.DocumentPolicy = policy
+	s.Platform.InitializeDocuments(store)
+	log.Printf("Document storage initialized at %s", s.Config.Storage.Path)
+
+	// Initialize the payment gateway donations are collected through
+	if s.Config.Payments.Gateway != "" && (s.Config.Payments.WebhookSecret == "" || s.Config.Payments.WebhookSecret == placeholderWebhookSecret) {
+		return fmt.Errorf("PAYMENT_WEBHOOK_SECRET must be set to a secret of your own to use a payment gateway")
+	}
+	switch s.Config.Payments.Gateway {
+	case "": // Payment intents disabled
+	case payments.FakeGatewayName:
+		if s.Config.Platform.Environment != "development" {
+			return fmt.Errorf("the fake payment gateway can only be used in development")
+		}
+		s.Platform.InitializePayments(payments.NewFakeGateway(s.Config.Payments.WebhookSecret, s.Config.Payments.CheckoutBaseURL))
+		log.Println("Fake payment gateway initialized")
+	default:
+		return fmt.Errorf("unsupported payment gateway: %s", s.Config.Payments.Gateway)
+	}
+
 	return nil
 }
 
@@ -82,13 +202,12 @@ func (s *Server) SetupMiddleware() {
 	
 	// Health check
 	s.Router.Use(middleware.HealthCheck())
-	
-	// Content-Type validation for non-GET requests
-	s.Router.Use(middleware.JSONContentType())
 }
 
 // SetupRoutes configures all API routes
 func (s *Server) SetupRoutes() {
+	s.idempotency = middleware.Idempotency(newIdempotencyStore(), time.Duration(s.Config.Server.IdempotencyKeyTTLHours)*time.Hour)
+
 	// Root route
 	s.Router.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -102,15 +221,19 @@ func (s *Server) SetupRoutes() {
 	// API v1 routes
 	v1 := s.Router.Group("/api/v1")
 	{
+		// Content-Type validation for non-GET requests
+		api := v1.Group("", middleware.JSONContentType())
+
 		// Public routes (no authentication required)
-		public := v1.Group("")
+		public := api.Group("")
 		{
 			s.setupAuthRoutes(public)
 			s.setupPublicRoutes(public)
+			s.setupPaymentRoutes(public)
 		}
 
 		// Protected routes (authentication required)
-		protected := v1.Group("")
+		protected := api.Group("")
 		protected.Use(auth.AuthMiddleware())
 		{
 			s.setupNGORoutes(protected)
@@ -119,6 +242,13 @@ func (s *Server) SetupRoutes() {
 			s.setupTransactionRoutes(protected)
 			s.setupBlockchainRoutes(protected)
 		}
+
+		// Documents are uploaded as multipart forms, so they skip the JSON check
+		uploads := v1.Group("")
+		uploads.Use(auth.AuthMiddleware())
+		{
+			s.setupDocumentRoutes(uploads)
+		}
 	}
 
 	// Swagger documentation
@@ -145,17 +275,37 @@ func (s *Server) setupPublicRoutes(router *gin.RouterGroup) {
 	router.GET("/ngos", s.GetPublicNGOsHandler)
 	router.GET("/ngos/:id", s.GetPublicNGOHandler)
 	router.GET("/ngos/:id/rating", s.GetNGORatingHandler)
+	router.GET("/ngos/:id/rating/history", s.GetNGORatingHistoryHandler)
+	router.GET("/ngos/:id/campaigns", s.GetNGOCampaignsHandler)
+	router.GET("/campaigns/:id", s.GetCampaignHandler)
+	router.GET("/vendors/:gstin", s.GetVendorHandler)
+	router.GET("/compliance/rules", s.GetComplianceRulesHandler)
 	
 	// Platform statistics
 	router.GET("/stats", s.GetPlatformStatsHandler)
 	
 	// Blockchain verification
 	router.GET("/verify/:hash", s.VerifyBlockchainDataHandler)
+	router.GET("/donations/:id/trace", s.TraceDonationHandler)
 	
 	// Health and status
 	router.GET("/status", s.GetSystemStatusHandler)
 }
 
+// setupPaymentRoutes sets up payment gateway callbacks, which are
+// authenticated by their signature rather than a JWT
+func (s *Server) setupPaymentRoutes(router *gin.RouterGroup) {
+	paymentGroup := router.Group("/payments")
+	{
+		paymentGroup.POST("/webhook", s.PaymentWebhookHandler)
+
+		if _, ok := s.Platform.PaymentGateway.(*payments.FakeGateway); ok {
+			paymentGroup.POST("/fake/checkout/:order_id", s.FakeCheckoutHandler)
+			paymentGroup.POST("/fake/chargeback/:order_id", s.FakeChargebackHandler)
+		}
+	}
+}
+
 // setupNGORoutes sets up NGO-specific routes
 func (s *Server) setupNGORoutes(router *gin.RouterGroup) {
 	ngoGroup := router.Group("/ngos")
@@ -165,14 +315,28 @@ func (s *Server) setupNGORoutes(router *gin.RouterGroup) {
 		ngoGroup.PUT("/profile", s.UpdateNGOProfileHandler)
 		ngoGroup.GET("/dashboard", s.GetNGODashboardHandler)
 		ngoGroup.GET("/donations", s.GetNGODonationsHandler)
+		ngoGroup.POST("/donations/:id/refund", s.RefundDonationHandler)
 		ngoGroup.GET("/expenditures", s.GetNGOExpendituresHandler)
-		ngoGroup.POST("/expenditures", s.CreateExpenditureHandler)
+		ngoGroup.POST("/expenditures", s.idempotency, s.CreateExpenditureHandler)
+		ngoGroup.GET("/expenditures/pending", s.GetNGOPendingExpendituresHandler)
+		ngoGroup.GET("/expenditures/rejected", s.GetNGORejectedExpendituresHandler)
 		ngoGroup.GET("/expenditures/:id", s.GetExpenditureHandler)
+		ngoGroup.POST("/expenditures/:id/information", s.ProvideExpenditureInformationHandler)
+		ngoGroup.POST("/expenditures/:id/appeal", s.AppealExpenditureHandler)
 		ngoGroup.PUT("/expenditures/:id", s.UpdateExpenditureHandler)
+		ngoGroup.GET("/expenditures/:id/history", s.GetNGOExpenditureHistoryHandler)
 		ngoGroup.GET("/blockchain/donations", s.GetNGODonationBlocksHandler)
 		ngoGroup.GET("/blockchain/expenditures", s.GetNGOExpenditureBlocksHandler)
 		ngoGroup.POST("/kyc/submit", s.SubmitNGOKYCHandler)
 		ngoGroup.GET("/financial-summary", s.GetNGOFinancialSummaryHandler)
+		ngoGroup.GET("/ledger/journal", s.GetNGOJournalHandler)
+		ngoGroup.GET("/ledger/trial-balance", s.GetNGOTrialBalanceHandler)
+		ngoGroup.GET("/ledger/balance-sheet", s.GetNGOBalanceSheetHandler)
+		ngoGroup.POST("/reconciliation", s.ReconcileStatementHandler)
+		ngoGroup.POST("/campaigns", s.CreateCampaignHandler)
+		ngoGroup.POST("/vendors", s.RegisterVendorHandler)
+		ngoGroup.POST("/einvoices/import", s.ImportEInvoiceHandler)
+		ngoGroup.POST("/campaigns/:id/close", s.CloseCampaignHandler)
 	}
 }
 
@@ -185,8 +349,21 @@ func (s *Server) setupDonorRoutes(router *gin.RouterGroup) {
 		donorGroup.PUT("/profile", s.UpdateDonorProfileHandler)
 		donorGroup.GET("/dashboard", s.GetDonorDashboardHandler)
 		donorGroup.GET("/donations", s.GetDonorDonationsHandler)
-		donorGroup.POST("/donations", s.CreateDonationHandler)
+		donorGroup.POST("/donations", s.idempotency, s.CreateDonationHandler)
 		donorGroup.GET("/donations/:id", s.GetDonationHandler)
+		donorGroup.GET("/payments/:id", s.GetPaymentIntentHandler)
+		donorGroup.POST("/mandates", s.CreateMandateHandler)
+		donorGroup.GET("/mandates", s.GetMandatesHandler)
+		donorGroup.POST("/mandates/:id/pause", s.PauseMandateHandler)
+		donorGroup.POST("/mandates/:id/resume", s.ResumeMandateHandler)
+		donorGroup.POST("/mandates/:id/cancel", s.CancelMandateHandler)
+		donorGroup.GET("/receipts/annual", s.GetAnnualReceiptsHandler)
+		donorGroup.POST("/corporate", s.RegisterCorporateHandler)
+		donorGroup.GET("/corporate", s.GetCorporateProfileHandler)
+		donorGroup.PUT("/corporate/matching-rule", s.UpdateMatchingRuleHandler)
+		donorGroup.POST("/corporate/employees", s.AddEmployeeHandler)
+		donorGroup.DELETE("/corporate/employees/:donor_id", s.RemoveEmployeeHandler)
+		donorGroup.GET("/corporate/csr-report", s.GetCSRReportHandler)
 		donorGroup.GET("/tax-benefits", s.GetTaxBenefitsHandler)
 		donorGroup.GET("/preferred-ngos", s.GetPreferredNGOsHandler)
 		donorGroup.POST("/preferred-ngos/:ngo_id", s.AddPreferredNGOHandler)
@@ -206,7 +383,13 @@ func (s *Server) setupAuditorRoutes(router *gin.RouterGroup) {
 		auditorGroup.GET("/dashboard", s.GetAuditorDashboardHandler)
 		auditorGroup.GET("/audits", s.GetAuditorAuditsHandler)
 		auditorGroup.GET("/pending-expenditures", s.GetPendingExpendituresHandler)
+		auditorGroup.GET("/flagged-invoices", s.GetFlaggedExpendituresHandler)
 		auditorGroup.POST("/audit/:expenditure_id", s.AuditExpenditureHandler)
+		auditorGroup.GET("/assignments", s.GetAssignmentsHandler)
+		auditorGroup.GET("/escalations", s.GetEscalatedExpendituresHandler)
+		auditorGroup.GET("/anomalies", s.GetAnomalyReportHandler)
+		auditorGroup.GET("/anomalies/:ngo_id", s.GetNGOAnomaliesHandler)
+		auditorGroup.POST("/conflicts", s.DeclareConflictHandler)
 		auditorGroup.GET("/audits/:id", s.GetAuditHandler)
 		auditorGroup.POST("/kyc/submit", s.SubmitAuditorKYCHandler)
 	}
@@ -220,6 +403,7 @@ func (s *Server) setupTransactionRoutes(router *gin.RouterGroup) {
 		txGroup.GET("/expenditures/:id", s.GetExpenditureTransactionHandler)
 		txGroup.GET("/donations/:id/receipt", s.GetDonationReceiptHandler)
 		txGroup.GET("/expenditures/:id/compliance", s.GetComplianceReportHandler)
+		txGroup.GET("/expenditures/:id/history", s.GetExpenditureHistoryHandler)
 	}
 }
 
@@ -235,6 +419,15 @@ func (s *Server) setupBlockchainRoutes(router *gin.RouterGroup) {
 	}
 }
 
+// setupDocumentRoutes sets up document upload and download routes
+func (s *Server) setupDocumentRoutes(router *gin.RouterGroup) {
+	documentGroup := router.Group("/documents")
+	{
+		documentGroup.POST("", s.UploadDocumentHandler)
+		documentGroup.GET("/:hash", s.GetDocumentHandler)
+	}
+}
+
 // Start starts the HTTP server
 func (s *Server) Start() error {
 	address := fmt.Sprintf("%s:%s", s.Config.Server.Host, s.Config.Server.Port)
@@ -251,6 +444,9 @@ func (s *Server) Start() error {
 // Graceful shutdown
 func (s *Server) Shutdown() error {
 	middleware.Logger.Info("Shutting down server...")
+
+	s.stopMandateScheduler()
+	s.stopAnchorRetries()
 	
 	// Close database connections
 	if err := database.CloseDatabase(); err != nil {
@@ -285,6 +481,16 @@ func (s *Server) Initialize() error {
 	// Setup routes
 	s.SetupRoutes()
 
+	// Charge recurring donations in the background
+	if interval := s.Config.Recurring.SchedulerIntervalMinutes; interval > 0 {
+		s.startMandateScheduler(time.Duration(interval) * time.Minute)
+	}
+
+	// Anchor blocks that could not be anchored when committed
+	if interval := s.Config.Blockchain.AnchorRetryMinutes; interval > 0 && s.Platform.PolygonIntegration != nil {
+		s.startAnchorRetries(time.Duration(interval) * time.Minute)
+	}
+
 	middleware.Logger.Info("Server initialized successfully")
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ngo-transparency-platform/pkg/auth"
+	"ngo-transparency-platform/pkg/middleware"
+)
+
+// RegisterVendorRequest represents an NGO's request to add a supplier to the vendor registry
+type RegisterVendorRequest struct {
+	GSTIN     string `json:"gstin" binding:"required"`
+	LegalName string `json:"legal_name" binding:"required"`
+}
+
+// RegisterVendorHandler adds a supplier to the platform-wide vendor registry
+// @Summary Register vendor
+// @Description Add a GST-registered supplier to the vendor registry shared by all NGOs. The GSTIN's state code and check character are validated. Vendors are also registered automatically the first time an expenditure pays one of their invoices.
+// @Tags NGO
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body RegisterVendorRequest true "Vendor details"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/ngos/vendors [post]
+func (s *Server) RegisterVendorHandler(c *gin.Context) {
+	_, _, entityID, err := auth.GetUserFromContext(c)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusUnauthorized, "unauthorized", "Unauthorized access", nil)
+		return
+	}
+
+	var req RegisterVendorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "validation_error", "Invalid request data", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	vendor, err := s.Platform.RegisterVendor(req.GSTIN, req.LegalName, entityID)
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusBadRequest, "vendor_registration_failed", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, vendor, "Vendor registered successfully")
+}
+
+// GetVendorHandler returns a registered vendor
+// @Summary Get vendor
+// @Description Get a vendor from the registry by GSTIN, with the state and PAN encoded in it
+// @Tags Public
+// @Produce json
+// @Param gstin path string true "Vendor GSTIN"
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/vendors/{gstin} [get]
+func (s *Server) GetVendorHandler(c *gin.Context) {
+	vendor, err := s.Platform.GetVendor(c.Param("gstin"))
+	if err != nil {
+		middleware.ErrorResponseWithDetails(c, http.StatusNotFound, "vendor_not_found", err.Error(), nil)
+		return
+	}
+
+	middleware.StandardResponse(c, vendor, "Vendor retrieved successfully")
+}
+
+// GetFlaggedExpendituresHandler lists expenditures whose invoices may be duplicates
+// @Summary List flagged invoices
+// @Description List recorded expenditures, across all NGOs, whose invoice matches an earlier invoice's vendor, amount and date under a different invoice number
+// @Tags Auditor
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} middleware.SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auditors/flagged-invoices [get]
+func (s *Server) GetFlaggedExpendituresHandler(c *gin.Context) {
+	middleware.StandardResponse(c, s.Platform.GetFlaggedExpenditures(), "Flagged invoices retrieved successfully")
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore keeps files on the local filesystem, each under a directory
+// named by the first two characters of its hash
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore returns a store rooted at root, creating the directory if needed
+func NewLocalStore(root string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create document store: %w", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+// Name identifies the store in logs
+func (s *LocalStore) Name() string {
+	return "local:" + s.root
+}
+
+// Put writes data to a temporary file and renames it into place, so a file
+// under its hash is always complete
+func (s *LocalStore) Put(hash string, data []byte, mimeType string) error {
+	if !ValidHash(hash) {
+		return fmt.Errorf("invalid document hash %q", hash)
+	}
+	if err := verify(hash, data); err != nil {
+		return err
+	}
+	if exists, err := s.Exists(hash); err != nil || exists {
+		return err
+	}
+
+	path := s.path(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to store document: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to store document: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to store document: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to store document: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store document: %w", err)
+	}
+	return nil
+}
+
+// Get reads the file stored under hash and checks its contents
+func (s *LocalStore) Get(hash string) ([]byte, error) {
+	if !ValidHash(hash) {
+		return nil, ErrNotFound
+	}
+	data, err := os.ReadFile(s.path(hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if err := verify(hash, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Exists reports whether a file is stored under hash
+func (s *LocalStore) Exists(hash string) (bool, error) {
+	if !ValidHash(hash) {
+		return false, nil
+	}
+	_, err := os.Stat(s.path(hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check document: %w", err)
+	}
+	return true, nil
+}
+
+func (s *LocalStore) path(hash string) string {
+	return filepath.Join(s.root, hash[:2], hash)
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"path"
+)
+
+// S3Client is the part of an S3-compatible object storage client the store
+// uses, e.g. an adapter over the AWS SDK or a MinIO client. GetObject returns
+// ErrNotFound for a missing key.
+type S3Client interface {
+	PutObject(bucket, key string, data []byte, contentType string) error
+	GetObject(bucket, key string) ([]byte, error)
+	ObjectExists(bucket, key string) (bool, error)
+}
+
+// S3Store keeps files in a bucket of an S3-compatible object store, keyed by
+// their hash under an optional prefix
+type S3Store struct {
+	client S3Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store returns a store keeping files in bucket under prefix
+func NewS3Store(client S3Client, bucket, prefix string) *S3Store {
+	return &S3Store{client: client, bucket: bucket, prefix: prefix}
+}
+
+// Name identifies the store in logs
+func (s *S3Store) Name() string {
+	return "s3:" + path.Join(s.bucket, s.prefix)
+}
+
+// Put uploads data unless the bucket already holds it
+func (s *S3Store) Put(hash string, data []byte, mimeType string) error {
+	if !ValidHash(hash) {
+		return fmt.Errorf("invalid document hash %q", hash)
+	}
+	if err := verify(hash, data); err != nil {
+		return err
+	}
+	if exists, err := s.Exists(hash); err != nil || exists {
+		return err
+	}
+	if err := s.client.PutObject(s.bucket, s.key(hash), data, mimeType); err != nil {
+		return fmt.Errorf("failed to store document: %w", err)
+	}
+	return nil
+}
+
+// Get downloads the file stored under hash and checks its contents
+func (s *S3Store) Get(hash string) ([]byte, error) {
+	if !ValidHash(hash) {
+		return nil, ErrNotFound
+	}
+	data, err := s.client.GetObject(s.bucket, s.key(hash))
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(hash, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Exists reports whether the bucket holds a file under hash
+func (s *S3Store) Exists(hash string) (bool, error) {
+	if !ValidHash(hash) {
+		return false, nil
+	}
+	return s.client.ObjectExists(s.bucket, s.key(hash))
+}
+
+func (s *S3Store) key(hash string) string {
+	return path.Join(s.prefix, hash[:2], hash)
+}
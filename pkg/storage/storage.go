@@ -0,0 +1,120 @@
+// Package storage keeps uploaded documents such as invoices, receipts and KYC
+// papers in a content-addressed store. Each file is stored under the SHA-256
+// of its contents, computed when it is uploaded and checked again whenever it
+// is read, so the hash recorded on chain names exactly the bytes an auditor
+// downloads.
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for a hash the store holds no file for
+var ErrNotFound = errors.New("document not found")
+
+// Store keeps files under the SHA-256 of their contents. Implementations must
+// be safe for concurrent use.
+type Store interface {
+	// Name identifies the store in logs
+	Name() string
+	// Put stores data under hash, which must be its SHA-256. Storing a file
+	// already held is not an error.
+	Put(hash string, data []byte, mimeType string) error
+	// Get returns the file stored under hash after checking its contents
+	// still match, or ErrNotFound
+	Get(hash string) ([]byte, error)
+	// Exists reports whether a file is stored under hash
+	Exists(hash string) (bool, error)
+}
+
+// Document describes an uploaded file
+type Document struct {
+	Hash       string    `json:"hash"` // Hex SHA-256 of the contents
+	Filename   string    `json:"filename"`
+	MIMEType   string    `json:"mime_type"` // Detected from the contents, not the uploader's claim
+	Size       int64     `json:"size"`
+	OwnerID    string    `json:"owner_id"`   // Entity that uploaded it
+	OwnerType  string    `json:"owner_type"` // ngo, donor or auditor
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// Hash returns the hex SHA-256 of data
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidHash reports whether hash is a lower-case hex SHA-256
+func ValidHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	for _, r := range hash {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// verify checks that data is the file stored under hash
+func verify(hash string, data []byte) error {
+	if actual := Hash(data); actual != hash {
+		return fmt.Errorf("document %s is corrupted: contents hash to %s", hash, actual)
+	}
+	return nil
+}
+
+// Policy limits the files that may be uploaded
+type Policy struct {
+	MaxSize      int64    // Largest file in bytes
+	AllowedTypes []string // MIME types accepted, as detected from the contents
+}
+
+// DefaultPolicy accepts PDFs and JPEG or PNG images of up to 10 MiB
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxSize:      10 << 20,
+		AllowedTypes: []string{"application/pdf", "image/jpeg", "image/png"},
+	}
+}
+
+// ParseTypes parses a comma-separated list of MIME types
+func ParseTypes(s string) []string {
+	var types []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.ToLower(strings.TrimSpace(part)); part != "" {
+			types = append(types, part)
+		}
+	}
+	return types
+}
+
+// Check detects the MIME type of data from its contents and returns it when
+// the policy accepts the file
+func (p Policy) Check(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("document is empty")
+	}
+	if p.MaxSize > 0 && int64(len(data)) > p.MaxSize {
+		return "", fmt.Errorf("document is %d bytes, more than the limit of %d", len(data), p.MaxSize)
+	}
+
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "", fmt.Errorf("cannot detect document type: %w", err)
+	}
+	for _, allowed := range p.AllowedTypes {
+		if mimeType == allowed {
+			return mimeType, nil
+		}
+	}
+	return "", fmt.Errorf("document type %s is not accepted, expected one of %s", mimeType, strings.Join(p.AllowedTypes, ", "))
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var samplePDF = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+
+func TestPolicyCheck(t *testing.T) {
+	policy := DefaultPolicy()
+
+	if mimeType, err := policy.Check(samplePDF); err != nil || mimeType != "application/pdf" {
+		t.Errorf("Expected a PDF to be accepted, got %q, %v", mimeType, err)
+	}
+	if _, err := policy.Check([]byte("plain text pretending to be a PDF")); err == nil {
+		t.Errorf("Expected text to be refused whatever its name")
+	}
+	if _, err := policy.Check(nil); err == nil {
+		t.Errorf("Expected an empty document to be refused")
+	}
+
+	policy.MaxSize = 10
+	if _, err := policy.Check(samplePDF); err == nil {
+		t.Errorf("Expected a document over the size limit to be refused")
+	}
+
+	if types := ParseTypes(" application/pdf, IMAGE/PNG ,"); len(types) != 2 || types[1] != "image/png" {
+		t.Errorf("Unexpected types: %v", types)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	hash := Hash(samplePDF)
+
+	if err := store.Put(Hash([]byte("other")), samplePDF, "application/pdf"); err == nil {
+		t.Errorf("Expected contents not matching their hash to be refused")
+	}
+	if _, err := store.Get(hash); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound before upload, got %v", err)
+	}
+
+	if err := store.Put(hash, samplePDF, "application/pdf"); err != nil {
+		t.Fatalf("Failed to store document: %v", err)
+	}
+	if err := store.Put(hash, samplePDF, "application/pdf"); err != nil {
+		t.Errorf("Expected storing a document twice to succeed, got %v", err)
+	}
+	if exists, err := store.Exists(hash); err != nil || !exists {
+		t.Errorf("Expected the document to exist, got %v, %v", exists, err)
+	}
+	data, err := store.Get(hash)
+	if err != nil || string(data) != string(samplePDF) {
+		t.Fatalf("Expected the stored document back, got %v", err)
+	}
+
+	// A file changed on disk no longer matches its hash
+	if err := os.WriteFile(filepath.Join(root, hash[:2], hash), []byte("tampered"), 0o600); err != nil {
+		t.Fatalf("Failed to tamper with document: %v", err)
+	}
+	if _, err := store.Get(hash); err == nil {
+		t.Errorf("Expected a tampered document to be refused")
+	}
+}
+
+type memoryS3 map[string][]byte
+
+func (m memoryS3) PutObject(bucket, key string, data []byte, contentType string) error {
+	m[bucket+"/"+key] = data
+	return nil
+}
+
+func (m memoryS3) GetObject(bucket, key string) ([]byte, error) {
+	data, exists := m[bucket+"/"+key]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (m memoryS3) ObjectExists(bucket, key string) (bool, error) {
+	_, exists := m[bucket+"/"+key]
+	return exists, nil
+}
+
+func TestS3Store(t *testing.T) {
+	client := memoryS3{}
+	store := NewS3Store(client, "documents", "trusture")
+	hash := Hash(samplePDF)
+
+	if err := store.Put(hash, samplePDF, "application/pdf"); err != nil {
+		t.Fatalf("Failed to store document: %v", err)
+	}
+	if _, exists := client["documents/trusture/"+hash[:2]+"/"+hash]; !exists {
+		t.Errorf("Expected the document keyed by its hash, got %v", client)
+	}
+	if data, err := store.Get(hash); err != nil || string(data) != string(samplePDF) {
+		t.Errorf("Expected the stored document back, got %v", err)
+	}
+	if _, err := store.Get(Hash([]byte("missing"))); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
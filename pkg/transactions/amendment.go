@@ -0,0 +1,155 @@
+package transactions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"ngo-transparency-platform/pkg/compliance"
+	"ngo-transparency-platform/pkg/money"
+)
+
+// States of an amendment
+const (
+	AmendmentPending  = "pending"  // Awaiting re-audit
+	AmendmentRecorded = "recorded" // Appended to the expenditure blockchain
+	AmendmentRejected = "rejected" // Refused on re-audit, the previous version stands
+)
+
+// ExpenditureChanges are the corrections an NGO asks to make to a recorded
+// expenditure. Fields left nil are unchanged.
+type ExpenditureChanges struct {
+	Amount            *money.Money `json:"amount,omitempty"`
+	Category          *string      `json:"category,omitempty"`
+	Description       *string      `json:"description,omitempty"`
+	InvoiceNumber     *string      `json:"invoice_number,omitempty"`
+	InvoiceDate       *time.Time   `json:"invoice_date,omitempty"`
+	GSTIN             *string      `json:"gstin,omitempty"`
+	VendorName        *string      `json:"vendor_name,omitempty"`
+	VendorGSTIN       *string      `json:"vendor_gstin,omitempty"`
+	BankTransactionID *string      `json:"bank_transaction_id,omitempty"`
+	ChequeNumber      *string      `json:"cheque_number,omitempty"`
+	Documents         []string     `json:"documents,omitempty"`
+}
+
+// FieldChange is one field an amendment changes, with its old and new values
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ExpenditureAmendment corrects a recorded expenditure. It references the
+// expenditure's original block and the version it amends, and carries the
+// fields it changes. Changes to the amount or vendor must be re-audited
+// before they are recorded.
+type ExpenditureAmendment struct {
+	AmendmentID       string        `json:"amendment_id"`
+	Version           int           `json:"version"` // Version of the expenditure it creates, the original being 1
+	Reason            string        `json:"reason"`
+	Changes           []FieldChange `json:"changes"`
+	AmountChange      money.Money   `json:"amount_change"` // New amount less the previous one
+	RequiresReaudit   bool          `json:"requires_reaudit"`
+	OriginalBlockHash string        `json:"original_block_hash"`
+	PreviousBlockHash string        `json:"previous_block_hash"` // Block of the version amended
+	Status            string        `json:"status"`
+	RequestedAt       time.Time     `json:"requested_at"`
+	DecidedAt         *time.Time    `json:"decided_at,omitempty"`
+}
+
+// NewExpenditureAmendment starts an amendment creating version of an
+// expenditure last recorded in previousBlockHash
+func NewExpenditureAmendment(version int, reason, originalBlockHash, previousBlockHash string) *ExpenditureAmendment {
+	randomBytes := make([]byte, 16)
+	rand.Read(randomBytes)
+
+	return &ExpenditureAmendment{
+		AmendmentID:       hex.EncodeToString(randomBytes),
+		Version:           version,
+		Reason:            reason,
+		Changes:           make([]FieldChange, 0),
+		AmountChange:      money.Zero(),
+		OriginalBlockHash: originalBlockHash,
+		PreviousBlockHash: previousBlockHash,
+		Status:            AmendmentPending,
+		RequestedAt:       time.Now(),
+	}
+}
+
+// Amend applies changes to the expenditure, rescores it under rules and returns
+// the fields that actually changed. Amounts or vendors changed require re-audit.
+func (et *ExpenditureTransaction) Amend(changes ExpenditureChanges, rules *compliance.RuleSet) (fieldChanges []FieldChange, requiresReaudit bool) {
+	fieldChanges = make([]FieldChange, 0)
+	setString := func(field string, target *string, value *string) bool {
+		if value == nil || strings.TrimSpace(*value) == *target {
+			return false
+		}
+		fieldChanges = append(fieldChanges, FieldChange{Field: field, Old: *target, New: strings.TrimSpace(*value)})
+		*target = strings.TrimSpace(*value)
+		return true
+	}
+
+	if changes.Amount != nil && !changes.Amount.Equal(et.Amount) {
+		fieldChanges = append(fieldChanges, FieldChange{Field: "amount", Old: et.Amount, New: *changes.Amount})
+		et.Amount = *changes.Amount
+		requiresReaudit = true
+	}
+	setString("category", &et.Category, changes.Category)
+	setString("description", &et.Description, changes.Description)
+	setString("invoice_number", &et.InvoiceDetails.InvoiceNumber, changes.InvoiceNumber)
+	if changes.InvoiceDate != nil && !changes.InvoiceDate.Equal(et.InvoiceDetails.InvoiceDate) {
+		fieldChanges = append(fieldChanges, FieldChange{Field: "invoice_date", Old: et.InvoiceDetails.InvoiceDate, New: *changes.InvoiceDate})
+		et.InvoiceDetails.InvoiceDate = *changes.InvoiceDate
+	}
+	if changes.GSTIN != nil {
+		gstin := strings.ToUpper(*changes.GSTIN)
+		setString("gstin", &et.InvoiceDetails.GSTIN, &gstin)
+	}
+	if setString("vendor_name", &et.InvoiceDetails.VendorName, changes.VendorName) {
+		requiresReaudit = true
+	}
+	if changes.VendorGSTIN != nil {
+		vendorGSTIN := strings.ToUpper(*changes.VendorGSTIN)
+		if setString("vendor_gstin", &et.InvoiceDetails.VendorGSTIN, &vendorGSTIN) {
+			requiresReaudit = true
+		}
+	}
+	setString("bank_transaction_id", &et.InvoiceDetails.BankTransactionID, changes.BankTransactionID)
+	setString("cheque_number", &et.InvoiceDetails.ChequeNumber, changes.ChequeNumber)
+	if changes.Documents != nil && strings.Join(changes.Documents, "\x00") != strings.Join(et.InvoiceDetails.Documents, "\x00") {
+		fieldChanges = append(fieldChanges, FieldChange{Field: "documents", Old: et.InvoiceDetails.Documents, New: changes.Documents})
+		et.InvoiceDetails.Documents = changes.Documents
+	}
+
+	// A corrected invoice is no longer the e-invoice that was verified
+	for _, change := range fieldChanges {
+		switch change.Field {
+		case "invoice_number", "invoice_date", "gstin", "vendor_gstin":
+			et.InvoiceDetails.EInvoice = nil
+		}
+	}
+
+	et.ComplianceScore = et.calculateComplianceScore(rules)
+	return fieldChanges, requiresReaudit
+}
+
+// FileAmendment sends an amended expenditure for re-audit by a panel
+func (et *ExpenditureTransaction) FileAmendment(amendment *ExpenditureAmendment, panel []string, requiredApprovals int) {
+	et.Amendment = amendment
+	et.Reviews = nil
+	et.addReview(et.NGOID, DecisionAmend, amendment.Reason)
+
+	et.AuditorValidation = nil
+	et.Consensus = nil
+	et.InvoiceFlags = nil
+	et.Panel = panel
+	et.AssignedAuditorID = panel[0]
+	et.RequiredApprovals = requiredApprovals
+	et.Status = "under_amendment"
+}
+
+// IsUnderAmendment checks if an amendment of a recorded expenditure awaits re-audit
+func (et *ExpenditureTransaction) IsUnderAmendment() bool {
+	return et.Status == "under_amendment"
+}
@@ -7,8 +7,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"math"
 	"ngo-transparency-platform/pkg/crypto"
+	"ngo-transparency-platform/pkg/money"
 	"strings"
 	"time"
 )
@@ -16,8 +16,8 @@ import (
 // TaxBenefit represents tax benefit information
 type TaxBenefit struct {
 	Section          string  `json:"section"`
-	DeductibleAmount float64 `json:"deductible_amount"`
-	TaxSaving        float64 `json:"tax_saving"`
+	DeductibleAmount money.Money `json:"deductible_amount"`
+	TaxSaving        money.Money `json:"tax_saving"`
 	Note             string  `json:"note"`
 }
 
@@ -25,7 +25,7 @@ type TaxBenefit struct {
 type EBill struct {
 	BillID           string     `json:"bill_id"`
 	TransactionID    string     `json:"transaction_id"`
-	Amount           float64    `json:"amount"`
+	Amount           money.Money `json:"amount"`
 	Currency         string     `json:"currency"`
 	Timestamp        time.Time  `json:"timestamp"`
 	NGOID            string     `json:"ngo_id"`
@@ -37,6 +37,9 @@ type EBill struct {
 	QRCode           string     `json:"qr_code"`
 	DownloadURL      string     `json:"download_url"`
 	ValidityPeriod   string     `json:"validity_period"`
+	Replaces         string     `json:"replaces,omitempty"` // Bill ID of the voided bill this one reissues
+	VoidedAt         *time.Time `json:"voided_at,omitempty"`
+	VoidReason       string     `json:"void_reason,omitempty"`
 }
 
 // DonationTransaction represents a donation transaction
@@ -44,7 +47,7 @@ type DonationTransaction struct {
 	TransactionID   string            `json:"transaction_id"`
 	DonorID         string            `json:"donor_id"`
 	NGOID           string            `json:"ngo_id"`
-	Amount          float64           `json:"amount"`
+	Amount          money.Money       `json:"amount"`
 	PaymentMethod   string            `json:"payment_method"`
 	Timestamp       time.Time         `json:"timestamp"`
 	Status          string            `json:"status"`
@@ -54,15 +57,24 @@ type DonationTransaction struct {
 	CompletedAt     *time.Time        `json:"completed_at,omitempty"`
 	FailedAt        *time.Time        `json:"failed_at,omitempty"`
 	FailureReason   string            `json:"failure_reason,omitempty"`
+	CampaignID      string            `json:"campaign_id,omitempty"` // Campaign the donation is earmarked to
+	MandateID       string            `json:"mandate_id,omitempty"`  // Recurring mandate that made the donation
+	MatchFor        string            `json:"match_for,omitempty"`   // Employee donation a corporate donation matches
 }
 
 // NewDonationTransaction creates a new donation transaction
-func NewDonationTransaction(donorID, ngoID string, amount float64, paymentMethod, donorKYCHash string) *DonationTransaction {
+func NewDonationTransaction(donorID, ngoID string, amount money.Money, paymentMethod, donorKYCHash string) *DonationTransaction {
 	// Generate transaction ID
 	randomBytes := make([]byte, 16)
 	rand.Read(randomBytes)
 	transactionID := hex.EncodeToString(randomBytes)
 
+	return NewDonationTransactionWithID(transactionID, donorID, ngoID, amount, paymentMethod, donorKYCHash)
+}
+
+// NewDonationTransactionWithID creates a donation transaction with a given ID,
+// e.g. one derived from an idempotency key
+func NewDonationTransactionWithID(transactionID, donorID, ngoID string, amount money.Money, paymentMethod, donorKYCHash string) *DonationTransaction {
 	timestamp := time.Now()
 
 	transaction := &DonationTransaction{
@@ -77,7 +89,7 @@ func NewDonationTransaction(donorID, ngoID string, amount float64, paymentMethod
 	}
 
 	// Generate ZK proof
-	transaction.ZKProof = crypto.GenerateProof(donorID, amount, timestamp)
+	transaction.ZKProof = crypto.GenerateProof(donorID, amount.Float64(), timestamp)
 
 	// Generate e-bill
 	transaction.EBill = transaction.generateEBill()
@@ -131,12 +143,14 @@ func (dt *DonationTransaction) generateEBill() *EBill {
 // calculateTaxBenefit calculates the tax benefit for the donation
 func (dt *DonationTransaction) calculateTaxBenefit() TaxBenefit {
 	// 80G deduction calculation (simplified)
-	maxDeduction := math.Min(dt.Amount, 10000) // Simplified calculation
+	maxDeduction := money.Min(dt.Amount, money.INR(10000*100)) // Simplified calculation
+	// 30% of at most ₹10,000 cannot overflow
+	taxSaving, _ := maxDeduction.MulRatio(30, 100, money.RoundDown) // Assuming 30% tax bracket
 
 	return TaxBenefit{
 		Section:          "80G",
 		DeductibleAmount: maxDeduction,
-		TaxSaving:        maxDeduction * 0.3, // Assuming 30% tax bracket
+		TaxSaving:        taxSaving,
 		Note:             "Consult tax advisor for accurate calculations",
 	}
 }
@@ -5,7 +5,11 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"regexp"
+	"ngo-transparency-platform/pkg/compliance"
+	"ngo-transparency-platform/pkg/consensus"
+	"ngo-transparency-platform/pkg/einvoice"
+	"ngo-transparency-platform/pkg/gst"
+	"ngo-transparency-platform/pkg/money"
 	"time"
 )
 
@@ -19,6 +23,8 @@ type InvoiceDetails struct {
 	Documents         []string  `json:"documents"`
 	BankTransactionID string    `json:"bank_transaction_id"`
 	ChequeNumber      string    `json:"cheque_number"`
+	// Registration of a GST e-invoice, verified against the IRP's signature
+	EInvoice *einvoice.Record `json:"einvoice,omitempty"`
 }
 
 // Attachment represents a file attachment
@@ -39,28 +45,90 @@ type AuditorValidation struct {
 	Signature  string    `json:"signature"`
 }
 
+// FundAllocation is the part of an expenditure paid for by one donation
+type FundAllocation struct {
+	DonationID string      `json:"donation_id"` // Transaction ID of the funding donation
+	Amount     money.Money `json:"amount"`
+}
+
 // ExpenditureTransaction represents an expenditure transaction
 type ExpenditureTransaction struct {
-	TransactionID     string             `json:"transaction_id"`
-	NGOID             string             `json:"ngo_id"`
-	Amount            float64            `json:"amount"`
-	Category          string             `json:"category"`
-	Description       string             `json:"description"`
-	Timestamp         time.Time          `json:"timestamp"`
-	InvoiceDetails    InvoiceDetails     `json:"invoice_details"`
-	Attachments       []Attachment       `json:"attachments"`
-	Status            string             `json:"status"`
-	AuditorValidation *AuditorValidation `json:"auditor_validation,omitempty"`
-	ComplianceScore   float64            `json:"compliance_score"`
-}
-
-// NewExpenditureTransaction creates a new expenditure transaction
-func NewExpenditureTransaction(ngoID string, amount float64, category, description string, invoiceDetails InvoiceDetails, attachments []Attachment) *ExpenditureTransaction {
+	TransactionID          string                `json:"transaction_id"`
+	NGOID                  string                `json:"ngo_id"`
+	Amount                 money.Money           `json:"amount"`
+	Category               string                `json:"category"`
+	Description            string                `json:"description"`
+	Timestamp              time.Time             `json:"timestamp"`
+	InvoiceDetails         InvoiceDetails        `json:"invoice_details"`
+	Attachments            []Attachment          `json:"attachments"`
+	Status                 string                `json:"status"`
+	AuditorValidation      *AuditorValidation    `json:"auditor_validation,omitempty"`
+	ComplianceScore        float64               `json:"compliance_score"`
+	ComplianceRulesVersion string                `json:"compliance_rules_version,omitempty"` // Version of the rules the score was calculated under
+	CampaignID             string                `json:"campaign_id,omitempty"`              // Campaign whose restricted funds pay for it
+	Funding                []FundAllocation      `json:"funding,omitempty"`                  // Donations that pay for it
+	InvoiceFlags           []InvoiceFlag         `json:"invoice_flags,omitempty"`            // Earlier invoices this one may duplicate
+	AssignedAuditorID      string                `json:"assigned_auditor_id,omitempty"`      // Lead auditor of the panel
+	Panel                  []string              `json:"panel,omitempty"`                    // Auditors who must review it
+	RequiredApprovals      int                   `json:"required_approvals,omitempty"`       // Approvals of the panel needed
+	Consensus              *consensus.Outcome    `json:"consensus,omitempty"`                // Tally of the panel's votes
+	Reviews                []ExpenditureReview   `json:"reviews,omitempty"`                  // Review decisions and NGO replies, oldest first
+	Appeal                 *ExpenditureAppeal    `json:"appeal,omitempty"`                   // The NGO's appeal against a rejection
+	Amendment              *ExpenditureAmendment `json:"amendment,omitempty"`                // The latest amendment of the recorded expenditure
+}
+
+// Review decisions on a submitted expenditure
+const (
+	DecisionApprove      = "approve"
+	DecisionReject       = "reject"
+	DecisionRequestInfo  = "request_info"
+	DecisionInfoProvided = "info_provided" // The NGO's reply to a request for information
+	DecisionAppeal       = "appeal"        // The NGO's appeal against a rejection
+	DecisionAmend        = "amend"         // The NGO's amendment of a recorded expenditure
+)
+
+// Outcomes of an appeal
+const (
+	AppealGranted = "granted" // The new panel approved the expenditure
+	AppealDenied  = "denied"  // The new panel upheld the rejection
+)
+
+// ExpenditureAppeal is an NGO's appeal against the rejection of an
+// expenditure, re-reviewed by auditors other than those who rejected it
+type ExpenditureAppeal struct {
+	Reason            string             `json:"reason"`
+	OriginalDecision  *AuditorValidation `json:"original_decision"`            // The rejection appealed against
+	OriginalPanel     []string           `json:"original_panel"`               // Auditors who rejected it, excluded from the re-review
+	OriginalConsensus *consensus.Outcome `json:"original_consensus,omitempty"` // Tally of the rejecting panel's votes
+	AppealedAt        time.Time          `json:"appealed_at"`
+	Outcome           string             `json:"outcome,omitempty"` // granted or denied once the new panel decides
+	DecidedAt         *time.Time         `json:"decided_at,omitempty"`
+}
+
+// ExpenditureReview is an auditor's decision on a submitted expenditure, or
+// the NGO's reply to the auditor's request for information
+type ExpenditureReview struct {
+	ReviewerID string    `json:"reviewer_id"` // Auditor, or the NGO for info_provided
+	Decision   string    `json:"decision"`
+	Notes      string    `json:"notes"`
+	Score      *float64  `json:"score,omitempty"`     // Reviewer's compliance score for approve and reject
+	Signature  string    `json:"signature,omitempty"` // Reviewer's signature of an approve or reject
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// NewExpenditureTransaction creates a new expenditure transaction scored under rules
+func NewExpenditureTransaction(ngoID string, amount money.Money, category, description string, invoiceDetails InvoiceDetails, attachments []Attachment, rules *compliance.RuleSet) *ExpenditureTransaction {
 	// Generate transaction ID
 	randomBytes := make([]byte, 16)
 	rand.Read(randomBytes)
 	transactionID := hex.EncodeToString(randomBytes)
 
+	return NewExpenditureTransactionWithID(transactionID, ngoID, amount, category, description, invoiceDetails, attachments, rules)
+}
+
+// NewExpenditureTransactionWithID creates an expenditure transaction with a
+// given ID, e.g. one derived from an idempotency key
+func NewExpenditureTransactionWithID(transactionID, ngoID string, amount money.Money, category, description string, invoiceDetails InvoiceDetails, attachments []Attachment, rules *compliance.RuleSet) *ExpenditureTransaction {
 	if attachments == nil {
 		attachments = make([]Attachment, 0)
 	}
@@ -78,60 +146,38 @@ func NewExpenditureTransaction(ngoID string, amount float64, category, descripti
 	}
 
 	// Calculate compliance score
-	transaction.ComplianceScore = transaction.calculateComplianceScore()
+	transaction.ComplianceScore = transaction.calculateComplianceScore(rules)
 
 	return transaction
 }
 
-// calculateComplianceScore calculates the compliance score for the expenditure
-func (et *ExpenditureTransaction) calculateComplianceScore() float64 {
-	var score float64 = 0
-	maxScore := 100.0
-
-	// Invoice number present (20%)
-	if et.InvoiceDetails.InvoiceNumber != "" {
-		score += 20
-	}
-
-	// GSTIN validation (20%)
-	if et.VerifyGSTIN(et.InvoiceDetails.GSTIN) {
-		score += 20
-	}
-
-	// Vendor details complete (15%)
-	if et.InvoiceDetails.VendorName != "" && et.InvoiceDetails.VendorGSTIN != "" {
-		score += 15
-	}
-
-	// Payment proof (15%)
-	if et.InvoiceDetails.BankTransactionID != "" || et.InvoiceDetails.ChequeNumber != "" {
-		score += 15
-	}
-
-	// Supporting documents (15%)
-	if len(et.InvoiceDetails.Documents) > 0 {
-		score += 15
-	}
-
-	// Attachments (10%)
-	if len(et.Attachments) > 0 {
-		score += 10
-	}
-
-	// Recent invoice (within 90 days) (5%)
-	daysDiff := time.Since(et.InvoiceDetails.InvoiceDate).Hours() / 24
-	if daysDiff <= 90 {
-		score += 5
-	}
+// calculateComplianceScore calculates the compliance score for the
+// expenditure under rules and records their version
+func (et *ExpenditureTransaction) calculateComplianceScore(rules *compliance.RuleSet) float64 {
+	result := et.evaluateCompliance(rules)
+	et.ComplianceRulesVersion = result.Version
+	return result.Score
+}
 
-	return min(score, maxScore)
+// evaluateCompliance checks the expenditure's documentation under rules
+func (et *ExpenditureTransaction) evaluateCompliance(rules *compliance.RuleSet) compliance.Result {
+	return rules.Evaluate(compliance.Evidence{
+		Category:       et.Category,
+		InvoiceNumber:  et.InvoiceDetails.InvoiceNumber != "",
+		GSTINValid:     et.VerifyGSTIN(et.InvoiceDetails.GSTIN),
+		VendorDetails:  et.InvoiceDetails.VendorName != "" && et.VerifyGSTIN(et.InvoiceDetails.VendorGSTIN),
+		PaymentProof:   et.InvoiceDetails.BankTransactionID != "" || et.InvoiceDetails.ChequeNumber != "",
+		Documents:      len(et.InvoiceDetails.Documents),
+		Attachments:    len(et.Attachments),
+		InvoiceAgeDays: time.Since(et.InvoiceDetails.InvoiceDate).Hours() / 24,
+	})
 }
 
-// ValidateByAuditor validates the expenditure by an auditor
+// ValidateByAuditor validates the expenditure by an auditor, keeping its
+// current compliance score unless score is given
 func (et *ExpenditureTransaction) ValidateByAuditor(auditorID string, isValid bool, remarks string, score *float64) {
 	if score == nil {
-		defaultScore := et.calculateComplianceScore()
-		score = &defaultScore
+		score = &et.ComplianceScore
 	}
 
 	// Create validation signature
@@ -162,31 +208,169 @@ func (et *ExpenditureTransaction) ValidateByAuditor(auditorID string, isValid bo
 		et.Status = "rejected"
 		et.ComplianceScore = 0
 	}
+
+	// A decision on an appealed expenditure is the appeal's outcome
+	if et.Appeal != nil && et.Appeal.Outcome == "" {
+		et.Appeal.Outcome = AppealDenied
+		if isValid {
+			et.Appeal.Outcome = AppealGranted
+		}
+		decidedAt := et.AuditorValidation.Timestamp
+		et.Appeal.DecidedAt = &decidedAt
+	}
+
+	// A decision on an amended expenditure decides the amendment
+	if et.Amendment != nil && et.Amendment.Status == AmendmentPending {
+		et.Amendment.Status = AmendmentRejected
+		if isValid {
+			et.Amendment.Status = AmendmentRecorded
+		}
+		decidedAt := et.AuditorValidation.Timestamp
+		et.Amendment.DecidedAt = &decidedAt
+	}
 }
 
-// VerifyGSTIN validates GSTIN format
-func (et *ExpenditureTransaction) VerifyGSTIN(gstin string) bool {
-	if gstin == "" {
-		return false
+// Vote records a panel member's signed approval or rejection with the
+// auditor's notes and compliance score
+func (et *ExpenditureTransaction) Vote(auditorID string, approve bool, notes string, score float64, signature string) {
+	decision := DecisionReject
+	if approve {
+		decision = DecisionApprove
+	}
+	et.addReview(auditorID, decision, notes)
+	review := &et.Reviews[len(et.Reviews)-1]
+	review.Score = &score
+	review.Signature = signature
+}
+
+// Votes returns the latest approval or rejection of each current panel member
+func (et *ExpenditureTransaction) Votes() []ExpenditureReview {
+	latest := make(map[string]int)
+	for i, review := range et.Reviews {
+		if (review.Decision == DecisionApprove || review.Decision == DecisionReject) && et.IsAssignedTo(review.ReviewerID) {
+			latest[review.ReviewerID] = i
+		}
+	}
+
+	votes := make([]ExpenditureReview, 0, len(latest))
+	for i, review := range et.Reviews {
+		if j, exists := latest[review.ReviewerID]; exists && i == j {
+			votes = append(votes, review)
+		}
+	}
+	return votes
+}
+
+// ConsensusVotes returns the panel's votes for tallying
+func (et *ExpenditureTransaction) ConsensusVotes() []consensus.Vote {
+	var votes []consensus.Vote
+	for _, review := range et.Votes() {
+		vote := consensus.Vote{AuditorID: review.ReviewerID, Approve: review.Decision == DecisionApprove, Score: et.ComplianceScore}
+		if review.Score != nil {
+			vote.Score = *review.Score
+		}
+		votes = append(votes, vote)
+	}
+	return votes
+}
+
+// HasVoted reports whether a current panel member has approved or rejected the expenditure
+func (et *ExpenditureTransaction) HasVoted(auditorID string) bool {
+	for _, vote := range et.Votes() {
+		if vote.ReviewerID == auditorID {
+			return true
+		}
 	}
+	return false
+}
 
-	// GSTIN format: 2-digit state code + 10-character PAN + 1-character entity code + 1-character check digit + 1-character default 'Z' + 1-character check digit
-	gstinRegex := regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z]{1}[1-9A-Z]{1}Z[0-9A-Z]{1}$`)
-	return gstinRegex.MatchString(gstin)
+// Reviewers returns the auditors on the expenditure's panel
+func (et *ExpenditureTransaction) Reviewers() []string {
+	if len(et.Panel) > 0 {
+		return et.Panel
+	}
+	if et.AssignedAuditorID != "" {
+		return []string{et.AssignedAuditorID}
+	}
+	return nil
 }
 
-// VerifyInvoiceUniqueness checks if the invoice number is unique among existing invoices
-func (et *ExpenditureTransaction) VerifyInvoiceUniqueness(existingInvoices []string) bool {
-	for _, existing := range existingInvoices {
-		if existing == et.InvoiceDetails.InvoiceNumber {
-			return false
+// IsAssignedTo reports whether an auditor is on the expenditure's panel
+func (et *ExpenditureTransaction) IsAssignedTo(auditorID string) bool {
+	for _, reviewer := range et.Reviewers() {
+		if reviewer == auditorID {
+			return true
 		}
 	}
-	return true
+	return false
+}
+
+// Approvals returns how many of the panel's approvals decide the expenditure
+func (et *ExpenditureTransaction) Approvals() int {
+	if et.RequiredApprovals > 0 {
+		return et.RequiredApprovals
+	}
+	return 1
+}
+
+// RequestInformation returns the expenditure to the NGO with the auditor's questions
+func (et *ExpenditureTransaction) RequestInformation(auditorID, notes string) {
+	et.addReview(auditorID, DecisionRequestInfo, notes)
+	et.Status = "information_requested"
+}
+
+// ProvideInformation records the NGO's reply to a request for information,
+// rescores the expenditure and returns it to the auditor's queue
+func (et *ExpenditureTransaction) ProvideInformation(notes string, rules *compliance.RuleSet) {
+	et.addReview(et.NGOID, DecisionInfoProvided, notes)
+	et.ComplianceScore = et.calculateComplianceScore(rules)
+	et.Status = "pending_validation"
+	if et.Appeal != nil {
+		et.Status = "under_appeal"
+	}
+	if et.Amendment != nil && et.Amendment.Status == AmendmentPending {
+		et.Status = "under_amendment"
+	}
+}
+
+// FileAppeal reopens a rejected expenditure for review by a new panel,
+// keeping the rejection and the panel that made it as the decision under
+// appeal. The expenditure is rescored with any documents added.
+func (et *ExpenditureTransaction) FileAppeal(reason string, panel []string, requiredApprovals int, rules *compliance.RuleSet) {
+	et.Appeal = &ExpenditureAppeal{
+		Reason:            reason,
+		OriginalDecision:  et.AuditorValidation,
+		OriginalPanel:     et.Reviewers(),
+		OriginalConsensus: et.Consensus,
+		AppealedAt:        time.Now(),
+	}
+	et.addReview(et.NGOID, DecisionAppeal, reason)
+
+	et.AuditorValidation = nil
+	et.Consensus = nil
+	et.Panel = panel
+	et.AssignedAuditorID = panel[0]
+	et.RequiredApprovals = requiredApprovals
+	et.ComplianceScore = et.calculateComplianceScore(rules)
+	et.Status = "under_appeal"
+}
+
+func (et *ExpenditureTransaction) addReview(reviewerID, decision, notes string) {
+	et.Reviews = append(et.Reviews, ExpenditureReview{
+		ReviewerID: reviewerID,
+		Decision:   decision,
+		Notes:      notes,
+		Timestamp:  time.Now(),
+	})
+}
+
+// VerifyGSTIN validates a GSTIN's format, state code and check character
+func (et *ExpenditureTransaction) VerifyGSTIN(gstin string) bool {
+	return gst.Valid(gstin)
 }
 
-// AddAttachment adds a new attachment to the transaction
-func (et *ExpenditureTransaction) AddAttachment(filename, hash, attachmentType string) {
+// AddAttachment adds a new attachment to the transaction and rescores it under rules
+func (et *ExpenditureTransaction) AddAttachment(filename, hash, attachmentType string, rules *compliance.RuleSet) {
 	attachment := Attachment{
 		Filename:   filename,
 		Hash:       hash,
@@ -197,7 +381,17 @@ func (et *ExpenditureTransaction) AddAttachment(filename, hash, attachmentType s
 	et.Attachments = append(et.Attachments, attachment)
 
 	// Recalculate compliance score
-	et.ComplianceScore = et.calculateComplianceScore()
+	et.ComplianceScore = et.calculateComplianceScore(rules)
+}
+
+// HasAttachment reports whether a file with the given hash is attached
+func (et *ExpenditureTransaction) HasAttachment(hash string) bool {
+	for _, attachment := range et.Attachments {
+		if attachment.Hash == hash {
+			return true
+		}
+	}
+	return false
 }
 
 // IsValidated checks if the transaction is validated
@@ -215,6 +409,16 @@ func (et *ExpenditureTransaction) IsPendingValidation() bool {
 	return et.Status == "pending_validation"
 }
 
+// IsUnderAppeal checks if a new panel is re-reviewing a rejected expenditure
+func (et *ExpenditureTransaction) IsUnderAppeal() bool {
+	return et.Status == "under_appeal"
+}
+
+// IsInformationRequested checks if an auditor is waiting on the NGO for more information
+func (et *ExpenditureTransaction) IsInformationRequested() bool {
+	return et.Status == "information_requested"
+}
+
 // GetTransactionSummary returns a summary of the expenditure transaction
 func (et *ExpenditureTransaction) GetTransactionSummary() map[string]interface{} {
 	summary := map[string]interface{}{
@@ -240,6 +444,25 @@ func (et *ExpenditureTransaction) GetTransactionSummary() map[string]interface{}
 			"validated_at": et.AuditorValidation.Timestamp,
 		}
 	}
+	if et.AssignedAuditorID != "" {
+		summary["assigned_auditor_id"] = et.AssignedAuditorID
+	}
+	if len(et.Panel) > 1 {
+		summary["panel"] = et.Panel
+		summary["required_approvals"] = et.Approvals()
+	}
+	if et.Consensus != nil {
+		summary["consensus"] = et.Consensus
+	}
+	if len(et.Reviews) > 0 {
+		summary["reviews"] = et.Reviews
+	}
+	if et.Appeal != nil {
+		summary["appeal"] = et.Appeal
+	}
+	if et.Amendment != nil {
+		summary["amendment"] = et.Amendment
+	}
 
 	return summary
 }
@@ -260,112 +483,45 @@ func (et *ExpenditureTransaction) GetInvoiceInfo() map[string]interface{} {
 	}
 }
 
-// GetComplianceBreakdown returns detailed compliance score breakdown
-func (et *ExpenditureTransaction) GetComplianceBreakdown() map[string]interface{} {
+// GetComplianceBreakdown returns detailed compliance score breakdown under rules
+func (et *ExpenditureTransaction) GetComplianceBreakdown(rules *compliance.RuleSet) map[string]interface{} {
+	result := et.evaluateCompliance(rules)
 	breakdown := map[string]interface{}{
-		"total_score": et.ComplianceScore,
-		"max_score":   100.0,
+		"total_score":   et.ComplianceScore,
+		"max_score":     100.0,
+		"rules_version": result.Version,
 	}
 
 	components := make(map[string]interface{})
-
-	// Invoice number check
-	components["invoice_number"] = map[string]interface{}{
-		"score":     getScore(et.InvoiceDetails.InvoiceNumber != "", 20),
-		"max_score": 20,
-		"status":    et.InvoiceDetails.InvoiceNumber != "",
-	}
-
-	// GSTIN validation
-	components["gstin_validation"] = map[string]interface{}{
-		"score":     getScore(et.VerifyGSTIN(et.InvoiceDetails.GSTIN), 20),
-		"max_score": 20,
-		"status":    et.VerifyGSTIN(et.InvoiceDetails.GSTIN),
-	}
-
-	// Vendor details
-	vendorComplete := et.InvoiceDetails.VendorName != "" && et.InvoiceDetails.VendorGSTIN != ""
-	components["vendor_details"] = map[string]interface{}{
-		"score":     getScore(vendorComplete, 15),
-		"max_score": 15,
-		"status":    vendorComplete,
-	}
-
-	// Payment proof
-	hasPaymentProof := et.InvoiceDetails.BankTransactionID != "" || et.InvoiceDetails.ChequeNumber != ""
-	components["payment_proof"] = map[string]interface{}{
-		"score":     getScore(hasPaymentProof, 15),
-		"max_score": 15,
-		"status":    hasPaymentProof,
-	}
-
-	// Supporting documents
-	hasDocuments := len(et.InvoiceDetails.Documents) > 0
-	components["supporting_documents"] = map[string]interface{}{
-		"score":     getScore(hasDocuments, 15),
-		"max_score": 15,
-		"status":    hasDocuments,
-		"count":     len(et.InvoiceDetails.Documents),
-	}
-
-	// Attachments
-	hasAttachments := len(et.Attachments) > 0
-	components["attachments"] = map[string]interface{}{
-		"score":     getScore(hasAttachments, 10),
-		"max_score": 10,
-		"status":    hasAttachments,
-		"count":     len(et.Attachments),
-	}
-
-	// Invoice recency
-	daysDiff := time.Since(et.InvoiceDetails.InvoiceDate).Hours() / 24
-	isRecent := daysDiff <= 90
-	components["invoice_recency"] = map[string]interface{}{
-		"score":     getScore(isRecent, 5),
-		"max_score": 5,
-		"status":    isRecent,
-		"days_old":  int(daysDiff),
-		"threshold": 90,
+	for _, component := range result.Components {
+		details := map[string]interface{}{
+			"score":     component.Score,
+			"max_score": component.MaxScore,
+			"status":    component.Passed,
+		}
+		switch component.Check {
+		case compliance.CheckSupportingDocuments:
+			details["count"] = len(et.InvoiceDetails.Documents)
+		case compliance.CheckAttachments:
+			details["count"] = len(et.Attachments)
+		case compliance.CheckInvoiceRecency:
+			details["days_old"] = int(time.Since(et.InvoiceDetails.InvoiceDate).Hours() / 24)
+			details["threshold"] = rules.MaxInvoiceAgeDays(et.Category)
+		}
+		components[component.Check] = details
 	}
 
 	breakdown["components"] = components
 	return breakdown
 }
 
-// getScore returns the score based on condition and max value
-func getScore(condition bool, maxScore float64) float64 {
-	if condition {
-		return maxScore
-	}
-	return 0
-}
-
-// min returns the minimum of two float64 values
-func min(a, b float64) float64 {
-	if a < b {
-		return a
-	}
-	return b
-}
-
 // GetValidationRecommendation returns a recommendation based on compliance score
-func (et *ExpenditureTransaction) GetValidationRecommendation() string {
-	score := et.ComplianceScore
-
-	switch {
-	case score >= 90:
-		return "Approve - Excellent compliance"
-	case score >= 70:
-		return "Approve with minor observations"
-	case score >= 50:
-		return "Conditional approval - requires additional documentation"
-	default:
-		return "Reject - insufficient compliance"
-	}
+func (et *ExpenditureTransaction) GetValidationRecommendation(rules *compliance.RuleSet) string {
+	return rules.Recommend(et.ComplianceScore)
 }
 
 // GetComplianceIssues returns a list of compliance issues
-func (et *ExpenditureTransaction) GetComplianceIssues() []string {
+func (et *ExpenditureTransaction) GetComplianceIssues(rules *compliance.RuleSet) []string {
 	var issues []string
 
 	if et.InvoiceDetails.InvoiceNumber == "" {
@@ -373,7 +529,7 @@ func (et *ExpenditureTransaction) GetComplianceIssues() []string {
 	}
 
 	if !et.VerifyGSTIN(et.InvoiceDetails.GSTIN) {
-		issues = append(issues, "Invalid GSTIN format")
+		issues = append(issues, "Invalid GSTIN")
 	}
 
 	if et.InvoiceDetails.VendorName == "" {
@@ -382,6 +538,12 @@ func (et *ExpenditureTransaction) GetComplianceIssues() []string {
 
 	if et.InvoiceDetails.VendorGSTIN == "" {
 		issues = append(issues, "Missing vendor GSTIN")
+	} else if !et.VerifyGSTIN(et.InvoiceDetails.VendorGSTIN) {
+		issues = append(issues, "Invalid vendor GSTIN")
+	}
+
+	for _, flag := range et.InvoiceFlags {
+		issues = append(issues, flag.Description())
 	}
 
 	if et.InvoiceDetails.BankTransactionID == "" && et.InvoiceDetails.ChequeNumber == "" {
@@ -397,8 +559,8 @@ func (et *ExpenditureTransaction) GetComplianceIssues() []string {
 	}
 
 	daysDiff := time.Since(et.InvoiceDetails.InvoiceDate).Hours() / 24
-	if daysDiff > 90 {
-		issues = append(issues, fmt.Sprintf("Invoice is too old (%d days, threshold: 90 days)", int(daysDiff)))
+	if maxAge := rules.MaxInvoiceAgeDays(et.Category); maxAge > 0 && daysDiff > float64(maxAge) {
+		issues = append(issues, fmt.Sprintf("Invoice is too old (%d days, threshold: %d days)", int(daysDiff), maxAge))
 	}
 
 	return issues
@@ -0,0 +1,14 @@
+package transactions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// IdempotentTransactionID derives a transaction ID from a client idempotency
+// key. Keys are scoped to the kind of transaction and the donor or NGO that
+// sent it, so retries of one request always map to the same transaction.
+func IdempotentTransactionID(kind, ownerID, idempotencyKey string) string {
+	hash := sha256.Sum256([]byte(kind + "\x00" + ownerID + "\x00" + idempotencyKey))
+	return hex.EncodeToString(hash[:16])
+}
@@ -0,0 +1,142 @@
+package transactions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Kinds of invoice duplicate
+const (
+	DuplicateInvoiceNumber = "invoice_number" // Same vendor and invoice number
+	DuplicateAmountDate    = "amount_date"    // Same vendor, amount and invoice date under another number
+)
+
+// InvoiceRecord is a vendor invoice paid by a recorded expenditure
+type InvoiceRecord struct {
+	ExpenditureID string      `json:"expenditure_id"`
+	NGOID         string      `json:"ngo_id"`
+	VendorGSTIN   string      `json:"vendor_gstin"`
+	InvoiceNumber string      `json:"invoice_number"`
+	Amount        money.Money `json:"amount"`
+	InvoiceDate   time.Time   `json:"invoice_date"`
+}
+
+// InvoiceFlag marks an earlier expenditure whose invoice an expenditure may duplicate
+type InvoiceFlag struct {
+	Kind string `json:"kind"`
+	InvoiceRecord
+}
+
+// Description explains the flag for audit findings
+func (f InvoiceFlag) Description() string {
+	if f.Kind == DuplicateInvoiceNumber {
+		return fmt.Sprintf("Invoice %s from vendor %s was already paid by expenditure %s of NGO %s",
+			f.InvoiceNumber, f.VendorGSTIN, f.ExpenditureID, f.NGOID)
+	}
+	return fmt.Sprintf("Possible duplicate invoice: vendor %s billed ₹%s on %s as invoice %s, paid by expenditure %s of NGO %s",
+		f.VendorGSTIN, f.Amount, f.InvoiceDate.Format("2006-01-02"), f.InvoiceNumber, f.ExpenditureID, f.NGOID)
+}
+
+// NewInvoiceRecord returns the invoice an expenditure pays. It reports false
+// when the invoice names no vendor GSTIN, as there is no vendor to key it by.
+func NewInvoiceRecord(et *ExpenditureTransaction) (InvoiceRecord, bool) {
+	vendorGSTIN := strings.ToUpper(strings.TrimSpace(et.InvoiceDetails.VendorGSTIN))
+	if vendorGSTIN == "" {
+		return InvoiceRecord{}, false
+	}
+	return InvoiceRecord{
+		ExpenditureID: et.TransactionID,
+		NGOID:         et.NGOID,
+		VendorGSTIN:   vendorGSTIN,
+		InvoiceNumber: et.InvoiceDetails.InvoiceNumber,
+		Amount:        et.Amount,
+		InvoiceDate:   et.InvoiceDetails.InvoiceDate,
+	}, true
+}
+
+// InvoiceIndex holds the invoices paid across all NGOs, keyed by vendor and
+// invoice number and by vendor, amount and invoice date
+type InvoiceIndex struct {
+	byNumber      map[string]InvoiceRecord
+	byFingerprint map[string][]InvoiceRecord
+}
+
+func NewInvoiceIndex() *InvoiceIndex {
+	return &InvoiceIndex{
+		byNumber:      make(map[string]InvoiceRecord),
+		byFingerprint: make(map[string][]InvoiceRecord),
+	}
+}
+
+// Add indexes a paid invoice
+func (idx *InvoiceIndex) Add(record InvoiceRecord) {
+	if _, exists := idx.byNumber[numberKey(record)]; !exists && normalizeInvoiceNumber(record.InvoiceNumber) != "" {
+		idx.byNumber[numberKey(record)] = record
+	}
+	key := fingerprintKey(record)
+	idx.byFingerprint[key] = append(idx.byFingerprint[key], record)
+}
+
+// Remove drops the invoice an expenditure pays, e.g. before indexing the
+// corrected invoice of an amendment
+func (idx *InvoiceIndex) Remove(expenditureID string) {
+	for key, record := range idx.byNumber {
+		if record.ExpenditureID == expenditureID {
+			delete(idx.byNumber, key)
+		}
+	}
+	for key, records := range idx.byFingerprint {
+		kept := records[:0]
+		for _, record := range records {
+			if record.ExpenditureID != expenditureID {
+				kept = append(kept, record)
+			}
+		}
+		if len(kept) == 0 {
+			delete(idx.byFingerprint, key)
+		} else {
+			idx.byFingerprint[key] = kept
+		}
+	}
+}
+
+// Check returns the indexed invoices that record may duplicate: one with the
+// same vendor and invoice number, and any with the same vendor, amount and
+// invoice date under another number. Invoice numbers are compared ignoring
+// case and punctuation.
+func (idx *InvoiceIndex) Check(record InvoiceRecord) []InvoiceFlag {
+	var flags []InvoiceFlag
+	if existing, exists := idx.byNumber[numberKey(record)]; exists && existing.ExpenditureID != record.ExpenditureID {
+		flags = append(flags, InvoiceFlag{Kind: DuplicateInvoiceNumber, InvoiceRecord: existing})
+	}
+	for _, existing := range idx.byFingerprint[fingerprintKey(record)] {
+		if existing.ExpenditureID == record.ExpenditureID || normalizeInvoiceNumber(existing.InvoiceNumber) == normalizeInvoiceNumber(record.InvoiceNumber) {
+			continue
+		}
+		flags = append(flags, InvoiceFlag{Kind: DuplicateAmountDate, InvoiceRecord: existing})
+	}
+	return flags
+}
+
+func numberKey(record InvoiceRecord) string {
+	return record.VendorGSTIN + "|" + normalizeInvoiceNumber(record.InvoiceNumber)
+}
+
+func fingerprintKey(record InvoiceRecord) string {
+	return fmt.Sprintf("%s|%d|%s", record.VendorGSTIN, record.Amount.Minor(), record.InvoiceDate.Format("2006-01-02"))
+}
+
+// normalizeInvoiceNumber keeps only the letters and digits of an invoice
+// number, upper-cased, so "inv/001" and "INV-001" compare equal
+func normalizeInvoiceNumber(number string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, number)
+}
@@ -0,0 +1,111 @@
+package transactions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"ngo-transparency-platform/pkg/money"
+)
+
+// Reversal kinds
+const (
+	ReversalRefund     = "refund"     // Initiated by the NGO
+	ReversalChargeback = "chargeback" // Raised by the donor's bank through the gateway
+)
+
+// DonationReversal returns all or part of a completed donation to the donor.
+// The platform gives up its share of the fee pro rata, so the NGO bears only
+// the net share of the returned amount.
+type DonationReversal struct {
+	ReversalID        string      `json:"reversal_id"`
+	Kind              string      `json:"kind"`
+	TransactionID     string      `json:"transaction_id"` // Reversed donation
+	OriginalBlockHash string      `json:"original_block_hash"`
+	DonorID           string      `json:"donor_id"`
+	NGOID             string      `json:"ngo_id"`
+	CampaignID        string      `json:"campaign_id,omitempty"` // Campaign the reversed donation was earmarked to
+	GrossAmount       money.Money `json:"gross_amount"`          // Returned to the donor
+	PlatformFee       money.Money `json:"platform_fee"`          // Share of the platform fee returned
+	Amount            money.Money `json:"amount"`                // Share borne by the NGO
+	Reason            string      `json:"reason"`
+	GatewayReference  string      `json:"gateway_reference,omitempty"` // Gateway refund or dispute ID
+	VoidedBillID      string      `json:"voided_bill_id,omitempty"`
+	ReissuedEBill     *EBill      `json:"reissued_e_bill,omitempty"`
+	BlockHash         string      `json:"block_hash,omitempty"`
+	Timestamp         time.Time   `json:"timestamp"`
+}
+
+// NewDonationReversal creates a reversal of grossAmount, of which platformFee
+// is the platform's share
+func NewDonationReversal(kind, transactionID, originalBlockHash, donorID, ngoID string, grossAmount, platformFee money.Money, reason string) (*DonationReversal, error) {
+	if kind != ReversalRefund && kind != ReversalChargeback {
+		return nil, fmt.Errorf("unsupported reversal kind: %s", kind)
+	}
+	if !grossAmount.IsPositive() {
+		return nil, fmt.Errorf("reversal amount must be positive")
+	}
+	if platformFee.IsNegative() || platformFee.Cmp(grossAmount) > 0 {
+		return nil, fmt.Errorf("invalid platform fee share")
+	}
+
+	randomBytes := make([]byte, 16)
+	rand.Read(randomBytes)
+
+	return &DonationReversal{
+		ReversalID:        "REV_" + hex.EncodeToString(randomBytes),
+		Kind:              kind,
+		TransactionID:     transactionID,
+		OriginalBlockHash: originalBlockHash,
+		DonorID:           donorID,
+		NGOID:             ngoID,
+		GrossAmount:       grossAmount,
+		PlatformFee:       platformFee,
+		Amount:            grossAmount.Sub(platformFee),
+		Reason:            reason,
+		Timestamp:         time.Now(),
+	}, nil
+}
+
+// ReplaceEBill voids the donation's current e-bill and, when part of the
+// donation remains, reissues one for the remaining amount
+func (r *DonationReversal) ReplaceEBill(current *EBill, remaining money.Money) {
+	if current == nil {
+		return
+	}
+	r.VoidedBillID = current.BillID
+	if remaining.IsPositive() {
+		r.ReissuedEBill = ReissueEBill(current, r.DonorID, remaining)
+	}
+}
+
+// Void marks the e-bill as no longer valid for tax purposes
+func (b *EBill) Void(reason string) {
+	now := time.Now()
+	b.VoidedAt = &now
+	b.VoidReason = reason
+}
+
+// IsVoided reports whether the e-bill has been voided
+func (b *EBill) IsVoided() bool {
+	return b.VoidedAt != nil
+}
+
+// ReissueEBill issues a new e-bill for the amount of a donation that remains
+// after a partial reversal. It keeps the donation date so the tax benefit
+// stays in the original financial year.
+func ReissueEBill(original *EBill, donorID string, amount money.Money) *EBill {
+	donation := &DonationTransaction{
+		TransactionID: original.TransactionID,
+		DonorID:       donorID,
+		NGOID:         original.NGOID,
+		Amount:        amount,
+		PaymentMethod: original.PaymentMethod,
+		Timestamp:     original.Timestamp,
+	}
+
+	eBill := donation.generateEBill()
+	eBill.Replaces = original.BillID
+	return eBill
+}